@@ -2,13 +2,40 @@ package main
 
 import (
 	"blockbustermvc/internal/database"
+	exportsModule "blockbustermvc/internal/exports"
+	healthModule "blockbustermvc/internal/health"
+	"blockbustermvc/internal/httputil"
+	importsModule "blockbustermvc/internal/imports"
+	inventoryModule "blockbustermvc/internal/inventory"
+	jobsModule "blockbustermvc/internal/jobs"
+	liveModule "blockbustermvc/internal/live"
 	loansModule "blockbustermvc/internal/loans"
+	"blockbustermvc/internal/logging"
+	metadataModule "blockbustermvc/internal/metadata"
+	healthModels "blockbustermvc/internal/models/health"
 	moviesModule "blockbustermvc/internal/movies"
+	notificationsModule "blockbustermvc/internal/notifications"
+	outboxModule "blockbustermvc/internal/outbox"
+	recommendationsModule "blockbustermvc/internal/recommendations"
+	reportsModule "blockbustermvc/internal/reports"
+	reviewsModule "blockbustermvc/internal/reviews"
+	statsModule "blockbustermvc/internal/stats"
+	stocktakesModule "blockbustermvc/internal/stocktakes"
+	storesModule "blockbustermvc/internal/stores"
+	transfersModule "blockbustermvc/internal/transfers"
 	usersModule "blockbustermvc/internal/users"
 	webModule "blockbustermvc/internal/web"
+	webhooksModule "blockbustermvc/internal/webhooks"
+	wishlistsModule "blockbustermvc/internal/wishlists"
+	"context"
 	"encoding/gob"
-	"log"
+	"log/slog"
+	"net/http"
 	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -18,59 +45,319 @@ import (
 func main() {
 	gob.Register(uuid.UUID{})
 
+	level, err := logging.ConfiguredLevel()
+	if err != nil {
+		logging.Fatal("Failed to configure logging", "error", err)
+	}
+	logging.Setup(os.Stdout, level)
+
 	// Initialize database configuration
 	dbConfig := database.NewConfig()
 
 	// Connect to database
 	db, err := database.NewDatabase(dbConfig)
 	if err != nil {
-		log.Fatal("Failed to connect to database:", err)
+		logging.Fatal("Failed to connect to database", "error", err)
 	}
-	defer db.Close()
 
 	// Initialize repositories
+	storeRepo := storesModule.NewStoreRepository(db.Pool)
 	movieRepo := moviesModule.NewMovieRepository(db.Pool)
 	userRepo := usersModule.NewUserRepository(db.Pool)
 	loanRepo := loansModule.NewLoanRepository(db.Pool)
+	inventoryRepo := inventoryModule.NewInventoryRepository(db.Pool)
+	stocktakeRepo := stocktakesModule.NewStocktakeRepository(db.Pool)
+	transferRepo := transfersModule.NewTransferRepository(db.Pool)
+	importRepo := importsModule.NewImportRepository(db.Pool)
+	exportRepo := exportsModule.NewExportRepository(db.Pool)
+	metadataRepo := metadataModule.NewMetadataRepository(db.Pool)
+	reviewRepo := reviewsModule.NewReviewRepository(db.Pool)
+	recommendationRepo := recommendationsModule.NewRecommendationRepository(db.Pool)
+	wishlistRepo := wishlistsModule.NewWishlistRepository(db.Pool)
+	notificationRepo := notificationsModule.NewNotificationRepository(db.Pool)
+	jobRepo := jobsModule.NewJobRepository(db.Pool)
+	eventRepo := outboxModule.NewEventRepository(db.Pool)
+	webhookRepo := webhooksModule.NewWebhookRepository(db.Pool)
+	statsRepo := statsModule.NewStatsRepository(db.Pool)
+	reportRepo := reportsModule.NewReportRepository(db.Pool)
+	healthRepo := healthModule.NewHealthRepository(db.Pool)
+
+	// Metadata enrichment stays disabled when no provider is configured
+	metadataProvider, err := metadataModule.NewConfiguredProvider()
+	if err != nil {
+		logging.Fatal("Failed to load metadata provider", "error", err)
+	}
+
+	recommendationsInterval, err := recommendationsModule.ConfiguredRefreshInterval()
+	if err != nil {
+		logging.Fatal("Failed to configure recommendations", "error", err)
+	}
+
+	// Notifications stay queued until an SMTP server is configured
+	notifier, err := notificationsModule.NewConfiguredNotifier()
+	if err != nil {
+		logging.Fatal("Failed to configure notifier", "error", err)
+	}
+
+	loanPeriod, err := notificationsModule.ConfiguredLoanPeriod()
+	if err != nil {
+		logging.Fatal("Failed to configure notifications", "error", err)
+	}
+
+	notificationsInterval, err := notificationsModule.ConfiguredDispatchInterval()
+	if err != nil {
+		logging.Fatal("Failed to configure notifications", "error", err)
+	}
+
+	embeddedWorker, err := jobsModule.ConfiguredEmbeddedWorker()
+	if err != nil {
+		logging.Fatal("Failed to configure jobs", "error", err)
+	}
+
+	workerConfig, err := jobsModule.ConfiguredWorker()
+	if err != nil {
+		logging.Fatal("Failed to configure jobs", "error", err)
+	}
+
+	outboxInterval, err := outboxModule.ConfiguredPollInterval()
+	if err != nil {
+		logging.Fatal("Failed to configure outbox", "error", err)
+	}
+
+	webhooksInterval, err := webhooksModule.ConfiguredDeliveryInterval()
+	if err != nil {
+		logging.Fatal("Failed to configure webhooks", "error", err)
+	}
+
+	reportsInterval, err := reportsModule.ConfiguredRefreshInterval()
+	if err != nil {
+		logging.Fatal("Failed to configure reports", "error", err)
+	}
+
+	archiveYears, err := loansModule.ConfiguredArchiveYears()
+	if err != nil {
+		logging.Fatal("Failed to configure loan archiving", "error", err)
+	}
+
+	storeScope, err := storesModule.ConfiguredScope()
+	if err != nil {
+		logging.Fatal("Failed to configure the store scope", "error", err)
+	}
+
+	shutdownTimeout, err := healthModule.ConfiguredShutdownTimeout()
+	if err != nil {
+		logging.Fatal("Failed to configure shutdown", "error", err)
+	}
+
+	// Readiness fails until the schema has every migration this build ships with
+	migration, err := database.LatestMigration()
+	if err != nil {
+		logging.Fatal("Failed to read migrations", "error", err)
+	}
 
 	// Initialize services
+	storeService := storesModule.NewStoreService(storeRepo, storeScope)
 	movieService := moviesModule.NewMovieService(movieRepo)
 	userService := usersModule.NewUserService(userRepo)
+	inventoryService := inventoryModule.NewInventoryService(inventoryRepo)
+	notificationService, err := notificationsModule.NewNotificationService(notificationRepo, userService, notifier, loanPeriod)
+	if err != nil {
+		logging.Fatal("Failed to load notification templates", "error", err)
+	}
 	loanService := loansModule.NewLoanService(loanRepo, movieService, userService)
+	stocktakeService := stocktakesModule.NewStocktakeService(stocktakeRepo, movieService)
+	transferService := transfersModule.NewTransferService(transferRepo, movieService, storeService)
+	importService := importsModule.NewImportService(importRepo)
+	exportService := exportsModule.NewExportService(exportRepo)
+	metadataService := metadataModule.NewMetadataService(metadataRepo, movieService, metadataProvider)
+	reviewService := reviewsModule.NewReviewService(reviewRepo, movieService, userService)
+	recommendationService := recommendationsModule.NewRecommendationService(recommendationRepo, movieService, userService)
+	wishlistService := wishlistsModule.NewWishlistService(wishlistRepo, movieService, userService)
+	jobService := jobsModule.NewJobService(jobRepo)
+	eventService := outboxModule.NewEventService(eventRepo)
+	webhookService := webhooksModule.NewWebhookService(webhookRepo)
+	statsService := statsModule.NewStatsService(statsRepo, loanPeriod)
+	reportService := reportsModule.NewReportService(reportRepo, loanPeriod)
+
+	// Only a job worker running in this process is part of the readiness check
+	var workerMonitors []healthModels.IWorkerMonitor
+	if embeddedWorker {
+		workerConfig.Status = jobsModule.NewWorkerStatus()
+		workerMonitors = append(workerMonitors, workerConfig.Status)
+	}
+	healthService := healthModule.NewHealthService(healthRepo, migration, workerMonitors...)
+
+	// Live pages hear about events committed by any instance through Postgres
+	liveHub := liveModule.NewHub()
+
+	// Subscribers hear about domain events through the outbox
+	notificationsModule.Subscribe(eventService, notificationService)
+	webhooksModule.Subscribe(eventService, webhookService)
+
+	// Background work runs as jobs; register the kinds and their schedules
+	if err := recommendationsModule.RegisterJobs(context.Background(), jobService, recommendationService, recommendationsInterval); err != nil {
+		logging.Fatal("Failed to schedule recommendations", "error", err)
+	}
+	if err := notificationsModule.RegisterJobs(context.Background(), jobService, notificationService, notificationsInterval); err != nil {
+		logging.Fatal("Failed to schedule notifications", "error", err)
+	}
+	if err := outboxModule.RegisterJobs(context.Background(), jobService, eventService); err != nil {
+		logging.Fatal("Failed to schedule outbox pruning", "error", err)
+	}
+	if err := webhooksModule.RegisterJobs(context.Background(), jobService, webhookService, webhooksInterval); err != nil {
+		logging.Fatal("Failed to schedule webhooks", "error", err)
+	}
+	if err := reportsModule.RegisterJobs(context.Background(), jobService, reportService, reportsInterval); err != nil {
+		logging.Fatal("Failed to schedule reports", "error", err)
+	}
+	if err := loansModule.RegisterJobs(context.Background(), jobService, loanService, archiveYears); err != nil {
+		logging.Fatal("Failed to schedule loan archiving", "error", err)
+	}
 
 	// Initialize controllers with services
+	storesController := storesModule.NewStoresController(storeService)
 	moviesController := moviesModule.NewMoviesController(&movieService)
 	usersController := usersModule.NewUserController(userService)
 	loansController := loansModule.NewLoansController(loanService)
+	inventoryController := inventoryModule.NewInventoryController(inventoryService)
+	stocktakesController := stocktakesModule.NewStocktakesController(stocktakeService)
+	transfersController := transfersModule.NewTransfersController(transferService)
+	importsController := importsModule.NewImportsController(importService)
+	exportsController := exportsModule.NewExportsController(exportService)
+	metadataController := metadataModule.NewMetadataController(metadataService)
+	reviewsController := reviewsModule.NewReviewsController(reviewService)
+	recommendationsController := recommendationsModule.NewRecommendationsController(recommendationService)
+	wishlistController := wishlistsModule.NewWishlistController(wishlistService)
+	notificationsController := notificationsModule.NewNotificationsController(notificationService)
+	jobsController := jobsModule.NewJobsController(jobService)
+	eventsController := outboxModule.NewEventsController(eventService)
+	webhooksController := webhooksModule.NewWebhooksController(webhookService)
+	statsController := statsModule.NewStatsController(statsService)
+	reportsController := reportsModule.NewReportsController(reportService)
+	healthController := healthModule.NewHealthController(healthService)
+
+	webController := webModule.NewWebController(movieService, userService, loanService, inventoryService, stocktakeService, storeService, transferService, metadataService, reviewService, recommendationService, wishlistService, notificationService, jobService, webhookService, statsService, reportService, liveHub)
 
-	webController := webModule.NewWebController(movieService, userService, loanService)
+	// Initialize Gin router; every request gets an ID and one access log line
+	router := gin.New()
+	router.Use(logging.RequestID(), logging.AccessLog("/healthz", "/readyz"), logging.Recovery())
 
-	// Initialize Gin router
-	router := gin.Default()
+	// Probes are registered before the middleware, so they never depend on a store
+	healthController.RegisterRoutes(&router.RouterGroup)
 
 	// Config router
 	config := cors.DefaultConfig()
 	config.AllowAllOrigins = true
-	config.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization"}
-	config.AllowMethods = []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}
+	config.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization", "If-Match", httputil.StoreHeader, logging.RequestIDHeader}
+	config.ExposeHeaders = []string{"ETag", "Location", "Content-Disposition", logging.RequestIDHeader}
+	config.AllowMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
 	router.Use(cors.New(config))
 
+	// Every request acts on behalf of a store
+	router.Use(storesModule.StoreScope(storeService))
+
 	// Register routes
 	apiRouter := router.Group("/api")
+	storesController.RegisterRoutes(apiRouter)
 	usersController.RegisterRoutes(apiRouter)
 	moviesController.RegisterRoutes(apiRouter)
 	loansController.RegisterRoutes(apiRouter)
+	inventoryController.RegisterRoutes(apiRouter)
+	stocktakesController.RegisterRoutes(apiRouter)
+	transfersController.RegisterRoutes(apiRouter)
+	importsController.RegisterRoutes(apiRouter)
+	exportsController.RegisterRoutes(apiRouter)
+	metadataController.RegisterRoutes(apiRouter)
+	reviewsController.RegisterRoutes(apiRouter)
+	recommendationsController.RegisterRoutes(apiRouter)
+	wishlistController.RegisterRoutes(apiRouter)
+	notificationsController.RegisterRoutes(apiRouter)
+	jobsController.RegisterRoutes(apiRouter)
+	eventsController.RegisterRoutes(apiRouter)
+	webhooksController.RegisterRoutes(apiRouter)
+	statsController.RegisterRoutes(apiRouter)
+	reportsController.RegisterRoutes(apiRouter)
 
 	webController.RegisterRoutes(router)
 
+	// Background work stops when the server has drained, before the pool closes
+	background, stopBackground := context.WithCancel(context.Background())
+	var workers sync.WaitGroup
+
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		liveModule.RunListener(background, db.Pool, liveHub)
+	}()
+
+	// Run the jobs and deliver events here unless separate cmd/worker processes do
+	if embeddedWorker {
+		workers.Add(2)
+		go func() {
+			defer workers.Done()
+			jobsModule.RunWorker(background, jobService, workerConfig)
+		}()
+		go func() {
+			defer workers.Done()
+			outboxModule.RunDispatcher(background, eventService, outboxInterval)
+		}()
+	}
+
 	// Get server port from environment or use default
 	port := os.Getenv("SERVER_PORT")
 	if port == "" {
 		port = "8080"
 	}
 
-	log.Printf("Starting server on port %s", port)
-	if err := router.Run(":" + port); err != nil {
-		log.Fatal("Failed to start server:", err)
+	server := &http.Server{
+		Addr:              ":" + port,
+		Handler:           router,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	// Live streams never end on their own; close them so the server can drain
+	server.RegisterOnShutdown(liveHub.Close)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		slog.Info("Starting server", "port", port)
+		serverErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serverErr:
+		logging.Fatal("Failed to start server", "error", err)
+	case <-ctx.Done():
 	}
+	stop()
+
+	// Stop taking requests, let in-flight ones finish, then stop the
+	// background work and close the pool last, all within shutdownTimeout
+	slog.Info("Shutting down", "timeout", shutdownTimeout.String())
+	healthService.Drain()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		slog.Warn("Requests still running at the shutdown timeout were cut off", "error", err)
+		server.Close()
+	}
+
+	stopBackground()
+	stopped := make(chan struct{})
+	go func() {
+		workers.Wait()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-shutdownCtx.Done():
+		slog.Warn("Background workers did not stop before the shutdown timeout")
+	}
+
+	db.Close()
+	slog.Info("Server stopped")
 }
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"blockbustermvc/internal/database"
+	"blockbustermvc/internal/logging"
+	metadataModule "blockbustermvc/internal/metadata"
+	moviesModule "blockbustermvc/internal/movies"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/joho/godotenv"
+)
+
+// Looks up every movie of the catalog with the configured metadata provider
+// and stores the missing metadata it finds as proposals for staff review.
+func main() {
+	// The environment may also be set directly, so a missing .env is fine
+	if err := godotenv.Load(); err != nil && !errors.Is(err, os.ErrNotExist) {
+		logging.Fatal("Failed to load .env", "error", err)
+	}
+
+	level, err := logging.ConfiguredLevel()
+	if err != nil {
+		logging.Fatal("Failed to configure logging", "error", err)
+	}
+	logging.Setup(os.Stderr, level)
+
+	provider, err := metadataModule.NewConfiguredProvider()
+	if err != nil {
+		logging.Fatal("Failed to load metadata provider", "error", err)
+	}
+	if provider == nil {
+		logging.Fatal("No metadata provider configured, set BLK_METADATA_DUMP")
+	}
+
+	db, err := database.NewDatabase(database.NewConfig())
+	if err != nil {
+		logging.Fatal("Failed to connect to database", "error", err)
+	}
+	defer db.Close()
+
+	movieService := moviesModule.NewMovieService(moviesModule.NewMovieRepository(db.Pool))
+	metadataService := metadataModule.NewMetadataService(metadataModule.NewMetadataRepository(db.Pool), movieService, provider)
+
+	run, err := metadataService.ProposeEnrichment(context.Background())
+	if err != nil {
+		logging.Fatal("Enrichment failed", "error", err)
+	}
+
+	fmt.Printf("%s: %d movies checked, %d found, %d proposals waiting for review\n", run.Provider, run.Checked, run.Matched, run.Proposed)
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"blockbustermvc/internal/database"
+	importsModule "blockbustermvc/internal/imports"
+	"blockbustermvc/internal/logging"
+	models "blockbustermvc/internal/models/imports"
+	storeModels "blockbustermvc/internal/models/store"
+	storesModule "blockbustermvc/internal/stores"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	kind := flag.String("kind", "", "what the file holds: movies, copies, users or loans")
+	path := flag.String("file", "", "path of the CSV or JSON Lines file to import")
+	format := flag.String("format", "", "csv or jsonl (default: guessed from the file extension)")
+	store := flag.String("store", "", "code or ID of the store that receives stock and loans (default: the default store)")
+	dryRun := flag.Bool("dry-run", false, "validate the file without writing anything")
+	flag.Parse()
+
+	if *kind == "" || *path == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *format == "" {
+		*format = models.FormatFromFileName(*path)
+	}
+
+	// The environment may also be set directly, so a missing .env is fine
+	if err := godotenv.Load(); err != nil && !errors.Is(err, os.ErrNotExist) {
+		logging.Fatal("Failed to load .env", "error", err)
+	}
+
+	level, err := logging.ConfiguredLevel()
+	if err != nil {
+		logging.Fatal("Failed to configure logging", "error", err)
+	}
+	logging.Setup(os.Stderr, level)
+
+	db, err := database.NewDatabase(database.NewConfig())
+	if err != nil {
+		logging.Fatal("Failed to connect to database", "error", err)
+	}
+	defer db.Close()
+
+	storeService := storesModule.NewStoreService(storesModule.NewStoreRepository(db.Pool), nil)
+	importService := importsModule.NewImportService(importsModule.NewImportRepository(db.Pool))
+
+	ctx := context.Background()
+	storeId, err := resolveStore(ctx, storeService, *store)
+	if err != nil {
+		logging.Fatal("Failed to find store", "error", err)
+	}
+
+	file, err := os.Open(*path)
+	if err != nil {
+		logging.Fatal("Failed to open import file", "error", err)
+	}
+	defer file.Close()
+
+	result, err := importService.Import(ctx, &models.ImportOptionsDTO{
+		Kind:    *kind,
+		Format:  *format,
+		StoreID: storeId,
+		DryRun:  *dryRun,
+	}, file)
+	if err != nil {
+		logging.Fatal("Import failed", "error", err)
+	}
+
+	for _, rowErr := range result.Errors {
+		fields := make([]string, 0, len(rowErr.Errors))
+		for field := range rowErr.Errors {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+
+		for _, field := range fields {
+			fmt.Printf("row %d: %s %s\n", rowErr.Row, field, rowErr.Errors[field])
+		}
+	}
+
+	fmt.Printf("%d rows read, %d valid, %d invalid, %d imported", result.Total, result.Valid, result.Invalid, result.Imported)
+	if result.DryRun {
+		fmt.Print(" (dry run)")
+	}
+	fmt.Println()
+
+	if result.Invalid > 0 {
+		os.Exit(1)
+	}
+}
+
+func resolveStore(ctx context.Context, storeService storeModels.IStoreService, store string) (uuid.UUID, error) {
+	if store == "" {
+		defaultStore, err := storeService.GetDefaultStore(ctx)
+		if err != nil {
+			return uuid.Nil, err
+		}
+		return defaultStore.ID, nil
+	}
+
+	if id, err := uuid.Parse(store); err == nil {
+		found, err := storeService.GetStore(ctx, id)
+		if err != nil {
+			return uuid.Nil, err
+		}
+		return found.ID, nil
+	}
+
+	stores, err := storeService.GetAllStores(ctx)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	for _, s := range stores {
+		if strings.EqualFold(s.Code, store) {
+			return s.ID, nil
+		}
+	}
+
+	return uuid.Nil, storeModels.ErrStoreNotFound
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"blockbustermvc/internal/database"
+	jobsModule "blockbustermvc/internal/jobs"
+	loansModule "blockbustermvc/internal/loans"
+	"blockbustermvc/internal/logging"
+	moviesModule "blockbustermvc/internal/movies"
+	notificationsModule "blockbustermvc/internal/notifications"
+	outboxModule "blockbustermvc/internal/outbox"
+	recommendationsModule "blockbustermvc/internal/recommendations"
+	reportsModule "blockbustermvc/internal/reports"
+	usersModule "blockbustermvc/internal/users"
+	webhooksModule "blockbustermvc/internal/webhooks"
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/joho/godotenv"
+)
+
+// Runs background jobs and delivers outbox events outside the API process.
+// Start as many workers as needed; they share the jobs and outbox tables
+// and never run the same job twice. Set BLK_JOBS_EMBEDDED=false so the API
+// leaves the work to them.
+func main() {
+	// The environment may also be set directly, so a missing .env is fine
+	if err := godotenv.Load(); err != nil && !errors.Is(err, os.ErrNotExist) {
+		logging.Fatal("Failed to load .env", "error", err)
+	}
+
+	level, err := logging.ConfiguredLevel()
+	if err != nil {
+		logging.Fatal("Failed to configure logging", "error", err)
+	}
+	logging.Setup(os.Stdout, level)
+
+	db, err := database.NewDatabase(database.NewConfig())
+	if err != nil {
+		logging.Fatal("Failed to connect to database", "error", err)
+	}
+	defer db.Close()
+
+	notifier, err := notificationsModule.NewConfiguredNotifier()
+	if err != nil {
+		logging.Fatal("Failed to configure notifier", "error", err)
+	}
+
+	loanPeriod, err := notificationsModule.ConfiguredLoanPeriod()
+	if err != nil {
+		logging.Fatal("Failed to configure notifications", "error", err)
+	}
+
+	notificationsInterval, err := notificationsModule.ConfiguredDispatchInterval()
+	if err != nil {
+		logging.Fatal("Failed to configure notifications", "error", err)
+	}
+
+	recommendationsInterval, err := recommendationsModule.ConfiguredRefreshInterval()
+	if err != nil {
+		logging.Fatal("Failed to configure recommendations", "error", err)
+	}
+
+	workerConfig, err := jobsModule.ConfiguredWorker()
+	if err != nil {
+		logging.Fatal("Failed to configure jobs", "error", err)
+	}
+
+	outboxInterval, err := outboxModule.ConfiguredPollInterval()
+	if err != nil {
+		logging.Fatal("Failed to configure outbox", "error", err)
+	}
+
+	webhooksInterval, err := webhooksModule.ConfiguredDeliveryInterval()
+	if err != nil {
+		logging.Fatal("Failed to configure webhooks", "error", err)
+	}
+
+	reportsInterval, err := reportsModule.ConfiguredRefreshInterval()
+	if err != nil {
+		logging.Fatal("Failed to configure reports", "error", err)
+	}
+
+	archiveYears, err := loansModule.ConfiguredArchiveYears()
+	if err != nil {
+		logging.Fatal("Failed to configure loan archiving", "error", err)
+	}
+
+	movieService := moviesModule.NewMovieService(moviesModule.NewMovieRepository(db.Pool))
+	userService := usersModule.NewUserService(usersModule.NewUserRepository(db.Pool))
+	loanService := loansModule.NewLoanService(loansModule.NewLoanRepository(db.Pool), movieService, userService)
+	notificationService, err := notificationsModule.NewNotificationService(notificationsModule.NewNotificationRepository(db.Pool), userService, notifier, loanPeriod)
+	if err != nil {
+		logging.Fatal("Failed to load notification templates", "error", err)
+	}
+	recommendationService := recommendationsModule.NewRecommendationService(recommendationsModule.NewRecommendationRepository(db.Pool), movieService, userService)
+	jobService := jobsModule.NewJobService(jobsModule.NewJobRepository(db.Pool))
+	eventService := outboxModule.NewEventService(outboxModule.NewEventRepository(db.Pool))
+	webhookService := webhooksModule.NewWebhookService(webhooksModule.NewWebhookRepository(db.Pool))
+	reportService := reportsModule.NewReportService(reportsModule.NewReportRepository(db.Pool), loanPeriod)
+
+	notificationsModule.Subscribe(eventService, notificationService)
+	webhooksModule.Subscribe(eventService, webhookService)
+
+	if err := recommendationsModule.RegisterJobs(context.Background(), jobService, recommendationService, recommendationsInterval); err != nil {
+		logging.Fatal("Failed to schedule recommendations", "error", err)
+	}
+	if err := notificationsModule.RegisterJobs(context.Background(), jobService, notificationService, notificationsInterval); err != nil {
+		logging.Fatal("Failed to schedule notifications", "error", err)
+	}
+	if err := outboxModule.RegisterJobs(context.Background(), jobService, eventService); err != nil {
+		logging.Fatal("Failed to schedule outbox pruning", "error", err)
+	}
+	if err := webhooksModule.RegisterJobs(context.Background(), jobService, webhookService, webhooksInterval); err != nil {
+		logging.Fatal("Failed to schedule webhooks", "error", err)
+	}
+	if err := reportsModule.RegisterJobs(context.Background(), jobService, reportService, reportsInterval); err != nil {
+		logging.Fatal("Failed to schedule reports", "error", err)
+	}
+	if err := loansModule.RegisterJobs(context.Background(), jobService, loanService, archiveYears); err != nil {
+		logging.Fatal("Failed to schedule loan archiving", "error", err)
+	}
+
+	// Stop taking jobs on Ctrl+C or SIGTERM, and let running ones finish
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// The deferred db.Close runs once both have stopped
+	var dispatcher sync.WaitGroup
+	dispatcher.Add(1)
+	go func() {
+		defer dispatcher.Done()
+		outboxModule.RunDispatcher(ctx, eventService, outboxInterval)
+	}()
+
+	jobsModule.RunWorker(ctx, jobService, workerConfig)
+	dispatcher.Wait()
+}
@@ -0,0 +1,25 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// Duration reads a duration such as 30s or 15m from the environment
+// variable name, or returns fallback when it is unset. Negative durations
+// are rejected, and so is zero unless allowZero is set, for the settings
+// where zero turns a schedule off.
+func Duration(name string, fallback time.Duration, allowZero bool) (time.Duration, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration < 0 || (duration == 0 && !allowZero) {
+		return 0, fmt.Errorf("invalid %s %q: expected a duration such as 30m", name, value)
+	}
+
+	return duration, nil
+}
@@ -0,0 +1,46 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDuration(t *testing.T) {
+	const name = "BLK_TEST_DURATION"
+
+	tests := []struct {
+		name      string
+		value     string
+		allowZero bool
+		want      time.Duration
+		wantErr   bool
+	}{
+		{name: "unset falls back", value: "", want: time.Hour},
+		{name: "parsed", value: "15m", want: 15 * time.Minute},
+		{name: "zero when allowed", value: "0", allowZero: true, want: 0},
+		{name: "zero when not allowed", value: "0", wantErr: true},
+		{name: "negative", value: "-1s", allowZero: true, wantErr: true},
+		{name: "not a duration", value: "soon", wantErr: true},
+		{name: "missing unit", value: "30", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(name, tt.value)
+
+			got, err := Duration(name, time.Hour, tt.allowZero)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Duration() = %s, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Duration() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Duration() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
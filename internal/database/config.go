@@ -3,7 +3,7 @@ package database
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
 
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -46,7 +46,13 @@ type Database struct {
 }
 
 func NewDatabase(config *Config) (*Database, error) {
-	pool, err := pgxpool.New(context.Background(), config.ConnectionString())
+	poolConfig, err := pgxpool.ParseConfig(config.ConnectionString())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse connection string: %w", err)
+	}
+	poolConfig.ConnConfig.Tracer = newTracer()
+
+	pool, err := pgxpool.NewWithConfig(context.Background(), poolConfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create connection pool: %w", err)
 	}
@@ -56,13 +62,13 @@ func NewDatabase(config *Config) (*Database, error) {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	log.Println("Successfully connected to PostgreSQL database")
+	slog.Info("Connected to PostgreSQL", "host", config.Host, "database", config.DBName)
 	return &Database{Pool: pool}, nil
 }
 
 func (db *Database) Close() {
 	if db.Pool != nil {
 		db.Pool.Close()
-		log.Println("Database connection closed")
+		slog.Info("Database connection closed")
 	}
 }
@@ -0,0 +1,116 @@
+// Package dbtest connects integration tests to a scratch Postgres database.
+package dbtest
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// URLVariable names the connection string of a database migrated with tern
+// that integration tests may write to. Tests that need a database are
+// skipped when it is unset.
+const URLVariable = "BLK_TEST_DATABASE_URL"
+
+// Open connects to the test database, or skips the test when there is none.
+// The pool is closed when the test ends.
+func Open(t testing.TB) *pgxpool.Pool {
+	t.Helper()
+
+	url := os.Getenv(URLVariable)
+	if url == "" {
+		t.Skipf("%s is not set", URLVariable)
+	}
+
+	pool, err := pgxpool.New(context.Background(), url)
+	if err != nil {
+		t.Fatalf("failed to connect to the test database: %v", err)
+	}
+	t.Cleanup(pool.Close)
+
+	return pool
+}
+
+// Store inserts a store with a unique code and returns its ID.
+func Store(t testing.TB, pool *pgxpool.Pool) uuid.UUID {
+	t.Helper()
+
+	suffix := uniqueSuffix()
+	return insert(t, pool, `INSERT INTO stores (code, name) VALUES ($1, $2) RETURNING id`, "T"+suffix, "Test store "+suffix)
+}
+
+// Movie inserts a movie with no stock and returns its ID.
+func Movie(t testing.TB, pool *pgxpool.Pool) uuid.UUID {
+	t.Helper()
+
+	suffix := uniqueSuffix()
+	return insert(t, pool, `INSERT INTO movies (name, director, year, quantity) VALUES ($1, $2, 2000, 0) RETURNING id`, "Movie "+suffix, "Director "+suffix)
+}
+
+// User inserts a user and returns its ID.
+func User(t testing.TB, pool *pgxpool.Pool) uuid.UUID {
+	t.Helper()
+
+	suffix := uniqueSuffix()
+	return insert(t, pool, `INSERT INTO users (user_name, email) VALUES ($1, $2) RETURNING id`, "user"+suffix, "user"+suffix+"@example.com")
+}
+
+// Copy inserts a copy of the movie with a unique barcode and returns its ID.
+func Copy(t testing.TB, pool *pgxpool.Pool, movieId uuid.UUID) uuid.UUID {
+	t.Helper()
+
+	return insert(t, pool, `INSERT INTO copies (movie_id, barcode) VALUES ($1, $2) RETURNING id`, movieId, "BB-"+uniqueSuffix())
+}
+
+// ArchivedLoan inserts a returned loan and moves it to loans_archive, as the
+// archive job would, and returns its ID.
+func ArchivedLoan(t testing.TB, pool *pgxpool.Pool, storeId, movieId, userId uuid.UUID) uuid.UUID {
+	t.Helper()
+
+	id := insert(t, pool, `
+		INSERT INTO loans (movie_id, user_id, store_id, borrowed_at, returned_at, status)
+		VALUES ($1, $2, $3, now() - interval '3 years', now() - interval '3 years', 'returned')
+		RETURNING id`, movieId, userId, storeId)
+
+	_, err := pool.Exec(context.Background(), `
+		WITH moved AS (
+			DELETE FROM loans WHERE id = $1
+			RETURNING id, movie_id, user_id, store_id, return_store_id, borrowed_at, returned_at, status, price_cents, version, created_at, updated_at
+		)
+		INSERT INTO loans_archive (id, movie_id, user_id, store_id, return_store_id, borrowed_at, returned_at, status, price_cents, version, created_at, updated_at)
+		SELECT * FROM moved`, id)
+	if err != nil {
+		t.Fatalf("failed to archive test loan: %v", err)
+	}
+
+	return id
+}
+
+func insert(t testing.TB, pool *pgxpool.Pool, query string, args ...any) uuid.UUID {
+	t.Helper()
+
+	var id uuid.UUID
+	if err := pool.QueryRow(context.Background(), query, args...).Scan(&id); err != nil {
+		t.Fatalf("failed to insert test row: %v", err)
+	}
+	return id
+}
+
+func uniqueSuffix() string {
+	return uuid.NewString()[:8]
+}
+
+// Int runs a query returning a single number, such as a count or a
+// quantity, and fails the test if it cannot.
+func Int(t testing.TB, pool *pgxpool.Pool, query string, args ...any) int64 {
+	t.Helper()
+
+	var value int64
+	if err := pool.QueryRow(context.Background(), query, args...).Scan(&value); err != nil {
+		t.Fatalf("failed to query the test database: %v", err)
+	}
+	return value
+}
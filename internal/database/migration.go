@@ -3,7 +3,7 @@ package database
 import (
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 
 	"github.com/golang-migrate/migrate/v4"
 	_ "github.com/golang-migrate/migrate/v4/database/postgres"
@@ -21,7 +21,7 @@ func RunMigrations(connectionString string) error {
 		return fmt.Errorf("failed to run migrations: %w", err)
 	}
 
-	log.Println("Migrations completed successfully")
+	slog.Info("Migrations completed")
 	return nil
 }
 
@@ -36,6 +36,6 @@ func RollbackMigrations(connectionString string) error {
 		return fmt.Errorf("failed to rollback migrations: %w", err)
 	}
 
-	log.Println("Migrations rolled back successfully")
+	slog.Info("Migrations rolled back")
 	return nil
 }
@@ -0,0 +1,22 @@
+-- Write your migrate up statements here
+CREATE TABLE IF NOT EXISTS copies (
+  id UUID PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+
+  movie_id UUID NOT NULL,
+  barcode VARCHAR(64) UNIQUE NOT NULL,
+
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+  CONSTRAINT fk_copies_movie_id FOREIGN KEY (movie_id) REFERENCES movies(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_loans_movie_id_status ON loans (movie_id, status);
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_loans_movie_id_status;
+DROP TABLE IF EXISTS copies;
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
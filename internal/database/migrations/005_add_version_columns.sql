@@ -0,0 +1,13 @@
+-- Write your migrate up statements here
+ALTER TABLE movies ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE loans ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+
+---- create above / drop below ----
+
+ALTER TABLE loans DROP COLUMN IF EXISTS version;
+ALTER TABLE users DROP COLUMN IF EXISTS version;
+ALTER TABLE movies DROP COLUMN IF EXISTS version;
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
@@ -0,0 +1,35 @@
+-- Write your migrate up statements here
+CREATE TABLE IF NOT EXISTS inventory_movements (
+  id UUID PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+
+  movie_id UUID NOT NULL,
+  reason VARCHAR(30) NOT NULL,
+  quantity_delta INTEGER NOT NULL,
+  quantity_after INTEGER NOT NULL,
+  loan_id UUID,
+  note TEXT NOT NULL DEFAULT '',
+
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+  CONSTRAINT fk_inventory_movements_movie_id FOREIGN KEY (movie_id) REFERENCES movies(id) ON DELETE CASCADE,
+  CONSTRAINT chk_inventory_movements_reason CHECK (
+    reason IN ('purchase', 'loan', 'return', 'loss', 'damage', 'manual_correction')
+  ),
+  CONSTRAINT chk_inventory_movements_quantity_delta CHECK (quantity_delta <> 0),
+  CONSTRAINT chk_inventory_movements_quantity_after CHECK (quantity_after >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_inventory_movements_movie_id_created_at ON inventory_movements (movie_id, created_at);
+
+-- Opening balance so the ledger reconciles with the stock recorded so far.
+INSERT INTO inventory_movements (movie_id, reason, quantity_delta, quantity_after, note)
+SELECT id, 'manual_correction', quantity, quantity, 'Opening balance'
+FROM movies
+WHERE quantity <> 0;
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS inventory_movements;
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
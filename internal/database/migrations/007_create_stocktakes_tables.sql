@@ -0,0 +1,46 @@
+-- Write your migrate up statements here
+CREATE TABLE IF NOT EXISTS stocktakes (
+  id UUID PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+
+  status VARCHAR(20) NOT NULL DEFAULT 'open',
+  note TEXT NOT NULL DEFAULT '',
+  opened_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  closed_at TIMESTAMPTZ,
+
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+  CONSTRAINT chk_stocktakes_status CHECK (status IN ('open', 'approved', 'cancelled'))
+);
+
+-- Only one count may be running at a time.
+CREATE UNIQUE INDEX IF NOT EXISTS uq_stocktakes_single_open ON stocktakes (status) WHERE status = 'open';
+
+CREATE TABLE IF NOT EXISTS stocktake_counts (
+  id UUID PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+
+  stocktake_id UUID NOT NULL,
+  movie_id UUID NOT NULL,
+  counted_quantity INTEGER NOT NULL,
+  expected_quantity INTEGER NOT NULL,
+
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+  CONSTRAINT fk_stocktake_counts_stocktake_id FOREIGN KEY (stocktake_id) REFERENCES stocktakes(id) ON DELETE CASCADE,
+  CONSTRAINT fk_stocktake_counts_movie_id FOREIGN KEY (movie_id) REFERENCES movies(id) ON DELETE CASCADE,
+  CONSTRAINT uq_stocktake_counts_movie UNIQUE (stocktake_id, movie_id),
+  CONSTRAINT chk_stocktake_counts_counted_quantity CHECK (counted_quantity >= 0)
+);
+
+ALTER TABLE inventory_movements ADD COLUMN IF NOT EXISTS stocktake_id UUID
+  REFERENCES stocktakes(id) ON DELETE SET NULL;
+
+---- create above / drop below ----
+
+ALTER TABLE inventory_movements DROP COLUMN IF EXISTS stocktake_id;
+DROP TABLE IF EXISTS stocktake_counts;
+DROP TABLE IF EXISTS stocktakes;
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
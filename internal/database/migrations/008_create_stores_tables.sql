@@ -0,0 +1,75 @@
+-- Write your migrate up statements here
+CREATE TABLE IF NOT EXISTS stores (
+  id UUID PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+
+  code VARCHAR(20) NOT NULL,
+  name VARCHAR(100) NOT NULL,
+  address TEXT NOT NULL DEFAULT '',
+  is_default BOOLEAN NOT NULL DEFAULT false,
+
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+  CONSTRAINT uq_stores_code UNIQUE (code)
+);
+
+-- Requests that do not pick a store are served by the default one.
+CREATE UNIQUE INDEX IF NOT EXISTS uq_stores_single_default ON stores (is_default) WHERE is_default;
+
+INSERT INTO stores (code, name, is_default) VALUES ('MAIN', 'Main Store', true);
+
+CREATE TABLE IF NOT EXISTS store_stock (
+  store_id UUID NOT NULL,
+  movie_id UUID NOT NULL,
+  quantity INTEGER NOT NULL DEFAULT 0,
+
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+  PRIMARY KEY (store_id, movie_id),
+  CONSTRAINT fk_store_stock_store_id FOREIGN KEY (store_id) REFERENCES stores(id) ON DELETE CASCADE,
+  CONSTRAINT fk_store_stock_movie_id FOREIGN KEY (movie_id) REFERENCES movies(id) ON DELETE CASCADE,
+  CONSTRAINT chk_store_stock_quantity CHECK (quantity >= 0)
+);
+
+-- Everything on hand so far belongs to the default store; movies.quantity stays the chain-wide total.
+INSERT INTO store_stock (store_id, movie_id, quantity)
+SELECT s.id, m.id, m.quantity
+FROM movies m
+CROSS JOIN stores s
+WHERE s.is_default AND m.quantity > 0;
+
+ALTER TABLE loans ADD COLUMN IF NOT EXISTS store_id UUID REFERENCES stores(id);
+ALTER TABLE loans ADD COLUMN IF NOT EXISTS return_store_id UUID REFERENCES stores(id);
+UPDATE loans SET store_id = (SELECT id FROM stores WHERE is_default) WHERE store_id IS NULL;
+UPDATE loans SET return_store_id = store_id WHERE status = 'returned' AND return_store_id IS NULL;
+ALTER TABLE loans ALTER COLUMN store_id SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_loans_store_id ON loans (store_id);
+
+ALTER TABLE inventory_movements ADD COLUMN IF NOT EXISTS store_id UUID REFERENCES stores(id);
+UPDATE inventory_movements SET store_id = (SELECT id FROM stores WHERE is_default) WHERE store_id IS NULL;
+ALTER TABLE inventory_movements ALTER COLUMN store_id SET NOT NULL;
+
+ALTER TABLE stocktakes ADD COLUMN IF NOT EXISTS store_id UUID REFERENCES stores(id);
+UPDATE stocktakes SET store_id = (SELECT id FROM stores WHERE is_default) WHERE store_id IS NULL;
+ALTER TABLE stocktakes ALTER COLUMN store_id SET NOT NULL;
+
+-- Each store runs its own count.
+DROP INDEX IF EXISTS uq_stocktakes_single_open;
+CREATE UNIQUE INDEX IF NOT EXISTS uq_stocktakes_single_open ON stocktakes (store_id) WHERE status = 'open';
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS uq_stocktakes_single_open;
+ALTER TABLE stocktakes DROP COLUMN IF EXISTS store_id;
+CREATE UNIQUE INDEX IF NOT EXISTS uq_stocktakes_single_open ON stocktakes (status) WHERE status = 'open';
+
+ALTER TABLE inventory_movements DROP COLUMN IF EXISTS store_id;
+DROP INDEX IF EXISTS idx_loans_store_id;
+ALTER TABLE loans DROP COLUMN IF EXISTS return_store_id;
+ALTER TABLE loans DROP COLUMN IF EXISTS store_id;
+DROP TABLE IF EXISTS store_stock;
+DROP TABLE IF EXISTS stores;
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
@@ -0,0 +1,50 @@
+-- Write your migrate up statements here
+CREATE TABLE IF NOT EXISTS stock_transfers (
+  id UUID PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+
+  movie_id UUID NOT NULL,
+  from_store_id UUID NOT NULL,
+  to_store_id UUID NOT NULL,
+  quantity INTEGER NOT NULL,
+  status VARCHAR(20) NOT NULL DEFAULT 'requested',
+  note TEXT NOT NULL DEFAULT '',
+
+  requested_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  shipped_at TIMESTAMPTZ,
+  received_at TIMESTAMPTZ,
+
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+  CONSTRAINT fk_stock_transfers_movie_id FOREIGN KEY (movie_id) REFERENCES movies(id) ON DELETE CASCADE,
+  CONSTRAINT fk_stock_transfers_from_store_id FOREIGN KEY (from_store_id) REFERENCES stores(id),
+  CONSTRAINT fk_stock_transfers_to_store_id FOREIGN KEY (to_store_id) REFERENCES stores(id),
+  CONSTRAINT chk_stock_transfers_stores CHECK (from_store_id <> to_store_id),
+  CONSTRAINT chk_stock_transfers_quantity CHECK (quantity > 0),
+  CONSTRAINT chk_stock_transfers_status CHECK (status IN ('requested', 'in_transit', 'received', 'cancelled'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_stock_transfers_from_store_id_status ON stock_transfers (from_store_id, status);
+CREATE INDEX IF NOT EXISTS idx_stock_transfers_to_store_id_status ON stock_transfers (to_store_id, status);
+
+ALTER TABLE inventory_movements ADD COLUMN IF NOT EXISTS transfer_id UUID
+  REFERENCES stock_transfers(id) ON DELETE SET NULL;
+
+ALTER TABLE inventory_movements DROP CONSTRAINT IF EXISTS chk_inventory_movements_reason;
+ALTER TABLE inventory_movements ADD CONSTRAINT chk_inventory_movements_reason CHECK (
+  reason IN ('purchase', 'loan', 'return', 'loss', 'damage', 'manual_correction', 'transfer_out', 'transfer_in')
+);
+
+---- create above / drop below ----
+
+DELETE FROM inventory_movements WHERE reason IN ('transfer_out', 'transfer_in');
+ALTER TABLE inventory_movements DROP CONSTRAINT IF EXISTS chk_inventory_movements_reason;
+ALTER TABLE inventory_movements ADD CONSTRAINT chk_inventory_movements_reason CHECK (
+  reason IN ('purchase', 'loan', 'return', 'loss', 'damage', 'manual_correction')
+);
+
+ALTER TABLE inventory_movements DROP COLUMN IF EXISTS transfer_id;
+DROP TABLE IF EXISTS stock_transfers;
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
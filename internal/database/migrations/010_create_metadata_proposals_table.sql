@@ -0,0 +1,33 @@
+-- Write your migrate up statements here
+ALTER TABLE movies ADD COLUMN IF NOT EXISTS synopsis TEXT NOT NULL DEFAULT '';
+ALTER TABLE movies ADD COLUMN IF NOT EXISTS cover_url TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS metadata_proposals (
+  id UUID PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+
+  movie_id UUID NOT NULL,
+  source VARCHAR(50) NOT NULL,
+  external_id VARCHAR(50) NOT NULL DEFAULT '',
+  changes JSONB NOT NULL,
+  status VARCHAR(20) NOT NULL DEFAULT 'pending',
+  decided_at TIMESTAMPTZ,
+
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+  CONSTRAINT fk_metadata_proposals_movie_id FOREIGN KEY (movie_id) REFERENCES movies(id) ON DELETE CASCADE,
+  CONSTRAINT chk_metadata_proposals_status CHECK (status IN ('pending', 'approved', 'rejected'))
+);
+
+-- A movie has at most one proposal waiting for review.
+CREATE UNIQUE INDEX IF NOT EXISTS uq_metadata_proposals_pending ON metadata_proposals (movie_id) WHERE status = 'pending';
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS uq_metadata_proposals_pending;
+DROP TABLE IF EXISTS metadata_proposals;
+ALTER TABLE movies DROP COLUMN IF EXISTS cover_url;
+ALTER TABLE movies DROP COLUMN IF EXISTS synopsis;
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
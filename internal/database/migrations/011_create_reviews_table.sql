@@ -0,0 +1,37 @@
+-- Write your migrate up statements here
+CREATE TABLE IF NOT EXISTS reviews (
+  id UUID PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+
+  movie_id UUID NOT NULL,
+  user_id UUID NOT NULL,
+  rating SMALLINT NOT NULL,
+  body TEXT NOT NULL DEFAULT '',
+  status VARCHAR(20) NOT NULL DEFAULT 'pending',
+  moderation_note TEXT NOT NULL DEFAULT '',
+  moderated_at TIMESTAMPTZ,
+
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+  CONSTRAINT fk_reviews_movie_id FOREIGN KEY (movie_id) REFERENCES movies(id) ON DELETE CASCADE,
+  CONSTRAINT fk_reviews_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+  CONSTRAINT uq_reviews_movie_id_user_id UNIQUE (movie_id, user_id),
+  CONSTRAINT chk_reviews_rating CHECK (rating BETWEEN 1 AND 5),
+  CONSTRAINT chk_reviews_status CHECK (status IN ('pending', 'approved', 'rejected'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_reviews_status_created_at ON reviews (status, created_at);
+
+-- Aggregate of the approved reviews, kept in step by the reviews module.
+ALTER TABLE movies ADD COLUMN IF NOT EXISTS rating_average NUMERIC(3, 2) NOT NULL DEFAULT 0;
+ALTER TABLE movies ADD COLUMN IF NOT EXISTS rating_count INTEGER NOT NULL DEFAULT 0;
+
+---- create above / drop below ----
+
+ALTER TABLE movies DROP COLUMN IF EXISTS rating_count;
+ALTER TABLE movies DROP COLUMN IF EXISTS rating_average;
+DROP INDEX IF EXISTS idx_reviews_status_created_at;
+DROP TABLE IF EXISTS reviews;
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
@@ -0,0 +1,24 @@
+-- Write your migrate up statements here
+CREATE TABLE IF NOT EXISTS movie_similarities (
+  movie_id UUID NOT NULL,
+  similar_movie_id UUID NOT NULL,
+  shared_renters INTEGER NOT NULL,
+  score NUMERIC(6,5) NOT NULL,
+  computed_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+  PRIMARY KEY (movie_id, similar_movie_id),
+  CONSTRAINT fk_movie_similarities_movie_id FOREIGN KEY (movie_id) REFERENCES movies(id) ON DELETE CASCADE,
+  CONSTRAINT fk_movie_similarities_similar_movie_id FOREIGN KEY (similar_movie_id) REFERENCES movies(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_movie_similarities_movie_id_score ON movie_similarities (movie_id, score DESC);
+CREATE INDEX IF NOT EXISTS idx_loans_user_id_movie_id ON loans (user_id, movie_id);
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_loans_user_id_movie_id;
+DROP INDEX IF EXISTS idx_movie_similarities_movie_id_score;
+DROP TABLE IF EXISTS movie_similarities;
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
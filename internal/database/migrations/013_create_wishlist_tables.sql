@@ -0,0 +1,47 @@
+-- Write your migrate up statements here
+CREATE TABLE IF NOT EXISTS wishlist_items (
+  id UUID PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+
+  user_id UUID NOT NULL,
+  movie_id UUID NOT NULL,
+  notified_at TIMESTAMPTZ,
+
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+  CONSTRAINT fk_wishlist_items_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+  CONSTRAINT fk_wishlist_items_movie_id FOREIGN KEY (movie_id) REFERENCES movies(id) ON DELETE CASCADE,
+  CONSTRAINT uq_wishlist_items_user_movie UNIQUE (user_id, movie_id)
+);
+
+-- Restocks look up who is still waiting for a movie.
+CREATE INDEX IF NOT EXISTS idx_wishlist_items_waiting ON wishlist_items (movie_id) WHERE notified_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS notifications (
+  id UUID PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+
+  user_id UUID NOT NULL,
+  kind VARCHAR(50) NOT NULL,
+  movie_id UUID,
+  store_id UUID,
+  status VARCHAR(20) NOT NULL DEFAULT 'queued',
+
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+  CONSTRAINT fk_notifications_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+  CONSTRAINT fk_notifications_movie_id FOREIGN KEY (movie_id) REFERENCES movies(id) ON DELETE CASCADE,
+  CONSTRAINT fk_notifications_store_id FOREIGN KEY (store_id) REFERENCES stores(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_status_created_at ON notifications (status, created_at);
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_notifications_status_created_at;
+DROP TABLE IF EXISTS notifications;
+DROP INDEX IF EXISTS idx_wishlist_items_waiting;
+DROP TABLE IF EXISTS wishlist_items;
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
@@ -0,0 +1,65 @@
+-- Write your migrate up statements here
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS loan_id UUID REFERENCES loans(id) ON DELETE CASCADE;
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS note TEXT NOT NULL DEFAULT '';
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS attempts INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS last_error TEXT NOT NULL DEFAULT '';
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now();
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS sent_at TIMESTAMPTZ;
+ALTER TABLE notifications ADD CONSTRAINT chk_notifications_status CHECK (status IN ('queued', 'sent', 'failed', 'skipped'));
+
+DROP INDEX IF EXISTS idx_notifications_status_created_at;
+CREATE INDEX IF NOT EXISTS idx_notifications_due ON notifications (next_attempt_at) WHERE status = 'queued';
+CREATE INDEX IF NOT EXISTS idx_notifications_user_id_created_at ON notifications (user_id, created_at DESC);
+
+-- A loan gets each kind of reminder at most once.
+CREATE UNIQUE INDEX IF NOT EXISTS uq_notifications_loan_reminder ON notifications (loan_id, kind)
+  WHERE kind IN ('due_soon', 'overdue');
+
+-- Absent rows mean the kind is enabled.
+CREATE TABLE IF NOT EXISTS notification_preferences (
+  user_id UUID NOT NULL,
+  kind VARCHAR(50) NOT NULL,
+  enabled BOOLEAN NOT NULL,
+
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+  PRIMARY KEY (user_id, kind),
+  CONSTRAINT fk_notification_preferences_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS notification_deliveries (
+  id UUID PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+
+  notification_id UUID NOT NULL,
+  attempt INTEGER NOT NULL,
+  channel VARCHAR(20) NOT NULL,
+  recipient VARCHAR(255) NOT NULL,
+  status VARCHAR(20) NOT NULL,
+  error TEXT NOT NULL DEFAULT '',
+
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+  CONSTRAINT fk_notification_deliveries_notification_id FOREIGN KEY (notification_id) REFERENCES notifications(id) ON DELETE CASCADE,
+  CONSTRAINT chk_notification_deliveries_status CHECK (status IN ('sent', 'failed'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_notification_deliveries_notification_id ON notification_deliveries (notification_id, attempt);
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS notification_deliveries;
+DROP TABLE IF EXISTS notification_preferences;
+DROP INDEX IF EXISTS uq_notifications_loan_reminder;
+DROP INDEX IF EXISTS idx_notifications_user_id_created_at;
+DROP INDEX IF EXISTS idx_notifications_due;
+CREATE INDEX IF NOT EXISTS idx_notifications_status_created_at ON notifications (status, created_at);
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS chk_notifications_status;
+ALTER TABLE notifications DROP COLUMN IF EXISTS sent_at;
+ALTER TABLE notifications DROP COLUMN IF EXISTS next_attempt_at;
+ALTER TABLE notifications DROP COLUMN IF EXISTS last_error;
+ALTER TABLE notifications DROP COLUMN IF EXISTS attempts;
+ALTER TABLE notifications DROP COLUMN IF EXISTS note;
+ALTER TABLE notifications DROP COLUMN IF EXISTS loan_id;
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
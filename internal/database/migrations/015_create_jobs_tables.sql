@@ -0,0 +1,52 @@
+-- Write your migrate up statements here
+CREATE TABLE IF NOT EXISTS jobs (
+  id UUID PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+
+  kind VARCHAR(100) NOT NULL,
+  payload JSONB NOT NULL DEFAULT '{}',
+  status VARCHAR(20) NOT NULL DEFAULT 'queued',
+  attempts INTEGER NOT NULL DEFAULT 0,
+  max_attempts INTEGER NOT NULL DEFAULT 5,
+  run_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  locked_at TIMESTAMPTZ,
+  locked_by VARCHAR(255),
+  last_error TEXT NOT NULL DEFAULT '',
+  schedule_name VARCHAR(100),
+  finished_at TIMESTAMPTZ,
+
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+  CONSTRAINT chk_jobs_status CHECK (status IN ('queued', 'running', 'succeeded', 'dead')),
+  CONSTRAINT chk_jobs_max_attempts CHECK (max_attempts > 0)
+);
+
+-- Workers claim the job that has been due the longest.
+CREATE INDEX IF NOT EXISTS idx_jobs_due ON jobs (run_at) WHERE status = 'queued';
+-- Jobs of a crashed worker are claimed again once their lock is stale.
+CREATE INDEX IF NOT EXISTS idx_jobs_running ON jobs (locked_at) WHERE status = 'running';
+CREATE INDEX IF NOT EXISTS idx_jobs_status_created_at ON jobs (status, created_at);
+
+CREATE TABLE IF NOT EXISTS job_schedules (
+  name VARCHAR(100) PRIMARY KEY NOT NULL,
+
+  kind VARCHAR(100) NOT NULL,
+  payload JSONB NOT NULL DEFAULT '{}',
+  spec VARCHAR(100) NOT NULL,
+  next_run_at TIMESTAMPTZ NOT NULL,
+  last_run_at TIMESTAMPTZ,
+
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS job_schedules;
+DROP INDEX IF EXISTS idx_jobs_status_created_at;
+DROP INDEX IF EXISTS idx_jobs_running;
+DROP INDEX IF EXISTS idx_jobs_due;
+DROP TABLE IF EXISTS jobs;
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
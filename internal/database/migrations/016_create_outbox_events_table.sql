@@ -0,0 +1,39 @@
+-- Write your migrate up statements here
+CREATE TABLE IF NOT EXISTS outbox_events (
+  id UUID PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+
+  type VARCHAR(100) NOT NULL,
+  aggregate_id UUID NOT NULL,
+  payload JSONB NOT NULL DEFAULT '{}',
+  status VARCHAR(20) NOT NULL DEFAULT 'pending',
+  attempts INTEGER NOT NULL DEFAULT 0,
+  last_error TEXT NOT NULL DEFAULT '',
+  delivered_to TEXT[] NOT NULL DEFAULT '{}',
+  next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  dispatched_at TIMESTAMPTZ,
+
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+  CONSTRAINT chk_outbox_events_status CHECK (status IN ('pending', 'dispatched', 'failed'))
+);
+
+-- The dispatcher looks for pending events whose next attempt is due.
+CREATE INDEX IF NOT EXISTS idx_outbox_events_due ON outbox_events (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_outbox_events_type_created_at ON outbox_events (type, created_at);
+CREATE INDEX IF NOT EXISTS idx_outbox_events_created_at ON outbox_events (created_at);
+
+-- Subscribers use the event ID to skip events they already handled.
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS event_id UUID;
+CREATE UNIQUE INDEX IF NOT EXISTS uq_notifications_event_id ON notifications (event_id) WHERE event_id IS NOT NULL;
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS uq_notifications_event_id;
+ALTER TABLE notifications DROP COLUMN IF EXISTS event_id;
+DROP INDEX IF EXISTS idx_outbox_events_created_at;
+DROP INDEX IF EXISTS idx_outbox_events_type_created_at;
+DROP INDEX IF EXISTS idx_outbox_events_due;
+DROP TABLE IF EXISTS outbox_events;
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
@@ -0,0 +1,67 @@
+-- Write your migrate up statements here
+CREATE TABLE IF NOT EXISTS webhooks (
+  id UUID PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+
+  url TEXT NOT NULL,
+  event_types TEXT[] NOT NULL,
+  secret TEXT NOT NULL,
+  active BOOLEAN NOT NULL DEFAULT true,
+
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Events are pruned from the outbox, so event_id is kept without a foreign key.
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+  id UUID PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+
+  webhook_id UUID NOT NULL,
+  event_id UUID,
+  event_type VARCHAR(100) NOT NULL,
+  payload JSONB NOT NULL,
+  status VARCHAR(20) NOT NULL,
+  attempts INTEGER NOT NULL DEFAULT 0,
+  response_code INTEGER,
+  last_error TEXT NOT NULL DEFAULT '',
+  next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  delivered_at TIMESTAMPTZ,
+
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+  CONSTRAINT fk_webhook_deliveries_webhook_id FOREIGN KEY (webhook_id) REFERENCES webhooks(id) ON DELETE CASCADE,
+  CONSTRAINT chk_webhook_deliveries_status CHECK (status IN ('queued', 'delivered', 'failed'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries (next_attempt_at) WHERE status = 'queued';
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook_id_created_at ON webhook_deliveries (webhook_id, created_at DESC);
+
+-- A webhook gets each event at most once.
+CREATE UNIQUE INDEX IF NOT EXISTS uq_webhook_deliveries_webhook_event ON webhook_deliveries (webhook_id, event_id)
+  WHERE event_id IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS webhook_attempts (
+  id UUID PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+
+  delivery_id UUID NOT NULL,
+  attempt INTEGER NOT NULL,
+  response_code INTEGER,
+  response_body TEXT NOT NULL DEFAULT '',
+  error TEXT NOT NULL DEFAULT '',
+  duration_ms INTEGER NOT NULL DEFAULT 0,
+
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+  CONSTRAINT fk_webhook_attempts_delivery_id FOREIGN KEY (delivery_id) REFERENCES webhook_deliveries(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_attempts_delivery_id ON webhook_attempts (delivery_id, attempt);
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS webhook_attempts;
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhooks;
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
@@ -0,0 +1,11 @@
+-- Write your migrate up statements here
+CREATE INDEX IF NOT EXISTS idx_loans_store_id_borrowed_at ON loans (store_id, borrowed_at);
+CREATE INDEX IF NOT EXISTS idx_loans_return_store_id ON loans (return_store_id);
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_loans_return_store_id;
+DROP INDEX IF EXISTS idx_loans_store_id_borrowed_at;
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
@@ -0,0 +1,20 @@
+-- Write your migrate up statements here
+ALTER TABLE movies ADD COLUMN IF NOT EXISTS genre TEXT NOT NULL DEFAULT '';
+ALTER TABLE movies ADD COLUMN IF NOT EXISTS rental_price_cents INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE movies ADD CONSTRAINT chk_movies_rental_price_cents CHECK (rental_price_cents >= 0);
+
+-- The price a loan was charged, copied from the movie when it is borrowed
+ALTER TABLE loans ADD COLUMN IF NOT EXISTS price_cents INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_loans_returned_at ON loans (returned_at);
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_loans_returned_at;
+ALTER TABLE loans DROP COLUMN IF EXISTS price_cents;
+ALTER TABLE movies DROP CONSTRAINT IF EXISTS chk_movies_rental_price_cents;
+ALTER TABLE movies DROP COLUMN IF EXISTS rental_price_cents;
+ALTER TABLE movies DROP COLUMN IF EXISTS genre;
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
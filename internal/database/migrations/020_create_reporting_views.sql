@@ -0,0 +1,78 @@
+-- Write your migrate up statements here
+
+-- Days follow the database TimeZone setting, which should match the application's
+
+-- Daily loan facts: rentals and revenue by the day and store a loan was borrowed,
+-- returns by the day and store it came back to, bucketed by how many whole hours
+-- it was out so late returns can be told apart for any loan period.
+-- Rental rows have loan_hours -1.
+CREATE MATERIALIZED VIEW IF NOT EXISTS report_daily_loans AS
+SELECT day, store_id, movie_id, loan_hours,
+  SUM(rentals)::BIGINT AS rentals,
+  SUM(revenue_cents)::BIGINT AS revenue_cents,
+  SUM(returns)::BIGINT AS returns
+FROM (
+  SELECT borrowed_at::DATE AS day, store_id, movie_id, -1 AS loan_hours,
+    1 AS rentals, price_cents AS revenue_cents, 0 AS returns
+  FROM loans
+  UNION ALL
+  SELECT returned_at::DATE, COALESCE(return_store_id, store_id), movie_id,
+    FLOOR(EXTRACT(EPOCH FROM returned_at - borrowed_at) / 3600)::INTEGER,
+    0, 0, 1
+  FROM loans
+  WHERE returned_at IS NOT NULL
+) facts
+GROUP BY day, store_id, movie_id, loan_hours;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_report_daily_loans ON report_daily_loans (day, store_id, movie_id, loan_hours);
+
+-- Per-movie utilization: how long the copies of each movie were out on each day,
+-- counting loans not returned yet up to the refresh.
+CREATE MATERIALIZED VIEW IF NOT EXISTS report_movie_utilization AS
+SELECT d.day::DATE AS day, l.store_id, l.movie_id,
+  SUM(EXTRACT(EPOCH FROM
+    LEAST(COALESCE(l.returned_at, now()), d.day + INTERVAL '1 day') - GREATEST(l.borrowed_at, d.day)
+  ))::FLOAT8 AS rented_seconds
+FROM loans l
+CROSS JOIN LATERAL generate_series(
+  date_trunc('day', l.borrowed_at), COALESCE(l.returned_at, now()), INTERVAL '1 day'
+) AS d(day)
+GROUP BY d.day::DATE, l.store_id, l.movie_id;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_report_movie_utilization ON report_movie_utilization (day, store_id, movie_id);
+
+-- Per-customer activity at each store
+CREATE MATERIALIZED VIEW IF NOT EXISTS report_customer_activity AS
+SELECT user_id, store_id,
+  COUNT(*) AS loans,
+  COUNT(*) FILTER (WHERE status = 'active') AS active_loans,
+  COALESCE(SUM(price_cents), 0)::BIGINT AS revenue_cents,
+  COALESCE(SUM(EXTRACT(EPOCH FROM returned_at - borrowed_at)) FILTER (WHERE returned_at IS NOT NULL), 0)::FLOAT8 AS returned_seconds,
+  COUNT(returned_at) AS returns,
+  MIN(borrowed_at) AS first_borrowed_at,
+  MAX(borrowed_at) AS last_borrowed_at
+FROM loans
+GROUP BY user_id, store_id;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_report_customer_activity ON report_customer_activity (user_id, store_id);
+
+-- Postgres does not record when a materialized view was refreshed
+CREATE TABLE IF NOT EXISTS report_refreshes (
+  view_name TEXT PRIMARY KEY,
+  refreshed_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  duration_ms INTEGER NOT NULL DEFAULT 0
+);
+
+INSERT INTO report_refreshes (view_name)
+VALUES ('report_daily_loans'), ('report_movie_utilization'), ('report_customer_activity')
+ON CONFLICT (view_name) DO NOTHING;
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS report_refreshes;
+DROP MATERIALIZED VIEW IF EXISTS report_customer_activity;
+DROP MATERIALIZED VIEW IF EXISTS report_movie_utilization;
+DROP MATERIALIZED VIEW IF EXISTS report_daily_loans;
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
@@ -0,0 +1,256 @@
+-- Write your migrate up statements here
+
+-- The reporting views and the notifications' foreign key depend on the table being replaced.
+-- A partitioned table can only have unique keys that include the partition key, so loan IDs
+-- can no longer be referenced.
+DROP MATERIALIZED VIEW IF EXISTS report_customer_activity;
+DROP MATERIALIZED VIEW IF EXISTS report_movie_utilization;
+DROP MATERIALIZED VIEW IF EXISTS report_daily_loans;
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_loan_id_fkey;
+
+ALTER TABLE loans RENAME TO loans_unpartitioned;
+ALTER INDEX loans_pkey RENAME TO loans_unpartitioned_pkey;
+
+-- Loans are split into one partition per year they were borrowed in (UTC). The loans.partition
+-- job creates the partitions ahead of time; loans_default only catches what falls outside them.
+CREATE TABLE loans (
+  id UUID NOT NULL DEFAULT gen_random_uuid(),
+
+  movie_id UUID NOT NULL,
+  user_id UUID NOT NULL,
+  store_id UUID NOT NULL,
+  return_store_id UUID,
+  borrowed_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  returned_at TIMESTAMPTZ,
+  status VARCHAR(20) NOT NULL DEFAULT 'active',
+  price_cents INTEGER NOT NULL DEFAULT 0,
+  version INTEGER NOT NULL DEFAULT 1,
+
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+  CONSTRAINT loans_pkey PRIMARY KEY (id, borrowed_at),
+  CONSTRAINT fk_loans_movie_id FOREIGN KEY (movie_id) REFERENCES movies(id) ON DELETE CASCADE,
+  CONSTRAINT fk_loans_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+  CONSTRAINT fk_loans_store_id FOREIGN KEY (store_id) REFERENCES stores(id),
+  CONSTRAINT fk_loans_return_store_id FOREIGN KEY (return_store_id) REFERENCES stores(id)
+) PARTITION BY RANGE (borrowed_at);
+
+DO $$
+DECLARE
+  first_year INTEGER := EXTRACT(YEAR FROM COALESCE((SELECT MIN(borrowed_at) FROM loans_unpartitioned), now()) AT TIME ZONE 'UTC');
+  last_year INTEGER := EXTRACT(YEAR FROM now() AT TIME ZONE 'UTC') + 1;
+BEGIN
+  FOR year IN first_year..last_year LOOP
+    EXECUTE format(
+      'CREATE TABLE IF NOT EXISTS %I PARTITION OF loans FOR VALUES FROM (%L) TO (%L)',
+      'loans_' || year,
+      make_timestamptz(year, 1, 1, 0, 0, 0, 'UTC'),
+      make_timestamptz(year + 1, 1, 1, 0, 0, 0, 'UTC')
+    );
+  END LOOP;
+END $$;
+
+CREATE TABLE IF NOT EXISTS loans_default PARTITION OF loans DEFAULT;
+
+INSERT INTO loans (id, movie_id, user_id, store_id, return_store_id, borrowed_at, returned_at, status, price_cents, version, created_at, updated_at)
+SELECT id, movie_id, user_id, store_id, return_store_id, borrowed_at, returned_at, status, price_cents, version, created_at, updated_at
+FROM loans_unpartitioned;
+
+DROP TABLE loans_unpartitioned;
+
+CREATE INDEX IF NOT EXISTS idx_loans_movie_id_status ON loans (movie_id, status);
+CREATE INDEX IF NOT EXISTS idx_loans_store_id ON loans (store_id);
+CREATE INDEX IF NOT EXISTS idx_loans_store_id_borrowed_at ON loans (store_id, borrowed_at);
+CREATE INDEX IF NOT EXISTS idx_loans_return_store_id ON loans (return_store_id);
+CREATE INDEX IF NOT EXISTS idx_loans_returned_at ON loans (returned_at);
+CREATE INDEX IF NOT EXISTS idx_loans_user_id_status ON loans (user_id, status);
+
+-- Returned loans older than the retention period are moved here by the loans.archive job
+CREATE TABLE IF NOT EXISTS loans_archive (
+  id UUID PRIMARY KEY NOT NULL,
+
+  movie_id UUID NOT NULL,
+  user_id UUID NOT NULL,
+  store_id UUID NOT NULL,
+  return_store_id UUID,
+  borrowed_at TIMESTAMPTZ NOT NULL,
+  returned_at TIMESTAMPTZ,
+  status VARCHAR(20) NOT NULL,
+  price_cents INTEGER NOT NULL DEFAULT 0,
+  version INTEGER NOT NULL DEFAULT 1,
+
+  created_at TIMESTAMPTZ NOT NULL,
+  updated_at TIMESTAMPTZ NOT NULL,
+  archived_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+  CONSTRAINT fk_loans_archive_movie_id FOREIGN KEY (movie_id) REFERENCES movies(id) ON DELETE CASCADE,
+  CONSTRAINT fk_loans_archive_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+  CONSTRAINT fk_loans_archive_store_id FOREIGN KEY (store_id) REFERENCES stores(id),
+  CONSTRAINT fk_loans_archive_return_store_id FOREIGN KEY (return_store_id) REFERENCES stores(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_loans_archive_store_id ON loans_archive (store_id);
+CREATE INDEX IF NOT EXISTS idx_loans_archive_return_store_id ON loans_archive (return_store_id);
+CREATE INDEX IF NOT EXISTS idx_loans_archive_user_id ON loans_archive (user_id);
+
+-- Every loan ever made, archived or not
+CREATE OR REPLACE VIEW loan_history AS
+SELECT id, movie_id, user_id, store_id, return_store_id, borrowed_at, returned_at, status, price_cents, version,
+  created_at, updated_at, FALSE AS archived
+FROM loans
+UNION ALL
+SELECT id, movie_id, user_id, store_id, return_store_id, borrowed_at, returned_at, status, price_cents, version,
+  created_at, updated_at, TRUE
+FROM loans_archive;
+
+-- The reporting views from 020, now reading the archived loans too so archiving leaves reports alone
+CREATE MATERIALIZED VIEW IF NOT EXISTS report_daily_loans AS
+SELECT day, store_id, movie_id, loan_hours,
+  SUM(rentals)::BIGINT AS rentals,
+  SUM(revenue_cents)::BIGINT AS revenue_cents,
+  SUM(returns)::BIGINT AS returns
+FROM (
+  SELECT borrowed_at::DATE AS day, store_id, movie_id, -1 AS loan_hours,
+    1 AS rentals, price_cents AS revenue_cents, 0 AS returns
+  FROM loan_history
+  UNION ALL
+  SELECT returned_at::DATE, COALESCE(return_store_id, store_id), movie_id,
+    FLOOR(EXTRACT(EPOCH FROM returned_at - borrowed_at) / 3600)::INTEGER,
+    0, 0, 1
+  FROM loan_history
+  WHERE returned_at IS NOT NULL
+) facts
+GROUP BY day, store_id, movie_id, loan_hours;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_report_daily_loans ON report_daily_loans (day, store_id, movie_id, loan_hours);
+
+CREATE MATERIALIZED VIEW IF NOT EXISTS report_movie_utilization AS
+SELECT d.day::DATE AS day, l.store_id, l.movie_id,
+  SUM(EXTRACT(EPOCH FROM
+    LEAST(COALESCE(l.returned_at, now()), d.day + INTERVAL '1 day') - GREATEST(l.borrowed_at, d.day)
+  ))::FLOAT8 AS rented_seconds
+FROM loan_history l
+CROSS JOIN LATERAL generate_series(
+  date_trunc('day', l.borrowed_at), COALESCE(l.returned_at, now()), INTERVAL '1 day'
+) AS d(day)
+GROUP BY d.day::DATE, l.store_id, l.movie_id;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_report_movie_utilization ON report_movie_utilization (day, store_id, movie_id);
+
+CREATE MATERIALIZED VIEW IF NOT EXISTS report_customer_activity AS
+SELECT user_id, store_id,
+  COUNT(*) AS loans,
+  COUNT(*) FILTER (WHERE status = 'active') AS active_loans,
+  COALESCE(SUM(price_cents), 0)::BIGINT AS revenue_cents,
+  COALESCE(SUM(EXTRACT(EPOCH FROM returned_at - borrowed_at)) FILTER (WHERE returned_at IS NOT NULL), 0)::FLOAT8 AS returned_seconds,
+  COUNT(returned_at) AS returns,
+  MIN(borrowed_at) AS first_borrowed_at,
+  MAX(borrowed_at) AS last_borrowed_at
+FROM loan_history
+GROUP BY user_id, store_id;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_report_customer_activity ON report_customer_activity (user_id, store_id);
+
+UPDATE report_refreshes SET refreshed_at = now();
+
+---- create above / drop below ----
+
+DROP MATERIALIZED VIEW IF EXISTS report_customer_activity;
+DROP MATERIALIZED VIEW IF EXISTS report_movie_utilization;
+DROP MATERIALIZED VIEW IF EXISTS report_daily_loans;
+DROP VIEW IF EXISTS loan_history;
+
+ALTER TABLE loans RENAME TO loans_partitioned;
+ALTER INDEX loans_pkey RENAME TO loans_partitioned_pkey;
+
+CREATE TABLE loans (
+  id UUID PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+
+  movie_id UUID NOT NULL,
+  user_id UUID NOT NULL,
+  store_id UUID NOT NULL REFERENCES stores(id),
+  return_store_id UUID REFERENCES stores(id),
+  borrowed_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  returned_at TIMESTAMPTZ,
+  status VARCHAR(20) NOT NULL DEFAULT 'active',
+  price_cents INTEGER NOT NULL DEFAULT 0,
+  version INTEGER NOT NULL DEFAULT 1,
+
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+  CONSTRAINT fk_loans_movie_id FOREIGN KEY (movie_id) REFERENCES movies(id) ON DELETE CASCADE,
+  CONSTRAINT fk_loans_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+INSERT INTO loans (id, movie_id, user_id, store_id, return_store_id, borrowed_at, returned_at, status, price_cents, version, created_at, updated_at)
+SELECT id, movie_id, user_id, store_id, return_store_id, borrowed_at, returned_at, status, price_cents, version, created_at, updated_at
+FROM loans_partitioned
+UNION ALL
+SELECT id, movie_id, user_id, store_id, return_store_id, borrowed_at, returned_at, status, price_cents, version, created_at, updated_at
+FROM loans_archive;
+
+DROP TABLE loans_partitioned;
+DROP TABLE IF EXISTS loans_archive;
+
+CREATE INDEX IF NOT EXISTS idx_loans_movie_id_status ON loans (movie_id, status);
+CREATE INDEX IF NOT EXISTS idx_loans_store_id ON loans (store_id);
+CREATE INDEX IF NOT EXISTS idx_loans_store_id_borrowed_at ON loans (store_id, borrowed_at);
+CREATE INDEX IF NOT EXISTS idx_loans_return_store_id ON loans (return_store_id);
+CREATE INDEX IF NOT EXISTS idx_loans_returned_at ON loans (returned_at);
+
+UPDATE notifications SET loan_id = NULL WHERE loan_id IS NOT NULL AND loan_id NOT IN (SELECT id FROM loans);
+ALTER TABLE notifications ADD CONSTRAINT notifications_loan_id_fkey FOREIGN KEY (loan_id) REFERENCES loans(id) ON DELETE CASCADE;
+
+CREATE MATERIALIZED VIEW IF NOT EXISTS report_daily_loans AS
+SELECT day, store_id, movie_id, loan_hours,
+  SUM(rentals)::BIGINT AS rentals,
+  SUM(revenue_cents)::BIGINT AS revenue_cents,
+  SUM(returns)::BIGINT AS returns
+FROM (
+  SELECT borrowed_at::DATE AS day, store_id, movie_id, -1 AS loan_hours,
+    1 AS rentals, price_cents AS revenue_cents, 0 AS returns
+  FROM loans
+  UNION ALL
+  SELECT returned_at::DATE, COALESCE(return_store_id, store_id), movie_id,
+    FLOOR(EXTRACT(EPOCH FROM returned_at - borrowed_at) / 3600)::INTEGER,
+    0, 0, 1
+  FROM loans
+  WHERE returned_at IS NOT NULL
+) facts
+GROUP BY day, store_id, movie_id, loan_hours;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_report_daily_loans ON report_daily_loans (day, store_id, movie_id, loan_hours);
+
+CREATE MATERIALIZED VIEW IF NOT EXISTS report_movie_utilization AS
+SELECT d.day::DATE AS day, l.store_id, l.movie_id,
+  SUM(EXTRACT(EPOCH FROM
+    LEAST(COALESCE(l.returned_at, now()), d.day + INTERVAL '1 day') - GREATEST(l.borrowed_at, d.day)
+  ))::FLOAT8 AS rented_seconds
+FROM loans l
+CROSS JOIN LATERAL generate_series(
+  date_trunc('day', l.borrowed_at), COALESCE(l.returned_at, now()), INTERVAL '1 day'
+) AS d(day)
+GROUP BY d.day::DATE, l.store_id, l.movie_id;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_report_movie_utilization ON report_movie_utilization (day, store_id, movie_id);
+
+CREATE MATERIALIZED VIEW IF NOT EXISTS report_customer_activity AS
+SELECT user_id, store_id,
+  COUNT(*) AS loans,
+  COUNT(*) FILTER (WHERE status = 'active') AS active_loans,
+  COALESCE(SUM(price_cents), 0)::BIGINT AS revenue_cents,
+  COALESCE(SUM(EXTRACT(EPOCH FROM returned_at - borrowed_at)) FILTER (WHERE returned_at IS NOT NULL), 0)::FLOAT8 AS returned_seconds,
+  COUNT(returned_at) AS returns,
+  MIN(borrowed_at) AS first_borrowed_at,
+  MAX(borrowed_at) AS last_borrowed_at
+FROM loans
+GROUP BY user_id, store_id;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_report_customer_activity ON report_customer_activity (user_id, store_id);
+
+UPDATE report_refreshes SET refreshed_at = now();
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
@@ -0,0 +1,46 @@
+-- Write your migrate up statements here
+
+-- Since 021 the loans primary key is (id, borrowed_at), because a partitioned table can only have
+-- unique keys that include the partition key, and loans_archive is a separate table. Neither
+-- keeps a loan ID unique on its own, and notifications.loan_id lost its foreign key.
+-- loan_ids registers every loan ID once: inserting a loan registers its ID, so a duplicate ID
+-- fails the insert, and archiving leaves the registration alone since the loan still exists.
+-- Registrations go away with the user or movie, as the loans and the archived loans do.
+CREATE TABLE IF NOT EXISTS loan_ids (
+  id UUID PRIMARY KEY NOT NULL,
+
+  movie_id UUID NOT NULL,
+  user_id UUID NOT NULL,
+
+  CONSTRAINT fk_loan_ids_movie_id FOREIGN KEY (movie_id) REFERENCES movies(id) ON DELETE CASCADE,
+  CONSTRAINT fk_loan_ids_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+INSERT INTO loan_ids (id, movie_id, user_id)
+SELECT id, movie_id, user_id FROM loan_history;
+
+-- Loans never change their ID or borrowed_at, so rows never move between partitions and this
+-- only fires for new loans
+CREATE OR REPLACE FUNCTION register_loan_id() RETURNS TRIGGER AS $$
+BEGIN
+  INSERT INTO loan_ids (id, movie_id, user_id) VALUES (NEW.id, NEW.movie_id, NEW.user_id);
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_loans_register_id
+  BEFORE INSERT ON loans
+  FOR EACH ROW EXECUTE FUNCTION register_loan_id();
+
+UPDATE notifications SET loan_id = NULL WHERE loan_id IS NOT NULL AND loan_id NOT IN (SELECT id FROM loan_ids);
+ALTER TABLE notifications ADD CONSTRAINT notifications_loan_id_fkey FOREIGN KEY (loan_id) REFERENCES loan_ids(id) ON DELETE CASCADE;
+
+---- create above / drop below ----
+
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_loan_id_fkey;
+DROP TRIGGER IF EXISTS trg_loans_register_id ON loans;
+DROP FUNCTION IF EXISTS register_loan_id();
+DROP TABLE IF EXISTS loan_ids;
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
@@ -0,0 +1,19 @@
+-- Write your migrate up statements here
+
+-- The copy lent out, so a scanned copy barcode finds its own loan even when several customers
+-- have the same movie. Loans made before this, or checked out by movie alone, have none.
+ALTER TABLE loans ADD COLUMN IF NOT EXISTS copy_id UUID;
+ALTER TABLE loans ADD CONSTRAINT fk_loans_copy_id FOREIGN KEY (copy_id) REFERENCES copies(id) ON DELETE SET NULL;
+ALTER TABLE loans_archive ADD COLUMN IF NOT EXISTS copy_id UUID;
+ALTER TABLE loans_archive ADD CONSTRAINT fk_loans_archive_copy_id FOREIGN KEY (copy_id) REFERENCES copies(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_loans_copy_id_status ON loans (copy_id, status);
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS idx_loans_copy_id_status;
+ALTER TABLE loans_archive DROP COLUMN IF EXISTS copy_id;
+ALTER TABLE loans DROP COLUMN IF EXISTS copy_id;
+
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
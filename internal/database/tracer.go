@@ -0,0 +1,47 @@
+package database
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+
+	"github.com/jackc/pgx/v5/tracelog"
+)
+
+// newTracer logs the queries the repositories run: failed ones at warn, as
+// the callers decide how bad a failure is, and every one of them at debug.
+// Query arguments are never logged, they hold emails and other user data.
+func newTracer() *tracelog.TraceLog {
+	level := tracelog.LogLevelError
+	if slog.Default().Enabled(context.Background(), slog.LevelDebug) {
+		level = tracelog.LogLevelInfo
+	}
+
+	return &tracelog.TraceLog{
+		Logger:   tracelog.LoggerFunc(logQuery),
+		LogLevel: level,
+		Config:   &tracelog.TraceLogConfig{TimeKey: "duration"},
+	}
+}
+
+func logQuery(ctx context.Context, level tracelog.LogLevel, msg string, data map[string]any) {
+	slogLevel := slog.LevelDebug
+	if level <= tracelog.LogLevelWarn {
+		slogLevel = slog.LevelWarn
+	}
+
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		if key != "args" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	attrs := make([]slog.Attr, 0, len(keys))
+	for _, key := range keys {
+		attrs = append(attrs, slog.Any(key, data[key]))
+	}
+
+	slog.LogAttrs(ctx, slogLevel, "Database "+msg, attrs...)
+}
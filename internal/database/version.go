@@ -0,0 +1,33 @@
+package database
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// LatestMigration returns the number of the newest migration, which is the
+// version tern records in schema_version once every migration has run.
+func LatestMigration() (int, error) {
+	names, err := fs.Glob(migrations, "migrations/*.sql")
+	if err != nil {
+		return 0, fmt.Errorf("failed to list migrations: %w", err)
+	}
+
+	latest := 0
+	for _, name := range names {
+		prefix, _, _ := strings.Cut(strings.TrimPrefix(name, "migrations/"), "_")
+		version, err := strconv.Atoi(prefix)
+		if err != nil {
+			return 0, fmt.Errorf("migration %s is not numbered", name)
+		}
+		latest = max(latest, version)
+	}
+
+	return latest, nil
+}
@@ -0,0 +1,139 @@
+package exports
+
+import (
+	"blockbustermvc/internal/httputil"
+	models "blockbustermvc/internal/models/export"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ExportsController struct {
+	exportService models.IExportService
+}
+
+func NewExportsController(exportService models.IExportService) *ExportsController {
+	return &ExportsController{
+		exportService: exportService,
+	}
+}
+
+func (ec *ExportsController) RegisterRoutes(r *gin.RouterGroup) {
+	exports := r.Group("/exports")
+
+	{
+		exports.GET("/movies", ec.ExportMovies)
+		exports.GET("/users", ec.ExportUsers)
+		exports.GET("/loans", ec.ExportLoans)
+	}
+}
+
+func (ec *ExportsController) ExportMovies(ctx *gin.Context) {
+	writer, ok := ec.newWriter(ctx, "movies", models.MovieColumns)
+	if !ok {
+		return
+	}
+
+	err := ec.exportService.ExportMovies(ctx.Request.Context(), exportFilter(ctx), func(movie *models.MovieRowDTO) error {
+		return writer.write(movie)
+	})
+	finishExport(ctx, writer, err)
+}
+
+func (ec *ExportsController) ExportUsers(ctx *gin.Context) {
+	writer, ok := ec.newWriter(ctx, "users", models.UserColumns)
+	if !ok {
+		return
+	}
+
+	err := ec.exportService.ExportUsers(ctx.Request.Context(), exportFilter(ctx), func(user *models.UserRowDTO) error {
+		return writer.write(user)
+	})
+	finishExport(ctx, writer, err)
+}
+
+func (ec *ExportsController) ExportLoans(ctx *gin.Context) {
+	writer, ok := ec.newWriter(ctx, "loans", models.LoanColumns)
+	if !ok {
+		return
+	}
+
+	err := ec.exportService.ExportLoans(ctx.Request.Context(), exportFilter(ctx), func(loan *models.LoanRowDTO) error {
+		return writer.write(loan)
+	})
+	finishExport(ctx, writer, err)
+}
+
+func (ec *ExportsController) newWriter(ctx *gin.Context, name string, columns []string) (*exportWriter, bool) {
+	format, err := negotiateFormat(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusNotAcceptable, gin.H{
+			"error": err.Error(),
+		})
+		return nil, false
+	}
+
+	return newExportWriter(ctx, format, name, columns), true
+}
+
+// negotiateFormat picks the export format from the format parameter, or from
+// the Accept header when there is none. CSV is the default.
+func negotiateFormat(ctx *gin.Context) (string, error) {
+	if format := ctx.Query("format"); format != "" {
+		if _, ok := contentTypes[format]; !ok {
+			return "", models.ErrUnsupportedFormat
+		}
+		return format, nil
+	}
+
+	switch ctx.NegotiateFormat("text/csv", "application/json", "application/x-ndjson", "application/jsonl") {
+	case "text/csv":
+		return models.FormatCSV, nil
+	case "application/json":
+		return models.FormatJSON, nil
+	case "application/x-ndjson", "application/jsonl":
+		return models.FormatNDJSON, nil
+	default:
+		return "", models.ErrUnsupportedFormat
+	}
+}
+
+func exportFilter(ctx *gin.Context) *models.ExportFilterDTO {
+	return &models.ExportFilterDTO{
+		StoreID:         httputil.StoreID(ctx),
+		Query:           ctx.Query("q"),
+		Status:          ctx.Query("status"),
+		IncludeArchived: ctx.Query("include_archived") == "true",
+	}
+}
+
+// finishExport ends the response. Once rows have been sent the status can no
+// longer change, so a late error only cuts the download short.
+func finishExport(ctx *gin.Context, writer *exportWriter, err error) {
+	if err == nil {
+		err = writer.close()
+	}
+	if err == nil {
+		return
+	}
+
+	if writer.started {
+		ctx.Error(err)
+		ctx.Abort()
+		return
+	}
+
+	status := http.StatusInternalServerError
+	if errors.Is(err, models.ErrInvalidStatus) {
+		status = http.StatusBadRequest
+	}
+
+	if status == http.StatusInternalServerError {
+		slog.ErrorContext(ctx.Request.Context(), "Failed to finish export", "error", err)
+	}
+	ctx.JSON(status, gin.H{
+		"error": err.Error(),
+	})
+}
@@ -0,0 +1,202 @@
+package exports
+
+import (
+	models "blockbustermvc/internal/models/export"
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// fetchSize is the number of rows pulled from the cursor per round trip.
+const fetchSize = 500
+
+/*
+exportRepository is a struct that represents a Postgres database for streaming exports.
+
+Fields:
+- DB (*pgxpool.Pool): A pointer to a Postgres database connection pool.
+
+Behavior:
+- Reads export rows through a server-side cursor, so only one batch is held in memory at a time.
+*/
+type exportRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewExportRepository(db *pgxpool.Pool) models.IExportRepository {
+	return &exportRepository{
+		DB: db,
+	}
+}
+
+/*
+StreamMovies is a method of exportRepository struct that streams the movies matching a filter from the postgres database.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- filter (*models.ExportFilterDTO): The store whose quantity is exported and an optional name or director search.
+- yield (func(*models.MovieRowDTO) error): Called once per movie, newest first; an error stops the export.
+
+Returns:
+- (error): An error if the query fails or yield returns one.
+
+Behavior:
+- Matches the search as a case-sensitive substring of the name or director, like the movies page.
+*/
+func (r *exportRepository) StreamMovies(ctx context.Context, filter *models.ExportFilterDTO, yield func(*models.MovieRowDTO) error) error {
+	query := `
+		SELECT m.id, m.name, m.director, m.year, COALESCE(ss.quantity, 0), m.created_at, m.updated_at
+		FROM movies m
+		LEFT JOIN store_stock ss ON ss.movie_id = m.id AND ss.store_id = $1
+		WHERE $2 = '' OR strpos(m.name, $2) > 0 OR strpos(m.director, $2) > 0
+		ORDER BY m.created_at DESC`
+
+	return r.stream(ctx, "movies", query, []any{filter.StoreID, filter.Query}, func(rows pgx.Rows) error {
+		var movie models.MovieRowDTO
+		if err := rows.Scan(
+			&movie.ID,
+			&movie.Name,
+			&movie.Director,
+			&movie.Year,
+			&movie.Quantity,
+			&movie.CreatedAt,
+			&movie.UpdatedAt,
+		); err != nil {
+			return fmt.Errorf("failed to scan movie: %w", err)
+		}
+
+		return yield(&movie)
+	})
+}
+
+/*
+StreamUsers is a method of exportRepository struct that streams the users matching a filter from the postgres database.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- filter (*models.ExportFilterDTO): An optional user name or email search.
+- yield (func(*models.UserRowDTO) error): Called once per user, newest first; an error stops the export.
+
+Returns:
+- (error): An error if the query fails or yield returns one.
+
+Behavior:
+- Matches the search as a case-sensitive substring of the user name or email, like the users page.
+*/
+func (r *exportRepository) StreamUsers(ctx context.Context, filter *models.ExportFilterDTO, yield func(*models.UserRowDTO) error) error {
+	query := `
+		SELECT id, user_name, email, created_at, updated_at
+		FROM users
+		WHERE $1 = '' OR strpos(user_name, $1) > 0 OR strpos(email, $1) > 0
+		ORDER BY created_at DESC`
+
+	return r.stream(ctx, "users", query, []any{filter.Query}, func(rows pgx.Rows) error {
+		var user models.UserRowDTO
+		if err := rows.Scan(
+			&user.ID,
+			&user.UserName,
+			&user.Email,
+			&user.CreatedAt,
+			&user.UpdatedAt,
+		); err != nil {
+			return fmt.Errorf("failed to scan user: %w", err)
+		}
+
+		return yield(&user)
+	})
+}
+
+/*
+StreamLoans is a method of exportRepository struct that streams a store's loans matching a filter from the postgres database.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- filter (*models.ExportFilterDTO): The store, an optional loan status and whether to include archived loans.
+- yield (func(*models.LoanRowDTO) error): Called once per loan, newest first; an error stops the export.
+
+Returns:
+- (error): An error if the query fails or yield returns one.
+
+Behavior:
+- Includes the loans checked out at the store and the loans returned there, like the loans page.
+- Adds the movie and user names so the export reads without lookups.
+- With filter.IncludeArchived, reads loan_history instead, marking the archived loans.
+*/
+func (r *exportRepository) StreamLoans(ctx context.Context, filter *models.ExportFilterDTO, yield func(*models.LoanRowDTO) error) error {
+	source := "(SELECT *, FALSE AS archived FROM loans)"
+	if filter.IncludeArchived {
+		source = "loan_history"
+	}
+
+	query := fmt.Sprintf(`
+		SELECT l.id, l.movie_id, m.name, l.user_id, u.user_name, l.store_id, l.return_store_id,
+			l.borrowed_at, l.returned_at, l.status, l.archived, l.created_at, l.updated_at
+		FROM %s l
+		JOIN movies m ON m.id = l.movie_id
+		JOIN users u ON u.id = l.user_id
+		WHERE (l.store_id = $1 OR l.return_store_id = $1) AND ($2 = '' OR l.status = $2)
+		ORDER BY l.created_at DESC`, source)
+
+	return r.stream(ctx, "loans", query, []any{filter.StoreID, filter.Status}, func(rows pgx.Rows) error {
+		var loan models.LoanRowDTO
+		if err := rows.Scan(
+			&loan.ID,
+			&loan.MovieID,
+			&loan.MovieName,
+			&loan.UserID,
+			&loan.UserName,
+			&loan.StoreID,
+			&loan.ReturnStoreID,
+			&loan.BorrowedAt,
+			&loan.ReturnedAt,
+			&loan.Status,
+			&loan.Archived,
+			&loan.CreatedAt,
+			&loan.UpdatedAt,
+		); err != nil {
+			return fmt.Errorf("failed to scan loan: %w", err)
+		}
+
+		return yield(&loan)
+	})
+}
+
+// stream declares a cursor for query in a read-only transaction and hands
+// every row to scan, fetching fetchSize rows at a time.
+func (r *exportRepository) stream(ctx context.Context, name, query string, args []any, scan func(pgx.Rows) error) error {
+	tx, err := r.DB.BeginTx(ctx, pgx.TxOptions{AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return fmt.Errorf("failed to begin %s export: %w", name, err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, "DECLARE export_cursor NO SCROLL CURSOR FOR "+query, args...); err != nil {
+		return fmt.Errorf("failed to open %s export cursor: %w", name, err)
+	}
+
+	for {
+		rows, err := tx.Query(ctx, fmt.Sprintf("FETCH FORWARD %d FROM export_cursor", fetchSize))
+		if err != nil {
+			return fmt.Errorf("failed to fetch %s: %w", name, err)
+		}
+
+		fetched := 0
+		for rows.Next() {
+			fetched++
+			if err := scan(rows); err != nil {
+				rows.Close()
+				return err
+			}
+		}
+		rows.Close()
+
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("failed to fetch %s: %w", name, err)
+		}
+		if fetched < fetchSize {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package exports
+
+import (
+	models "blockbustermvc/internal/models/export"
+	"context"
+)
+
+type ExportService struct {
+	exportRepository models.IExportRepository
+}
+
+func NewExportService(exportRepo models.IExportRepository) models.IExportService {
+	return &ExportService{
+		exportRepository: exportRepo,
+	}
+}
+
+func (e ExportService) ExportMovies(ctx context.Context, filter *models.ExportFilterDTO, yield func(*models.MovieRowDTO) error) error {
+	return e.exportRepository.StreamMovies(ctx, filter, yield)
+}
+
+func (e ExportService) ExportUsers(ctx context.Context, filter *models.ExportFilterDTO, yield func(*models.UserRowDTO) error) error {
+	return e.exportRepository.StreamUsers(ctx, filter, yield)
+}
+
+func (e ExportService) ExportLoans(ctx context.Context, filter *models.ExportFilterDTO, yield func(*models.LoanRowDTO) error) error {
+	switch filter.Status {
+	case "", "active", "returned":
+	default:
+		return models.ErrInvalidStatus
+	}
+
+	return e.exportRepository.StreamLoans(ctx, filter, yield)
+}
@@ -0,0 +1,139 @@
+package exports
+
+import (
+	models "blockbustermvc/internal/models/export"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// flushEvery is the number of rows written between flushes to the client.
+const flushEvery = 100
+
+var contentTypes = map[string]string{
+	models.FormatCSV:    "text/csv; charset=utf-8",
+	models.FormatJSON:   "application/json; charset=utf-8",
+	models.FormatNDJSON: "application/x-ndjson",
+}
+
+type record interface {
+	Record() []string
+}
+
+// exportWriter encodes export rows straight into the response. Nothing is
+// sent until the first row, so a failing query can still answer with an
+// error status.
+type exportWriter struct {
+	ctx     *gin.Context
+	format  string
+	name    string
+	columns []string
+	csv     *csv.Writer
+	rows    int
+	started bool
+}
+
+func newExportWriter(ctx *gin.Context, format, name string, columns []string) *exportWriter {
+	return &exportWriter{
+		ctx:     ctx,
+		format:  format,
+		name:    name,
+		columns: columns,
+	}
+}
+
+func (w *exportWriter) start() error {
+	w.started = true
+
+	filename := fmt.Sprintf("%s-%s.%s", w.name, time.Now().Format("20060102"), w.format)
+	w.ctx.Header("Content-Type", contentTypes[w.format])
+	w.ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	w.ctx.Status(http.StatusOK)
+
+	switch w.format {
+	case models.FormatCSV:
+		w.csv = csv.NewWriter(w.ctx.Writer)
+		return w.csv.Write(w.columns)
+	case models.FormatJSON:
+		_, err := w.ctx.Writer.WriteString("[")
+		return err
+	}
+
+	return nil
+}
+
+func (w *exportWriter) write(row record) error {
+	if !w.started {
+		if err := w.start(); err != nil {
+			return err
+		}
+	}
+
+	var err error
+	switch w.format {
+	case models.FormatCSV:
+		err = w.csv.Write(row.Record())
+	case models.FormatJSON:
+		separator := ",\n"
+		if w.rows == 0 {
+			separator = "\n"
+		}
+
+		var data []byte
+		if data, err = json.Marshal(row); err != nil {
+			return err
+		}
+		if _, err = w.ctx.Writer.WriteString(separator); err != nil {
+			return err
+		}
+		_, err = w.ctx.Writer.Write(data)
+	case models.FormatNDJSON:
+		err = json.NewEncoder(w.ctx.Writer).Encode(row)
+	}
+	if err != nil {
+		return err
+	}
+
+	w.rows++
+	if w.rows%flushEvery == 0 {
+		return w.flush()
+	}
+
+	return nil
+}
+
+func (w *exportWriter) close() error {
+	if !w.started {
+		if err := w.start(); err != nil {
+			return err
+		}
+	}
+
+	if w.format == models.FormatJSON {
+		closing := "]\n"
+		if w.rows > 0 {
+			closing = "\n]\n"
+		}
+		if _, err := w.ctx.Writer.WriteString(closing); err != nil {
+			return err
+		}
+	}
+
+	return w.flush()
+}
+
+func (w *exportWriter) flush() error {
+	if w.csv != nil {
+		w.csv.Flush()
+		if err := w.csv.Error(); err != nil {
+			return err
+		}
+	}
+
+	w.ctx.Writer.Flush()
+	return nil
+}
@@ -0,0 +1,15 @@
+package health
+
+import (
+	"blockbustermvc/internal/config"
+	"time"
+)
+
+// defaultShutdownTimeout is used when BLK_SHUTDOWN_TIMEOUT is unset.
+const defaultShutdownTimeout = 30 * time.Second
+
+// ConfiguredShutdownTimeout reads from BLK_SHUTDOWN_TIMEOUT how long a
+// shutdown waits for in-flight requests, then for the background workers.
+func ConfiguredShutdownTimeout() (time.Duration, error) {
+	return config.Duration("BLK_SHUTDOWN_TIMEOUT", defaultShutdownTimeout, false)
+}
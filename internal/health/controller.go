@@ -0,0 +1,37 @@
+package health
+
+import (
+	models "blockbustermvc/internal/models/health"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HealthController struct {
+	healthService models.IHealthService
+}
+
+func NewHealthController(healthService models.IHealthService) *HealthController {
+	return &HealthController{
+		healthService: healthService,
+	}
+}
+
+func (hc *HealthController) RegisterRoutes(r *gin.RouterGroup) {
+	r.GET("/healthz", hc.GetLiveness)
+	r.GET("/readyz", hc.GetReadiness)
+}
+
+func (hc *HealthController) GetLiveness(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, hc.healthService.Live())
+}
+
+func (hc *HealthController) GetReadiness(ctx *gin.Context) {
+	health := hc.healthService.Ready(ctx.Request.Context())
+	if health.Status != models.StatusOK {
+		ctx.JSON(http.StatusServiceUnavailable, health)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, health)
+}
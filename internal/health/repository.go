@@ -0,0 +1,66 @@
+package health
+
+import (
+	models "blockbustermvc/internal/models/health"
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+/*
+healthRepository is a struct that represents the Postgres database the readiness check looks at.
+
+Fields:
+- DB (*pgxpool.Pool): A pointer to a Postgres database connection pool.
+
+Behavior:
+- Runs the cheap queries that tell whether the database can serve requests.
+*/
+type healthRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewHealthRepository(db *pgxpool.Pool) models.IHealthRepository {
+	return &healthRepository{
+		DB: db,
+	}
+}
+
+/*
+Ping is a method of healthRepository struct that checks the postgres database answers.
+
+Parameters:
+- ctx (context.Context): Bounds how long the check may take.
+
+Returns:
+- (error): An error if no connection can be acquired or the database does not answer.
+*/
+func (r *healthRepository) Ping(ctx context.Context) error {
+	if err := r.DB.Ping(ctx); err != nil {
+		return fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	return nil
+}
+
+/*
+GetMigrationVersion is a method of healthRepository struct that reads the schema version from the postgres database.
+
+Parameters:
+- ctx (context.Context): Bounds how long the check may take.
+
+Returns:
+- (int, error): The number of the last migration tern ran, or an error if it cannot be read.
+
+Behavior:
+- Reads the schema_version table kept by tern; it is missing until the first migration runs.
+*/
+func (r *healthRepository) GetMigrationVersion(ctx context.Context) (int, error) {
+	var version int
+	if err := r.DB.QueryRow(ctx, `SELECT version FROM schema_version`).Scan(&version); err != nil {
+		return 0, fmt.Errorf("failed to get migration version: %w", err)
+	}
+
+	return version, nil
+}
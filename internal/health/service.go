@@ -0,0 +1,102 @@
+package health
+
+import (
+	models "blockbustermvc/internal/models/health"
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+type HealthService struct {
+	healthRepository models.IHealthRepository
+	migration        int
+	workers          []models.IWorkerMonitor
+	draining         *atomic.Bool
+}
+
+// NewHealthService needs the migration the schema should be at, and the
+// background workers running in this process, if any.
+func NewHealthService(healthRepo models.IHealthRepository, migration int, workers ...models.IWorkerMonitor) models.IHealthService {
+	return &HealthService{
+		healthRepository: healthRepo,
+		migration:        migration,
+		workers:          workers,
+		draining:         &atomic.Bool{},
+	}
+}
+
+// Live only tells the process is up and serving; it checks nothing else so
+// a database outage does not get the process restarted.
+func (h HealthService) Live() *models.HealthDTO {
+	return &models.HealthDTO{
+		Status:    models.StatusOK,
+		CheckedAt: time.Now(),
+	}
+}
+
+// Ready runs every check, so the response shows all that is wrong at once.
+func (h HealthService) Ready(ctx context.Context) *models.HealthDTO {
+	ctx, cancel := context.WithTimeout(ctx, models.CheckTimeout)
+	defer cancel()
+
+	checks := []*models.CheckDTO{
+		check(models.CheckShutdown, "", h.shutdown()),
+		check(models.CheckDatabase, "", h.healthRepository.Ping(ctx)),
+	}
+
+	var detail string
+	version, err := h.healthRepository.GetMigrationVersion(ctx)
+	if err == nil {
+		detail = fmt.Sprintf("version %d", version)
+		if version != h.migration {
+			err = fmt.Errorf("schema is at migration %d, expected %d", version, h.migration)
+		}
+	}
+	checks = append(checks, check(models.CheckMigrations, detail, err))
+
+	for _, worker := range h.workers {
+		checks = append(checks, check(worker.Name(), "", worker.Check()))
+	}
+
+	health := &models.HealthDTO{
+		Status:    models.StatusOK,
+		Checks:    checks,
+		CheckedAt: time.Now(),
+	}
+	for _, check := range checks {
+		if check.Status != models.StatusOK {
+			health.Status = models.StatusFailing
+		}
+	}
+
+	return health
+}
+
+// Drain makes the readiness check fail from now on, so load balancers stop
+// sending requests while the server shuts down.
+func (h HealthService) Drain() {
+	h.draining.Store(true)
+}
+
+func (h HealthService) shutdown() error {
+	if h.draining.Load() {
+		return fmt.Errorf("server is shutting down")
+	}
+
+	return nil
+}
+
+func check(name, detail string, err error) *models.CheckDTO {
+	check := &models.CheckDTO{
+		Name:   name,
+		Status: models.StatusOK,
+		Detail: detail,
+	}
+	if err != nil {
+		check.Status = models.StatusFailing
+		check.Error = err.Error()
+	}
+
+	return check
+}
@@ -0,0 +1,63 @@
+package httputil
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrPreconditionRequired is returned for an update that says nothing about
+// the version it was made from.
+var ErrPreconditionRequired = errors.New("updates must send an If-Match header or the version they were made from")
+
+// ETag formats a resource version as a strong entity tag.
+func ETag(version int64) string {
+	return fmt.Sprintf(`"%d"`, version)
+}
+
+// IfMatchVersion reads the If-Match header of the request and returns the
+// resource version it refers to. A missing header or "*" yields 0, which the
+// repositories treat as an unconditional update.
+func IfMatchVersion(ctx *gin.Context) (int64, error) {
+	header := strings.TrimSpace(ctx.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return 0, nil
+	}
+
+	if strings.Contains(header, ",") {
+		return 0, errors.New("If-Match must contain a single entity tag")
+	}
+
+	tag := strings.TrimPrefix(header, "W/")
+	tag = strings.Trim(tag, `"`)
+
+	version, err := strconv.ParseInt(tag, 10, 64)
+	if err != nil || version < 1 {
+		return 0, fmt.Errorf("invalid If-Match entity tag %s", header)
+	}
+
+	return version, nil
+}
+
+// RequiredVersion returns the version an update is made from: the one in the
+// If-Match header, else bodyVersion. "If-Match: *" asks for an unconditional
+// update and leaves bodyVersion as is. An update sending neither gets
+// ErrPreconditionRequired, so clients cannot overwrite changes they never saw.
+func RequiredVersion(ctx *gin.Context, bodyVersion int64) (int64, error) {
+	version, err := IfMatchVersion(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if version != 0 {
+		return version, nil
+	}
+
+	if strings.TrimSpace(ctx.GetHeader("If-Match")) == "" && bodyVersion == 0 {
+		return 0, ErrPreconditionRequired
+	}
+
+	return bodyVersion, nil
+}
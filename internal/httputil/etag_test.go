@@ -0,0 +1,76 @@
+package httputil
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func testContext(ifMatch string) *gin.Context {
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ctx.Request = httptest.NewRequest(http.MethodPut, "/", nil)
+	if ifMatch != "" {
+		ctx.Request.Header.Set("If-Match", ifMatch)
+	}
+	return ctx
+}
+
+func TestIfMatchVersion(t *testing.T) {
+	tests := []struct {
+		header  string
+		want    int64
+		wantErr bool
+	}{
+		{header: "", want: 0},
+		{header: "*", want: 0},
+		{header: `"3"`, want: 3},
+		{header: `W/"7"`, want: 7},
+		{header: `"1", "2"`, wantErr: true},
+		{header: `"abc"`, wantErr: true},
+		{header: `"0"`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			got, err := IfMatchVersion(testContext(tt.header))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("IfMatchVersion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("IfMatchVersion() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequiredVersion(t *testing.T) {
+	tests := []struct {
+		name        string
+		header      string
+		bodyVersion int64
+		want        int64
+		wantErr     error
+	}{
+		{name: "neither", wantErr: ErrPreconditionRequired},
+		{name: "body version", bodyVersion: 4, want: 4},
+		{name: "header wins over body", header: `"5"`, bodyVersion: 4, want: 5},
+		{name: "header only", header: `"2"`, want: 2},
+		{name: "wildcard is unconditional", header: "*", want: 0},
+		{name: "wildcard keeps body version", header: "*", bodyVersion: 4, want: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RequiredVersion(testContext(tt.header), tt.bodyVersion)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RequiredVersion() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("RequiredVersion() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
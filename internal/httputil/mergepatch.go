@@ -0,0 +1,73 @@
+package httputil
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const MergePatchContentType = "application/merge-patch+json"
+
+var ErrUnsupportedMediaType = errors.New("patch requests must use application/merge-patch+json or application/json")
+
+// FieldErrors maps JSON member names to a description of what is wrong with
+// them, so clients can point at the offending field.
+type FieldErrors map[string]string
+
+func (fe FieldErrors) Error() string {
+	fields := make([]string, 0, len(fe))
+	for field := range fe {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	messages := make([]string, 0, len(fields))
+	for _, field := range fields {
+		messages = append(messages, field+" "+fe[field])
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// BindMergePatch decodes a JSON Merge Patch (RFC 7396) document into dst, a
+// pointer to a struct of pointer fields where nil means "leave unchanged".
+// Members set to null are rejected because none of the patchable columns are
+// nullable, and unknown members are rejected so typos don't silently no-op.
+func BindMergePatch(ctx *gin.Context, dst any) error {
+	contentType := ctx.ContentType()
+	if contentType != MergePatchContentType && contentType != gin.MIMEJSON {
+		return ErrUnsupportedMediaType
+	}
+
+	body, err := io.ReadAll(ctx.Request.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read patch body: %w", err)
+	}
+
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(body, &members); err != nil {
+		return fmt.Errorf("patch must be a JSON object: %w", err)
+	}
+
+	fieldErrors := FieldErrors{}
+	for name, raw := range members {
+		if bytes.Equal(bytes.TrimSpace(raw), []byte("null")) {
+			fieldErrors[name] = "must not be null"
+		}
+	}
+	if len(fieldErrors) > 0 {
+		return fieldErrors
+	}
+
+	if err := DecodeStrictJSON(body, dst); err != nil {
+		return err
+	}
+
+	return ValidateStruct(dst)
+}
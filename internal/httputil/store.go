@@ -0,0 +1,27 @@
+package httputil
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	// StoreHeader lets API clients pick the store a request is made from.
+	StoreHeader = "X-Store-ID"
+	// StoreCookie remembers the store picked on the web interface.
+	StoreCookie = "store_id"
+
+	storeContextKey = "store_id"
+)
+
+func SetStoreID(ctx *gin.Context, storeId uuid.UUID) {
+	ctx.Set(storeContextKey, storeId)
+}
+
+// StoreID returns the store resolved for the request, or uuid.Nil when no
+// store scope middleware ran.
+func StoreID(ctx *gin.Context) uuid.UUID {
+	storeId, _ := ctx.Get(storeContextKey)
+	id, _ := storeId.(uuid.UUID)
+	return id
+}
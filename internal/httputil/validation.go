@@ -0,0 +1,95 @@
+package httputil
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// DecodeStrictJSON decodes a single JSON object into dst, rejecting unknown
+// members. Type mismatches and unknown members are reported as FieldErrors.
+func DecodeStrictJSON(data []byte, dst any) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(dst); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return FieldErrors{typeErr.Field: "must be of type " + typeErr.Type.String()}
+		}
+		if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+			return FieldErrors{strings.Trim(field, `"`): "is not a known field"}
+		}
+		return err
+	}
+
+	return nil
+}
+
+// ValidateStruct runs the binding rules of dst and reports failures as
+// FieldErrors keyed by JSON member name.
+func ValidateStruct(dst any) error {
+	err := binding.Validator.ValidateStruct(dst)
+	if err == nil {
+		return nil
+	}
+
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return err
+	}
+
+	fieldErrors := FieldErrors{}
+	for _, fieldErr := range validationErrors {
+		fieldErrors[jsonFieldName(dst, fieldErr.StructField())] = describeValidation(fieldErr)
+	}
+
+	return fieldErrors
+}
+
+func jsonFieldName(dst any, structField string) string {
+	t := reflect.TypeOf(dst)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	field, ok := t.FieldByName(structField)
+	if !ok {
+		return structField
+	}
+
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return structField
+	}
+
+	return name
+}
+
+func describeValidation(fieldErr validator.FieldError) string {
+	unit := ""
+	if fieldErr.Kind() == reflect.String {
+		unit = " characters long"
+	}
+
+	switch fieldErr.Tag() {
+	case "required":
+		return "is required"
+	case "min":
+		return "must be at least " + fieldErr.Param() + unit
+	case "max":
+		return "must be at most " + fieldErr.Param() + unit
+	case "email":
+		return "must be a valid email address"
+	case "http_url":
+		return "must be an http or https URL"
+	case "oneof":
+		return "must be one of " + fieldErr.Param()
+	default:
+		return "failed " + fieldErr.Tag() + " validation"
+	}
+}
@@ -0,0 +1,117 @@
+package imports
+
+import (
+	"blockbustermvc/internal/httputil"
+	models "blockbustermvc/internal/models/imports"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxUploadSize bounds the size of an uploaded import file.
+const maxUploadSize = 32 << 20
+
+type ImportsController struct {
+	importService models.IImportService
+}
+
+func NewImportsController(importService models.IImportService) *ImportsController {
+	return &ImportsController{
+		importService: importService,
+	}
+}
+
+func (ic *ImportsController) RegisterRoutes(r *gin.RouterGroup) {
+	imports := r.Group("/imports")
+
+	{
+		imports.POST("/:kind", ic.Import)
+	}
+}
+
+// Import loads a CSV or JSON Lines file sent either as the "file" field of a
+// multipart form or as the raw request body.
+func (ic *ImportsController) Import(ctx *gin.Context) {
+	dryRun, err := strconv.ParseBool(ctx.DefaultQuery("dry_run", "false"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "dry_run must be true or false",
+		})
+		return
+	}
+
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxUploadSize)
+
+	format := ctx.Query("format")
+
+	var file io.Reader = ctx.Request.Body
+	if strings.HasPrefix(ctx.ContentType(), "multipart/") {
+		upload, header, err := ctx.Request.FormFile("file")
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error": "Missing import file",
+			})
+			return
+		}
+		defer upload.Close()
+
+		file = upload
+		if format == "" {
+			format = models.FormatFromFileName(header.Filename)
+		}
+	}
+
+	if format == "" {
+		format = formatFromContentType(ctx.ContentType())
+	}
+
+	result, err := ic.importService.Import(ctx.Request.Context(), &models.ImportOptionsDTO{
+		Kind:    ctx.Param("kind"),
+		Format:  format,
+		StoreID: httputil.StoreID(ctx),
+		DryRun:  dryRun,
+	}, file)
+	if err != nil {
+		respondWithImportError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+func formatFromContentType(contentType string) string {
+	switch contentType {
+	case "text/csv":
+		return models.FormatCSV
+	case "application/jsonl", "application/x-ndjson":
+		return models.FormatJSONL
+	default:
+		return ""
+	}
+}
+
+func respondWithImportError(ctx *gin.Context, err error) {
+	var maxBytesErr *http.MaxBytesError
+
+	status := http.StatusInternalServerError
+	switch {
+	case errors.As(err, &maxBytesErr):
+		status = http.StatusRequestEntityTooLarge
+	case errors.Is(err, models.ErrUnsupportedKind),
+		errors.Is(err, models.ErrUnsupportedFormat),
+		errors.Is(err, models.ErrInvalidFile):
+		status = http.StatusBadRequest
+	}
+
+	if status == http.StatusInternalServerError {
+		slog.ErrorContext(ctx.Request.Context(), "Import request failed", "error", err)
+	}
+	ctx.JSON(status, gin.H{
+		"error": err.Error(),
+	})
+}
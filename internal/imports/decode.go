@@ -0,0 +1,252 @@
+package imports
+
+import (
+	"blockbustermvc/internal/httputil"
+	models "blockbustermvc/internal/models/imports"
+	"bufio"
+	"bytes"
+	"encoding"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// maxLineSize bounds a single JSON Lines record.
+const maxLineSize = 1 << 20
+
+// parsedRow is one record of an import file. Row is the 1-based record
+// number, not counting a CSV header.
+type parsedRow[T any] struct {
+	Row    int
+	Value  *T
+	Errors httputil.FieldErrors
+}
+
+func (p *parsedRow[T]) addError(field, message string) {
+	if p.Errors == nil {
+		p.Errors = httputil.FieldErrors{}
+	}
+	if _, ok := p.Errors[field]; !ok {
+		p.Errors[field] = message
+	}
+}
+
+// decodeRows reads every record of the file into a T and runs the binding
+// rules on it. Malformed records are kept with their errors; only a file that
+// cannot be read at all returns an error.
+func decodeRows[T any](format string, file io.Reader) ([]*parsedRow[T], error) {
+	var rows []*parsedRow[T]
+	var err error
+
+	switch format {
+	case models.FormatCSV:
+		rows, err = decodeCSV[T](file)
+	case models.FormatJSONL:
+		rows, err = decodeJSONL[T](file)
+	default:
+		return nil, models.ErrUnsupportedFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		if _, unreadable := row.Errors["row"]; unreadable {
+			continue
+		}
+		if err := httputil.ValidateStruct(row.Value); err != nil {
+			for field, message := range toFieldErrors(err) {
+				row.addError(field, message)
+			}
+		}
+	}
+
+	return rows, nil
+}
+
+func decodeJSONL[T any](file io.Reader) ([]*parsedRow[T], error) {
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	var rows []*parsedRow[T]
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		row := &parsedRow[T]{Row: len(rows) + 1, Value: new(T)}
+		if err := httputil.DecodeStrictJSON(line, row.Value); err != nil {
+			row.Errors = locateJSONError[T](line, err)
+		}
+		rows = append(rows, row)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %w", models.ErrInvalidFile, err)
+	}
+
+	return rows, nil
+}
+
+func decodeCSV[T any](file io.Reader) ([]*parsedRow[T], error) {
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", models.ErrInvalidFile, err)
+	}
+
+	fields := csvFields(reflect.TypeFor[T]())
+	columns := make([][]int, len(header))
+	for i, name := range header {
+		name = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+		index, ok := fields[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown column %q", models.ErrInvalidFile, name)
+		}
+		columns[i] = index
+	}
+
+	var rows []*parsedRow[T]
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		row := &parsedRow[T]{Row: len(rows) + 1, Value: new(T)}
+		rows = append(rows, row)
+
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			row.addError("row", parseErr.Err.Error())
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", models.ErrInvalidFile, err)
+		}
+		if len(record) != len(header) {
+			row.addError("row", fmt.Sprintf("has %d columns, the header has %d", len(record), len(header)))
+			continue
+		}
+
+		value := reflect.ValueOf(row.Value).Elem()
+		for i, cell := range record {
+			if err := setField(value.FieldByIndex(columns[i]), strings.TrimSpace(cell)); err != nil {
+				row.addError(header[i], err.Error())
+			}
+		}
+	}
+
+	return rows, nil
+}
+
+// csvFields maps the JSON names of the exported fields of t, including
+// promoted ones, to their field index.
+func csvFields(t reflect.Type) map[string][]int {
+	fields := make(map[string][]int)
+	for _, field := range reflect.VisibleFields(t) {
+		if !field.IsExported() || field.Anonymous {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = field.Index
+	}
+
+	return fields
+}
+
+// setField parses a CSV cell into field. Empty cells leave the zero value so
+// the binding rules decide whether the column was required.
+func setField(field reflect.Value, cell string) error {
+	if cell == "" {
+		return nil
+	}
+
+	if field.Kind() == reflect.Pointer {
+		value := reflect.New(field.Type().Elem())
+		if err := setField(value.Elem(), cell); err != nil {
+			return err
+		}
+		field.Set(value)
+		return nil
+	}
+
+	if unmarshaler, ok := field.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		if err := unmarshaler.UnmarshalText([]byte(cell)); err != nil {
+			return fmt.Errorf("must be a valid %s", field.Type().String())
+		}
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(cell)
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(cell, 10, 64)
+		if err != nil {
+			return errors.New("must be a whole number")
+		}
+		field.SetInt(n)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(cell)
+		if err != nil {
+			return errors.New("must be true or false")
+		}
+		field.SetBool(b)
+	default:
+		return fmt.Errorf("cannot be imported from %s", models.FormatCSV)
+	}
+
+	return nil
+}
+
+// locateJSONError attributes a decoding error that does not name a field,
+// such as a malformed UUID, to the member that caused it.
+func locateJSONError[T any](line []byte, err error) httputil.FieldErrors {
+	var members map[string]json.RawMessage
+	if json.Unmarshal(line, &members) != nil {
+		return httputil.FieldErrors{"row": "must be a JSON object"}
+	}
+
+	var fieldErrors httputil.FieldErrors
+	if errors.As(err, &fieldErrors) {
+		return fieldErrors
+	}
+
+	for name, raw := range members {
+		member, _ := json.Marshal(map[string]json.RawMessage{name: raw})
+		if memberErr := json.Unmarshal(member, new(T)); memberErr != nil {
+			return httputil.FieldErrors{name: "is invalid: " + memberErr.Error()}
+		}
+	}
+
+	return httputil.FieldErrors{"row": err.Error()}
+}
+
+func toFieldErrors(err error) httputil.FieldErrors {
+	var fieldErrors httputil.FieldErrors
+	if errors.As(err, &fieldErrors) {
+		return fieldErrors
+	}
+
+	return httputil.FieldErrors{"row": err.Error()}
+}
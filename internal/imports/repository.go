@@ -0,0 +1,389 @@
+package imports
+
+import (
+	"blockbustermvc/internal/inventory"
+	eventModels "blockbustermvc/internal/models/event"
+	models "blockbustermvc/internal/models/imports"
+	inventoryModels "blockbustermvc/internal/models/inventory"
+	"blockbustermvc/internal/outbox"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// lookupQueries are the only lookups FindExisting runs; each returns the
+// matching values as text.
+var lookupQueries = map[string]string{
+	models.LookupMovieID:        `SELECT id::text FROM movies WHERE id = ANY($1::uuid[])`,
+	models.LookupMovieName:      `SELECT name FROM movies WHERE name = ANY($1)`,
+	models.LookupMovieDirector:  `SELECT director FROM movies WHERE director = ANY($1)`,
+	models.LookupUserID:         `SELECT id::text FROM users WHERE id = ANY($1::uuid[])`,
+	models.LookupUserName:       `SELECT user_name FROM users WHERE user_name = ANY($1)`,
+	models.LookupUserEmail:      `SELECT email FROM users WHERE email = ANY($1)`,
+	models.LookupBarcode:        `SELECT barcode FROM copies WHERE barcode = ANY($1)`,
+	models.LookupActiveLoanUser: `SELECT DISTINCT user_id::text FROM loans WHERE status = 'active' AND user_id = ANY($1::uuid[])`,
+}
+
+/*
+importRepository is a struct that represents a Postgres database for bulk loading catalog data.
+
+Fields:
+- DB (*pgxpool.Pool): A pointer to a Postgres database connection pool.
+
+Behavior:
+- Looks up existing values so import files can be validated before anything is written.
+- Writes validated rows with the COPY protocol, one transaction per import.
+*/
+type importRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewImportRepository(db *pgxpool.Pool) models.IImportRepository {
+	return &importRepository{
+		DB: db,
+	}
+}
+
+/*
+FindExisting is a method of importRepository struct that reports which of the given values already exist in the postgres database.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- lookup (string): One of the models.Lookup constants naming the column to search.
+- values ([]string): The values to look for; ids must be valid UUIDs.
+
+Returns:
+- (map[string]bool, error): The set of values that already exist, or an error if the lookup fails.
+
+Behavior:
+- Runs the fixed query registered for the lookup, never SQL built from the input.
+- Returns an error if the lookup is unknown or the query fails.
+*/
+func (r *importRepository) FindExisting(ctx context.Context, lookup string, values []string) (map[string]bool, error) {
+	query, ok := lookupQueries[lookup]
+	if !ok {
+		return nil, fmt.Errorf("unknown import lookup %q", lookup)
+	}
+
+	existing := make(map[string]bool)
+	if len(values) == 0 {
+		return existing, nil
+	}
+
+	rows, err := r.DB.Query(ctx, query, values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up existing %s: %w", lookup, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			return nil, fmt.Errorf("failed to scan existing %s: %w", lookup, err)
+		}
+		existing[value] = true
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to look up existing %s: %w", lookup, err)
+	}
+
+	return existing, nil
+}
+
+/*
+GetStoreStock is a method of importRepository struct that retrieves a store's quantity of the given movies from the postgres database.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- storeId (uuid.UUID): The ID of the store.
+- movieIds ([]uuid.UUID): The IDs of the movies.
+
+Returns:
+- (map[uuid.UUID]int64, error): The store's quantity per movie, or an error if the query fails.
+
+Behavior:
+- Movies without a store_stock row are left out, meaning the store has none.
+*/
+func (r *importRepository) GetStoreStock(ctx context.Context, storeId uuid.UUID, movieIds []uuid.UUID) (map[uuid.UUID]int64, error) {
+	stock := make(map[uuid.UUID]int64)
+	if len(movieIds) == 0 {
+		return stock, nil
+	}
+
+	rows, err := r.DB.Query(ctx,
+		`SELECT movie_id, quantity FROM store_stock WHERE store_id = $1 AND movie_id = ANY($2)`,
+		storeId,
+		movieIds,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get store stock: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var movieId uuid.UUID
+		var quantity int64
+		if err := rows.Scan(&movieId, &quantity); err != nil {
+			return nil, fmt.Errorf("failed to scan store stock: %w", err)
+		}
+		stock[movieId] = quantity
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get store stock: %w", err)
+	}
+
+	return stock, nil
+}
+
+/*
+ImportMovies is a method of importRepository struct that bulk inserts movies into the postgres database.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- storeId (uuid.UUID): The ID of the store that receives the initial stock.
+- rows ([]*models.MovieRow): The validated movie rows.
+
+Returns:
+- (int64, error): The number of movies inserted, or an error if the import fails.
+
+Behavior:
+- Copies the movies in one statement, then books the stock of each stocked movie as a purchase through inventory.ApplyMovement, in the same transaction.
+- Movies without an id in the file are given a new one.
+- Nothing is written if any part of the copy fails.
+*/
+func (r *importRepository) ImportMovies(ctx context.Context, storeId uuid.UUID, rows []*models.MovieRow) (int64, error) {
+	now := time.Now()
+
+	movies := make([][]any, 0, len(rows))
+	stocked := make(map[uuid.UUID]int64, len(rows))
+	for _, row := range rows {
+		id := uuid.New()
+		if row.ID != nil {
+			id = *row.ID
+		}
+
+		movies = append(movies, []any{id, row.Name, row.Director, row.Year, 0, now, now})
+		if row.Quantity > 0 {
+			stocked[id] = row.Quantity
+		}
+	}
+
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin movie import: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	imported, err := tx.CopyFrom(ctx,
+		pgx.Identifier{"movies"},
+		[]string{"id", "name", "director", "year", "quantity", "created_at", "updated_at"},
+		pgx.CopyFromRows(movies),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to copy movies: %w", err)
+	}
+
+	for _, movieId := range inventory.SortedMovieIDs(stocked) {
+		_, err := inventory.ApplyMovement(ctx, tx, movieId, &inventoryModels.CreateMovementDTO{
+			Reason:        inventoryModels.ReasonPurchase,
+			QuantityDelta: stocked[movieId],
+			Note:          "Imported stock",
+			StoreID:       storeId,
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit movie import: %w", err)
+	}
+
+	return imported, nil
+}
+
+/*
+ImportCopies is a method of importRepository struct that bulk inserts physical copies into the postgres database.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- rows ([]*models.CopyRow): The validated copy rows.
+
+Returns:
+- (int64, error): The number of copies inserted, or an error if the import fails.
+
+Behavior:
+- Copies all rows in one statement, so either every copy is inserted or none is.
+*/
+func (r *importRepository) ImportCopies(ctx context.Context, rows []*models.CopyRow) (int64, error) {
+	now := time.Now()
+
+	imported, err := r.DB.CopyFrom(ctx,
+		pgx.Identifier{"copies"},
+		[]string{"movie_id", "barcode", "created_at", "updated_at"},
+		pgx.CopyFromSlice(len(rows), func(i int) ([]any, error) {
+			return []any{rows[i].MovieID, rows[i].Barcode, now, now}, nil
+		}),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to copy copies: %w", err)
+	}
+
+	return imported, nil
+}
+
+/*
+ImportUsers is a method of importRepository struct that bulk inserts users into the postgres database.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- rows ([]*models.UserRow): The validated user rows.
+
+Returns:
+- (int64, error): The number of users inserted, or an error if the import fails.
+
+Behavior:
+- Users without an id in the file are given a new one.
+- Copies all rows in one statement, so either every user is inserted or none is.
+*/
+func (r *importRepository) ImportUsers(ctx context.Context, rows []*models.UserRow) (int64, error) {
+	now := time.Now()
+
+	imported, err := r.DB.CopyFrom(ctx,
+		pgx.Identifier{"users"},
+		[]string{"id", "user_name", "email", "created_at", "updated_at"},
+		pgx.CopyFromSlice(len(rows), func(i int) ([]any, error) {
+			id := uuid.New()
+			if rows[i].ID != nil {
+				id = *rows[i].ID
+			}
+			return []any{id, rows[i].UserName, rows[i].Email, now, now}, nil
+		}),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to copy users: %w", err)
+	}
+
+	return imported, nil
+}
+
+/*
+ImportLoans is a method of importRepository struct that bulk inserts open loans into the postgres database.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- storeId (uuid.UUID): The ID of the store the loans were checked out at.
+- rows ([]*models.LoanRow): The validated loan rows.
+
+Returns:
+- (int64, error): The number of loans inserted, or an error if the import fails.
+
+Behavior:
+- Copies the loans in one statement, charged at the movie's current rental price as new loans are.
+- Then takes each loaned copy out of stock through inventory.ApplyMovement, locking the movies in a fixed order, and writes its loan.created event to the outbox.
+- Returns inventoryModels.ErrInsufficientStock if the store no longer has enough copies of a movie.
+- Nothing is written if any part of the import fails.
+*/
+func (r *importRepository) ImportLoans(ctx context.Context, storeId uuid.UUID, rows []*models.LoanRow) (int64, error) {
+	now := time.Now()
+
+	byMovie := make(map[uuid.UUID][]*eventModels.LoanCreatedPayload)
+	for _, row := range rows {
+		borrowedAt := row.BorrowedAt
+		if borrowedAt.IsZero() {
+			borrowedAt = now
+		}
+
+		byMovie[row.MovieID] = append(byMovie[row.MovieID], &eventModels.LoanCreatedPayload{
+			LoanID:     uuid.New(),
+			MovieID:    row.MovieID,
+			UserID:     row.UserID,
+			StoreID:    storeId,
+			BorrowedAt: borrowedAt,
+		})
+	}
+
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin loan import: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	prices, err := rentalPrices(ctx, tx, inventory.SortedMovieIDs(byMovie))
+	if err != nil {
+		return 0, err
+	}
+
+	loans := make([][]any, 0, len(rows))
+	for _, movieId := range inventory.SortedMovieIDs(byMovie) {
+		for _, loan := range byMovie[movieId] {
+			loans = append(loans, []any{loan.LoanID, loan.MovieID, loan.UserID, storeId, loan.BorrowedAt, "active", prices[movieId], now, now})
+		}
+	}
+
+	imported, err := tx.CopyFrom(ctx,
+		pgx.Identifier{"loans"},
+		[]string{"id", "movie_id", "user_id", "store_id", "borrowed_at", "status", "price_cents", "created_at", "updated_at"},
+		pgx.CopyFromRows(loans),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to copy loans: %w", err)
+	}
+
+	for _, movieId := range inventory.SortedMovieIDs(byMovie) {
+		for _, loan := range byMovie[movieId] {
+			_, err := inventory.ApplyMovement(ctx, tx, movieId, &inventoryModels.CreateMovementDTO{
+				Reason:        inventoryModels.ReasonLoan,
+				QuantityDelta: -1,
+				Note:          "Imported loan",
+				StoreID:       storeId,
+				LoanID:        &loan.LoanID,
+			})
+			if err != nil {
+				return 0, fmt.Errorf("movie %s: %w", movieId, err)
+			}
+
+			if err := outbox.Write(ctx, tx, eventModels.TypeLoanCreated, loan.LoanID, loan); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit loan import: %w", err)
+	}
+
+	return imported, nil
+}
+
+// rentalPrices returns the current rental price of each movie, which
+// imported loans are charged as new loans are.
+func rentalPrices(ctx context.Context, tx pgx.Tx, movieIds []uuid.UUID) (map[uuid.UUID]int64, error) {
+	rows, err := tx.Query(ctx, `SELECT id, rental_price_cents FROM movies WHERE id = ANY($1)`, movieIds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rental prices: %w", err)
+	}
+	defer rows.Close()
+
+	prices := make(map[uuid.UUID]int64, len(movieIds))
+	for rows.Next() {
+		var movieId uuid.UUID
+		var price int64
+		if err := rows.Scan(&movieId, &price); err != nil {
+			return nil, fmt.Errorf("failed to scan rental price: %w", err)
+		}
+		prices[movieId] = price
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rental prices: %w", err)
+	}
+
+	return prices, nil
+}
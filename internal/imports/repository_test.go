@@ -0,0 +1,57 @@
+package imports
+
+import (
+	"blockbustermvc/internal/database/dbtest"
+	"blockbustermvc/internal/inventory"
+	eventModels "blockbustermvc/internal/models/event"
+	models "blockbustermvc/internal/models/imports"
+	inventoryModels "blockbustermvc/internal/models/inventory"
+	"testing"
+)
+
+func TestImportLoansChargesAndAnnouncesEachLoan(t *testing.T) {
+	pool := dbtest.Open(t)
+	ctx := t.Context()
+
+	storeId := dbtest.Store(t, pool)
+	movieId := dbtest.Movie(t, pool)
+	firstUser := dbtest.User(t, pool)
+	secondUser := dbtest.User(t, pool)
+
+	if _, err := pool.Exec(ctx, `UPDATE movies SET rental_price_cents = 399 WHERE id = $1`, movieId); err != nil {
+		t.Fatalf("failed to price movie: %v", err)
+	}
+	_, err := inventory.NewInventoryRepository(pool).CreateMovement(ctx, movieId, &inventoryModels.CreateMovementDTO{
+		Reason:        inventoryModels.ReasonPurchase,
+		QuantityDelta: 2,
+		StoreID:       storeId,
+	})
+	if err != nil {
+		t.Fatalf("CreateMovement() error = %v", err)
+	}
+
+	imported, err := NewImportRepository(pool).ImportLoans(ctx, storeId, []*models.LoanRow{
+		{MovieID: movieId, UserID: firstUser},
+		{MovieID: movieId, UserID: secondUser},
+	})
+	if err != nil {
+		t.Fatalf("ImportLoans() error = %v", err)
+	}
+	if imported != 2 {
+		t.Errorf("imported %d loans, want 2", imported)
+	}
+
+	if got := dbtest.Int(t, pool, `SELECT COUNT(*) FROM loans WHERE movie_id = $1 AND price_cents = 399`, movieId); got != 2 {
+		t.Errorf("loans charged the rental price = %d, want 2", got)
+	}
+	events := `
+		SELECT COUNT(*) FROM outbox_events e
+		JOIN loans l ON l.id = e.aggregate_id
+		WHERE l.movie_id = $1 AND e.type = $2`
+	if got := dbtest.Int(t, pool, events, movieId, eventModels.TypeLoanCreated); got != 2 {
+		t.Errorf("loan.created events = %d, want 2", got)
+	}
+	if got := dbtest.Int(t, pool, `SELECT quantity FROM store_stock WHERE store_id = $1 AND movie_id = $2`, storeId, movieId); got != 0 {
+		t.Errorf("store stock after the import = %d, want 0", got)
+	}
+}
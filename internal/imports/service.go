@@ -0,0 +1,285 @@
+package imports
+
+import (
+	models "blockbustermvc/internal/models/imports"
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ImportService struct {
+	importRepository models.IImportRepository
+}
+
+func NewImportService(importRepo models.IImportRepository) models.IImportService {
+	return &ImportService{
+		importRepository: importRepo,
+	}
+}
+
+func (i ImportService) Import(ctx context.Context, options *models.ImportOptionsDTO, file io.Reader) (*models.ImportResultDTO, error) {
+	result := &models.ImportResultDTO{
+		Kind:    options.Kind,
+		Format:  options.Format,
+		StoreID: options.StoreID,
+		DryRun:  options.DryRun,
+		Errors:  []*models.RowErrorDTO{},
+	}
+
+	var err error
+	switch options.Kind {
+	case models.KindMovies:
+		err = i.importMovies(ctx, result, file)
+	case models.KindCopies:
+		err = i.importCopies(ctx, result, file)
+	case models.KindUsers:
+		err = i.importUsers(ctx, result, file)
+	case models.KindLoans:
+		err = i.importLoans(ctx, result, file)
+	default:
+		return nil, models.ErrUnsupportedKind
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (i ImportService) importMovies(ctx context.Context, result *models.ImportResultDTO, file io.Reader) error {
+	rows, err := decodeRows[models.MovieRow](result.Format, file)
+	if err != nil {
+		return err
+	}
+
+	if err := checkUnique(ctx, i.importRepository, rows, models.LookupMovieID, "id", "already exists", func(m *models.MovieRow) string {
+		return optionalID(m.ID)
+	}); err != nil {
+		return err
+	}
+	if err := checkUnique(ctx, i.importRepository, rows, models.LookupMovieName, "name", "already exists", func(m *models.MovieRow) string {
+		return m.Name
+	}); err != nil {
+		return err
+	}
+	if err := checkUnique(ctx, i.importRepository, rows, models.LookupMovieDirector, "director", "already exists", func(m *models.MovieRow) string {
+		return m.Director
+	}); err != nil {
+		return err
+	}
+
+	return writeRows(ctx, result, rows, func(ctx context.Context, valid []*models.MovieRow) (int64, error) {
+		return i.importRepository.ImportMovies(ctx, result.StoreID, valid)
+	})
+}
+
+func (i ImportService) importCopies(ctx context.Context, result *models.ImportResultDTO, file io.Reader) error {
+	rows, err := decodeRows[models.CopyRow](result.Format, file)
+	if err != nil {
+		return err
+	}
+
+	if err := checkExists(ctx, i.importRepository, rows, models.LookupMovieID, "movie_id", func(c *models.CopyRow) string {
+		return requiredID(c.MovieID)
+	}); err != nil {
+		return err
+	}
+	if err := checkUnique(ctx, i.importRepository, rows, models.LookupBarcode, "barcode", "already exists", func(c *models.CopyRow) string {
+		return c.Barcode
+	}); err != nil {
+		return err
+	}
+
+	return writeRows(ctx, result, rows, i.importRepository.ImportCopies)
+}
+
+func (i ImportService) importUsers(ctx context.Context, result *models.ImportResultDTO, file io.Reader) error {
+	rows, err := decodeRows[models.UserRow](result.Format, file)
+	if err != nil {
+		return err
+	}
+
+	if err := checkUnique(ctx, i.importRepository, rows, models.LookupUserID, "id", "already exists", func(u *models.UserRow) string {
+		return optionalID(u.ID)
+	}); err != nil {
+		return err
+	}
+	if err := checkUnique(ctx, i.importRepository, rows, models.LookupUserName, "user_name", "already exists", func(u *models.UserRow) string {
+		return u.UserName
+	}); err != nil {
+		return err
+	}
+	if err := checkUnique(ctx, i.importRepository, rows, models.LookupUserEmail, "email", "already exists", func(u *models.UserRow) string {
+		return u.Email
+	}); err != nil {
+		return err
+	}
+
+	return writeRows(ctx, result, rows, i.importRepository.ImportUsers)
+}
+
+func (i ImportService) importLoans(ctx context.Context, result *models.ImportResultDTO, file io.Reader) error {
+	rows, err := decodeRows[models.LoanRow](result.Format, file)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, row := range rows {
+		if row.Value.BorrowedAt.After(now) {
+			row.addError("borrowed_at", "cannot be in the future")
+		}
+	}
+
+	if err := checkExists(ctx, i.importRepository, rows, models.LookupMovieID, "movie_id", func(l *models.LoanRow) string {
+		return requiredID(l.MovieID)
+	}); err != nil {
+		return err
+	}
+	if err := checkExists(ctx, i.importRepository, rows, models.LookupUserID, "user_id", func(l *models.LoanRow) string {
+		return requiredID(l.UserID)
+	}); err != nil {
+		return err
+	}
+	// Users may only hold one active loan, as when lending over the counter.
+	if err := checkUnique(ctx, i.importRepository, rows, models.LookupActiveLoanUser, "user_id", "already has an active loan", func(l *models.LoanRow) string {
+		return requiredID(l.UserID)
+	}); err != nil {
+		return err
+	}
+
+	// Stock is handed out in file order to the rows that are otherwise valid.
+	movieIds := make([]uuid.UUID, 0, len(rows))
+	for _, row := range rows {
+		if len(row.Errors) == 0 {
+			movieIds = append(movieIds, row.Value.MovieID)
+		}
+	}
+
+	available, err := i.importRepository.GetStoreStock(ctx, result.StoreID, movieIds)
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		if len(row.Errors) > 0 {
+			continue
+		}
+		if available[row.Value.MovieID] <= 0 {
+			row.addError("movie_id", "is not available at this store")
+			continue
+		}
+		available[row.Value.MovieID]--
+	}
+
+	return writeRows(ctx, result, rows, func(ctx context.Context, valid []*models.LoanRow) (int64, error) {
+		return i.importRepository.ImportLoans(ctx, result.StoreID, valid)
+	})
+}
+
+// checkUnique flags rows whose key repeats an earlier row of the file or is
+// already stored under lookup.
+func checkUnique[T any](ctx context.Context, repo models.IImportRepository, rows []*parsedRow[T], lookup, field, existsMessage string, key func(*T) string) error {
+	first := make(map[string]int)
+	values := make([]string, 0, len(rows))
+	for _, row := range rows {
+		value := key(row.Value)
+		if value == "" {
+			continue
+		}
+		if seen, ok := first[value]; ok {
+			row.addError(field, fmt.Sprintf("is the same as row %d", seen))
+			continue
+		}
+		first[value] = row.Row
+		values = append(values, value)
+	}
+
+	existing, err := repo.FindExisting(ctx, lookup, values)
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		value := key(row.Value)
+		if existing[value] && first[value] == row.Row {
+			row.addError(field, existsMessage)
+		}
+	}
+
+	return nil
+}
+
+// checkExists flags rows whose key refers to nothing stored under lookup.
+func checkExists[T any](ctx context.Context, repo models.IImportRepository, rows []*parsedRow[T], lookup, field string, key func(*T) string) error {
+	values := make([]string, 0, len(rows))
+	for _, row := range rows {
+		if value := key(row.Value); value != "" {
+			values = append(values, value)
+		}
+	}
+
+	existing, err := repo.FindExisting(ctx, lookup, values)
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		if value := key(row.Value); value != "" && !existing[value] {
+			row.addError(field, "does not exist")
+		}
+	}
+
+	return nil
+}
+
+// writeRows reports the rows with errors and, unless this is a dry run,
+// writes the rest.
+func writeRows[T any](ctx context.Context, result *models.ImportResultDTO, rows []*parsedRow[T], write func(context.Context, []*T) (int64, error)) error {
+	valid := make([]*T, 0, len(rows))
+	for _, row := range rows {
+		if len(row.Errors) > 0 {
+			result.Errors = append(result.Errors, &models.RowErrorDTO{
+				Row:    row.Row,
+				Errors: row.Errors,
+			})
+			continue
+		}
+		valid = append(valid, row.Value)
+	}
+
+	result.Total = len(rows)
+	result.Valid = len(valid)
+	result.Invalid = len(result.Errors)
+
+	if result.DryRun || len(valid) == 0 {
+		return nil
+	}
+
+	imported, err := write(ctx, valid)
+	if err != nil {
+		return err
+	}
+	result.Imported = imported
+
+	return nil
+}
+
+func optionalID(id *uuid.UUID) string {
+	if id == nil {
+		return ""
+	}
+
+	return requiredID(*id)
+}
+
+func requiredID(id uuid.UUID) string {
+	if id == uuid.Nil {
+		return ""
+	}
+
+	return id.String()
+}
@@ -0,0 +1,177 @@
+package inventory
+
+import (
+	"blockbustermvc/internal/httputil"
+	models "blockbustermvc/internal/models/inventory"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type InventoryController struct {
+	inventoryService models.IInventoryService
+}
+
+func NewInventoryController(inventoryService models.IInventoryService) *InventoryController {
+	return &InventoryController{
+		inventoryService: inventoryService,
+	}
+}
+
+func (ic *InventoryController) RegisterRoutes(r *gin.RouterGroup) {
+	stock := r.Group("/movies/:id/stock")
+
+	{
+		stock.GET("", ic.GetMovieStock)
+		stock.GET("/movements", ic.GetMovieMovements)
+		stock.POST("/movements", ic.AdjustStock)
+	}
+
+	r.GET("/movies/:id/availability", ic.GetMovieAvailability)
+
+	r.GET("/stock/reconciliation", ic.GetUnreconciledStock)
+}
+
+func (ic *InventoryController) AdjustStock(ctx *gin.Context) {
+	if err := uuid.Validate(ctx.Param("id")); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid movie ID",
+		})
+		return
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	var movement models.CreateMovementDTO
+	if err := ctx.ShouldBindJSON(&movement); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body",
+		})
+		return
+	}
+
+	created, err := ic.inventoryService.AdjustStock(ctx.Request.Context(), httputil.StoreID(ctx), id, &movement)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, models.ErrInvalidMovement) || errors.Is(err, models.ErrInsufficientStock) {
+			status = http.StatusUnprocessableEntity
+		}
+
+		if status == http.StatusInternalServerError {
+			slog.ErrorContext(ctx.Request.Context(), "Failed to adjust stock", "error", err)
+		}
+		ctx.JSON(status, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.Header("Location", "/api/movies/"+id.String()+"/stock/movements")
+	ctx.JSON(http.StatusCreated, created)
+}
+
+func (ic *InventoryController) GetMovieMovements(ctx *gin.Context) {
+	if err := uuid.Validate(ctx.Param("id")); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid movie ID",
+		})
+		return
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	movements, err := ic.inventoryService.GetMovieMovements(ctx.Request.Context(), id)
+	if err != nil {
+		slog.ErrorContext(ctx.Request.Context(), "Failed to get movie movements", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, movements)
+}
+
+func (ic *InventoryController) GetMovieStock(ctx *gin.Context) {
+	if err := uuid.Validate(ctx.Param("id")); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid movie ID",
+		})
+		return
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	stock, err := ic.inventoryService.GetMovieStock(ctx.Request.Context(), id)
+	if err != nil {
+		slog.ErrorContext(ctx.Request.Context(), "Failed to get movie stock", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, stock)
+}
+
+func (ic *InventoryController) GetMovieAvailability(ctx *gin.Context) {
+	if err := uuid.Validate(ctx.Param("id")); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid movie ID",
+		})
+		return
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	availability, err := ic.inventoryService.GetMovieAvailability(ctx.Request.Context(), id)
+	if err != nil {
+		slog.ErrorContext(ctx.Request.Context(), "Failed to get movie availability", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, availability)
+}
+
+func (ic *InventoryController) GetUnreconciledStock(ctx *gin.Context) {
+	stocks, err := ic.inventoryService.GetUnreconciledStock(ctx.Request.Context())
+	if err != nil {
+		slog.ErrorContext(ctx.Request.Context(), "Failed to get unreconciled stock", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, stocks)
+}
@@ -0,0 +1,143 @@
+package inventory
+
+import (
+	eventModels "blockbustermvc/internal/models/event"
+	models "blockbustermvc/internal/models/inventory"
+	"blockbustermvc/internal/outbox"
+	"blockbustermvc/internal/wishlists"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// ApplyMovement records a stock movement inside tx, so it commits together
+// with the change that caused it, such as a loan, a transfer or a stocktake.
+// Every stock change goes through it: it locks the movie and the store's
+// stock, applies the delta to both, writes the ledger row and a
+// stock.changed event, and queues the notifications of the customers
+// waiting for the movie when the store had no copies left.
+//
+// It returns models.ErrInsufficientStock if the movement would take the
+// store's stock below zero.
+func ApplyMovement(ctx context.Context, tx pgx.Tx, movieId uuid.UUID, movement *models.CreateMovementDTO) (*models.MovementDTO, error) {
+	var quantity int64
+	err := tx.QueryRow(ctx, `SELECT quantity FROM movies WHERE id = $1 FOR UPDATE`, movieId).Scan(&quantity)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("movie with id %s not found", movieId)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock movie stock: %w", err)
+	}
+
+	now := time.Now()
+
+	var storeQuantity int64
+	err = tx.QueryRow(ctx, `
+		INSERT INTO store_stock (store_id, movie_id, quantity, updated_at)
+		VALUES ($1, $2, 0, $3)
+		ON CONFLICT (store_id, movie_id) DO UPDATE SET store_id = EXCLUDED.store_id
+		RETURNING quantity`,
+		movement.StoreID,
+		movieId,
+		now,
+	).Scan(&storeQuantity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock store stock: %w", err)
+	}
+
+	if storeQuantity+movement.QuantityDelta < 0 {
+		return nil, models.ErrInsufficientStock
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE store_stock SET quantity = quantity + $3, updated_at = $4 WHERE store_id = $1 AND movie_id = $2`,
+		movement.StoreID,
+		movieId,
+		movement.QuantityDelta,
+		now,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update store stock: %w", err)
+	}
+
+	if storeQuantity == 0 && movement.QuantityDelta > 0 {
+		if err := wishlists.QueueAvailabilityNotifications(ctx, tx, movement.StoreID, movieId); err != nil {
+			return nil, err
+		}
+	}
+
+	quantityAfter := quantity + movement.QuantityDelta
+	_, err = tx.Exec(ctx, `UPDATE movies SET quantity = $2, updated_at = $3 WHERE id = $1`,
+		movieId,
+		quantityAfter,
+		now,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update movie stock: %w", err)
+	}
+
+	query := `
+		INSERT INTO inventory_movements (movie_id, store_id, reason, quantity_delta, quantity_after, loan_id, stocktake_id, transfer_id, note, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id, movie_id, store_id, reason, quantity_delta, quantity_after, loan_id, note, created_at`
+
+	var created models.MovementDTO
+	err = tx.QueryRow(ctx, query,
+		movieId,
+		movement.StoreID,
+		movement.Reason,
+		movement.QuantityDelta,
+		quantityAfter,
+		movement.LoanID,
+		movement.StocktakeID,
+		movement.TransferID,
+		movement.Note,
+		now,
+	).Scan(
+		&created.ID,
+		&created.MovieID,
+		&created.StoreID,
+		&created.Reason,
+		&created.QuantityDelta,
+		&created.QuantityAfter,
+		&created.LoanID,
+		&created.Note,
+		&created.CreatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stock movement: %w", err)
+	}
+
+	err = outbox.Write(ctx, tx, eventModels.TypeStockChanged, movieId, &eventModels.StockChangedPayload{
+		MovieID:       movieId,
+		StoreID:       movement.StoreID,
+		Reason:        movement.Reason,
+		QuantityDelta: movement.QuantityDelta,
+		StoreQuantity: storeQuantity + movement.QuantityDelta,
+		TotalQuantity: quantityAfter,
+		LoanID:        movement.LoanID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+// SortedMovieIDs returns the keys of byId in a fixed order. A transaction
+// that moves the stock of several movies applies them in this order, so two
+// of them always lock the movies in the same order and cannot deadlock.
+func SortedMovieIDs[T any](byId map[uuid.UUID]T) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(byId))
+	for id := range byId {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i].String() < ids[j].String()
+	})
+	return ids
+}
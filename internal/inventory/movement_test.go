@@ -0,0 +1,129 @@
+package inventory
+
+import (
+	"blockbustermvc/internal/database/dbtest"
+	eventModels "blockbustermvc/internal/models/event"
+	models "blockbustermvc/internal/models/inventory"
+	notificationModels "blockbustermvc/internal/models/notification"
+	"blockbustermvc/internal/wishlists"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestCreateMovementKeepsStockLedgerAndOutboxInStep(t *testing.T) {
+	pool := dbtest.Open(t)
+	ctx := t.Context()
+	repo := NewInventoryRepository(pool)
+
+	storeId := dbtest.Store(t, pool)
+	movieId := dbtest.Movie(t, pool)
+
+	steps := []struct {
+		name          string
+		delta         int64
+		wantErr       error
+		wantStore     int64
+		wantMovements int64
+	}{
+		{name: "restock", delta: 3, wantStore: 3, wantMovements: 1},
+		{name: "loss", delta: -2, wantStore: 1, wantMovements: 2},
+		{name: "below zero", delta: -2, wantErr: models.ErrInsufficientStock, wantStore: 1, wantMovements: 2},
+	}
+
+	for _, step := range steps {
+		movement, err := repo.CreateMovement(ctx, movieId, &models.CreateMovementDTO{
+			Reason:        models.ReasonManualCorrection,
+			QuantityDelta: step.delta,
+			StoreID:       storeId,
+		})
+		if !errors.Is(err, step.wantErr) {
+			t.Fatalf("%s: CreateMovement() error = %v, want %v", step.name, err, step.wantErr)
+		}
+		if err == nil && movement.QuantityAfter != step.wantStore {
+			t.Errorf("%s: QuantityAfter = %d, want %d", step.name, movement.QuantityAfter, step.wantStore)
+		}
+
+		store := dbtest.Int(t, pool, `SELECT quantity FROM store_stock WHERE store_id = $1 AND movie_id = $2`, storeId, movieId)
+		chain := dbtest.Int(t, pool, `SELECT quantity FROM movies WHERE id = $1`, movieId)
+		movements := dbtest.Int(t, pool, `SELECT COUNT(*) FROM inventory_movements WHERE movie_id = $1`, movieId)
+		events := dbtest.Int(t, pool, `SELECT COUNT(*) FROM outbox_events WHERE aggregate_id = $1 AND type = $2`, movieId, eventModels.TypeStockChanged)
+
+		if store != step.wantStore || chain != step.wantStore {
+			t.Errorf("%s: store stock = %d, movies.quantity = %d, want %d", step.name, store, chain, step.wantStore)
+		}
+		if movements != step.wantMovements || events != step.wantMovements {
+			t.Errorf("%s: %d movements and %d stock.changed events, want %d of each", step.name, movements, events, step.wantMovements)
+		}
+	}
+}
+
+func TestMovementQueuesWishlistNotificationsWhenStoreRestocks(t *testing.T) {
+	pool := dbtest.Open(t)
+	ctx := t.Context()
+	repo := NewInventoryRepository(pool)
+
+	storeId := dbtest.Store(t, pool)
+	movieId := dbtest.Movie(t, pool)
+	userId := dbtest.User(t, pool)
+
+	if _, err := wishlists.NewWishlistRepository(pool).AddItem(ctx, userId, movieId); err != nil {
+		t.Fatalf("AddItem() error = %v", err)
+	}
+
+	queued := `SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND movie_id = $2 AND kind = $3`
+	waiting := `SELECT COUNT(*) FROM wishlist_items WHERE user_id = $1 AND movie_id = $2 AND notified_at IS NULL`
+
+	steps := []struct {
+		name        string
+		delta       int64
+		wantQueued  int64
+		wantWaiting int64
+	}{
+		{name: "0 to n", delta: 2, wantQueued: 1, wantWaiting: 0},
+		{name: "n to n+1", delta: 1, wantQueued: 1, wantWaiting: 0},
+	}
+
+	for _, step := range steps {
+		_, err := repo.CreateMovement(ctx, movieId, &models.CreateMovementDTO{
+			Reason:        models.ReasonPurchase,
+			QuantityDelta: step.delta,
+			StoreID:       storeId,
+		})
+		if err != nil {
+			t.Fatalf("%s: CreateMovement() error = %v", step.name, err)
+		}
+
+		if got := dbtest.Int(t, pool, queued, userId, movieId, notificationModels.KindMovieAvailable); got != step.wantQueued {
+			t.Errorf("%s: queued notifications = %d, want %d", step.name, got, step.wantQueued)
+		}
+		if got := dbtest.Int(t, pool, waiting, userId, movieId); got != step.wantWaiting {
+			t.Errorf("%s: wishlist items still waiting = %d, want %d", step.name, got, step.wantWaiting)
+		}
+	}
+}
+
+func TestSortedMovieIDsIsStable(t *testing.T) {
+	differences := map[uuid.UUID]int64{}
+	for range 20 {
+		differences[uuid.New()] = 1
+	}
+
+	want := SortedMovieIDs(differences)
+	for i := 1; i < len(want); i++ {
+		if want[i-1].String() >= want[i].String() {
+			t.Fatalf("SortedMovieIDs() = %v, not in ascending order", want)
+		}
+	}
+
+	// Map iteration order changes between runs, the lock order must not
+	for range 10 {
+		got := SortedMovieIDs(differences)
+		for i := range want {
+			if got[i] != want[i] {
+				t.Fatalf("SortedMovieIDs() = %v, want %v", got, want)
+			}
+		}
+	}
+}
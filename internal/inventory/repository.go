@@ -0,0 +1,266 @@
+package inventory
+
+import (
+	models "blockbustermvc/internal/models/inventory"
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+/*
+inventoryRepository is a struct that represents a Postgres database for storing stock movements.
+
+Fields:
+- DB (*pgxpool.Pool): A pointer to a Postgres database connection pool.
+
+Behavior:
+- Provides methods for interacting with the inventory_movements table in the database.
+- Keeps movies.quantity and the per-store store_stock in step with the ledger whenever a movement is recorded.
+*/
+type inventoryRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewInventoryRepository(db *pgxpool.Pool) models.IInventoryRepository {
+	return &inventoryRepository{
+		DB: db,
+	}
+}
+
+/*
+CreateMovement is a method of inventoryRepository struct that records a stock movement in the postgres database.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- movieId (uuid.UUID): The ID of the movie whose stock changes.
+- movement (*models.CreateMovementDTO): A pointer to a CreateMovementDTO struct containing the store, reason and quantity delta.
+
+Returns:
+- (*models.MovementDTO, error): A pointer to a MovementDTO struct containing the persisted movement, or an error if recording fails.
+
+Behavior:
+- Applies the movement with ApplyMovement in a transaction of its own.
+- Locks the movie row and the store's stock row, applies the delta to both and inserts the movement.
+- QuantityAfter is the chain-wide stock, so the ledger keeps reconciling with movies.quantity.
+- Leaves the movie version untouched, since stock is not part of the editable movie record.
+- When the movement brings the store back from zero copies, queues a notification for every customer waiting for the movie on their wishlist.
+- Writes a stock.changed event to the outbox in the same transaction.
+- Returns models.ErrInsufficientStock if the movement would take the store's stock below zero.
+- Returns an error if the movie does not exist or the movement cannot be recorded.
+*/
+func (r *inventoryRepository) CreateMovement(ctx context.Context, movieId uuid.UUID, movement *models.CreateMovementDTO) (*models.MovementDTO, error) {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin stock movement: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	created, err := ApplyMovement(ctx, tx, movieId, movement)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit stock movement: %w", err)
+	}
+
+	return created, nil
+}
+
+/*
+GetMovieMovements is a method of inventoryRepository struct that retrieves the stock history of a movie from the postgres database.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- movieId (uuid.UUID): The ID of the movie whose movements should be retrieved.
+
+Returns:
+- ([]*models.MovementDTO, error): A slice of pointers to MovementDTO structs, newest first, or an error if the retrieval fails.
+
+Behavior:
+- Retrieves all movements of the movie from the inventory_movements table.
+- Returns an error if the retrieval fails.
+*/
+func (r *inventoryRepository) GetMovieMovements(ctx context.Context, movieId uuid.UUID) ([]*models.MovementDTO, error) {
+	query := `
+		SELECT id, movie_id, store_id, reason, quantity_delta, quantity_after, loan_id, note, created_at
+		FROM inventory_movements
+		WHERE movie_id = $1
+		ORDER BY created_at DESC`
+
+	rows, err := r.DB.Query(ctx, query, movieId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stock movements: %w", err)
+	}
+	defer rows.Close()
+
+	var movements []*models.MovementDTO
+	for rows.Next() {
+		var movement models.MovementDTO
+		err := rows.Scan(
+			&movement.ID,
+			&movement.MovieID,
+			&movement.StoreID,
+			&movement.Reason,
+			&movement.QuantityDelta,
+			&movement.QuantityAfter,
+			&movement.LoanID,
+			&movement.Note,
+			&movement.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan stock movement: %w", err)
+		}
+		movements = append(movements, &movement)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over stock movements: %w", err)
+	}
+
+	return movements, nil
+}
+
+/*
+GetMovieStock is a method of inventoryRepository struct that reconciles the stock of a movie against its ledger.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- movieId (uuid.UUID): The ID of the movie to reconcile.
+
+Returns:
+- (*models.StockDTO, error): A pointer to a StockDTO struct comparing movies.quantity with the ledger sum, or an error if the retrieval fails.
+
+Behavior:
+- Sums every movement of the movie and compares the result with movies.quantity.
+- Returns an error if the movie does not exist.
+*/
+func (r *inventoryRepository) GetMovieStock(ctx context.Context, movieId uuid.UUID) (*models.StockDTO, error) {
+	query := `
+		SELECT m.id, m.name, m.quantity, COALESCE(SUM(im.quantity_delta), 0)
+		FROM movies m
+		LEFT JOIN inventory_movements im ON im.movie_id = m.id
+		WHERE m.id = $1
+		GROUP BY m.id, m.name, m.quantity`
+
+	var stock models.StockDTO
+	err := r.DB.QueryRow(ctx, query, movieId).Scan(
+		&stock.MovieID,
+		&stock.MovieName,
+		&stock.Quantity,
+		&stock.LedgerQuantity,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get movie stock: %w", err)
+	}
+
+	stock.Difference = stock.Quantity - stock.LedgerQuantity
+	stock.Reconciled = stock.Difference == 0
+
+	return &stock, nil
+}
+
+/*
+GetMovieAvailability is a method of inventoryRepository struct that retrieves the stock of a movie at every store.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- movieId (uuid.UUID): The ID of the movie to look up.
+
+Returns:
+- ([]*models.StoreStockDTO, error): A slice of pointers to StoreStockDTO structs, one per store, or an error if the retrieval fails.
+
+Behavior:
+- Lists every store, reporting zero for stores that have never stocked the movie.
+- Orders stores by quantity so the best place to send a customer comes first.
+- Returns an error if the retrieval fails.
+*/
+func (r *inventoryRepository) GetMovieAvailability(ctx context.Context, movieId uuid.UUID) ([]*models.StoreStockDTO, error) {
+	query := `
+		SELECT s.id, s.code, s.name, $1::uuid, COALESCE(ss.quantity, 0) AS quantity
+		FROM stores s
+		LEFT JOIN store_stock ss ON ss.store_id = s.id AND ss.movie_id = $1
+		ORDER BY quantity DESC, s.name`
+
+	rows, err := r.DB.Query(ctx, query, movieId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get movie availability: %w", err)
+	}
+	defer rows.Close()
+
+	var stocks []*models.StoreStockDTO
+	for rows.Next() {
+		var stock models.StoreStockDTO
+		err := rows.Scan(
+			&stock.StoreID,
+			&stock.StoreCode,
+			&stock.StoreName,
+			&stock.MovieID,
+			&stock.Quantity,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan store stock: %w", err)
+		}
+		stocks = append(stocks, &stock)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over store stock: %w", err)
+	}
+
+	return stocks, nil
+}
+
+/*
+GetUnreconciledStock is a method of inventoryRepository struct that retrieves every movie whose stock disagrees with its ledger.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+
+Returns:
+- ([]*models.StockDTO, error): A slice of pointers to StockDTO structs, or an error if the retrieval fails.
+
+Behavior:
+- Compares movies.quantity with the sum of movements for every movie.
+- Returns only the movies where the two differ.
+*/
+func (r *inventoryRepository) GetUnreconciledStock(ctx context.Context) ([]*models.StockDTO, error) {
+	query := `
+		SELECT m.id, m.name, m.quantity, COALESCE(SUM(im.quantity_delta), 0) AS ledger_quantity
+		FROM movies m
+		LEFT JOIN inventory_movements im ON im.movie_id = m.id
+		GROUP BY m.id, m.name, m.quantity
+		HAVING m.quantity <> COALESCE(SUM(im.quantity_delta), 0)
+		ORDER BY m.name`
+
+	rows, err := r.DB.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get unreconciled stock: %w", err)
+	}
+	defer rows.Close()
+
+	var stocks []*models.StockDTO
+	for rows.Next() {
+		var stock models.StockDTO
+		err := rows.Scan(
+			&stock.MovieID,
+			&stock.MovieName,
+			&stock.Quantity,
+			&stock.LedgerQuantity,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan stock: %w", err)
+		}
+
+		stock.Difference = stock.Quantity - stock.LedgerQuantity
+		stocks = append(stocks, &stock)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over stock: %w", err)
+	}
+
+	return stocks, nil
+}
@@ -0,0 +1,63 @@
+package inventory
+
+import (
+	models "blockbustermvc/internal/models/inventory"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type InventoryService struct {
+	inventoryRepository models.IInventoryRepository
+}
+
+func NewInventoryService(inventoryRepo models.IInventoryRepository) models.IInventoryService {
+	return &InventoryService{
+		inventoryRepository: inventoryRepo,
+	}
+}
+
+func (i InventoryService) AdjustStock(ctx context.Context, storeId, movieId uuid.UUID, movement *models.CreateMovementDTO) (*models.MovementDTO, error) {
+	movement.StoreID = storeId
+	movement.Note = strings.TrimSpace(movement.Note)
+
+	switch movement.Reason {
+	case models.ReasonPurchase:
+		if movement.QuantityDelta <= 0 {
+			return nil, fmt.Errorf("%w: purchase must add copies", models.ErrInvalidMovement)
+		}
+	case models.ReasonLoss, models.ReasonDamage:
+		if movement.QuantityDelta >= 0 {
+			return nil, fmt.Errorf("%w: %s must remove copies", models.ErrInvalidMovement, movement.Reason)
+		}
+	case models.ReasonManualCorrection:
+		if movement.QuantityDelta == 0 {
+			return nil, fmt.Errorf("%w: correction must change the stock", models.ErrInvalidMovement)
+		}
+		if movement.Note == "" {
+			return nil, fmt.Errorf("%w: correction needs a note", models.ErrInvalidMovement)
+		}
+	default:
+		return nil, fmt.Errorf("%w: reason %s cannot be recorded manually", models.ErrInvalidMovement, movement.Reason)
+	}
+
+	return i.inventoryRepository.CreateMovement(ctx, movieId, movement)
+}
+
+func (i InventoryService) GetMovieMovements(ctx context.Context, movieId uuid.UUID) ([]*models.MovementDTO, error) {
+	return i.inventoryRepository.GetMovieMovements(ctx, movieId)
+}
+
+func (i InventoryService) GetMovieStock(ctx context.Context, movieId uuid.UUID) (*models.StockDTO, error) {
+	return i.inventoryRepository.GetMovieStock(ctx, movieId)
+}
+
+func (i InventoryService) GetMovieAvailability(ctx context.Context, movieId uuid.UUID) ([]*models.StoreStockDTO, error) {
+	return i.inventoryRepository.GetMovieAvailability(ctx, movieId)
+}
+
+func (i InventoryService) GetUnreconciledStock(ctx context.Context) ([]*models.StockDTO, error) {
+	return i.inventoryRepository.GetUnreconciledStock(ctx)
+}
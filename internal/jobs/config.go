@@ -0,0 +1,68 @@
+package jobs
+
+import (
+	"blockbustermvc/internal/config"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultConcurrency  = 2
+	defaultPollInterval = 5 * time.Second
+)
+
+// ConfiguredEmbeddedWorker reads from BLK_JOBS_EMBEDDED whether the API
+// process runs the job worker itself. Set it to false when the jobs run in
+// separate cmd/worker processes instead.
+func ConfiguredEmbeddedWorker() (bool, error) {
+	value := os.Getenv("BLK_JOBS_EMBEDDED")
+	if value == "" {
+		return true, nil
+	}
+
+	embedded, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid BLK_JOBS_EMBEDDED %q: expected true or false", value)
+	}
+
+	return embedded, nil
+}
+
+// ConfiguredWorker reads how many jobs a worker runs at once from
+// BLK_JOBS_CONCURRENCY and how often an idle worker looks for due jobs
+// from BLK_JOBS_POLL.
+func ConfiguredWorker() (WorkerConfig, error) {
+	worker := WorkerConfig{
+		ID:          workerID(),
+		Concurrency: defaultConcurrency,
+	}
+
+	if value := os.Getenv("BLK_JOBS_CONCURRENCY"); value != "" {
+		concurrency, err := strconv.Atoi(value)
+		if err != nil || concurrency < 1 {
+			return worker, fmt.Errorf("invalid BLK_JOBS_CONCURRENCY %q: expected a positive number", value)
+		}
+		worker.Concurrency = concurrency
+	}
+
+	interval, err := config.Duration("BLK_JOBS_POLL", defaultPollInterval, false)
+	if err != nil {
+		return worker, err
+	}
+	worker.PollInterval = interval
+
+	return worker, nil
+}
+
+// workerID names the worker after its host and process, so the lock of a
+// running job shows who holds it.
+func workerID() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
+}
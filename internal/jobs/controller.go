@@ -0,0 +1,148 @@
+package jobs
+
+import (
+	models "blockbustermvc/internal/models/job"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type JobsController struct {
+	jobService models.IJobService
+}
+
+func NewJobsController(jobService models.IJobService) *JobsController {
+	return &JobsController{
+		jobService: jobService,
+	}
+}
+
+func (jc *JobsController) RegisterRoutes(r *gin.RouterGroup) {
+	jobs := r.Group("/jobs")
+
+	{
+		jobs.POST("", jc.EnqueueJob)
+		jobs.GET("", jc.GetJobs)
+		jobs.GET("/kinds", jc.GetKinds)
+		jobs.GET("/schedules", jc.GetSchedules)
+		jobs.POST("/schedules/:name/run", jc.RunSchedule)
+		jobs.GET("/:id", jc.GetJob)
+		jobs.POST("/:id/retry", jc.RetryJob)
+	}
+}
+
+func (jc *JobsController) EnqueueJob(ctx *gin.Context) {
+	var req models.EnqueueJobDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body",
+		})
+		return
+	}
+
+	job, err := jc.jobService.Enqueue(ctx.Request.Context(), &req)
+	if err != nil {
+		respondWithJobError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, job)
+}
+
+func (jc *JobsController) GetJobs(ctx *gin.Context) {
+	jobs, err := jc.jobService.GetJobs(ctx.Request.Context(), ctx.Query("status"))
+	if err != nil {
+		respondWithJobError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, jobs)
+}
+
+func (jc *JobsController) GetKinds(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, jc.jobService.Kinds())
+}
+
+func (jc *JobsController) GetSchedules(ctx *gin.Context) {
+	schedules, err := jc.jobService.GetSchedules(ctx.Request.Context())
+	if err != nil {
+		respondWithJobError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, schedules)
+}
+
+func (jc *JobsController) RunSchedule(ctx *gin.Context) {
+	job, err := jc.jobService.RunSchedule(ctx.Request.Context(), ctx.Param("name"))
+	if err != nil {
+		respondWithJobError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, job)
+}
+
+func (jc *JobsController) GetJob(ctx *gin.Context) {
+	jobId, ok := parseJobID(ctx)
+	if !ok {
+		return
+	}
+
+	job, err := jc.jobService.GetJob(ctx.Request.Context(), jobId)
+	if err != nil {
+		respondWithJobError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, job)
+}
+
+func (jc *JobsController) RetryJob(ctx *gin.Context) {
+	jobId, ok := parseJobID(ctx)
+	if !ok {
+		return
+	}
+
+	job, err := jc.jobService.Retry(ctx.Request.Context(), jobId)
+	if err != nil {
+		respondWithJobError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, job)
+}
+
+func parseJobID(ctx *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid job ID",
+		})
+		return uuid.Nil, false
+	}
+
+	return id, true
+}
+
+func respondWithJobError(ctx *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, models.ErrJobNotFound), errors.Is(err, models.ErrScheduleNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, models.ErrNotDead):
+		status = http.StatusConflict
+	case errors.Is(err, models.ErrUnknownKind):
+		status = http.StatusUnprocessableEntity
+	}
+
+	if status == http.StatusInternalServerError {
+		slog.ErrorContext(ctx.Request.Context(), "Job request failed", "error", err)
+	}
+	ctx.JSON(status, gin.H{
+		"error": err.Error(),
+	})
+}
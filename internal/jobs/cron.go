@@ -0,0 +1,188 @@
+package jobs
+
+import (
+	models "blockbustermvc/internal/models/job"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// schedule tells when a recurring job runs next.
+type schedule interface {
+	Next(after time.Time) time.Time
+}
+
+// every runs a job at a fixed interval, written "@every 30m".
+type every time.Duration
+
+func (e every) Next(after time.Time) time.Time {
+	return after.Add(time.Duration(e))
+}
+
+// cron is a standard five-field cron expression (minute, hour, day of
+// month, month, day of week) evaluated in the server's time zone. Each
+// field is a set of allowed values stored as a bit mask.
+type cron struct {
+	minute, hour, dayOfMonth, month, dayOfWeek uint64
+
+	// As in cron(8), when both day fields are restricted a day matching
+	// either of them fires.
+	anyDayOfMonth, anyDayOfWeek bool
+}
+
+var cronAliases = map[string]string{
+	"@hourly":   "0 * * * *",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@weekly":   "0 0 * * 0",
+	"@monthly":  "0 0 1 * *",
+}
+
+// Every returns the spec of a schedule that runs every interval.
+func Every(interval time.Duration) string {
+	return "@every " + interval.String()
+}
+
+// parseSchedule reads "@every <duration>", one of the @hourly, @daily,
+// @weekly and @monthly shorthands, or a five-field cron expression such as
+// "*/15 8-20 * * 1-5".
+func parseSchedule(spec string) (schedule, error) {
+	spec = strings.TrimSpace(spec)
+
+	if value, ok := strings.CutPrefix(spec, "@every "); ok {
+		interval, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil || interval < time.Second {
+			return nil, fmt.Errorf("%w: %q needs a duration of at least 1s", models.ErrInvalidSchedule, spec)
+		}
+		return every(interval), nil
+	}
+
+	expression := spec
+	if alias, ok := cronAliases[spec]; ok {
+		expression = alias
+	}
+
+	fields := strings.Fields(expression)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("%w: %q should have 5 fields", models.ErrInvalidSchedule, spec)
+	}
+
+	var c cron
+	var err error
+	if c.minute, err = parseCronField(fields[0], 0, 59); err != nil {
+		return nil, fmt.Errorf("%w: %q minute: %v", models.ErrInvalidSchedule, spec, err)
+	}
+	if c.hour, err = parseCronField(fields[1], 0, 23); err != nil {
+		return nil, fmt.Errorf("%w: %q hour: %v", models.ErrInvalidSchedule, spec, err)
+	}
+	if c.dayOfMonth, err = parseCronField(fields[2], 1, 31); err != nil {
+		return nil, fmt.Errorf("%w: %q day of month: %v", models.ErrInvalidSchedule, spec, err)
+	}
+	if c.month, err = parseCronField(fields[3], 1, 12); err != nil {
+		return nil, fmt.Errorf("%w: %q month: %v", models.ErrInvalidSchedule, spec, err)
+	}
+	if c.dayOfWeek, err = parseCronField(fields[4], 0, 7); err != nil {
+		return nil, fmt.Errorf("%w: %q day of week: %v", models.ErrInvalidSchedule, spec, err)
+	}
+
+	// Sunday is both 0 and 7
+	if c.dayOfWeek&(1<<7) != 0 {
+		c.dayOfWeek |= 1
+	}
+	c.anyDayOfMonth = fields[2] == "*"
+	c.anyDayOfWeek = fields[4] == "*"
+
+	if c.Next(time.Now()).IsZero() {
+		return nil, fmt.Errorf("%w: %q never runs", models.ErrInvalidSchedule, spec)
+	}
+
+	return c, nil
+}
+
+// parseCronField reads a comma-separated list of values, ranges ("1-5")
+// and steps ("*/15", "0-30/10") between min and max.
+func parseCronField(field string, min, max int) (uint64, error) {
+	var bits uint64
+
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if value, stepValue, ok := strings.Cut(part, "/"); ok {
+			n, err := strconv.Atoi(stepValue)
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("invalid step %q", stepValue)
+			}
+			part, step = value, n
+		}
+
+		low, high := min, max
+		switch {
+		case part == "*":
+		case strings.Contains(part, "-"):
+			from, to, _ := strings.Cut(part, "-")
+			var err error
+			if low, err = strconv.Atoi(from); err != nil {
+				return 0, fmt.Errorf("invalid value %q", from)
+			}
+			if high, err = strconv.Atoi(to); err != nil {
+				return 0, fmt.Errorf("invalid value %q", to)
+			}
+		default:
+			n, err := strconv.Atoi(part)
+			if err != nil {
+				return 0, fmt.Errorf("invalid value %q", part)
+			}
+			low, high = n, n
+			// "5/15" starts at 5 and steps through the whole range
+			if step > 1 {
+				high = max
+			}
+		}
+
+		if low < min || high > max || low > high {
+			return 0, fmt.Errorf("%q is outside %d-%d", part, min, max)
+		}
+
+		for value := low; value <= high; value += step {
+			bits |= 1 << value
+		}
+	}
+
+	return bits, nil
+}
+
+// Next returns the first matching minute after the given time, or the zero
+// time if the expression does not match within five years.
+func (c cron) Next(after time.Time) time.Time {
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+	location := t.Location()
+
+	for t.Before(limit) {
+		switch {
+		case c.month&(1<<int(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, location)
+		case !c.matchesDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, location)
+		case c.hour&(1<<t.Hour()) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, location)
+		case c.minute&(1<<t.Minute()) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+func (c cron) matchesDay(t time.Time) bool {
+	dayOfMonth := c.dayOfMonth&(1<<t.Day()) != 0
+	dayOfWeek := c.dayOfWeek&(1<<int(t.Weekday())) != 0
+
+	if c.anyDayOfMonth || c.anyDayOfWeek {
+		return dayOfMonth && dayOfWeek
+	}
+
+	return dayOfMonth || dayOfWeek
+}
@@ -0,0 +1,548 @@
+package jobs
+
+import (
+	models "blockbustermvc/internal/models/job"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const jobColumns = `id, kind, payload::TEXT, status, attempts, max_attempts, run_at, locked_at, COALESCE(locked_by, ''),
+	last_error, COALESCE(schedule_name, ''), finished_at, created_at, updated_at`
+
+const scheduleColumns = `name, kind, payload::TEXT, spec, next_run_at, last_run_at, created_at, updated_at`
+
+/*
+jobRepository is a struct that represents a Postgres database for storing background jobs.
+
+Fields:
+- DB (*pgxpool.Pool): A pointer to a Postgres database connection pool.
+
+Behavior:
+- Provides methods for interacting with the jobs and job_schedules tables in the database.
+- Hands jobs out to workers with FOR UPDATE SKIP LOCKED, so several workers never run the same job.
+*/
+type jobRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewJobRepository(db *pgxpool.Pool) models.IJobRepository {
+	return &jobRepository{
+		DB: db,
+	}
+}
+
+/*
+Enqueue is a method of jobRepository struct that queues a job in the postgres database.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- job (*models.EnqueueJobDTO): A pointer to an EnqueueJobDTO struct containing the kind, payload, run time and attempt limit.
+- scheduleName (string): The schedule that queued the job, or an empty string for a one-off job.
+
+Returns:
+- (*models.JobDTO, error): A pointer to a JobDTO struct containing the queued job, or an error if the insertion fails.
+*/
+func (r *jobRepository) Enqueue(ctx context.Context, job *models.EnqueueJobDTO, scheduleName string) (*models.JobDTO, error) {
+	return enqueue(ctx, r.DB, job, scheduleName)
+}
+
+/*
+GetJob is a method of jobRepository struct that retrieves a job from the postgres database by its ID.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- id (uuid.UUID): The ID of the job.
+
+Returns:
+- (*models.JobDTO, error): A pointer to a JobDTO struct, or models.ErrJobNotFound if there is none.
+*/
+func (r *jobRepository) GetJob(ctx context.Context, id uuid.UUID) (*models.JobDTO, error) {
+	return scanJob(r.DB.QueryRow(ctx, `SELECT `+jobColumns+` FROM jobs WHERE id = $1`, id))
+}
+
+/*
+GetJobs is a method of jobRepository struct that retrieves jobs from the postgres database.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- status (string): Only jobs with this status are returned; an empty status returns all of them.
+- limit (int): The maximum number of jobs returned.
+
+Returns:
+- ([]*models.JobDTO, error): A slice of JobDTO structs, newest first, or an error if the retrieval fails.
+*/
+func (r *jobRepository) GetJobs(ctx context.Context, status string, limit int) ([]*models.JobDTO, error) {
+	rows, err := r.DB.Query(ctx, `
+		SELECT `+jobColumns+`
+		FROM jobs
+		WHERE $1 = '' OR status = $1
+		ORDER BY created_at DESC
+		LIMIT $2`,
+		status,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get jobs: %w", err)
+	}
+	defer rows.Close()
+
+	jobs := []*models.JobDTO{}
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over jobs: %w", err)
+	}
+
+	return jobs, nil
+}
+
+/*
+Claim is a method of jobRepository struct that locks the next due job for a worker.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- workerId (string): The ID of the worker claiming the job.
+- kinds ([]string): The job kinds the worker has handlers for.
+- staleBefore (time.Time): Running jobs locked before this time belong to a worker that died and are claimed again.
+
+Returns:
+- (*models.JobDTO, error): A pointer to a JobDTO struct containing the claimed job, nil if no job is due, or an error if claiming fails.
+
+Behavior:
+- Marks the job running and counts the attempt before the handler runs, so a job that crashes its worker still runs out of attempts.
+*/
+func (r *jobRepository) Claim(ctx context.Context, workerId string, kinds []string, staleBefore time.Time) (*models.JobDTO, error) {
+	now := time.Now()
+	job, err := scanJob(r.DB.QueryRow(ctx, `
+		UPDATE jobs
+		SET status = $2, attempts = attempts + 1, locked_at = $5, locked_by = $1, updated_at = $5
+		WHERE id = (
+			SELECT id FROM jobs
+			WHERE kind = ANY($4) AND ((status = $3 AND run_at <= $5) OR (status = $2 AND locked_at < $6))
+			ORDER BY run_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+jobColumns,
+		workerId,
+		models.StatusRunning,
+		models.StatusQueued,
+		kinds,
+		now,
+		staleBefore,
+	))
+	if errors.Is(err, models.ErrJobNotFound) {
+		return nil, nil
+	}
+
+	return job, err
+}
+
+/*
+Complete is a method of jobRepository struct that marks a claimed job as succeeded.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- id (uuid.UUID): The ID of the job.
+- workerId (string): The ID of the worker that ran the job.
+
+Returns:
+- (error): An error if the update fails.
+
+Behavior:
+- Does nothing if the job has since been claimed by another worker.
+*/
+func (r *jobRepository) Complete(ctx context.Context, id uuid.UUID, workerId string) error {
+	_, err := r.DB.Exec(ctx, `
+		UPDATE jobs
+		SET status = $3, last_error = '', locked_at = NULL, locked_by = NULL, finished_at = $4, updated_at = $4
+		WHERE id = $1 AND locked_by = $2`,
+		id,
+		workerId,
+		models.StatusSucceeded,
+		time.Now(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to complete job: %w", err)
+	}
+
+	return nil
+}
+
+/*
+Fail is a method of jobRepository struct that records a failed attempt of a claimed job.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- id (uuid.UUID): The ID of the job.
+- workerId (string): The ID of the worker that ran the job.
+- message (string): The error the attempt failed with.
+- runAt (time.Time): When the next attempt is due.
+- dead (bool): Whether the job ran out of attempts and moves to the dead-letter state instead.
+
+Returns:
+- (error): An error if the update fails.
+*/
+func (r *jobRepository) Fail(ctx context.Context, id uuid.UUID, workerId, message string, runAt time.Time, dead bool) error {
+	now := time.Now()
+	status, finishedAt := models.StatusQueued, (*time.Time)(nil)
+	if dead {
+		status, finishedAt = models.StatusDead, &now
+	}
+
+	_, err := r.DB.Exec(ctx, `
+		UPDATE jobs
+		SET status = $3, last_error = $4, run_at = $5, locked_at = NULL, locked_by = NULL, finished_at = $6, updated_at = $7
+		WHERE id = $1 AND locked_by = $2`,
+		id,
+		workerId,
+		status,
+		message,
+		runAt,
+		finishedAt,
+		now,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record job failure: %w", err)
+	}
+
+	return nil
+}
+
+/*
+Requeue is a method of jobRepository struct that takes a job out of the dead-letter state.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- id (uuid.UUID): The ID of the job.
+
+Returns:
+- (*models.JobDTO, error): A pointer to a JobDTO struct containing the queued job, or an error if the update fails.
+
+Behavior:
+- The job gets a fresh set of attempts and is due right away.
+- Returns models.ErrNotDead if the job is not dead.
+*/
+func (r *jobRepository) Requeue(ctx context.Context, id uuid.UUID) (*models.JobDTO, error) {
+	job, err := scanJob(r.DB.QueryRow(ctx, `
+		UPDATE jobs
+		SET status = $2, attempts = 0, run_at = $4, finished_at = NULL, updated_at = $4
+		WHERE id = $1 AND status = $3
+		RETURNING `+jobColumns,
+		id,
+		models.StatusQueued,
+		models.StatusDead,
+		time.Now(),
+	))
+	if errors.Is(err, models.ErrJobNotFound) {
+		if _, err := r.GetJob(ctx, id); err != nil {
+			return nil, err
+		}
+		return nil, models.ErrNotDead
+	}
+
+	return job, err
+}
+
+/*
+Prune is a method of jobRepository struct that deletes old succeeded jobs from the postgres database.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- finishedBefore (time.Time): Succeeded jobs finished before this time are deleted.
+
+Returns:
+- (int64, error): The number of jobs deleted, or an error if the deletion fails.
+
+Behavior:
+- Dead jobs are kept until they are retried.
+*/
+func (r *jobRepository) Prune(ctx context.Context, finishedBefore time.Time) (int64, error) {
+	tag, err := r.DB.Exec(ctx, `DELETE FROM jobs WHERE status = $1 AND finished_at < $2`, models.StatusSucceeded, finishedBefore)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune jobs: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+/*
+SaveSchedule is a method of jobRepository struct that creates or updates a recurring job in the postgres database.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- schedule (*models.ScheduleDTO): A pointer to a ScheduleDTO struct containing the name, kind, payload, spec and first run time.
+
+Returns:
+- (error): An error if the upsert fails.
+
+Behavior:
+- An existing schedule whose spec did not change keeps its next run time, so restarts do not postpone it.
+*/
+func (r *jobRepository) SaveSchedule(ctx context.Context, schedule *models.ScheduleDTO) error {
+	_, err := r.DB.Exec(ctx, `
+		INSERT INTO job_schedules (name, kind, payload, spec, next_run_at, created_at, updated_at)
+		VALUES ($1, $2, $3::JSONB, $4, $5, $6, $6)
+		ON CONFLICT (name) DO UPDATE SET
+			kind = EXCLUDED.kind,
+			payload = EXCLUDED.payload,
+			spec = EXCLUDED.spec,
+			next_run_at = CASE WHEN job_schedules.spec = EXCLUDED.spec THEN job_schedules.next_run_at ELSE EXCLUDED.next_run_at END,
+			updated_at = EXCLUDED.updated_at`,
+		schedule.Name,
+		schedule.Kind,
+		payloadText(schedule.Payload),
+		schedule.Spec,
+		schedule.NextRunAt,
+		time.Now(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save job schedule: %w", err)
+	}
+
+	return nil
+}
+
+/*
+DeleteSchedule is a method of jobRepository struct that removes a recurring job from the postgres database.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- name (string): The name of the schedule.
+
+Returns:
+- (error): An error if the deletion fails. Deleting a schedule that does not exist is not an error.
+*/
+func (r *jobRepository) DeleteSchedule(ctx context.Context, name string) error {
+	if _, err := r.DB.Exec(ctx, `DELETE FROM job_schedules WHERE name = $1`, name); err != nil {
+		return fmt.Errorf("failed to delete job schedule: %w", err)
+	}
+
+	return nil
+}
+
+/*
+GetSchedule is a method of jobRepository struct that retrieves a recurring job from the postgres database by its name.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- name (string): The name of the schedule.
+
+Returns:
+- (*models.ScheduleDTO, error): A pointer to a ScheduleDTO struct, or models.ErrScheduleNotFound if there is none.
+*/
+func (r *jobRepository) GetSchedule(ctx context.Context, name string) (*models.ScheduleDTO, error) {
+	return scanSchedule(r.DB.QueryRow(ctx, `SELECT `+scheduleColumns+` FROM job_schedules WHERE name = $1`, name))
+}
+
+/*
+GetSchedules is a method of jobRepository struct that retrieves every recurring job from the postgres database.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+
+Returns:
+- ([]*models.ScheduleDTO, error): A slice of ScheduleDTO structs ordered by name, or an error if the retrieval fails.
+*/
+func (r *jobRepository) GetSchedules(ctx context.Context) ([]*models.ScheduleDTO, error) {
+	return r.querySchedules(ctx, `SELECT `+scheduleColumns+` FROM job_schedules ORDER BY name`)
+}
+
+/*
+GetDueSchedules is a method of jobRepository struct that retrieves the recurring jobs due to run.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- now (time.Time): Schedules whose next run time is not after this time are due.
+
+Returns:
+- ([]*models.ScheduleDTO, error): A slice of ScheduleDTO structs, longest due first, or an error if the retrieval fails.
+*/
+func (r *jobRepository) GetDueSchedules(ctx context.Context, now time.Time) ([]*models.ScheduleDTO, error) {
+	return r.querySchedules(ctx, `SELECT `+scheduleColumns+` FROM job_schedules WHERE next_run_at <= $1 ORDER BY next_run_at`, now)
+}
+
+/*
+FireSchedule is a method of jobRepository struct that queues the job of a due schedule and moves the schedule to its next run.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- schedule (*models.ScheduleDTO): A pointer to a ScheduleDTO struct, as returned by GetDueSchedules.
+- nextRunAt (time.Time): When the schedule is due next.
+
+Returns:
+- (*models.JobDTO, error): A pointer to a JobDTO struct containing the queued job, nil if another worker fired the schedule first, or an error if the transaction fails.
+
+Behavior:
+- The schedule only moves if its next run time is still the one that was read, so each run is queued once however many workers are polling.
+*/
+func (r *jobRepository) FireSchedule(ctx context.Context, schedule *models.ScheduleDTO, nextRunAt time.Time) (*models.JobDTO, error) {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE job_schedules
+		SET next_run_at = $3, last_run_at = $4, updated_at = $4
+		WHERE name = $1 AND next_run_at = $2`,
+		schedule.Name,
+		schedule.NextRunAt,
+		nextRunAt,
+		time.Now(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to advance job schedule: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, nil
+	}
+
+	job, err := enqueue(ctx, tx, &models.EnqueueJobDTO{Kind: schedule.Kind, Payload: schedule.Payload}, schedule.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return job, nil
+}
+
+func (r *jobRepository) querySchedules(ctx context.Context, query string, args ...any) ([]*models.ScheduleDTO, error) {
+	rows, err := r.DB.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job schedules: %w", err)
+	}
+	defer rows.Close()
+
+	schedules := []*models.ScheduleDTO{}
+	for rows.Next() {
+		schedule, err := scanSchedule(rows)
+		if err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, schedule)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over job schedules: %w", err)
+	}
+
+	return schedules, nil
+}
+
+// queryRower is satisfied by both the pool and a transaction.
+type queryRower interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+func enqueue(ctx context.Context, db queryRower, job *models.EnqueueJobDTO, scheduleName string) (*models.JobDTO, error) {
+	runAt := time.Now()
+	if job.RunAt != nil {
+		runAt = *job.RunAt
+	}
+	maxAttempts := job.MaxAttempts
+	if maxAttempts == 0 {
+		maxAttempts = models.DefaultMaxAttempts
+	}
+	var schedule *string
+	if scheduleName != "" {
+		schedule = &scheduleName
+	}
+
+	created, err := scanJob(db.QueryRow(ctx, `
+		INSERT INTO jobs (kind, payload, status, max_attempts, run_at, schedule_name, created_at, updated_at)
+		VALUES ($1, $2::JSONB, $3, $4, $5, $6, $7, $7)
+		RETURNING `+jobColumns,
+		job.Kind,
+		payloadText(job.Payload),
+		models.StatusQueued,
+		maxAttempts,
+		runAt,
+		schedule,
+		time.Now(),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to enqueue job: %w", err)
+	}
+
+	return created, nil
+}
+
+func payloadText(payload json.RawMessage) string {
+	if len(payload) == 0 {
+		return "{}"
+	}
+
+	return string(payload)
+}
+
+func scanJob(row pgx.Row) (*models.JobDTO, error) {
+	var job models.JobDTO
+	var payload string
+	err := row.Scan(
+		&job.ID,
+		&job.Kind,
+		&payload,
+		&job.Status,
+		&job.Attempts,
+		&job.MaxAttempts,
+		&job.RunAt,
+		&job.LockedAt,
+		&job.LockedBy,
+		&job.LastError,
+		&job.ScheduleName,
+		&job.FinishedAt,
+		&job.CreatedAt,
+		&job.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, models.ErrJobNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan job: %w", err)
+	}
+
+	job.Payload = json.RawMessage(payload)
+	return &job, nil
+}
+
+func scanSchedule(row pgx.Row) (*models.ScheduleDTO, error) {
+	var schedule models.ScheduleDTO
+	var payload string
+	err := row.Scan(
+		&schedule.Name,
+		&schedule.Kind,
+		&payload,
+		&schedule.Spec,
+		&schedule.NextRunAt,
+		&schedule.LastRunAt,
+		&schedule.CreatedAt,
+		&schedule.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, models.ErrScheduleNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan job schedule: %w", err)
+	}
+
+	schedule.Payload = json.RawMessage(payload)
+	return &schedule, nil
+}
@@ -0,0 +1,232 @@
+package jobs
+
+import (
+	"blockbustermvc/internal/logging"
+	models "blockbustermvc/internal/models/job"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// PruneJob is the job kind that deletes old succeeded jobs.
+	PruneJob = "jobs.prune"
+
+	// lockTimeout is how long a job may run before it is considered
+	// abandoned by a crashed worker and claimed again.
+	lockTimeout = 30 * time.Minute
+
+	// listLimit caps how many jobs are listed at once.
+	listLimit = 200
+
+	defaultKeepDays = 7
+)
+
+// PrunePayload is the payload of a PruneJob.
+type PrunePayload struct {
+	KeepDays int `json:"keep_days"`
+}
+
+type JobService struct {
+	jobRepository models.IJobRepository
+	handlers      map[string]models.Handler
+}
+
+// NewJobService builds the service with the PruneJob handler registered;
+// modules register their own kinds with Register.
+func NewJobService(jobRepo models.IJobRepository) models.IJobService {
+	service := &JobService{
+		jobRepository: jobRepo,
+		handlers:      make(map[string]models.Handler),
+	}
+	service.Register(PruneJob, Typed(service.prune))
+
+	return service
+}
+
+// Typed wraps a handler that takes the job payload decoded into T.
+func Typed[T any](handle func(ctx context.Context, payload *T) error) models.Handler {
+	return func(ctx context.Context, job *models.JobDTO) error {
+		var payload T
+		if len(job.Payload) > 0 {
+			if err := json.Unmarshal(job.Payload, &payload); err != nil {
+				return fmt.Errorf("invalid %s payload: %w", job.Kind, err)
+			}
+		}
+
+		return handle(ctx, &payload)
+	}
+}
+
+// Register sets the handler of a job kind. It must be called before the
+// worker starts.
+func (j JobService) Register(kind string, handler models.Handler) {
+	j.handlers[kind] = handler
+}
+
+func (j JobService) Kinds() []string {
+	kinds := make([]string, 0, len(j.handlers))
+	for kind := range j.handlers {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+
+	return kinds
+}
+
+// Schedule creates or updates a recurring job. A schedule whose spec did
+// not change keeps its next run time.
+func (j JobService) Schedule(ctx context.Context, name, kind, spec string) error {
+	if _, ok := j.handlers[kind]; !ok {
+		return fmt.Errorf("%w: %s", models.ErrUnknownKind, kind)
+	}
+
+	parsed, err := parseSchedule(spec)
+	if err != nil {
+		return err
+	}
+
+	return j.jobRepository.SaveSchedule(ctx, &models.ScheduleDTO{
+		Name:      name,
+		Kind:      kind,
+		Spec:      spec,
+		NextRunAt: parsed.Next(time.Now()),
+	})
+}
+
+func (j JobService) Unschedule(ctx context.Context, name string) error {
+	return j.jobRepository.DeleteSchedule(ctx, name)
+}
+
+func (j JobService) Enqueue(ctx context.Context, job *models.EnqueueJobDTO) (*models.JobDTO, error) {
+	if _, ok := j.handlers[job.Kind]; !ok {
+		return nil, fmt.Errorf("%w: %s", models.ErrUnknownKind, job.Kind)
+	}
+
+	return j.jobRepository.Enqueue(ctx, job, "")
+}
+
+func (j JobService) GetJobs(ctx context.Context, status string) ([]*models.JobDTO, error) {
+	return j.jobRepository.GetJobs(ctx, status, listLimit)
+}
+
+func (j JobService) GetJob(ctx context.Context, id uuid.UUID) (*models.JobDTO, error) {
+	return j.jobRepository.GetJob(ctx, id)
+}
+
+func (j JobService) Retry(ctx context.Context, id uuid.UUID) (*models.JobDTO, error) {
+	return j.jobRepository.Requeue(ctx, id)
+}
+
+func (j JobService) GetSchedules(ctx context.Context) ([]*models.ScheduleDTO, error) {
+	return j.jobRepository.GetSchedules(ctx)
+}
+
+// RunSchedule queues the job of a schedule right away, without moving its
+// next run.
+func (j JobService) RunSchedule(ctx context.Context, name string) (*models.JobDTO, error) {
+	schedule, err := j.jobRepository.GetSchedule(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return j.jobRepository.Enqueue(ctx, &models.EnqueueJobDTO{Kind: schedule.Kind, Payload: schedule.Payload}, schedule.Name)
+}
+
+// EnqueueDueSchedules queues a job for every schedule that is due. Runs
+// missed while no worker was up are queued once, not once per miss.
+func (j JobService) EnqueueDueSchedules(ctx context.Context) (int, error) {
+	now := time.Now()
+
+	schedules, err := j.jobRepository.GetDueSchedules(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	queued := 0
+	for _, schedule := range schedules {
+		parsed, err := parseSchedule(schedule.Spec)
+		if err != nil {
+			slog.WarnContext(ctx, "Skipping job schedule", "schedule", schedule.Name, "error", err)
+			continue
+		}
+
+		job, err := j.jobRepository.FireSchedule(ctx, schedule, parsed.Next(now))
+		if err != nil {
+			return queued, err
+		}
+		if job != nil {
+			queued++
+		}
+	}
+
+	return queued, nil
+}
+
+// RunNext claims the next due job and runs it. It reports whether a job
+// was found, so workers know when to wait for more.
+func (j JobService) RunNext(ctx context.Context, workerId string) (bool, error) {
+	job, err := j.jobRepository.Claim(ctx, workerId, j.Kinds(), time.Now().Add(-lockTimeout))
+	if err != nil || job == nil {
+		return false, err
+	}
+
+	// Everything logged while the job runs says which job it was
+	ctx = logging.WithAttrs(ctx, slog.String("job_id", job.ID.String()), slog.String("job_kind", job.Kind))
+
+	runErr := j.run(ctx, job)
+
+	// The outcome is recorded even when the worker is stopping, so a job
+	// that ran is not run again
+	ctx = context.WithoutCancel(ctx)
+	if runErr == nil {
+		return true, j.jobRepository.Complete(ctx, job.ID, workerId)
+	}
+
+	dead := job.Attempts >= job.MaxAttempts
+	if dead {
+		slog.ErrorContext(ctx, "Job failed for good", "attempts", job.Attempts, "error", runErr)
+	} else {
+		slog.WarnContext(ctx, "Job failed, will retry", "attempt", job.Attempts, "error", runErr)
+	}
+
+	return true, j.jobRepository.Fail(ctx, job.ID, workerId, runErr.Error(), time.Now().Add(models.RetryDelay(job.Attempts)), dead)
+}
+
+func (j JobService) run(ctx context.Context, job *models.JobDTO) (err error) {
+	handler, ok := j.handlers[job.Kind]
+	if !ok {
+		return fmt.Errorf("%w: %s", models.ErrUnknownKind, job.Kind)
+	}
+
+	// A panicking handler fails its job instead of the worker
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("panic: %v", recovered)
+		}
+	}()
+
+	return handler(ctx, job)
+}
+
+func (j JobService) prune(ctx context.Context, payload *PrunePayload) error {
+	keepDays := payload.KeepDays
+	if keepDays <= 0 {
+		keepDays = defaultKeepDays
+	}
+
+	pruned, err := j.jobRepository.Prune(ctx, time.Now().AddDate(0, 0, -keepDays))
+	if err != nil {
+		return err
+	}
+	if pruned > 0 {
+		slog.InfoContext(ctx, "Pruned succeeded jobs", "pruned", pruned)
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package jobs
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// minStallTimeout is the least time a running worker may go without polling
+// before it counts as stalled.
+const minStallTimeout = 30 * time.Second
+
+// WorkerStatus follows a worker running in this process for the readiness
+// check: it is healthy while running and polling for due schedules.
+type WorkerStatus struct {
+	mu       sync.Mutex
+	running  bool
+	interval time.Duration
+	polledAt time.Time
+}
+
+func NewWorkerStatus() *WorkerStatus {
+	return &WorkerStatus{}
+}
+
+func (s *WorkerStatus) Name() string {
+	return "jobs"
+}
+
+// Check returns why the worker is not healthy, or nil.
+func (s *WorkerStatus) Check() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.running {
+		return errors.New("job worker is not running")
+	}
+
+	stallTimeout := max(3*s.interval, minStallTimeout)
+	if since := time.Since(s.polledAt); since > stallTimeout {
+		return fmt.Errorf("job worker has not polled for %s", since.Round(time.Second))
+	}
+
+	return nil
+}
+
+func (s *WorkerStatus) started(interval time.Duration) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.running = true
+	s.interval = interval
+	s.polledAt = time.Now()
+}
+
+func (s *WorkerStatus) polled() {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.polledAt = time.Now()
+}
+
+func (s *WorkerStatus) stopped() {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.running = false
+}
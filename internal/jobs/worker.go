@@ -0,0 +1,89 @@
+package jobs
+
+import (
+	models "blockbustermvc/internal/models/job"
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// pruneSpec is when old succeeded jobs are deleted.
+const pruneSpec = "0 4 * * *"
+
+// WorkerConfig configures a worker. Status, when set, follows the worker
+// for the readiness check.
+type WorkerConfig struct {
+	ID           string
+	Concurrency  int
+	PollInterval time.Duration
+	Status       *WorkerStatus
+}
+
+// RunWorker queues the jobs of due schedules and runs due jobs, with
+// config.Concurrency jobs at a time, until ctx is cancelled. Jobs already
+// running are finished before it returns.
+func RunWorker(ctx context.Context, service models.IJobService, config WorkerConfig) {
+	if err := service.Schedule(ctx, PruneJob, PruneJob, pruneSpec); err != nil {
+		slog.Error("Failed to schedule job pruning", "error", err)
+	}
+
+	slog.Info("Job worker started", "worker", config.ID, "slots", config.Concurrency, "kinds", service.Kinds())
+	config.Status.started(config.PollInterval)
+	defer config.Status.stopped()
+
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		poll(ctx, config.PollInterval, func() bool {
+			if _, err := service.EnqueueDueSchedules(ctx); err != nil {
+				slog.Error("Failed to queue scheduled jobs", "worker", config.ID, "error", err)
+			}
+			config.Status.polled()
+			return false
+		})
+	}()
+
+	for slot := 1; slot <= config.Concurrency; slot++ {
+		workerId := fmt.Sprintf("%s/%d", config.ID, slot)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			poll(ctx, config.PollInterval, func() bool {
+				ran, err := service.RunNext(ctx, workerId)
+				if err != nil {
+					slog.Error("Job worker failed to run a job", "worker", workerId, "error", err)
+				}
+				return ran && err == nil
+			})
+		}()
+	}
+
+	wg.Wait()
+	slog.Info("Job worker stopped", "worker", config.ID)
+}
+
+// poll calls step until ctx is cancelled, right away while step reports
+// there is more work and after interval otherwise.
+func poll(ctx context.Context, interval time.Duration, step func() bool) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		wait := interval
+		if step() {
+			wait = 0
+		}
+		timer.Reset(wait)
+	}
+}
@@ -0,0 +1,77 @@
+package live
+
+import (
+	models "blockbustermvc/internal/models/event"
+	"sync"
+)
+
+// subscriberBuffer is how many events a subscriber may fall behind before
+// further events are dropped for it.
+const subscriberBuffer = 64
+
+// Hub fans the events heard by the listener out to every subscriber of
+// this process.
+type Hub struct {
+	mu          sync.Mutex
+	subscribers map[chan *models.EventDTO]struct{}
+	closed      bool
+}
+
+func NewHub() *Hub {
+	return &Hub{
+		subscribers: make(map[chan *models.EventDTO]struct{}),
+	}
+}
+
+// Subscribe returns a channel of the events published from now on, and a
+// function that stops them and closes the channel. Once the hub is closed
+// the channel comes closed.
+func (h *Hub) Subscribe() (<-chan *models.EventDTO, func()) {
+	events := make(chan *models.EventDTO, subscriberBuffer)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		close(events)
+		return events, func() {}
+	}
+	h.subscribers[events] = struct{}{}
+
+	return events, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+
+		if _, ok := h.subscribers[events]; ok {
+			delete(h.subscribers, events)
+			close(events)
+		}
+	}
+}
+
+// Close closes the channel of every subscriber, ending their streams, so
+// the server can shut down without waiting for them.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for events := range h.subscribers {
+		delete(h.subscribers, events)
+		close(events)
+	}
+}
+
+// Publish hands an event to every subscriber. A subscriber that is too
+// far behind misses it rather than holding up the others.
+func (h *Hub) Publish(event *models.EventDTO) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for events := range h.subscribers {
+		select {
+		case events <- event:
+		default:
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package live
+
+import (
+	models "blockbustermvc/internal/models/event"
+	"blockbustermvc/internal/outbox"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	// minReconnectDelay and maxReconnectDelay bound the wait before the
+	// listener connects again after losing its connection.
+	minReconnectDelay = time.Second
+	maxReconnectDelay = 30 * time.Second
+)
+
+// RunListener listens on the outbox channel and publishes every announced
+// event to hub until ctx is cancelled. A lost connection is opened again;
+// events announced in the meantime are missed.
+func RunListener(ctx context.Context, pool *pgxpool.Pool, hub *Hub) {
+	delay := minReconnectDelay
+
+	for {
+		started := time.Now()
+		err := listen(ctx, pool, hub)
+		if ctx.Err() != nil {
+			return
+		}
+
+		// A connection that lasted a while starts the backoff over
+		if time.Since(started) > maxReconnectDelay {
+			delay = minReconnectDelay
+		}
+		slog.WarnContext(ctx, "Live event listener stopped, reconnecting", "delay", delay.String(), "error", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, maxReconnectDelay)
+	}
+}
+
+func listen(ctx context.Context, pool *pgxpool.Pool, hub *Hub) error {
+	pooled, err := pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+
+	// The connection keeps listening for as long as it lives, so it is
+	// taken out of the pool and closed when done
+	conn := pooled.Hijack()
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{outbox.NotifyChannel}.Sanitize()); err != nil {
+		return err
+	}
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		var event models.EventDTO
+		if err := json.Unmarshal([]byte(notification.Payload), &event); err != nil {
+			slog.WarnContext(ctx, "Ignoring malformed event announcement", "error", err)
+			continue
+		}
+		hub.Publish(&event)
+	}
+}
@@ -1,8 +1,12 @@
 package loans
 
 import (
+	"blockbustermvc/internal/httputil"
 	models "blockbustermvc/internal/models/loans"
+	"errors"
+	"log/slog"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -24,6 +28,8 @@ func (lc *LoansController) RegisterRoutes(r *gin.RouterGroup) {
 	{
 		loans.POST("", lc.CreateLoan)
 		loans.PUT("/:id/return", lc.ReturnMovie)
+		loans.POST("/returns/barcode", lc.ReturnByBarcode)
+		loans.POST("/returns/dropbox", lc.ProcessDropBox)
 		loans.GET("/:id", lc.GetLoan)
 		loans.GET("", lc.GetAllLoans)
 	}
@@ -36,8 +42,9 @@ func (lc *LoansController) RegisterRoutes(r *gin.RouterGroup) {
 
 func (lc *LoansController) CreateLoan(ctx *gin.Context) {
 	var req struct {
-		MovieId string `json:"movie_id"`
-		UserId  string `json:"user_id"`
+		MovieId     string `json:"movie_id"`
+		UserId      string `json:"user_id"`
+		CopyBarcode string `json:"copy_barcode"`
 	}
 
 	if err := ctx.ShouldBindJSON(&req); err != nil {
@@ -63,14 +70,21 @@ func (lc *LoansController) CreateLoan(ctx *gin.Context) {
 		return
 	}
 
-	loan, err := lc.loanService.CreateLoan(movieId, userId)
+	loan, err := lc.loanService.CreateLoan(ctx.Request.Context(), httputil.StoreID(ctx), movieId, userId, req.CopyBarcode)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{
+		status := http.StatusBadRequest
+		if errors.Is(err, models.ErrCopyOnLoan) {
+			status = http.StatusConflict
+		}
+
+		ctx.JSON(status, gin.H{
 			"error": err.Error(),
 		})
 		return
 	}
 
+	ctx.Header("Location", ctx.FullPath()+"/"+loan.ID.String())
+	ctx.Header("ETag", httputil.ETag(loan.Version))
 	ctx.JSON(http.StatusCreated, loan)
 }
 
@@ -90,20 +104,33 @@ func (lc *LoansController) GetLoan(ctx *gin.Context) {
 		return
 	}
 
-	loan, err := lc.loanService.GetLoan(id)
+	archived, ok := includeArchived(ctx)
+	if !ok {
+		return
+	}
+
+	loan, err := lc.loanService.GetLoan(ctx.Request.Context(), id, archived)
 	if err != nil {
+		slog.ErrorContext(ctx.Request.Context(), "Failed to get loan", "error", err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{
 			"error": err.Error(),
 		})
 		return
 	}
 
+	ctx.Header("ETag", httputil.ETag(loan.Version))
 	ctx.JSON(http.StatusOK, loan)
 }
 
 func (lc *LoansController) GetAllLoans(ctx *gin.Context) {
-	loans, err := lc.loanService.GetAllLoans()
+	archived, ok := includeArchived(ctx)
+	if !ok {
+		return
+	}
+
+	loans, err := lc.loanService.GetStoreLoans(ctx.Request.Context(), httputil.StoreID(ctx), archived)
 	if err != nil {
+		slog.ErrorContext(ctx.Request.Context(), "Failed to get all loans", "error", err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{
 			"error": err.Error(),
 		})
@@ -129,8 +156,14 @@ func (lc *LoansController) GetUserLoans(ctx *gin.Context) {
 		return
 	}
 
-	loans, err := lc.loanService.GetUserLoans(id)
+	archived, ok := includeArchived(ctx)
+	if !ok {
+		return
+	}
+
+	loans, err := lc.loanService.GetUserLoans(ctx.Request.Context(), id, archived)
 	if err != nil {
+		slog.ErrorContext(ctx.Request.Context(), "Failed to get user loans", "error", err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{
 			"error": err.Error(),
 		})
@@ -156,12 +189,90 @@ func (lc *LoansController) ReturnMovie(ctx *gin.Context) {
 		return
 	}
 
-	if err = lc.loanService.ReturnMovie(id); err != nil {
+	version, err := httputil.RequiredVersion(ctx, 0)
+	if err != nil {
+		ctx.JSON(versionErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	loan, err := lc.loanService.ReturnMovie(ctx.Request.Context(), httputil.StoreID(ctx), id, version)
+	if err != nil {
+		if errors.Is(err, models.ErrVersionConflict) {
+			ctx.JSON(http.StatusPreconditionFailed, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		slog.ErrorContext(ctx.Request.Context(), "Failed to return movie", "error", err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{
 			"error": err.Error(),
 		})
 		return
 	}
 
-	ctx.JSON(http.StatusOK, nil)
+	ctx.Header("ETag", httputil.ETag(loan.Version))
+	ctx.JSON(http.StatusOK, loan)
+}
+
+func (lc *LoansController) ReturnByBarcode(ctx *gin.Context) {
+	var req models.BarcodeReturnDTO
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body",
+		})
+		return
+	}
+
+	loan, err := lc.loanService.ReturnByCode(ctx.Request.Context(), httputil.StoreID(ctx), req.Code, req.ReturnedAt)
+	if err != nil {
+		status := http.StatusUnprocessableEntity
+		if errors.Is(err, models.ErrAmbiguousReturn) {
+			status = http.StatusConflict
+		}
+
+		ctx.JSON(status, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, loan)
+}
+
+func (lc *LoansController) ProcessDropBox(ctx *gin.Context) {
+	var req models.DropBoxReturnDTO
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, lc.loanService.ProcessDropBox(ctx.Request.Context(), httputil.StoreID(ctx), &req))
+}
+
+// includeArchived reads whether the archived loans are wanted too, from the
+// include_archived query parameter.
+func includeArchived(ctx *gin.Context) (bool, bool) {
+	archived, err := strconv.ParseBool(ctx.DefaultQuery("include_archived", "false"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "include_archived must be true or false",
+		})
+		return false, false
+	}
+
+	return archived, true
+}
+
+func versionErrorStatus(err error) int {
+	if errors.Is(err, httputil.ErrPreconditionRequired) {
+		return http.StatusPreconditionRequired
+	}
+	return http.StatusBadRequest
 }
@@ -0,0 +1,86 @@
+package loans
+
+import (
+	jobModels "blockbustermvc/internal/models/job"
+	models "blockbustermvc/internal/models/loans"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	// PartitionJob is the job kind that creates the coming loans partitions.
+	PartitionJob = "loans.partition"
+	// ArchiveJob is the job kind that moves old returned loans to the archive.
+	ArchiveJob = "loans.archive"
+
+	partitionSpec = "0 3 * * *"
+	archiveSpec   = "30 3 * * *"
+
+	// defaultArchiveYears is used when BLK_LOANS_ARCHIVE_YEARS is unset.
+	defaultArchiveYears = 3
+)
+
+// ConfiguredArchiveYears reads how many years returned loans are kept before
+// they are archived from BLK_LOANS_ARCHIVE_YEARS. Zero disables archiving.
+func ConfiguredArchiveYears() (int, error) {
+	value := os.Getenv("BLK_LOANS_ARCHIVE_YEARS")
+	if value == "" {
+		return defaultArchiveYears, nil
+	}
+
+	years, err := strconv.Atoi(value)
+	if err != nil || years < 0 {
+		return 0, fmt.Errorf("invalid BLK_LOANS_ARCHIVE_YEARS %q: expected a number of years such as 3", value)
+	}
+
+	return years, nil
+}
+
+// RegisterJobs lets the job worker create the loans partitions ahead of time
+// and archive the loans returned more than archiveYears ago, every night.
+// Zero years removes the archive schedule.
+func RegisterJobs(ctx context.Context, jobService jobModels.IJobService, service models.ILoanService, archiveYears int) error {
+	jobService.Register(PartitionJob, func(ctx context.Context, _ *jobModels.JobDTO) error {
+		created, err := service.EnsurePartitions(ctx)
+		if err != nil {
+			return err
+		}
+		if len(created) > 0 {
+			slog.InfoContext(ctx, "Created loans partitions", "partitions", created)
+		}
+		return nil
+	})
+
+	jobService.Register(ArchiveJob, func(ctx context.Context, _ *jobModels.JobDTO) error {
+		if archiveYears == 0 {
+			return nil
+		}
+
+		result, err := service.ArchiveLoans(ctx, time.Now().AddDate(-archiveYears, 0, 0))
+		if err != nil {
+			return err
+		}
+		if result.Archived > 0 || len(result.DroppedPartitions) > 0 {
+			slog.InfoContext(ctx, "Archived returned loans",
+				"archived", result.Archived,
+				"before", result.Before.Format(time.DateOnly),
+				"dropped_partitions", result.DroppedPartitions,
+			)
+		}
+		return nil
+	})
+
+	if err := jobService.Schedule(ctx, PartitionJob, PartitionJob, partitionSpec); err != nil {
+		return err
+	}
+
+	if archiveYears == 0 {
+		return jobService.Unschedule(ctx, ArchiveJob)
+	}
+
+	return jobService.Schedule(ctx, ArchiveJob, ArchiveJob, archiveSpec)
+}
@@ -1,12 +1,20 @@
 package loans
 
 import (
+	"blockbustermvc/internal/inventory"
+	eventModels "blockbustermvc/internal/models/event"
+	inventoryModels "blockbustermvc/internal/models/inventory"
 	models "blockbustermvc/internal/models/loans"
+	"blockbustermvc/internal/outbox"
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -33,111 +41,201 @@ func NewLoanRepository(db *pgxpool.Pool) models.ILoanRepository {
 CreateLoan is a method of loanRepository struct that creates a new loan object in the postgres database.
 
 Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
 - loan (*models.CreateLoanDTO): A pointer to a CreateLoanDTO struct containing the loan data to be created.
 
 Returns:
-- error: An error if the loan creation fails, otherwise nil.
+- (*models.LoanDTO, error): A pointer to a LoanDTO struct containing the persisted loan, or an error if the loan creation fails.
 
 Behavior:
-- Inserts a new loan into the loans table in the database.
+- Inserts a new loan into the loans table in the database, tied to the store it is checked out at.
+- Records the lent copy when one is given, and returns models.ErrCopyOnLoan if that copy is already out.
+- Charges the movie's current rental price, so later price changes leave past revenue alone.
+- Takes the copy out of the store's stock through inventory.ApplyMovement and writes a loan.created event to the outbox, all in the same transaction as the loan.
+- Returns inventoryModels.ErrInsufficientStock if the store has no copy left to lend.
+- Returns the stored row, including the generated ID, status and timestamps.
 - Returns an error if the loan creation fails.
 */
-func (r *loanRepository) CreateLoan(loan *models.CreateLoanDTO) error {
+func (r *loanRepository) CreateLoan(ctx context.Context, loan *models.CreateLoanDTO) (*models.LoanDTO, error) {
 	query := `
-		INSERT INTO loans (movie_id, user_id, borrowed_at, status, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6)`
+		INSERT INTO loans (movie_id, user_id, store_id, copy_id, borrowed_at, status, price_cents, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, COALESCE((SELECT rental_price_cents FROM movies WHERE id = $1), 0), $7, $8)
+		RETURNING id, movie_id, user_id, store_id, return_store_id, copy_id, borrowed_at, returned_at, status, version, created_at, updated_at`
+
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin loan creation: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if loan.CopyID != nil {
+		// Locking the copy serialises checkouts of it, so two desks cannot lend it at once
+		var onLoan bool
+		err = tx.QueryRow(ctx, `
+			SELECT EXISTS (SELECT 1 FROM loans WHERE copy_id = c.id AND status = 'active')
+			FROM copies c
+			WHERE c.id = $1
+			FOR UPDATE OF c`, loan.CopyID).Scan(&onLoan)
+		if err != nil {
+			return nil, fmt.Errorf("failed to lock copy: %w", err)
+		}
+		if onLoan {
+			return nil, models.ErrCopyOnLoan
+		}
+	}
 
 	now := time.Now()
 
-	_, err := r.DB.Exec(context.Background(), query,
+	var created models.LoanDTO
+	var returnedAt *time.Time
+
+	err = tx.QueryRow(ctx, query,
 		loan.MovieID,
 		loan.UserID,
+		loan.StoreID,
+		loan.CopyID,
 		now,
 		"active",
 		now,
 		now,
+	).Scan(
+		&created.ID,
+		&created.MovieID,
+		&created.UserID,
+		&created.StoreID,
+		&created.ReturnStoreID,
+		&created.CopyID,
+		&created.BorrowedAt,
+		&returnedAt,
+		&created.Status,
+		&created.Version,
+		&created.CreatedAt,
+		&created.UpdatedAt,
 	)
 	if err != nil {
-		return fmt.Errorf("failed to create loan: %w", err)
+		return nil, fmt.Errorf("failed to create loan: %w", err)
 	}
 
-	return nil
+	if returnedAt != nil {
+		created.ReturnedAt = *returnedAt
+	}
+
+	_, err = inventory.ApplyMovement(ctx, tx, created.MovieID, &inventoryModels.CreateMovementDTO{
+		Reason:        inventoryModels.ReasonLoan,
+		QuantityDelta: -1,
+		StoreID:       created.StoreID,
+		LoanID:        &created.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = outbox.Write(ctx, tx, eventModels.TypeLoanCreated, created.ID, &eventModels.LoanCreatedPayload{
+		LoanID:     created.ID,
+		MovieID:    created.MovieID,
+		UserID:     created.UserID,
+		StoreID:    created.StoreID,
+		BorrowedAt: created.BorrowedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit loan creation: %w", err)
+	}
+
+	return &created, nil
 }
 
 /*
 UpdateLoan is a method of loanRepository struct that updates a loan object in the postgres database.
 
 Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
 - loan (*models.LoanDTO): A pointer to a LoanDTO struct containing the loan data to be updated.
 
 Returns:
 - error: An error if the loan update fails, otherwise nil.
 
 Behavior:
-- Updates a loan in the loans table in the database.
+- Updates a loan in the loans table in the database and increments its version.
+- When the update returns an active loan, puts the copy back into the returning store's stock through inventory.ApplyMovement and writes a movie.returned event to the outbox, in the same transaction as the loan.
+- When loan.Version is set, the update only applies if the stored version still matches it.
+- Returns models.ErrVersionConflict if the loan was changed since that version was read.
 - Returns an error if the loan update fails.
 */
-func (r *loanRepository) UpdateLoan(loan *models.LoanDTO) error {
+func (r *loanRepository) UpdateLoan(ctx context.Context, loan *models.LoanDTO) error {
 	query := `
-		UPDATE loans
-		SET returned_at = $2, status = $3, updated_at = $4
-		WHERE id = $1`
+		UPDATE loans l
+		SET returned_at = $2, status = $3, return_store_id = $4, updated_at = $5, version = l.version + 1
+		FROM (SELECT id, status FROM loans WHERE id = $1 FOR UPDATE) previous
+		WHERE l.id = previous.id AND ($6 = 0 OR l.version = $6)
+		RETURNING previous.status`
+
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin loan update: %w", err)
+	}
+	defer tx.Rollback(ctx)
 
 	now := time.Now()
 
-	result, err := r.DB.Exec(context.Background(), query,
+	var previousStatus string
+	err = tx.QueryRow(ctx, query,
 		loan.ID,
 		loan.ReturnedAt,
 		loan.Status,
+		loan.ReturnStoreID,
 		now,
-	)
+		loan.Version,
+	).Scan(&previousStatus)
+	if errors.Is(err, pgx.ErrNoRows) {
+		if loan.Version != 0 {
+			if _, err := r.GetLoan(ctx, loan.ID); err == nil {
+				return models.ErrVersionConflict
+			}
+		}
+		return fmt.Errorf("loan with id %s not found", loan.ID)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to update loan: %w", err)
 	}
 
-	rowsAffected := result.RowsAffected()
-	if rowsAffected == 0 {
-		return fmt.Errorf("loan with id %s not found", loan.ID)
-	}
-
-	return nil
-}
-
-/*
-ReturnMovie is a method of loanRepository struct that returns a movie object from the postgres database.
-
-Parameters:
-- loanId (uuid.UUID): The ID of the loan to be returned.
-
-Returns:
-- error: An error if the movie return fails, otherwise nil.
+	if previousStatus != "returned" && loan.Status == "returned" {
+		storeId := loan.StoreID
+		if loan.ReturnStoreID != nil {
+			storeId = *loan.ReturnStoreID
+		}
 
-Behavior:
-- Updates a loan in the loans table in the database.
-- Returns an error if the movie return fails.
-*/
-func (r *loanRepository) ReturnMovie(loanId uuid.UUID) error {
-	query := `
-		UPDATE loans
-		SET returned_at = $2, status = $3, updated_at = $4
-		WHERE id = $1 AND status = 'active'`
+		_, err = inventory.ApplyMovement(ctx, tx, loan.MovieID, &inventoryModels.CreateMovementDTO{
+			Reason:        inventoryModels.ReasonReturn,
+			QuantityDelta: 1,
+			StoreID:       storeId,
+			LoanID:        &loan.ID,
+		})
+		if err != nil {
+			return err
+		}
 
-	now := time.Now()
-	result, err := r.DB.Exec(context.Background(), query,
-		loanId,
-		now,
-		"returned",
-		now,
-	)
-	if err != nil {
-		return fmt.Errorf("failed to return movie: %w", err)
+		err = outbox.Write(ctx, tx, eventModels.TypeMovieReturned, loan.ID, &eventModels.MovieReturnedPayload{
+			LoanID:     loan.ID,
+			MovieID:    loan.MovieID,
+			UserID:     loan.UserID,
+			StoreID:    storeId,
+			ReturnedAt: loan.ReturnedAt,
+		})
+		if err != nil {
+			return err
+		}
 	}
 
-	rowsAffected := result.RowsAffected()
-	if rowsAffected == 0 {
-		return fmt.Errorf("active loan with id %s not found", loanId)
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit loan update: %w", err)
 	}
 
+	loan.Version++
+
 	return nil
 }
 
@@ -145,6 +243,7 @@ func (r *loanRepository) ReturnMovie(loanId uuid.UUID) error {
 GetLoan is a method of loanRepository struct that retrieves a loan object from the postgres database by its ID.
 
 Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
 - id (uuid.UUID): The ID of the loan to be retrieved.
 
 Returns:
@@ -154,22 +253,26 @@ Behavior:
 - Retrieves a loan from the loans table in the database by its ID.
 - Returns an error if the loan retrieval fails.
 */
-func (r *loanRepository) GetLoan(id uuid.UUID) (*models.LoanDTO, error) {
+func (r *loanRepository) GetLoan(ctx context.Context, id uuid.UUID) (*models.LoanDTO, error) {
 	query := `
-		SELECT id, movie_id, user_id, borrowed_at, returned_at, status, created_at, updated_at
+		SELECT id, movie_id, user_id, store_id, return_store_id, copy_id, borrowed_at, returned_at, status, version, created_at, updated_at
 		FROM loans
 		WHERE id = $1`
 
 	var loan models.LoanDTO
 	var returnedAt *time.Time
 
-	err := r.DB.QueryRow(context.Background(), query, id).Scan(
+	err := r.DB.QueryRow(ctx, query, id).Scan(
 		&loan.ID,
 		&loan.MovieID,
 		&loan.UserID,
+		&loan.StoreID,
+		&loan.ReturnStoreID,
+		&loan.CopyID,
 		&loan.BorrowedAt,
 		&returnedAt,
 		&loan.Status,
+		&loan.Version,
 		&loan.CreatedAt,
 		&loan.UpdatedAt,
 	)
@@ -188,6 +291,7 @@ func (r *loanRepository) GetLoan(id uuid.UUID) (*models.LoanDTO, error) {
 GetActiveUserLoans is a method of loanRepository struct that retrieves all active loans for a specific user from the postgres database.
 
 Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
 - userId (uuid.UUID): The ID of the user to retrieve active loans for.
 
 Returns:
@@ -197,14 +301,14 @@ Behavior:
 - Retrieves all active loans from the loans table in the database for the specified user.
 - Returns an error if the retrieval fails.
 */
-func (r *loanRepository) GetActiveUserLoans(userId uuid.UUID) ([]*models.LoanDTO, error) {
+func (r *loanRepository) GetActiveUserLoans(ctx context.Context, userId uuid.UUID) ([]*models.LoanDTO, error) {
 	query := `
-		SELECT id, movie_id, user_id, borrowed_at, returned_at, status, created_at, updated_at
+		SELECT id, movie_id, user_id, store_id, return_store_id, copy_id, borrowed_at, returned_at, status, version, created_at, updated_at
 		FROM loans
 		WHERE user_id = $1 AND status = 'active'
 		ORDER BY borrowed_at DESC`
 
-	rows, err := r.DB.Query(context.Background(), query, userId)
+	rows, err := r.DB.Query(ctx, query, userId)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get active user loans: %w", err)
 	}
@@ -219,9 +323,13 @@ func (r *loanRepository) GetActiveUserLoans(userId uuid.UUID) ([]*models.LoanDTO
 			&loan.ID,
 			&loan.MovieID,
 			&loan.UserID,
+			&loan.StoreID,
+			&loan.ReturnStoreID,
+			&loan.CopyID,
 			&loan.BorrowedAt,
 			&returnedAt,
 			&loan.Status,
+			&loan.Version,
 			&loan.CreatedAt,
 			&loan.UpdatedAt,
 		)
@@ -244,24 +352,94 @@ func (r *loanRepository) GetActiveUserLoans(userId uuid.UUID) ([]*models.LoanDTO
 }
 
 /*
-GetAllLoans is a method of loanRepository struct that retrieves all loans from the postgres database.
+GetUserLoanHistory is a method of loanRepository struct that retrieves every loan a user ever made from the postgres database.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- userId (uuid.UUID): The ID of the user to retrieve loans for.
 
 Returns:
-- ([]*models.LoanDTO, error): A slice of LoanDTO structs containing all loans, or an error if the retrieval fails.
+- ([]*models.LoanDTO, error): A slice of LoanDTO structs containing the user's loans, newest first, or an error if the retrieval fails.
 
 Behavior:
-- Retrieves all loans from the loans table in the database.
+- Reads loan_history, so active, returned and archived loans are all included, with the archived ones marked.
 - Returns an error if the retrieval fails.
 */
-func (r *loanRepository) GetAllLoans() ([]*models.LoanDTO, error) {
+func (r *loanRepository) GetUserLoanHistory(ctx context.Context, userId uuid.UUID) ([]*models.LoanDTO, error) {
 	query := `
-		SELECT id, movie_id, user_id, borrowed_at, returned_at, status, created_at, updated_at
+		SELECT id, movie_id, user_id, store_id, return_store_id, borrowed_at, returned_at, status, version, archived, created_at, updated_at
+		FROM loan_history
+		WHERE user_id = $1
+		ORDER BY borrowed_at DESC`
+
+	rows, err := r.DB.Query(ctx, query, userId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user loan history: %w", err)
+	}
+	defer rows.Close()
+
+	var loans []*models.LoanDTO
+	for rows.Next() {
+		var loan models.LoanDTO
+		var returnedAt *time.Time
+
+		err := rows.Scan(
+			&loan.ID,
+			&loan.MovieID,
+			&loan.UserID,
+			&loan.StoreID,
+			&loan.ReturnStoreID,
+			&loan.BorrowedAt,
+			&returnedAt,
+			&loan.Status,
+			&loan.Version,
+			&loan.Archived,
+			&loan.CreatedAt,
+			&loan.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan loan: %w", err)
+		}
+
+		if returnedAt != nil {
+			loan.ReturnedAt = *returnedAt
+		}
+
+		loans = append(loans, &loan)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over loans: %w", err)
+	}
+
+	return loans, nil
+}
+
+/*
+GetActiveMovieLoans is a method of loanRepository struct that retrieves all active loans of a specific movie from the postgres database.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- movieId (uuid.UUID): The ID of the movie to retrieve active loans for.
+
+Returns:
+- ([]*models.LoanDTO, error): A slice of LoanDTO structs containing the active loans for the movie, oldest first, or an error if the retrieval fails.
+
+Behavior:
+- Retrieves all active loans from the loans table in the database for the specified movie.
+- Orders the loans by borrowed_at so the longest outstanding loan comes first.
+- Returns an error if the retrieval fails.
+*/
+func (r *loanRepository) GetActiveMovieLoans(ctx context.Context, movieId uuid.UUID) ([]*models.LoanDTO, error) {
+	query := `
+		SELECT id, movie_id, user_id, store_id, return_store_id, copy_id, borrowed_at, returned_at, status, version, created_at, updated_at
 		FROM loans
-		ORDER BY created_at DESC`
+		WHERE movie_id = $1 AND status = 'active'
+		ORDER BY borrowed_at ASC`
 
-	rows, err := r.DB.Query(context.Background(), query)
+	rows, err := r.DB.Query(ctx, query, movieId)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get all loans: %w", err)
+		return nil, fmt.Errorf("failed to get active movie loans: %w", err)
 	}
 	defer rows.Close()
 
@@ -274,9 +452,13 @@ func (r *loanRepository) GetAllLoans() ([]*models.LoanDTO, error) {
 			&loan.ID,
 			&loan.MovieID,
 			&loan.UserID,
+			&loan.StoreID,
+			&loan.ReturnStoreID,
+			&loan.CopyID,
 			&loan.BorrowedAt,
 			&returnedAt,
 			&loan.Status,
+			&loan.Version,
 			&loan.CreatedAt,
 			&loan.UpdatedAt,
 		)
@@ -297,3 +479,391 @@ func (r *loanRepository) GetAllLoans() ([]*models.LoanDTO, error) {
 
 	return loans, nil
 }
+
+/*
+GetActiveCopyLoan is a method of loanRepository struct that retrieves the active loan of a physical copy from the postgres database.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- copyId (uuid.UUID): The ID of the copy.
+
+Returns:
+- (*models.LoanDTO, error): A pointer to a LoanDTO struct containing the copy's active loan, or an error if the retrieval fails.
+
+Behavior:
+- Returns models.ErrLoanNotFound if the copy is not on loan, or went out before loans recorded their copy.
+*/
+func (r *loanRepository) GetActiveCopyLoan(ctx context.Context, copyId uuid.UUID) (*models.LoanDTO, error) {
+	query := `
+		SELECT id, movie_id, user_id, store_id, return_store_id, copy_id, borrowed_at, returned_at, status, version, created_at, updated_at
+		FROM loans
+		WHERE copy_id = $1 AND status = 'active'`
+
+	var loan models.LoanDTO
+	var returnedAt *time.Time
+
+	err := r.DB.QueryRow(ctx, query, copyId).Scan(
+		&loan.ID,
+		&loan.MovieID,
+		&loan.UserID,
+		&loan.StoreID,
+		&loan.ReturnStoreID,
+		&loan.CopyID,
+		&loan.BorrowedAt,
+		&returnedAt,
+		&loan.Status,
+		&loan.Version,
+		&loan.CreatedAt,
+		&loan.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, models.ErrLoanNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active copy loan: %w", err)
+	}
+
+	if returnedAt != nil {
+		loan.ReturnedAt = *returnedAt
+	}
+
+	return &loan, nil
+}
+
+/*
+GetStoreLoans is a method of loanRepository struct that retrieves the loans of one store from the postgres database.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- storeId (uuid.UUID): The ID of the store to retrieve loans for.
+- includeArchived (bool): Whether to include the loans moved to loans_archive.
+
+Returns:
+- ([]*models.LoanDTO, error): A slice of LoanDTO structs containing the store's loans, or an error if the retrieval fails.
+
+Behavior:
+- Retrieves the loans checked out at the store, plus the loans returned there from other stores.
+- With includeArchived, reads loan_history instead, marking the archived loans.
+- Returns an error if the retrieval fails.
+*/
+func (r *loanRepository) GetStoreLoans(ctx context.Context, storeId uuid.UUID, includeArchived bool) ([]*models.LoanDTO, error) {
+	source := "(SELECT *, FALSE AS archived FROM loans) loans"
+	if includeArchived {
+		source = "loan_history"
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, movie_id, user_id, store_id, return_store_id, borrowed_at, returned_at, status, version, archived, created_at, updated_at
+		FROM %s
+		WHERE store_id = $1 OR return_store_id = $1
+		ORDER BY created_at DESC`, source)
+
+	rows, err := r.DB.Query(ctx, query, storeId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get store loans: %w", err)
+	}
+	defer rows.Close()
+
+	var loans []*models.LoanDTO
+	for rows.Next() {
+		var loan models.LoanDTO
+		var returnedAt *time.Time
+
+		err := rows.Scan(
+			&loan.ID,
+			&loan.MovieID,
+			&loan.UserID,
+			&loan.StoreID,
+			&loan.ReturnStoreID,
+			&loan.BorrowedAt,
+			&returnedAt,
+			&loan.Status,
+			&loan.Version,
+			&loan.Archived,
+			&loan.CreatedAt,
+			&loan.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan loan: %w", err)
+		}
+
+		if returnedAt != nil {
+			loan.ReturnedAt = *returnedAt
+		}
+
+		loans = append(loans, &loan)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over loans: %w", err)
+	}
+
+	return loans, nil
+}
+
+/*
+GetRecentActiveStoreLoans is a method of loanRepository struct that retrieves the latest active loans checked out at a store from the postgres database.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- storeId (uuid.UUID): The ID of the store the loans were checked out at.
+- limit (int): The maximum number of loans to return.
+
+Returns:
+- ([]*models.LoanDTO, error): A slice of LoanDTO structs containing the active loans, newest first, or an error if the retrieval fails.
+
+Behavior:
+- Reads only the loans table, since archived loans are never active.
+- Returns an error if the retrieval fails.
+*/
+func (r *loanRepository) GetRecentActiveStoreLoans(ctx context.Context, storeId uuid.UUID, limit int) ([]*models.LoanDTO, error) {
+	query := `
+		SELECT id, movie_id, user_id, store_id, return_store_id, copy_id, borrowed_at, returned_at, status, version, created_at, updated_at
+		FROM loans
+		WHERE store_id = $1 AND status = 'active'
+		ORDER BY borrowed_at DESC
+		LIMIT $2`
+
+	rows, err := r.DB.Query(ctx, query, storeId, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recent active store loans: %w", err)
+	}
+	defer rows.Close()
+
+	var loans []*models.LoanDTO
+	for rows.Next() {
+		var loan models.LoanDTO
+		var returnedAt *time.Time
+
+		err := rows.Scan(
+			&loan.ID,
+			&loan.MovieID,
+			&loan.UserID,
+			&loan.StoreID,
+			&loan.ReturnStoreID,
+			&loan.CopyID,
+			&loan.BorrowedAt,
+			&returnedAt,
+			&loan.Status,
+			&loan.Version,
+			&loan.CreatedAt,
+			&loan.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan loan: %w", err)
+		}
+
+		if returnedAt != nil {
+			loan.ReturnedAt = *returnedAt
+		}
+
+		loans = append(loans, &loan)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over loans: %w", err)
+	}
+
+	return loans, nil
+}
+
+/*
+GetArchivedLoan is a method of loanRepository struct that retrieves an archived loan object from the postgres database by its ID.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- id (uuid.UUID): The ID of the loan to be retrieved.
+
+Returns:
+- (*models.LoanDTO, error): A pointer to a LoanDTO struct containing the loan data, or an error if the loan retrieval fails.
+
+Behavior:
+- Retrieves a loan from the loans_archive table in the database by its ID, marked as archived.
+- Returns an error if the loan retrieval fails.
+*/
+func (r *loanRepository) GetArchivedLoan(ctx context.Context, id uuid.UUID) (*models.LoanDTO, error) {
+	query := `
+		SELECT id, movie_id, user_id, store_id, return_store_id, copy_id, borrowed_at, returned_at, status, version, created_at, updated_at
+		FROM loans_archive
+		WHERE id = $1`
+
+	loan := models.LoanDTO{Archived: true}
+	var returnedAt *time.Time
+
+	err := r.DB.QueryRow(ctx, query, id).Scan(
+		&loan.ID,
+		&loan.MovieID,
+		&loan.UserID,
+		&loan.StoreID,
+		&loan.ReturnStoreID,
+		&loan.CopyID,
+		&loan.BorrowedAt,
+		&returnedAt,
+		&loan.Status,
+		&loan.Version,
+		&loan.CreatedAt,
+		&loan.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get archived loan: %w", err)
+	}
+
+	if returnedAt != nil {
+		loan.ReturnedAt = *returnedAt
+	}
+
+	return &loan, nil
+}
+
+/*
+CreatePartitions is a method of loanRepository struct that creates the yearly partitions of the loans table in the postgres database.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- firstYear (int): The first year to have a partition.
+- lastYear (int): The last year to have a partition, included.
+
+Returns:
+- ([]string, error): The names of the partitions that did not exist yet, or an error if one cannot be created.
+
+Behavior:
+- Creates a loans_<year> partition for every year missing one, holding the loans borrowed that year in UTC.
+- Fails for a year loans_default already holds loans of, which only happens when the partition was not created in time.
+*/
+func (r *loanRepository) CreatePartitions(ctx context.Context, firstYear, lastYear int) ([]string, error) {
+	created := []string{}
+	for year := firstYear; year <= lastYear; year++ {
+		name := fmt.Sprintf("loans_%d", year)
+
+		var exists bool
+		if err := r.DB.QueryRow(ctx, `SELECT to_regclass($1) IS NOT NULL`, name).Scan(&exists); err != nil {
+			return created, fmt.Errorf("failed to look up loans partition %s: %w", name, err)
+		}
+		if exists {
+			continue
+		}
+
+		// DDL takes no parameters; the bounds are formatted here, never read from input
+		from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+		to := from.AddDate(1, 0, 0)
+		statement := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s PARTITION OF loans FOR VALUES FROM ('%s') TO ('%s')`,
+			pgx.Identifier{name}.Sanitize(), from.Format(time.RFC3339), to.Format(time.RFC3339))
+
+		if _, err := r.DB.Exec(ctx, statement); err != nil {
+			return created, fmt.Errorf("failed to create loans partition %s: %w", name, err)
+		}
+		created = append(created, name)
+	}
+
+	return created, nil
+}
+
+/*
+ArchiveLoans is a method of loanRepository struct that moves old returned loans to the archive in the postgres database.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- before (time.Time): Loans borrowed and returned before this time are moved.
+- limit (int): The most loans to move at once.
+
+Returns:
+- (int64, error): How many loans were moved, or an error if the move fails.
+
+Behavior:
+- Deletes the oldest matching loans and inserts them into loans_archive in one statement, so a loan is never in both or neither.
+- Skips loans locked by a running update.
+- Returns an error if the move fails.
+*/
+func (r *loanRepository) ArchiveLoans(ctx context.Context, before time.Time, limit int) (int64, error) {
+	query := `
+		WITH moved AS (
+			DELETE FROM loans
+			WHERE (id, borrowed_at) IN (
+				SELECT id, borrowed_at FROM loans
+				WHERE status = 'returned' AND borrowed_at < $1 AND returned_at < $1
+				ORDER BY borrowed_at
+				LIMIT $2
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING id, movie_id, user_id, store_id, return_store_id, copy_id, borrowed_at, returned_at, status, price_cents, version, created_at, updated_at
+		)
+		INSERT INTO loans_archive (id, movie_id, user_id, store_id, return_store_id, copy_id, borrowed_at, returned_at, status, price_cents, version, created_at, updated_at, archived_at)
+		SELECT id, movie_id, user_id, store_id, return_store_id, copy_id, borrowed_at, returned_at, status, price_cents, version, created_at, updated_at, $3
+		FROM moved`
+
+	result, err := r.DB.Exec(ctx, query, before, limit, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("failed to archive loans: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
+
+/*
+DropEmptyPartitions is a method of loanRepository struct that drops the yearly loans partitions the archive emptied from the postgres database.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- before (time.Time): Only partitions ending by this time are dropped.
+
+Returns:
+- ([]string, error): The names of the dropped partitions, or an error if one cannot be dropped.
+
+Behavior:
+- Drops the loans_<year> partitions that hold no loans anymore and cover only years before the given time.
+- Keeps partitions that still hold loans, such as loans never returned.
+*/
+func (r *loanRepository) DropEmptyPartitions(ctx context.Context, before time.Time) ([]string, error) {
+	query := `
+		SELECT c.relname
+		FROM pg_inherits i
+		JOIN pg_class c ON c.oid = i.inhrelid
+		WHERE i.inhparent = 'loans'::regclass AND c.relname ~ '^loans_[0-9]{4}$'
+		ORDER BY c.relname`
+
+	rows, err := r.DB.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get loans partitions: %w", err)
+	}
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan loans partition: %w", err)
+		}
+		names = append(names, name)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over loans partitions: %w", err)
+	}
+
+	dropped := []string{}
+	for _, name := range names {
+		year, err := strconv.Atoi(strings.TrimPrefix(name, "loans_"))
+		if err != nil {
+			continue
+		}
+		if end := time.Date(year+1, time.January, 1, 0, 0, 0, 0, time.UTC); end.After(before) {
+			continue
+		}
+
+		table := pgx.Identifier{name}.Sanitize()
+
+		var used bool
+		if err := r.DB.QueryRow(ctx, fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM %s)`, table)).Scan(&used); err != nil {
+			return dropped, fmt.Errorf("failed to check loans partition %s: %w", name, err)
+		}
+		if used {
+			continue
+		}
+
+		if _, err := r.DB.Exec(ctx, fmt.Sprintf(`DROP TABLE %s`, table)); err != nil {
+			return dropped, fmt.Errorf("failed to drop loans partition %s: %w", name, err)
+		}
+		dropped = append(dropped, name)
+	}
+
+	return dropped, nil
+}
@@ -0,0 +1,190 @@
+package loans
+
+import (
+	"blockbustermvc/internal/database/dbtest"
+	"blockbustermvc/internal/inventory"
+	eventModels "blockbustermvc/internal/models/event"
+	inventoryModels "blockbustermvc/internal/models/inventory"
+	models "blockbustermvc/internal/models/loans"
+	notificationModels "blockbustermvc/internal/models/notification"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestLoanWritesStockAndEventsInOneTransaction(t *testing.T) {
+	pool := dbtest.Open(t)
+	ctx := t.Context()
+	repo := NewLoanRepository(pool)
+
+	storeId := dbtest.Store(t, pool)
+	returnStoreId := dbtest.Store(t, pool)
+	movieId := dbtest.Movie(t, pool)
+	userId := dbtest.User(t, pool)
+
+	stock := `SELECT COALESCE((SELECT quantity FROM store_stock WHERE store_id = $1 AND movie_id = $2), 0)`
+	events := `SELECT COUNT(*) FROM outbox_events WHERE aggregate_id = $1 AND type = $2`
+
+	// Without a copy in the store neither the loan nor its event may be written
+	_, err := repo.CreateLoan(ctx, &models.CreateLoanDTO{MovieID: movieId, UserID: userId, StoreID: storeId})
+	if !errors.Is(err, inventoryModels.ErrInsufficientStock) {
+		t.Fatalf("CreateLoan() without stock error = %v, want %v", err, inventoryModels.ErrInsufficientStock)
+	}
+	if got := dbtest.Int(t, pool, `SELECT COUNT(*) FROM loans WHERE movie_id = $1`, movieId); got != 0 {
+		t.Errorf("loans after a failed checkout = %d, want 0", got)
+	}
+
+	_, err = inventory.NewInventoryRepository(pool).CreateMovement(ctx, movieId, &inventoryModels.CreateMovementDTO{
+		Reason:        inventoryModels.ReasonPurchase,
+		QuantityDelta: 1,
+		StoreID:       storeId,
+	})
+	if err != nil {
+		t.Fatalf("CreateMovement() error = %v", err)
+	}
+
+	loan, err := repo.CreateLoan(ctx, &models.CreateLoanDTO{MovieID: movieId, UserID: userId, StoreID: storeId})
+	if err != nil {
+		t.Fatalf("CreateLoan() error = %v", err)
+	}
+
+	if got := dbtest.Int(t, pool, stock, storeId, movieId); got != 0 {
+		t.Errorf("store stock after checkout = %d, want 0", got)
+	}
+	if got := dbtest.Int(t, pool, `SELECT COUNT(*) FROM inventory_movements WHERE loan_id = $1 AND reason = $2`, loan.ID, inventoryModels.ReasonLoan); got != 1 {
+		t.Errorf("loan movements = %d, want 1", got)
+	}
+	if got := dbtest.Int(t, pool, events, loan.ID, eventModels.TypeLoanCreated); got != 1 {
+		t.Errorf("loan.created events = %d, want 1", got)
+	}
+
+	loan.Status = "returned"
+	loan.ReturnedAt = time.Now()
+	loan.ReturnStoreID = &returnStoreId
+	if err := repo.UpdateLoan(ctx, loan); err != nil {
+		t.Fatalf("UpdateLoan() error = %v", err)
+	}
+
+	if got := dbtest.Int(t, pool, stock, returnStoreId, movieId); got != 1 {
+		t.Errorf("returning store stock = %d, want 1", got)
+	}
+	if got := dbtest.Int(t, pool, `SELECT COUNT(*) FROM inventory_movements WHERE loan_id = $1 AND reason = $2`, loan.ID, inventoryModels.ReasonReturn); got != 1 {
+		t.Errorf("return movements = %d, want 1", got)
+	}
+	if got := dbtest.Int(t, pool, events, loan.ID, eventModels.TypeMovieReturned); got != 1 {
+		t.Errorf("movie.returned events = %d, want 1", got)
+	}
+	// The purchase, the checkout and the return
+	if got := dbtest.Int(t, pool, events, movieId, eventModels.TypeStockChanged); got != 3 {
+		t.Errorf("stock.changed events = %d, want 3", got)
+	}
+}
+
+func TestGetUserLoanHistoryIncludesArchivedLoans(t *testing.T) {
+	pool := dbtest.Open(t)
+	ctx := t.Context()
+	repo := NewLoanRepository(pool)
+
+	storeId := dbtest.Store(t, pool)
+	movieId := dbtest.Movie(t, pool)
+	userId := dbtest.User(t, pool)
+
+	archivedId := dbtest.ArchivedLoan(t, pool, storeId, movieId, userId)
+
+	active, err := repo.GetActiveUserLoans(ctx, userId)
+	if err != nil {
+		t.Fatalf("GetActiveUserLoans() error = %v", err)
+	}
+	if len(active) != 0 {
+		t.Errorf("active loans = %d, want 0", len(active))
+	}
+
+	history, err := repo.GetUserLoanHistory(ctx, userId)
+	if err != nil {
+		t.Fatalf("GetUserLoanHistory() error = %v", err)
+	}
+	if len(history) != 1 || history[0].ID != archivedId || !history[0].Archived {
+		t.Fatalf("GetUserLoanHistory() = %+v, want only archived loan %s", history, archivedId)
+	}
+}
+
+func TestLoanIDsStayUniqueAcrossTheArchive(t *testing.T) {
+	pool := dbtest.Open(t)
+	ctx := t.Context()
+
+	storeId := dbtest.Store(t, pool)
+	movieId := dbtest.Movie(t, pool)
+	userId := dbtest.User(t, pool)
+
+	archivedId := dbtest.ArchivedLoan(t, pool, storeId, movieId, userId)
+
+	// The archived loan's ID in the current partition of loans
+	_, err := pool.Exec(ctx, `
+		INSERT INTO loans (id, movie_id, user_id, store_id)
+		VALUES ($1, $2, $3, $4)`, archivedId, movieId, userId, storeId)
+	if err == nil {
+		t.Error("inserting a loan with an archived loan's ID succeeded, want a unique violation")
+	}
+
+	notify := `INSERT INTO notifications (user_id, kind, loan_id) VALUES ($1, $2, $3)`
+	if _, err := pool.Exec(ctx, notify, userId, notificationModels.KindReceipt, archivedId); err != nil {
+		t.Errorf("notification for an archived loan error = %v, want nil", err)
+	}
+	if _, err := pool.Exec(ctx, notify, userId, notificationModels.KindReceipt, uuid.New()); err == nil {
+		t.Error("notification for an unknown loan succeeded, want a foreign key violation")
+	}
+}
+
+func TestCopyCanOnlyBeLentOnce(t *testing.T) {
+	pool := dbtest.Open(t)
+	ctx := t.Context()
+	repo := NewLoanRepository(pool)
+
+	storeId := dbtest.Store(t, pool)
+	movieId := dbtest.Movie(t, pool)
+	firstUser := dbtest.User(t, pool)
+	secondUser := dbtest.User(t, pool)
+
+	_, err := inventory.NewInventoryRepository(pool).CreateMovement(ctx, movieId, &inventoryModels.CreateMovementDTO{
+		Reason:        inventoryModels.ReasonPurchase,
+		QuantityDelta: 2,
+		StoreID:       storeId,
+	})
+	if err != nil {
+		t.Fatalf("CreateMovement() error = %v", err)
+	}
+
+	copyId := dbtest.Copy(t, pool, movieId)
+
+	loan, err := repo.CreateLoan(ctx, &models.CreateLoanDTO{MovieID: movieId, UserID: firstUser, StoreID: storeId, CopyID: &copyId})
+	if err != nil {
+		t.Fatalf("CreateLoan() error = %v", err)
+	}
+	if loan.CopyID == nil || *loan.CopyID != copyId {
+		t.Errorf("loan copy = %v, want %s", loan.CopyID, copyId)
+	}
+
+	_, err = repo.CreateLoan(ctx, &models.CreateLoanDTO{MovieID: movieId, UserID: secondUser, StoreID: storeId, CopyID: &copyId})
+	if !errors.Is(err, models.ErrCopyOnLoan) {
+		t.Fatalf("CreateLoan() of a lent copy error = %v, want %v", err, models.ErrCopyOnLoan)
+	}
+
+	active, err := repo.GetActiveCopyLoan(ctx, copyId)
+	if err != nil {
+		t.Fatalf("GetActiveCopyLoan() error = %v", err)
+	}
+	if active.ID != loan.ID {
+		t.Errorf("active copy loan = %s, want %s", active.ID, loan.ID)
+	}
+
+	active.Status = "returned"
+	active.ReturnedAt = time.Now()
+	if err := repo.UpdateLoan(ctx, active); err != nil {
+		t.Fatalf("UpdateLoan() error = %v", err)
+	}
+	if _, err := repo.GetActiveCopyLoan(ctx, copyId); !errors.Is(err, models.ErrLoanNotFound) {
+		t.Errorf("GetActiveCopyLoan() after the return error = %v, want %v", err, models.ErrLoanNotFound)
+	}
+}
@@ -4,7 +4,10 @@ import (
 	models "blockbustermvc/internal/models/loans"
 	movieService "blockbustermvc/internal/models/movie"
 	userService "blockbustermvc/internal/models/user"
+	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -28,21 +31,23 @@ func NewLoanService(
 	}
 }
 
-func (l LoanService) CreateLoan(movieId, userId uuid.UUID) (*models.CreateLoanDTO, error) {
-	movie, err := l.movieService.GetMovie(movieId)
+// CreateLoan records the lent copy when copyBarcode is given, so returning
+// it by barcode finds this loan. Without one the loan names only the movie.
+func (l LoanService) CreateLoan(ctx context.Context, storeId, movieId, userId uuid.UUID, copyBarcode string) (*models.LoanDTO, error) {
+	movie, err := l.movieService.GetStoreMovie(ctx, storeId, movieId)
 	if err != nil {
 		return nil, err
 	}
-	if movie.Quantity < 0 {
-		return nil, errors.New("movie is not available")
+	if movie.Quantity <= 0 {
+		return nil, errors.New("movie is not available at this store")
 	}
 
-	user, err := l.userService.GetUser(userId)
+	user, err := l.userService.GetUser(ctx, userId)
 	if err != nil {
 		return nil, err
 	}
 
-	activeLoans, err := l.loanRepository.GetActiveUserLoans(user.ID)
+	activeLoans, err := l.loanRepository.GetActiveUserLoans(ctx, user.ID)
 	if err != nil {
 		return nil, err
 	}
@@ -51,81 +56,229 @@ func (l LoanService) CreateLoan(movieId, userId uuid.UUID) (*models.CreateLoanDT
 		return nil, errors.New("user has active loans")
 	}
 
+	var copyId *uuid.UUID
+	if copyBarcode = strings.TrimSpace(copyBarcode); copyBarcode != "" {
+		movieCopy, err := l.movieService.GetCopyByBarcode(ctx, copyBarcode)
+		if errors.Is(err, movieService.ErrCopyNotFound) {
+			return nil, fmt.Errorf("unknown barcode %s", copyBarcode)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if movieCopy.MovieID != movieId {
+			return nil, fmt.Errorf("barcode %s is a copy of another movie", copyBarcode)
+		}
+		copyId = &movieCopy.ID
+	}
+
 	loan := &models.CreateLoanDTO{
 		MovieID:    movieId,
 		UserID:     userId,
+		StoreID:    storeId,
+		CopyID:     copyId,
 		BorrowedAt: time.Now(),
 		Status:     "active",
 		CreatedAt:  time.Now(),
 	}
 
-	if err = l.loanRepository.CreateLoan(loan); err != nil {
+	return l.loanRepository.CreateLoan(ctx, loan)
+}
+
+// ReturnMovie only returns the loan if it is still at version, so a return
+// made from a stale view fails with ErrVersionConflict. A zero version returns
+// it unconditionally.
+func (l LoanService) ReturnMovie(ctx context.Context, storeId, loanId uuid.UUID, version int64) (*models.LoanDTO, error) {
+	return l.returnLoan(ctx, storeId, loanId, version, time.Now())
+}
+
+func (l LoanService) ReturnMovieAt(ctx context.Context, storeId, loanId uuid.UUID, returnedAt time.Time) (*models.LoanDTO, error) {
+	return l.returnLoan(ctx, storeId, loanId, 0, returnedAt)
+}
+
+func (l LoanService) returnLoan(ctx context.Context, storeId, loanId uuid.UUID, version int64, returnedAt time.Time) (*models.LoanDTO, error) {
+	loan, err := l.loanRepository.GetLoan(ctx, loanId)
+	if err != nil {
 		return nil, err
 	}
+	if version != 0 && loan.Version != version {
+		return nil, models.ErrVersionConflict
+	}
 
-	movie.Quantity--
+	if loan.Status == "returned" {
+		return nil, errors.New("movie already returned")
+	}
 
-	updateMovieDTO := &movieService.UpdateMovieDTO{
-		Name:      movie.Name,
-		Director:  movie.Director,
-		Year:      movie.Year,
-		Quantity:  movie.Quantity,
-		CreatedAt: movie.CreatedAt,
-		UpdatedAt: time.Now(),
+	if returnedAt.IsZero() {
+		returnedAt = time.Now()
 	}
+	if returnedAt.After(time.Now()) {
+		return nil, errors.New("return time cannot be in the future")
+	}
+	if returnedAt.Before(loan.BorrowedAt) {
+		return nil, errors.New("return time cannot be before the loan was borrowed")
+	}
+
+	loan.Status = "returned"
+	loan.UpdatedAt = time.Now()
+	loan.ReturnedAt = returnedAt
+	loan.ReturnStoreID = &storeId
 
-	if err = l.movieService.UpdateMovie(movieId, updateMovieDTO); err != nil {
+	if err := l.loanRepository.UpdateLoan(ctx, loan); err != nil {
 		return nil, err
 	}
 
 	return loan, nil
 }
 
-func (l LoanService) ReturnMovie(loanId uuid.UUID) error {
-	loan, err := l.loanRepository.GetLoan(loanId)
+// ReturnByCode returns the loan of the scanned copy wherever it was checked
+// out, as ReturnMovieAt allows. A movie ID names no copy, so it only returns
+// the movie's sole active loan, or the sole one checked out at storeId, and
+// refuses to guess between several.
+func (l LoanService) ReturnByCode(ctx context.Context, storeId uuid.UUID, code string, returnedAt time.Time) (*models.LoanDTO, error) {
+	code = strings.TrimSpace(code)
+
+	movieId, err := uuid.Parse(code)
 	if err != nil {
-		return err
+		movieCopy, err := l.movieService.GetCopyByBarcode(ctx, code)
+		if errors.Is(err, movieService.ErrCopyNotFound) {
+			return nil, fmt.Errorf("unknown barcode %s", code)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		loan, err := l.loanRepository.GetActiveCopyLoan(ctx, movieCopy.ID)
+		if errors.Is(err, models.ErrLoanNotFound) {
+			return nil, fmt.Errorf("no active loan found for %s", code)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		return l.ReturnMovieAt(ctx, storeId, loan.ID, returnedAt)
 	}
 
-	if loan.Status == "returned" {
-		return errors.New("movie already returned")
+	matches, err := l.loanRepository.GetActiveMovieLoans(ctx, movieId)
+	if err != nil {
+		return nil, err
 	}
 
-	loan.Status = "returned"
-	loan.UpdatedAt = time.Now()
-	loan.ReturnedAt = time.Now()
+	if len(matches) > 1 {
+		var here []*models.LoanDTO
+		for _, loan := range matches {
+			if loan.StoreID == storeId {
+				here = append(here, loan)
+			}
+		}
+		matches = here
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no active loan found for %s", code)
+	case 1:
+		return l.ReturnMovieAt(ctx, storeId, matches[0].ID, returnedAt)
+	default:
+		return nil, models.ErrAmbiguousReturn
+	}
+}
 
-	if err := l.loanRepository.UpdateLoan(loan); err != nil {
-		return err
+func (l LoanService) ProcessDropBox(ctx context.Context, storeId uuid.UUID, batch *models.DropBoxReturnDTO) *models.DropBoxSummaryDTO {
+	returnedAt := batch.ReturnedAt
+	if returnedAt.IsZero() {
+		returnedAt = time.Now()
 	}
 
-	movie, err := l.movieService.GetMovie(loan.MovieID)
-	if err != nil {
-		return err
+	summary := &models.DropBoxSummaryDTO{
+		ReturnedAt: returnedAt,
+		Results:    []*models.ReturnResultDTO{},
+	}
+
+	for _, code := range batch.Codes {
+		code = strings.TrimSpace(code)
+		if code == "" {
+			continue
+		}
+
+		result := &models.ReturnResultDTO{Code: code}
+
+		loan, err := l.ReturnByCode(ctx, storeId, code, returnedAt)
+		if err != nil {
+			result.Error = err.Error()
+			summary.Failed++
+		} else {
+			result.Success = true
+			result.Loan = loan
+			summary.Succeeded++
+		}
+
+		summary.Processed++
+		summary.Results = append(summary.Results, result)
+	}
+
+	return summary
+}
+
+// GetLoan looks the loan up in the archive too when includeArchived is set
+// and it is not among the current loans.
+func (l LoanService) GetLoan(ctx context.Context, id uuid.UUID, includeArchived bool) (*models.LoanDTO, error) {
+	loan, err := l.loanRepository.GetLoan(ctx, id)
+	if err != nil && includeArchived {
+		if archived, archiveErr := l.loanRepository.GetArchivedLoan(ctx, id); archiveErr == nil {
+			return archived, nil
+		}
 	}
 
-	movie.Quantity++
+	return loan, err
+}
 
-	updateMovieDTO := &movieService.UpdateMovieDTO{
-		Name:      movie.Name,
-		Director:  movie.Director,
-		Year:      movie.Year,
-		Quantity:  movie.Quantity,
-		CreatedAt: movie.CreatedAt,
-		UpdatedAt: time.Now(),
+// GetUserLoans returns the user's active loans, or with includeArchived
+// every loan they ever made, archived ones included.
+func (l LoanService) GetUserLoans(ctx context.Context, userId uuid.UUID, includeArchived bool) ([]*models.LoanDTO, error) {
+	if includeArchived {
+		return l.loanRepository.GetUserLoanHistory(ctx, userId)
 	}
 
-	return l.movieService.UpdateMovie(movie.ID, updateMovieDTO)
+	return l.loanRepository.GetActiveUserLoans(ctx, userId)
+}
+
+func (l LoanService) GetStoreLoans(ctx context.Context, storeId uuid.UUID, includeArchived bool) ([]*models.LoanDTO, error) {
+	return l.loanRepository.GetStoreLoans(ctx, storeId, includeArchived)
 }
 
-func (l LoanService) GetLoan(id uuid.UUID) (*models.LoanDTO, error) {
-	return l.loanRepository.GetLoan(id)
+func (l LoanService) GetRecentActiveStoreLoans(ctx context.Context, storeId uuid.UUID, limit int) ([]*models.LoanDTO, error) {
+	return l.loanRepository.GetRecentActiveStoreLoans(ctx, storeId, limit)
 }
 
-func (l LoanService) GetUserLoans(userId uuid.UUID) ([]*models.LoanDTO, error) {
-	return l.loanRepository.GetActiveUserLoans(userId)
+// EnsurePartitions creates the loans partitions of this year and the next
+// models.PartitionsAhead years, returning the ones it created.
+func (l LoanService) EnsurePartitions(ctx context.Context) ([]string, error) {
+	year := time.Now().UTC().Year()
+	return l.loanRepository.CreatePartitions(ctx, year, year+models.PartitionsAhead)
 }
 
-func (l LoanService) GetAllLoans() ([]*models.LoanDTO, error) {
-	return l.loanRepository.GetAllLoans()
+// ArchiveLoans moves the loans borrowed and returned before the given time
+// to the archive, a batch at a time, then drops the yearly partitions that
+// were left empty.
+func (l LoanService) ArchiveLoans(ctx context.Context, before time.Time) (*models.ArchiveResultDTO, error) {
+	result := &models.ArchiveResultDTO{Before: before}
+
+	for {
+		moved, err := l.loanRepository.ArchiveLoans(ctx, before, models.ArchiveBatchSize)
+		result.Archived += moved
+		if err != nil {
+			return result, err
+		}
+		if moved < models.ArchiveBatchSize {
+			break
+		}
+	}
+
+	dropped, err := l.loanRepository.DropEmptyPartitions(ctx, before)
+	result.DroppedPartitions = dropped
+	if err != nil {
+		return result, err
+	}
+
+	return result, nil
 }
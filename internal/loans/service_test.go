@@ -0,0 +1,174 @@
+package loans
+
+import (
+	models "blockbustermvc/internal/models/loans"
+	movieModels "blockbustermvc/internal/models/movie"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type fakeLoanRepository struct {
+	models.ILoanRepository
+	loans    []*models.LoanDTO
+	returned []uuid.UUID
+}
+
+func (f *fakeLoanRepository) GetActiveMovieLoans(_ context.Context, movieId uuid.UUID) ([]*models.LoanDTO, error) {
+	var active []*models.LoanDTO
+	for _, loan := range f.loans {
+		if loan.MovieID == movieId && loan.Status == "active" {
+			active = append(active, loan)
+		}
+	}
+	return active, nil
+}
+
+func (f *fakeLoanRepository) GetActiveCopyLoan(_ context.Context, copyId uuid.UUID) (*models.LoanDTO, error) {
+	for _, loan := range f.loans {
+		if loan.CopyID != nil && *loan.CopyID == copyId && loan.Status == "active" {
+			return loan, nil
+		}
+	}
+	return nil, models.ErrLoanNotFound
+}
+
+func (f *fakeLoanRepository) GetLoan(_ context.Context, id uuid.UUID) (*models.LoanDTO, error) {
+	for _, loan := range f.loans {
+		if loan.ID == id {
+			copied := *loan
+			return &copied, nil
+		}
+	}
+	return nil, errors.New("loan not found")
+}
+
+func (f *fakeLoanRepository) UpdateLoan(_ context.Context, loan *models.LoanDTO) error {
+	f.returned = append(f.returned, loan.ID)
+	return nil
+}
+
+type fakeMovieService struct {
+	movieModels.IMovieService
+	copies []*movieModels.CopyDTO
+}
+
+func (f *fakeMovieService) GetCopyByBarcode(_ context.Context, barcode string) (*movieModels.CopyDTO, error) {
+	for _, movieCopy := range f.copies {
+		if movieCopy.Barcode == barcode {
+			return movieCopy, nil
+		}
+	}
+	return nil, movieModels.ErrCopyNotFound
+}
+
+func TestReturnByCode(t *testing.T) {
+	store := uuid.New()
+	otherStore := uuid.New()
+	movieId := uuid.New()
+	borrowedAt := time.Now().Add(-time.Hour)
+
+	firstCopy := &movieModels.CopyDTO{ID: uuid.New(), MovieID: movieId, Barcode: "BB-0001"}
+	secondCopy := &movieModels.CopyDTO{ID: uuid.New(), MovieID: movieId, Barcode: "BB-0002"}
+	idleCopy := &movieModels.CopyDTO{ID: uuid.New(), MovieID: movieId, Barcode: "BB-0003"}
+
+	activeLoan := func(storeId uuid.UUID, movieCopy *movieModels.CopyDTO) *models.LoanDTO {
+		loan := &models.LoanDTO{ID: uuid.New(), MovieID: movieId, StoreID: storeId, Status: "active", BorrowedAt: borrowedAt}
+		if movieCopy != nil {
+			loan.CopyID = &movieCopy.ID
+		}
+		return loan
+	}
+
+	first := activeLoan(store, firstCopy)
+	second := activeLoan(store, secondCopy)
+	elsewhere := activeLoan(otherStore, secondCopy)
+	elsewhereByMovie := activeLoan(otherStore, nil)
+
+	tests := []struct {
+		name       string
+		code       string
+		loans      []*models.LoanDTO
+		wantLoan   *models.LoanDTO
+		wantErr    error
+		wantAnyErr bool
+	}{
+		{name: "barcode picks its copy's loan", code: "BB-0002", loans: []*models.LoanDTO{first, second}, wantLoan: second},
+		{name: "barcode of a copy lent at another store", code: "BB-0002", loans: []*models.LoanDTO{first, elsewhere}, wantLoan: elsewhere},
+		{name: "barcode of a copy not on loan", code: "BB-0003", loans: []*models.LoanDTO{first, second}, wantAnyErr: true},
+		{name: "unknown barcode", code: "BB-9999", loans: []*models.LoanDTO{first}, wantAnyErr: true},
+		{name: "movie ID with one loan at another store", code: movieId.String(), loans: []*models.LoanDTO{elsewhereByMovie}, wantLoan: elsewhereByMovie},
+		{name: "movie ID narrowed to this store", code: movieId.String(), loans: []*models.LoanDTO{elsewhereByMovie, first}, wantLoan: first},
+		{name: "movie ID with several loans at this store", code: movieId.String(), loans: []*models.LoanDTO{first, second}, wantErr: models.ErrAmbiguousReturn},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeLoanRepository{loans: tt.loans}
+			movies := &fakeMovieService{copies: []*movieModels.CopyDTO{firstCopy, secondCopy, idleCopy}}
+			service := NewLoanService(repo, movies, nil)
+
+			loan, err := service.ReturnByCode(t.Context(), store, tt.code, time.Now())
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ReturnByCode() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantAnyErr && err == nil {
+				t.Fatal("ReturnByCode() error = nil, want an error")
+			}
+			if tt.wantLoan == nil {
+				if len(repo.returned) != 0 {
+					t.Errorf("returned loans %v, want none", repo.returned)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ReturnByCode() error = %v", err)
+			}
+			if loan.ID != tt.wantLoan.ID {
+				t.Errorf("returned loan %s, want %s", loan.ID, tt.wantLoan.ID)
+			}
+			if loan.ReturnStoreID == nil || *loan.ReturnStoreID != store {
+				t.Errorf("return store = %v, want %s", loan.ReturnStoreID, store)
+			}
+		})
+	}
+}
+
+func TestReturnMovieChecksTheVersion(t *testing.T) {
+	store := uuid.New()
+	loan := &models.LoanDTO{ID: uuid.New(), MovieID: uuid.New(), StoreID: store, Status: "active", Version: 2, BorrowedAt: time.Now().Add(-time.Hour)}
+
+	tests := []struct {
+		name    string
+		version int64
+		wantErr error
+	}{
+		{name: "current version", version: 2},
+		{name: "unconditional", version: 0},
+		{name: "stale version", version: 1, wantErr: models.ErrVersionConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeLoanRepository{loans: []*models.LoanDTO{loan}}
+			service := NewLoanService(repo, &fakeMovieService{}, nil)
+
+			_, err := service.ReturnMovie(t.Context(), store, loan.ID, tt.version)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ReturnMovie() error = %v, want %v", err, tt.wantErr)
+			}
+
+			wantReturned := 1
+			if tt.wantErr != nil {
+				wantReturned = 0
+			}
+			if len(repo.returned) != wantReturned {
+				t.Errorf("returned loans %v, want %d", repo.returned, wantReturned)
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package logging
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// defaultLevel is used when BLK_LOG_LEVEL is unset.
+const defaultLevel = slog.LevelInfo
+
+// ConfiguredLevel reads the lowest level that is logged from BLK_LOG_LEVEL:
+// debug, info, warn or error.
+func ConfiguredLevel() (slog.Level, error) {
+	value := os.Getenv("BLK_LOG_LEVEL")
+	if value == "" {
+		return defaultLevel, nil
+	}
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(strings.TrimSpace(value))); err != nil {
+		return 0, fmt.Errorf("invalid BLK_LOG_LEVEL %q: expected debug, info, warn or error", value)
+	}
+
+	return level, nil
+}
@@ -0,0 +1,37 @@
+package logging
+
+import "testing"
+
+func TestConfiguredLevel(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{value: "", want: "INFO"},
+		{value: "debug", want: "DEBUG"},
+		{value: " WARN ", want: "WARN"},
+		{value: "error", want: "ERROR"},
+		{value: "loud", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Setenv("BLK_LOG_LEVEL", tt.value)
+
+			got, err := ConfiguredLevel()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ConfiguredLevel() = %s, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ConfiguredLevel() error = %v", err)
+			}
+			if got.String() != tt.want {
+				t.Errorf("ConfiguredLevel() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
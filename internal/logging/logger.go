@@ -0,0 +1,69 @@
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+)
+
+// Setup makes a JSON logger writing to w at level the default one, for slog
+// and for the standard log package alike.
+func Setup(w io.Writer, level slog.Level) *slog.Logger {
+	logger := New(w, level)
+	slog.SetDefault(logger)
+	return logger
+}
+
+// New returns a JSON logger that adds the attributes carried by the context
+// of each record and redacts sensitive values.
+func New(w io.Writer, level slog.Leveler) *slog.Logger {
+	handler := slog.NewJSONHandler(w, &slog.HandlerOptions{
+		Level:       level,
+		ReplaceAttr: redact,
+	})
+
+	return slog.New(contextHandler{handler})
+}
+
+// Fatal logs msg at error level with args and exits, for the failures a
+// command cannot start without.
+func Fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
+
+type contextKey struct{}
+
+// WithAttrs returns a context whose log records carry attrs, on top of the
+// ones ctx already carries.
+func WithAttrs(ctx context.Context, attrs ...slog.Attr) context.Context {
+	carried, _ := ctx.Value(contextKey{}).([]slog.Attr)
+
+	merged := make([]slog.Attr, 0, len(carried)+len(attrs))
+	merged = append(merged, carried...)
+	merged = append(merged, attrs...)
+
+	return context.WithValue(ctx, contextKey{}, merged)
+}
+
+// contextHandler adds the attributes stored with WithAttrs to the records
+// logged with a context, such as the request ID.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if attrs, ok := ctx.Value(contextKey{}).([]slog.Attr); ok {
+		record.AddAttrs(attrs...)
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,112 @@
+package logging
+
+import (
+	"blockbustermvc/internal/httputil"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"runtime/debug"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	// RequestIDHeader carries the ID of a request, from the client or
+	// proxy that picked it, or back from the server that made one up.
+	RequestIDHeader = "X-Request-ID"
+
+	requestIDContextKey = "request_id"
+)
+
+// requestIDPattern limits the request IDs taken from clients to ones that
+// are safe to log and echo.
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:\-]{1,128}$`)
+
+// RequestID gives every request an ID: the X-Request-ID header when the
+// client sent a usable one, or a new UUID. The ID is echoed in the response
+// and carried by the request context, so every log line written for the
+// request can be told apart.
+func RequestID() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		requestId := ctx.GetHeader(RequestIDHeader)
+		if !requestIDPattern.MatchString(requestId) {
+			requestId = uuid.NewString()
+		}
+
+		ctx.Set(requestIDContextKey, requestId)
+		ctx.Header(RequestIDHeader, requestId)
+		ctx.Request = ctx.Request.WithContext(WithAttrs(ctx.Request.Context(), slog.String("request_id", requestId)))
+
+		ctx.Next()
+	}
+}
+
+// RequestIDOf returns the ID the RequestID middleware gave the request, or
+// "" when it did not run.
+func RequestIDOf(ctx *gin.Context) string {
+	return ctx.GetString(requestIDContextKey)
+}
+
+// AccessLog logs every request once it is served: server errors at error
+// level, client errors at warn and the rest at info. Requests to the quiet
+// paths, such as the health probes, are logged at debug so polling them
+// does not drown the other requests.
+func AccessLog(quiet ...string) gin.HandlerFunc {
+	quietPaths := make(map[string]struct{}, len(quiet))
+	for _, path := range quiet {
+		quietPaths[path] = struct{}{}
+	}
+
+	return func(ctx *gin.Context) {
+		start := time.Now()
+
+		ctx.Next()
+
+		status := ctx.Writer.Status()
+		level := slog.LevelInfo
+		switch {
+		case status >= http.StatusInternalServerError:
+			level = slog.LevelError
+		case status >= http.StatusBadRequest:
+			level = slog.LevelWarn
+		}
+		if _, ok := quietPaths[ctx.Request.URL.Path]; ok {
+			level = slog.LevelDebug
+		}
+
+		// The query string is left out, it may carry search terms and emails
+		attrs := []slog.Attr{
+			slog.String("method", ctx.Request.Method),
+			slog.String("path", ctx.Request.URL.Path),
+			slog.String("route", ctx.FullPath()),
+			slog.Int("status", status),
+			slog.Int("bytes", ctx.Writer.Size()),
+			slog.Int64("duration_ms", time.Since(start).Milliseconds()),
+			slog.String("client_ip", ctx.ClientIP()),
+		}
+		if storeId := httputil.StoreID(ctx); storeId != uuid.Nil {
+			attrs = append(attrs, slog.String("store_id", storeId.String()))
+		}
+		if len(ctx.Errors) > 0 {
+			attrs = append(attrs, slog.String("error", ctx.Errors.String()))
+		}
+
+		slog.LogAttrs(ctx.Request.Context(), level, "Request served", attrs...)
+	}
+}
+
+// Recovery answers a request whose handler panicked with a 500, and logs
+// the panic with the request it happened in.
+func Recovery() gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(io.Discard, func(ctx *gin.Context, recovered any) {
+		slog.ErrorContext(ctx.Request.Context(), "Request handler panicked",
+			"panic", fmt.Sprint(recovered),
+			"stack", string(debug.Stack()),
+		)
+		ctx.AbortWithStatus(http.StatusInternalServerError)
+	})
+}
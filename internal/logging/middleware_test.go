@@ -0,0 +1,60 @@
+package logging
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func TestRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name      string
+		header    string
+		wantEcho  bool
+		wantFresh bool
+	}{
+		{name: "client ID is echoed", header: "req-42.a:b_c", wantEcho: true},
+		{name: "missing ID is made up", header: "", wantFresh: true},
+		{name: "unsafe ID is replaced", header: "bad id\nwith newline", wantFresh: true},
+		{name: "overlong ID is replaced", header: strings.Repeat("a", 129), wantFresh: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var seen string
+
+			router := gin.New()
+			router.Use(RequestID())
+			router.GET("/", func(ctx *gin.Context) {
+				seen = RequestIDOf(ctx)
+				ctx.Status(http.StatusNoContent)
+			})
+
+			request := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				request.Header.Set(RequestIDHeader, tt.header)
+			}
+			response := httptest.NewRecorder()
+			router.ServeHTTP(response, request)
+
+			echoed := response.Header().Get(RequestIDHeader)
+			if echoed != seen {
+				t.Errorf("%s = %q, handler saw %q", RequestIDHeader, echoed, seen)
+			}
+			if tt.wantEcho && echoed != tt.header {
+				t.Errorf("%s = %q, want %q", RequestIDHeader, echoed, tt.header)
+			}
+			if tt.wantFresh {
+				if _, err := uuid.Parse(echoed); err != nil {
+					t.Errorf("%s = %q, want a new UUID", RequestIDHeader, echoed)
+				}
+			}
+		})
+	}
+}
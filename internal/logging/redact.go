@@ -0,0 +1,66 @@
+package logging
+
+import (
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+// redacted replaces the values that must never reach the logs.
+const redacted = "[REDACTED]"
+
+// sensitiveKeys are the attribute keys whose values are always redacted.
+var sensitiveKeys = map[string]struct{}{
+	"email":         {},
+	"password":      {},
+	"secret":        {},
+	"token":         {},
+	"authorization": {},
+	"cookie":        {},
+}
+
+// emailPattern finds the email addresses written into messages and errors,
+// such as a validation error quoting the address it rejected.
+var emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+
+// redact is the ReplaceAttr of the handler: it hides the values of
+// sensitive keys, and masks email addresses within any other text.
+func redact(_ []string, attr slog.Attr) slog.Attr {
+	if isSensitive(attr.Key) {
+		return slog.String(attr.Key, redacted)
+	}
+
+	switch attr.Value.Kind() {
+	case slog.KindString:
+		return slog.String(attr.Key, RedactText(attr.Value.String()))
+	case slog.KindAny:
+		if err, ok := attr.Value.Any().(error); ok {
+			return slog.String(attr.Key, RedactText(err.Error()))
+		}
+	}
+
+	return attr
+}
+
+// RedactText masks the email addresses in text.
+func RedactText(text string) string {
+	if !strings.Contains(text, "@") {
+		return text
+	}
+	return emailPattern.ReplaceAllString(text, redacted)
+}
+
+// isSensitive matches keys such as "email", "user_email" or "smtp_password".
+func isSensitive(key string) bool {
+	key = strings.ToLower(key)
+	if _, ok := sensitiveKeys[key]; ok {
+		return true
+	}
+
+	for sensitive := range sensitiveKeys {
+		if strings.HasSuffix(key, "_"+sensitive) {
+			return true
+		}
+	}
+	return false
+}
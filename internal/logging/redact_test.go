@@ -0,0 +1,85 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+)
+
+func TestRedactText(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "no address", text: "user not found", want: "user not found"},
+		{name: "at sign alone", text: "meet @ noon", want: "meet @ noon"},
+		{name: "address", text: "jane.doe+films@example.co.uk", want: redacted},
+		{name: "address in an error", text: `email "jane@example.com" is already taken`, want: `email "` + redacted + `" is already taken`},
+		{name: "several addresses", text: "a@example.com, b@example.org", want: redacted + ", " + redacted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RedactText(tt.text); got != tt.want {
+				t.Errorf("RedactText(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedact(t *testing.T) {
+	tests := []struct {
+		name string
+		attr slog.Attr
+		want string
+	}{
+		{name: "sensitive key", attr: slog.String("password", "hunter2"), want: redacted},
+		{name: "sensitive key suffix", attr: slog.String("smtp_password", "hunter2"), want: redacted},
+		{name: "sensitive key case", attr: slog.String("Authorization", "Bearer abc"), want: redacted},
+		{name: "sensitive key of another kind", attr: slog.Int("token", 42), want: redacted},
+		{name: "address in a string", attr: slog.String("error", "no user jane@example.com"), want: "no user " + redacted},
+		{name: "address in an error", attr: slog.Any("error", errors.New("no user jane@example.com")), want: "no user " + redacted},
+		{name: "plain string", attr: slog.String("path", "/api/users"), want: "/api/users"},
+		{name: "key that only contains a sensitive word", attr: slog.String("emails_sent", "3"), want: "3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := redact(nil, tt.attr)
+			if got.Key != tt.attr.Key {
+				t.Errorf("redact() key = %q, want %q", got.Key, tt.attr.Key)
+			}
+			if got.Value.String() != tt.want {
+				t.Errorf("redact() value = %q, want %q", got.Value.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestNewRedactsAndAddsContextAttrs(t *testing.T) {
+	var out bytes.Buffer
+	logger := New(&out, slog.LevelInfo)
+
+	ctx := WithAttrs(t.Context(), slog.String("request_id", "abc-123"))
+	logger.ErrorContext(ctx, "Failed to create user", "email", "jane@example.com", "error", errors.New("jane@example.com is taken"))
+
+	var record map[string]any
+	if err := json.Unmarshal(out.Bytes(), &record); err != nil {
+		t.Fatalf("log line is not JSON: %v: %s", err, out.String())
+	}
+
+	want := map[string]any{
+		"msg":        "Failed to create user",
+		"request_id": "abc-123",
+		"email":      redacted,
+		"error":      redacted + " is taken",
+	}
+	for key, value := range want {
+		if record[key] != value {
+			t.Errorf("%s = %v, want %v", key, record[key], value)
+		}
+	}
+}
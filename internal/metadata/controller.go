@@ -0,0 +1,149 @@
+package metadata
+
+import (
+	models "blockbustermvc/internal/models/metadata"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type MetadataController struct {
+	metadataService models.IMetadataService
+}
+
+func NewMetadataController(metadataService models.IMetadataService) *MetadataController {
+	return &MetadataController{
+		metadataService: metadataService,
+	}
+}
+
+func (mc *MetadataController) RegisterRoutes(r *gin.RouterGroup) {
+	r.POST("/movies/:id/enrich", mc.EnrichMovie)
+
+	proposals := r.Group("/metadata/proposals")
+
+	{
+		proposals.POST("/run", mc.ProposeEnrichment)
+		proposals.GET("", mc.GetProposals)
+		proposals.GET("/:id", mc.GetProposal)
+		proposals.POST("/:id/approve", mc.ApproveProposal)
+		proposals.POST("/:id/reject", mc.RejectProposal)
+	}
+}
+
+func (mc *MetadataController) EnrichMovie(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid movie ID",
+		})
+		return
+	}
+
+	movie, err := mc.metadataService.EnrichMovie(ctx.Request.Context(), id)
+	if err != nil {
+		respondWithMetadataError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, movie)
+}
+
+func (mc *MetadataController) ProposeEnrichment(ctx *gin.Context) {
+	run, err := mc.metadataService.ProposeEnrichment(ctx.Request.Context())
+	if err != nil {
+		respondWithMetadataError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, run)
+}
+
+func (mc *MetadataController) GetProposals(ctx *gin.Context) {
+	proposals, err := mc.metadataService.GetProposals(ctx.Request.Context(), ctx.DefaultQuery("status", models.StatusPending))
+	if err != nil {
+		slog.ErrorContext(ctx.Request.Context(), "Failed to get proposals", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, proposals)
+}
+
+func (mc *MetadataController) GetProposal(ctx *gin.Context) {
+	id, ok := parseProposalID(ctx)
+	if !ok {
+		return
+	}
+
+	proposal, err := mc.metadataService.GetProposal(ctx.Request.Context(), id)
+	if err != nil {
+		respondWithMetadataError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, proposal)
+}
+
+func (mc *MetadataController) ApproveProposal(ctx *gin.Context) {
+	id, ok := parseProposalID(ctx)
+	if !ok {
+		return
+	}
+
+	proposal, err := mc.metadataService.ApproveProposal(ctx.Request.Context(), id)
+	if err != nil {
+		respondWithMetadataError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, proposal)
+}
+
+func (mc *MetadataController) RejectProposal(ctx *gin.Context) {
+	id, ok := parseProposalID(ctx)
+	if !ok {
+		return
+	}
+
+	proposal, err := mc.metadataService.RejectProposal(ctx.Request.Context(), id)
+	if err != nil {
+		respondWithMetadataError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, proposal)
+}
+
+func parseProposalID(ctx *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid proposal ID",
+		})
+		return uuid.Nil, false
+	}
+
+	return id, true
+}
+
+func respondWithMetadataError(ctx *gin.Context, err error) {
+	status := http.StatusUnprocessableEntity
+	switch {
+	case errors.Is(err, models.ErrNoProvider):
+		status = http.StatusServiceUnavailable
+	case errors.Is(err, models.ErrNoMatch), errors.Is(err, models.ErrProposalNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, models.ErrProposalDecided), errors.Is(err, models.ErrStaleProposal):
+		status = http.StatusConflict
+	}
+
+	ctx.JSON(status, gin.H{
+		"error": err.Error(),
+	})
+}
@@ -0,0 +1,181 @@
+package metadata
+
+import (
+	models "blockbustermvc/internal/models/metadata"
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// tmdbImageBase turns a TMDb poster_path into a full cover URL.
+const tmdbImageBase = "https://image.tmdb.org/t/p/w500"
+
+// dumpRecord covers the members of OMDb and TMDb movie exports that map to
+// our catalog. JSON member names match case-insensitively, so OMDb's
+// "Title" and TMDb's "title" land in the same field.
+type dumpRecord struct {
+	Title         string          `json:"title"`
+	OriginalTitle string          `json:"original_title"`
+	Year          json.RawMessage `json:"year"`
+	ReleaseDate   string          `json:"release_date"`
+	Director      string          `json:"director"`
+	Plot          string          `json:"plot"`
+	Overview      string          `json:"overview"`
+	Poster        string          `json:"poster"`
+	PosterPath    string          `json:"poster_path"`
+	IMDbID        string          `json:"imdbID"`
+	TMDbID        json.Number     `json:"id"`
+	Credits       struct {
+		Crew []struct {
+			Job  string `json:"job"`
+			Name string `json:"name"`
+		} `json:"crew"`
+	} `json:"credits"`
+}
+
+// jsonDumpProvider answers lookups from a local OMDb or TMDb JSON export,
+// loaded into memory once.
+type jsonDumpProvider struct {
+	byTitle map[string][]*models.Metadata
+}
+
+// NewJSONDumpProvider reads a dump file holding either a JSON array of
+// movies or one movie per line (JSON Lines), as both services export them.
+func NewJSONDumpProvider(path string) (models.MetadataProvider, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read metadata dump: %w", err)
+	}
+
+	var records []*dumpRecord
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &records); err != nil {
+			return nil, fmt.Errorf("failed to parse metadata dump: %w", err)
+		}
+	} else {
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+		for line := 1; scanner.Scan(); line++ {
+			if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+				continue
+			}
+
+			var record dumpRecord
+			if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+				return nil, fmt.Errorf("failed to parse metadata dump line %d: %w", line, err)
+			}
+			records = append(records, &record)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read metadata dump: %w", err)
+		}
+	}
+
+	provider := &jsonDumpProvider{byTitle: make(map[string][]*models.Metadata)}
+	for _, record := range records {
+		metadata := record.toMetadata()
+		for _, title := range []string{record.Title, record.OriginalTitle} {
+			if key := titleKey(title); key != "" {
+				provider.byTitle[key] = append(provider.byTitle[key], metadata)
+			}
+		}
+	}
+
+	return provider, nil
+}
+
+func (p *jsonDumpProvider) Name() string {
+	return "json_dump"
+}
+
+// Lookup matches titles case-insensitively. When the dump has several
+// movies of that title, or the year disagrees, only an entry of the same
+// year is accepted so remakes are not mixed up.
+func (p *jsonDumpProvider) Lookup(title string, year int64) (*models.Metadata, error) {
+	candidates := p.byTitle[titleKey(title)]
+
+	for _, candidate := range candidates {
+		if year != 0 && candidate.Year == year {
+			return candidate, nil
+		}
+	}
+
+	if len(candidates) == 1 && (year == 0 || candidates[0].Year == 0) {
+		return candidates[0], nil
+	}
+
+	return nil, models.ErrNoMatch
+}
+
+func (r *dumpRecord) toMetadata() *models.Metadata {
+	metadata := &models.Metadata{
+		Title:    known(r.Title),
+		Director: known(r.Director),
+		Synopsis: known(r.Plot),
+		CoverURL: known(r.Poster),
+		Year:     parseYear(r.Year),
+	}
+
+	if r.IMDbID != "" {
+		metadata.Source = "omdb"
+		metadata.ExternalID = r.IMDbID
+	} else {
+		metadata.Source = "tmdb"
+		metadata.ExternalID = r.TMDbID.String()
+	}
+
+	if metadata.Year == 0 && len(r.ReleaseDate) >= 4 {
+		metadata.Year, _ = strconv.ParseInt(r.ReleaseDate[:4], 10, 64)
+	}
+	if metadata.Synopsis == "" {
+		metadata.Synopsis = known(r.Overview)
+	}
+	if metadata.CoverURL == "" && r.PosterPath != "" {
+		metadata.CoverURL = tmdbImageBase + r.PosterPath
+	}
+	if metadata.Director == "" {
+		var directors []string
+		for _, member := range r.Credits.Crew {
+			if member.Job == "Director" {
+				directors = append(directors, member.Name)
+			}
+		}
+		metadata.Director = strings.Join(directors, ", ")
+	}
+
+	return metadata
+}
+
+// parseYear reads OMDb years such as "1979" or "1979–1983" as well as plain
+// numbers.
+func parseYear(raw json.RawMessage) int64 {
+	value := strings.Trim(string(raw), `" `)
+	if len(value) < 4 {
+		return 0
+	}
+
+	year, err := strconv.ParseInt(value[:4], 10, 64)
+	if err != nil {
+		return 0
+	}
+
+	return year
+}
+
+// known drops OMDb's "N/A" placeholder.
+func known(value string) string {
+	value = strings.TrimSpace(value)
+	if value == "N/A" {
+		return ""
+	}
+
+	return value
+}
+
+func titleKey(title string) string {
+	return strings.ToLower(strings.Join(strings.Fields(title), " "))
+}
@@ -0,0 +1,16 @@
+package metadata
+
+import (
+	models "blockbustermvc/internal/models/metadata"
+	"os"
+)
+
+// NewConfiguredProvider returns the metadata provider selected by the
+// environment, or nil when none is configured.
+func NewConfiguredProvider() (models.MetadataProvider, error) {
+	if path := os.Getenv("BLK_METADATA_DUMP"); path != "" {
+		return NewJSONDumpProvider(path)
+	}
+
+	return nil, nil
+}
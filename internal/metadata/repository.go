@@ -0,0 +1,364 @@
+package metadata
+
+import (
+	models "blockbustermvc/internal/models/metadata"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const selectProposal = `
+	SELECT p.id, p.movie_id, m.name, p.source, p.external_id, p.changes, p.status, p.decided_at, p.created_at, p.updated_at
+	FROM metadata_proposals p
+	JOIN movies m ON m.id = p.movie_id`
+
+/*
+metadataRepository is a struct that represents a Postgres database for movie metadata enrichment.
+
+Fields:
+- DB (*pgxpool.Pool): A pointer to a Postgres database connection pool.
+
+Behavior:
+- Applies metadata changes to movies and stores the proposals of catalog-wide enrichment runs until staff decide on them.
+*/
+type metadataRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewMetadataRepository(db *pgxpool.Pool) models.IMetadataRepository {
+	return &metadataRepository{
+		DB: db,
+	}
+}
+
+/*
+ApplyChanges is a method of metadataRepository struct that writes metadata changes to a movie in the postgres database.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- movieId (uuid.UUID): The ID of the movie to change.
+- changes ([]*models.FieldChangeDTO): The fields to change with the values they were computed against.
+
+Returns:
+- (error): An error if the changes cannot be applied.
+
+Behavior:
+- Locks the movie, checks every field still holds its Current value, then sets the Proposed values and increments the movie version.
+- Returns models.ErrStaleProposal if any field changed in the meantime; nothing is written then.
+*/
+func (r *metadataRepository) ApplyChanges(ctx context.Context, movieId uuid.UUID, changes []*models.FieldChangeDTO) error {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin metadata update: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := applyChanges(ctx, tx, movieId, changes); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit metadata update: %w", err)
+	}
+
+	return nil
+}
+
+/*
+ReplacePendingProposals is a method of metadataRepository struct that stores the proposals of an enrichment run in the postgres database.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- proposals ([]*models.CreateProposalDTO): The proposals of the run, at most one per movie.
+
+Returns:
+- (error): An error if the proposals cannot be stored.
+
+Behavior:
+- Discards every proposal still pending from earlier runs and inserts the new ones in one transaction.
+- Approved and rejected proposals are kept as history.
+*/
+func (r *metadataRepository) ReplacePendingProposals(ctx context.Context, proposals []*models.CreateProposalDTO) error {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin proposal replacement: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM metadata_proposals WHERE status = $1`, models.StatusPending); err != nil {
+		return fmt.Errorf("failed to discard pending proposals: %w", err)
+	}
+
+	now := time.Now()
+	for _, proposal := range proposals {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO metadata_proposals (movie_id, source, external_id, changes, status, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $6)`,
+			proposal.MovieID,
+			proposal.Source,
+			proposal.ExternalID,
+			proposal.Changes,
+			models.StatusPending,
+			now,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create proposal: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit proposal replacement: %w", err)
+	}
+
+	return nil
+}
+
+/*
+GetProposals is a method of metadataRepository struct that retrieves metadata proposals from the postgres database.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- status (string): Only proposals with this status are returned; an empty status returns all of them.
+
+Returns:
+- ([]*models.ProposalDTO, error): A slice of ProposalDTO structs, newest first, or an error if the retrieval fails.
+*/
+func (r *metadataRepository) GetProposals(ctx context.Context, status string) ([]*models.ProposalDTO, error) {
+	query := selectProposal + `
+		WHERE $1 = '' OR p.status = $1
+		ORDER BY p.created_at DESC, m.name`
+
+	rows, err := r.DB.Query(ctx, query, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get proposals: %w", err)
+	}
+	defer rows.Close()
+
+	var proposals []*models.ProposalDTO
+	for rows.Next() {
+		proposal, err := scanProposal(rows)
+		if err != nil {
+			return nil, err
+		}
+		proposals = append(proposals, proposal)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over proposals: %w", err)
+	}
+
+	return proposals, nil
+}
+
+/*
+GetProposal is a method of metadataRepository struct that retrieves one metadata proposal from the postgres database.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- id (uuid.UUID): The ID of the proposal.
+
+Returns:
+- (*models.ProposalDTO, error): A pointer to a ProposalDTO struct, or an error if the retrieval fails.
+
+Behavior:
+- Returns models.ErrProposalNotFound if there is no proposal with that ID.
+*/
+func (r *metadataRepository) GetProposal(ctx context.Context, id uuid.UUID) (*models.ProposalDTO, error) {
+	return scanProposal(r.DB.QueryRow(ctx, selectProposal+` WHERE p.id = $1`, id))
+}
+
+/*
+ApproveProposal is a method of metadataRepository struct that applies a pending metadata proposal in the postgres database.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- id (uuid.UUID): The ID of the proposal.
+
+Returns:
+- (*models.ProposalDTO, error): A pointer to a ProposalDTO struct containing the approved proposal, or an error if the approval fails.
+
+Behavior:
+- Locks the proposal, applies its changes to the movie and marks it approved in one transaction.
+- Returns models.ErrProposalDecided if the proposal is no longer pending.
+- Returns models.ErrStaleProposal if the movie changed since the proposal was made.
+*/
+func (r *metadataRepository) ApproveProposal(ctx context.Context, id uuid.UUID) (*models.ProposalDTO, error) {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin proposal approval: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var movieId uuid.UUID
+	var changes []*models.FieldChangeDTO
+	if err := lockPendingProposal(ctx, tx, id, &movieId, &changes); err != nil {
+		return nil, err
+	}
+
+	if err := applyChanges(ctx, tx, movieId, changes); err != nil {
+		return nil, err
+	}
+
+	if err := decideProposal(ctx, tx, id, models.StatusApproved); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit proposal approval: %w", err)
+	}
+
+	return r.GetProposal(ctx, id)
+}
+
+/*
+RejectProposal is a method of metadataRepository struct that rejects a pending metadata proposal in the postgres database.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- id (uuid.UUID): The ID of the proposal.
+
+Returns:
+- (*models.ProposalDTO, error): A pointer to a ProposalDTO struct containing the rejected proposal, or an error if the rejection fails.
+
+Behavior:
+- Marks the proposal rejected without touching the movie.
+- Returns models.ErrProposalDecided if the proposal is no longer pending.
+*/
+func (r *metadataRepository) RejectProposal(ctx context.Context, id uuid.UUID) (*models.ProposalDTO, error) {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin proposal rejection: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var movieId uuid.UUID
+	var changes []*models.FieldChangeDTO
+	if err := lockPendingProposal(ctx, tx, id, &movieId, &changes); err != nil {
+		return nil, err
+	}
+
+	if err := decideProposal(ctx, tx, id, models.StatusRejected); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit proposal rejection: %w", err)
+	}
+
+	return r.GetProposal(ctx, id)
+}
+
+func lockPendingProposal(ctx context.Context, tx pgx.Tx, id uuid.UUID, movieId *uuid.UUID, changes *[]*models.FieldChangeDTO) error {
+	var status string
+	err := tx.QueryRow(ctx, `SELECT movie_id, changes, status FROM metadata_proposals WHERE id = $1 FOR UPDATE`, id).
+		Scan(movieId, changes, &status)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.ErrProposalNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lock proposal: %w", err)
+	}
+
+	if status != models.StatusPending {
+		return models.ErrProposalDecided
+	}
+
+	return nil
+}
+
+func decideProposal(ctx context.Context, tx pgx.Tx, id uuid.UUID, status string) error {
+	now := time.Now()
+	_, err := tx.Exec(ctx, `UPDATE metadata_proposals SET status = $2, decided_at = $3, updated_at = $3 WHERE id = $1`,
+		id,
+		status,
+		now,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update proposal: %w", err)
+	}
+
+	return nil
+}
+
+func applyChanges(ctx context.Context, tx pgx.Tx, movieId uuid.UUID, changes []*models.FieldChangeDTO) error {
+	var director, synopsis, coverURL string
+	var year int64
+	err := tx.QueryRow(ctx, `SELECT director, year, synopsis, cover_url FROM movies WHERE id = $1 FOR UPDATE`, movieId).
+		Scan(&director, &year, &synopsis, &coverURL)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("movie with id %s not found", movieId)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lock movie: %w", err)
+	}
+
+	for _, change := range changes {
+		var current string
+		switch change.Field {
+		case models.FieldDirector:
+			current, director = director, change.Proposed
+		case models.FieldYear:
+			current = strconv.FormatInt(year, 10)
+			if year, err = strconv.ParseInt(change.Proposed, 10, 64); err != nil {
+				return fmt.Errorf("invalid proposed year %q: %w", change.Proposed, err)
+			}
+		case models.FieldSynopsis:
+			current, synopsis = synopsis, change.Proposed
+		case models.FieldCoverURL:
+			current, coverURL = coverURL, change.Proposed
+		default:
+			return fmt.Errorf("unknown metadata field %q", change.Field)
+		}
+
+		if current != change.Current {
+			return models.ErrStaleProposal
+		}
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE movies
+		SET director = $2, year = $3, synopsis = $4, cover_url = $5, version = version + 1, updated_at = $6
+		WHERE id = $1`,
+		movieId,
+		director,
+		year,
+		synopsis,
+		coverURL,
+		time.Now(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update movie metadata: %w", err)
+	}
+
+	return nil
+}
+
+func scanProposal(row pgx.Row) (*models.ProposalDTO, error) {
+	var proposal models.ProposalDTO
+	err := row.Scan(
+		&proposal.ID,
+		&proposal.MovieID,
+		&proposal.MovieName,
+		&proposal.Source,
+		&proposal.ExternalID,
+		&proposal.Changes,
+		&proposal.Status,
+		&proposal.DecidedAt,
+		&proposal.CreatedAt,
+		&proposal.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, models.ErrProposalNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan proposal: %w", err)
+	}
+
+	return &proposal, nil
+}
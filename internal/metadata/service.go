@@ -0,0 +1,145 @@
+package metadata
+
+import (
+	models "blockbustermvc/internal/models/metadata"
+	movieService "blockbustermvc/internal/models/movie"
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+type MetadataService struct {
+	metadataRepository models.IMetadataRepository
+	movieService       movieService.IMovieService
+	provider           models.MetadataProvider
+}
+
+// NewMetadataService builds the service around a provider; a nil provider
+// leaves enrichment disabled while proposals can still be reviewed.
+func NewMetadataService(
+	metadataRepo models.IMetadataRepository,
+	movieService movieService.IMovieService,
+	provider models.MetadataProvider,
+) models.IMetadataService {
+	return &MetadataService{
+		metadataRepository: metadataRepo,
+		movieService:       movieService,
+		provider:           provider,
+	}
+}
+
+func (m MetadataService) EnrichMovie(ctx context.Context, movieId uuid.UUID) (*movieService.MovieDTO, error) {
+	if m.provider == nil {
+		return nil, models.ErrNoProvider
+	}
+
+	movie, err := m.movieService.GetMovie(ctx, movieId)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err := m.provider.Lookup(movie.Name, movie.Year)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := missingFields(movie, metadata)
+	if len(changes) == 0 {
+		return nil, models.ErrNothingToEnrich
+	}
+
+	if err := m.metadataRepository.ApplyChanges(ctx, movie.ID, changes); err != nil {
+		return nil, err
+	}
+
+	return m.movieService.GetMovie(ctx, movie.ID)
+}
+
+func (m MetadataService) ProposeEnrichment(ctx context.Context) (*models.EnrichmentRunDTO, error) {
+	if m.provider == nil {
+		return nil, models.ErrNoProvider
+	}
+
+	movies, err := m.movieService.GetAllMovies(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	run := &models.EnrichmentRunDTO{Provider: m.provider.Name()}
+
+	var proposals []*models.CreateProposalDTO
+	for _, movie := range movies {
+		run.Checked++
+
+		metadata, err := m.provider.Lookup(movie.Name, movie.Year)
+		if errors.Is(err, models.ErrNoMatch) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		run.Matched++
+
+		changes := missingFields(movie, metadata)
+		if len(changes) == 0 {
+			continue
+		}
+
+		proposals = append(proposals, &models.CreateProposalDTO{
+			MovieID:    movie.ID,
+			Source:     metadata.Source,
+			ExternalID: metadata.ExternalID,
+			Changes:    changes,
+		})
+	}
+
+	if err := m.metadataRepository.ReplacePendingProposals(ctx, proposals); err != nil {
+		return nil, err
+	}
+	run.Proposed = len(proposals)
+
+	return run, nil
+}
+
+func (m MetadataService) GetProposals(ctx context.Context, status string) ([]*models.ProposalDTO, error) {
+	return m.metadataRepository.GetProposals(ctx, status)
+}
+
+func (m MetadataService) GetProposal(ctx context.Context, id uuid.UUID) (*models.ProposalDTO, error) {
+	return m.metadataRepository.GetProposal(ctx, id)
+}
+
+func (m MetadataService) ApproveProposal(ctx context.Context, id uuid.UUID) (*models.ProposalDTO, error) {
+	return m.metadataRepository.ApproveProposal(ctx, id)
+}
+
+func (m MetadataService) RejectProposal(ctx context.Context, id uuid.UUID) (*models.ProposalDTO, error) {
+	return m.metadataRepository.RejectProposal(ctx, id)
+}
+
+// missingFields lists the fields the movie lacks and the provider knows.
+// Fields the movie already has are never overwritten.
+func missingFields(movie *movieService.MovieDTO, metadata *models.Metadata) []*models.FieldChangeDTO {
+	var changes []*models.FieldChangeDTO
+
+	if movie.Director == "" && metadata.Director != "" {
+		changes = append(changes, &models.FieldChangeDTO{Field: models.FieldDirector, Proposed: metadata.Director})
+	}
+	if movie.Year == 0 && metadata.Year != 0 {
+		changes = append(changes, &models.FieldChangeDTO{
+			Field:    models.FieldYear,
+			Current:  "0",
+			Proposed: strconv.FormatInt(metadata.Year, 10),
+		})
+	}
+	if movie.Synopsis == "" && metadata.Synopsis != "" {
+		changes = append(changes, &models.FieldChangeDTO{Field: models.FieldSynopsis, Proposed: metadata.Synopsis})
+	}
+	if movie.CoverURL == "" && metadata.CoverURL != "" {
+		changes = append(changes, &models.FieldChangeDTO{Field: models.FieldCoverURL, Proposed: metadata.CoverURL})
+	}
+
+	return changes
+}
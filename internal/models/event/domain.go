@@ -0,0 +1,52 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const (
+	TypeLoanCreated    = "loan.created"
+	TypeMovieReturned  = "movie.returned"
+	TypeStockChanged   = "stock.changed"
+	TypeUserRegistered = "user.registered"
+)
+
+// Types lists every domain event type.
+var Types = []string{TypeLoanCreated, TypeMovieReturned, TypeStockChanged, TypeUserRegistered}
+
+const (
+	// StatusPending events still have subscribers to deliver to.
+	StatusPending    = "pending"
+	StatusDispatched = "dispatched"
+
+	// StatusFailed events gave up after MaxAttempts; redelivering them
+	// retries the subscribers that have not acknowledged them yet.
+	StatusFailed = "failed"
+)
+
+// MaxAttempts is how many times an event is offered to its subscribers
+// before it is marked failed.
+const MaxAttempts = 10
+
+var (
+	ErrEventNotFound = errors.New("event not found")
+	ErrNotFailed     = errors.New("only failed events can be redelivered")
+)
+
+// Subscriber handles a delivered event. Delivery is at least once, so a
+// subscriber may see the same event again and must be idempotent;
+// returning an error has the event delivered to it again later.
+type Subscriber func(ctx context.Context, event *EventDTO) error
+
+// RetryDelay is how long to wait before offering an event again: 10
+// seconds after the first failure, doubling up to an hour.
+func RetryDelay(attempts int) time.Duration {
+	delay := 10 * time.Second << (attempts - 1)
+	if attempts < 1 || delay > time.Hour || delay <= 0 {
+		return time.Hour
+	}
+
+	return delay
+}
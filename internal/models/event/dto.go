@@ -0,0 +1,60 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type EventDTO struct {
+	ID            uuid.UUID       `json:"id"`
+	Type          string          `json:"type"`
+	AggregateID   uuid.UUID       `json:"aggregate_id"`
+	Payload       json.RawMessage `json:"payload"`
+	Status        string          `json:"status"`
+	Attempts      int             `json:"attempts"`
+	LastError     string          `json:"last_error"`
+	DeliveredTo   []string        `json:"delivered_to"`
+	NextAttemptAt time.Time       `json:"next_attempt_at"`
+	DispatchedAt  *time.Time      `json:"dispatched_at,omitempty"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+type LoanCreatedPayload struct {
+	LoanID     uuid.UUID `json:"loan_id"`
+	MovieID    uuid.UUID `json:"movie_id"`
+	UserID     uuid.UUID `json:"user_id"`
+	StoreID    uuid.UUID `json:"store_id"`
+	BorrowedAt time.Time `json:"borrowed_at"`
+}
+
+type MovieReturnedPayload struct {
+	LoanID     uuid.UUID `json:"loan_id"`
+	MovieID    uuid.UUID `json:"movie_id"`
+	UserID     uuid.UUID `json:"user_id"`
+	StoreID    uuid.UUID `json:"store_id"`
+	ReturnedAt time.Time `json:"returned_at"`
+}
+
+type StockChangedPayload struct {
+	MovieID       uuid.UUID  `json:"movie_id"`
+	StoreID       uuid.UUID  `json:"store_id"`
+	Reason        string     `json:"reason"`
+	QuantityDelta int64      `json:"quantity_delta"`
+	StoreQuantity int64      `json:"store_quantity"`
+	TotalQuantity int64      `json:"total_quantity"`
+	LoanID        *uuid.UUID `json:"loan_id,omitempty"`
+}
+
+type UserRegisteredPayload struct {
+	UserID   uuid.UUID `json:"user_id"`
+	UserName string    `json:"user_name"`
+	Email    string    `json:"email"`
+}
+
+type DispatchResultDTO struct {
+	Events    int `json:"events"`
+	Delivered int `json:"delivered"`
+	Failed    int `json:"failed"`
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type IEventService interface {
+	Subscribe(name string, handler Subscriber, eventTypes ...string)
+	GetEvents(ctx context.Context, eventType, status string) ([]*EventDTO, error)
+	GetEvent(ctx context.Context, id uuid.UUID) (*EventDTO, error)
+	Redeliver(ctx context.Context, id uuid.UUID) (*EventDTO, error)
+	Dispatch(ctx context.Context) (*DispatchResultDTO, error)
+	Prune(ctx context.Context, olderThan time.Duration) (int64, error)
+}
+
+type IEventRepository interface {
+	GetEvents(ctx context.Context, eventType, status string, limit int) ([]*EventDTO, error)
+	GetEvent(ctx context.Context, id uuid.UUID) (*EventDTO, error)
+	Requeue(ctx context.Context, id uuid.UUID) (*EventDTO, error)
+	ClaimDue(ctx context.Context, limit int) ([]*EventDTO, error)
+	MarkDelivered(ctx context.Context, id uuid.UUID, subscriber string) error
+	Finish(ctx context.Context, id uuid.UUID) error
+	Fail(ctx context.Context, id uuid.UUID, message string, nextAttemptAt time.Time, failed bool) error
+	Prune(ctx context.Context, dispatchedBefore time.Time) (int64, error)
+}
+
+// IEventStream hands out domain events as they are committed, by any
+// process, to whoever is listening at the time. Nothing is replayed.
+type IEventStream interface {
+	Subscribe() (<-chan *EventDTO, func())
+}
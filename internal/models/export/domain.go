@@ -0,0 +1,23 @@
+package models
+
+import "errors"
+
+const (
+	FormatCSV    = "csv"
+	FormatJSON   = "json"
+	FormatNDJSON = "ndjson"
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported export format, use csv, json or ndjson")
+	ErrInvalidStatus     = errors.New("invalid loan status, use active or returned")
+)
+
+var (
+	MovieColumns = []string{"id", "name", "director", "year", "quantity", "created_at", "updated_at"}
+	UserColumns  = []string{"id", "user_name", "email", "created_at", "updated_at"}
+	LoanColumns  = []string{
+		"id", "movie_id", "movie_name", "user_id", "user_name", "store_id", "return_store_id",
+		"borrowed_at", "returned_at", "status", "archived", "created_at", "updated_at",
+	}
+)
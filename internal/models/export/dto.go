@@ -0,0 +1,104 @@
+package models
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ExportFilterDTO mirrors the filters of the web list pages, so an export
+// holds exactly the rows the page shows.
+type ExportFilterDTO struct {
+	StoreID         uuid.UUID
+	Query           string
+	Status          string
+	IncludeArchived bool
+}
+
+type MovieRowDTO struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	Director  string    `json:"director"`
+	Year      int64     `json:"year"`
+	Quantity  int64     `json:"quantity"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (m *MovieRowDTO) Record() []string {
+	return []string{
+		m.ID.String(),
+		m.Name,
+		m.Director,
+		strconv.FormatInt(m.Year, 10),
+		strconv.FormatInt(m.Quantity, 10),
+		formatTime(&m.CreatedAt),
+		formatTime(&m.UpdatedAt),
+	}
+}
+
+type UserRowDTO struct {
+	ID        uuid.UUID `json:"id"`
+	UserName  string    `json:"user_name"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (u *UserRowDTO) Record() []string {
+	return []string{
+		u.ID.String(),
+		u.UserName,
+		u.Email,
+		formatTime(&u.CreatedAt),
+		formatTime(&u.UpdatedAt),
+	}
+}
+
+type LoanRowDTO struct {
+	ID            uuid.UUID  `json:"id"`
+	MovieID       uuid.UUID  `json:"movie_id"`
+	MovieName     string     `json:"movie_name"`
+	UserID        uuid.UUID  `json:"user_id"`
+	UserName      string     `json:"user_name"`
+	StoreID       uuid.UUID  `json:"store_id"`
+	ReturnStoreID *uuid.UUID `json:"return_store_id"`
+	BorrowedAt    time.Time  `json:"borrowed_at"`
+	ReturnedAt    *time.Time `json:"returned_at"`
+	Status        string     `json:"status"`
+	Archived      bool       `json:"archived"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+func (l *LoanRowDTO) Record() []string {
+	returnStoreId := ""
+	if l.ReturnStoreID != nil {
+		returnStoreId = l.ReturnStoreID.String()
+	}
+
+	return []string{
+		l.ID.String(),
+		l.MovieID.String(),
+		l.MovieName,
+		l.UserID.String(),
+		l.UserName,
+		l.StoreID.String(),
+		returnStoreId,
+		formatTime(&l.BorrowedAt),
+		formatTime(l.ReturnedAt),
+		l.Status,
+		strconv.FormatBool(l.Archived),
+		formatTime(&l.CreatedAt),
+		formatTime(&l.UpdatedAt),
+	}
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,15 @@
+package models
+
+import "context"
+
+type IExportService interface {
+	ExportMovies(ctx context.Context, filter *ExportFilterDTO, yield func(*MovieRowDTO) error) error
+	ExportUsers(ctx context.Context, filter *ExportFilterDTO, yield func(*UserRowDTO) error) error
+	ExportLoans(ctx context.Context, filter *ExportFilterDTO, yield func(*LoanRowDTO) error) error
+}
+
+type IExportRepository interface {
+	StreamMovies(ctx context.Context, filter *ExportFilterDTO, yield func(*MovieRowDTO) error) error
+	StreamUsers(ctx context.Context, filter *ExportFilterDTO, yield func(*UserRowDTO) error) error
+	StreamLoans(ctx context.Context, filter *ExportFilterDTO, yield func(*LoanRowDTO) error) error
+}
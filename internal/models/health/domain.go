@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+const (
+	StatusOK      = "ok"
+	StatusFailing = "failing"
+
+	CheckDatabase   = "database"
+	CheckMigrations = "migrations"
+	CheckShutdown   = "shutdown"
+
+	// CheckTimeout bounds how long the database checks of /readyz may take.
+	CheckTimeout = 2 * time.Second
+)
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+type CheckDTO struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Detail string `json:"detail,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// HealthDTO is ok when every check is.
+type HealthDTO struct {
+	Status    string      `json:"status"`
+	Checks    []*CheckDTO `json:"checks,omitempty"`
+	CheckedAt time.Time   `json:"checked_at"`
+}
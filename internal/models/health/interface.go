@@ -0,0 +1,20 @@
+package models
+
+import "context"
+
+type IHealthService interface {
+	Live() *HealthDTO
+	Ready(ctx context.Context) *HealthDTO
+	Drain()
+}
+
+type IHealthRepository interface {
+	Ping(ctx context.Context) error
+	GetMigrationVersion(ctx context.Context) (int, error)
+}
+
+// IWorkerMonitor follows a background worker running in this process.
+type IWorkerMonitor interface {
+	Name() string
+	Check() error
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	KindMovies = "movies"
+	KindCopies = "copies"
+	KindUsers  = "users"
+	KindLoans  = "loans"
+
+	FormatCSV   = "csv"
+	FormatJSONL = "jsonl"
+)
+
+const (
+	LookupMovieID        = "movie_id"
+	LookupMovieName      = "movie_name"
+	LookupMovieDirector  = "movie_director"
+	LookupUserID         = "user_id"
+	LookupUserName       = "user_name"
+	LookupUserEmail      = "user_email"
+	LookupBarcode        = "barcode"
+	LookupActiveLoanUser = "active_loan_user"
+)
+
+var (
+	ErrUnsupportedKind   = errors.New("unsupported import kind, use movies, copies, users or loans")
+	ErrUnsupportedFormat = errors.New("unsupported import format, use csv or jsonl")
+	ErrInvalidFile       = errors.New("invalid import file")
+)
+
+// FormatFromFileName guesses the import format from a file extension and
+// returns an empty string when it cannot tell.
+func FormatFromFileName(name string) string {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".csv":
+		return FormatCSV
+	case ".jsonl", ".ndjson":
+		return FormatJSONL
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	movieModels "blockbustermvc/internal/models/movie"
+	userModels "blockbustermvc/internal/models/user"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MovieRow is one movie of an import file. The optional id lets copies and
+// loans imported afterwards refer to the movie.
+type MovieRow struct {
+	ID *uuid.UUID `json:"id"`
+	movieModels.CreateMovieDTO
+}
+
+// UserRow is one user of an import file. The optional id lets loans
+// imported afterwards refer to the user.
+type UserRow struct {
+	ID *uuid.UUID `json:"id"`
+	userModels.CreateUserDTO
+}
+
+type CopyRow struct {
+	MovieID uuid.UUID `json:"movie_id" binding:"required"`
+	movieModels.CreateCopyDTO
+}
+
+// LoanRow is one open loan of an import file; a zero BorrowedAt means now.
+type LoanRow struct {
+	MovieID    uuid.UUID `json:"movie_id" binding:"required"`
+	UserID     uuid.UUID `json:"user_id" binding:"required"`
+	BorrowedAt time.Time `json:"borrowed_at"`
+}
+
+type ImportOptionsDTO struct {
+	Kind    string
+	Format  string
+	StoreID uuid.UUID
+	DryRun  bool
+}
+
+type RowErrorDTO struct {
+	Row    int               `json:"row"`
+	Errors map[string]string `json:"errors"`
+}
+
+type ImportResultDTO struct {
+	Kind     string         `json:"kind"`
+	Format   string         `json:"format"`
+	StoreID  uuid.UUID      `json:"store_id"`
+	DryRun   bool           `json:"dry_run"`
+	Total    int            `json:"total"`
+	Valid    int            `json:"valid"`
+	Invalid  int            `json:"invalid"`
+	Imported int64          `json:"imported"`
+	Errors   []*RowErrorDTO `json:"errors"`
+}
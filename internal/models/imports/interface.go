@@ -0,0 +1,21 @@
+package models
+
+import (
+	"context"
+	"io"
+
+	"github.com/google/uuid"
+)
+
+type IImportService interface {
+	Import(ctx context.Context, options *ImportOptionsDTO, file io.Reader) (*ImportResultDTO, error)
+}
+
+type IImportRepository interface {
+	FindExisting(ctx context.Context, lookup string, values []string) (map[string]bool, error)
+	GetStoreStock(ctx context.Context, storeId uuid.UUID, movieIds []uuid.UUID) (map[uuid.UUID]int64, error)
+	ImportMovies(ctx context.Context, storeId uuid.UUID, rows []*MovieRow) (int64, error)
+	ImportCopies(ctx context.Context, rows []*CopyRow) (int64, error)
+	ImportUsers(ctx context.Context, rows []*UserRow) (int64, error)
+	ImportLoans(ctx context.Context, storeId uuid.UUID, rows []*LoanRow) (int64, error)
+}
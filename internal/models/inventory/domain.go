@@ -0,0 +1,19 @@
+package models
+
+import "errors"
+
+const (
+	ReasonPurchase         = "purchase"
+	ReasonLoan             = "loan"
+	ReasonReturn           = "return"
+	ReasonLoss             = "loss"
+	ReasonDamage           = "damage"
+	ReasonManualCorrection = "manual_correction"
+	ReasonTransferOut      = "transfer_out"
+	ReasonTransferIn       = "transfer_in"
+)
+
+var (
+	ErrInsufficientStock = errors.New("not enough copies in stock")
+	ErrInvalidMovement   = errors.New("invalid stock movement")
+)
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type MovementDTO struct {
+	ID            uuid.UUID  `json:"id"`
+	MovieID       uuid.UUID  `json:"movie_id"`
+	StoreID       uuid.UUID  `json:"store_id"`
+	Reason        string     `json:"reason"`
+	QuantityDelta int64      `json:"quantity_delta"`
+	QuantityAfter int64      `json:"quantity_after"`
+	LoanID        *uuid.UUID `json:"loan_id,omitempty"`
+	Note          string     `json:"note"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+type CreateMovementDTO struct {
+	Reason        string     `json:"reason" binding:"required,oneof=purchase loss damage manual_correction"`
+	QuantityDelta int64      `json:"quantity_delta" binding:"required"`
+	Note          string     `json:"note" binding:"max=500"`
+	StoreID       uuid.UUID  `json:"-"`
+	LoanID        *uuid.UUID `json:"-"`
+	StocktakeID   *uuid.UUID `json:"-"`
+	TransferID    *uuid.UUID `json:"-"`
+}
+
+type StockDTO struct {
+	MovieID        uuid.UUID `json:"movie_id"`
+	MovieName      string    `json:"movie_name"`
+	Quantity       int64     `json:"quantity"`
+	LedgerQuantity int64     `json:"ledger_quantity"`
+	Difference     int64     `json:"difference"`
+	Reconciled     bool      `json:"reconciled"`
+}
+
+// StoreStockDTO is the on-hand stock of a movie at one store.
+type StoreStockDTO struct {
+	StoreID   uuid.UUID `json:"store_id"`
+	StoreCode string    `json:"store_code"`
+	StoreName string    `json:"store_name"`
+	MovieID   uuid.UUID `json:"movie_id"`
+	Quantity  int64     `json:"quantity"`
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type IInventoryService interface {
+	AdjustStock(ctx context.Context, storeId, movieId uuid.UUID, movement *CreateMovementDTO) (*MovementDTO, error)
+	GetMovieMovements(ctx context.Context, movieId uuid.UUID) ([]*MovementDTO, error)
+	GetMovieStock(ctx context.Context, movieId uuid.UUID) (*StockDTO, error)
+	GetMovieAvailability(ctx context.Context, movieId uuid.UUID) ([]*StoreStockDTO, error)
+	GetUnreconciledStock(ctx context.Context) ([]*StockDTO, error)
+}
+
+type IInventoryRepository interface {
+	CreateMovement(ctx context.Context, movieId uuid.UUID, movement *CreateMovementDTO) (*MovementDTO, error)
+	GetMovieMovements(ctx context.Context, movieId uuid.UUID) ([]*MovementDTO, error)
+	GetMovieStock(ctx context.Context, movieId uuid.UUID) (*StockDTO, error)
+	GetMovieAvailability(ctx context.Context, movieId uuid.UUID) ([]*StoreStockDTO, error)
+	GetUnreconciledStock(ctx context.Context) ([]*StockDTO, error)
+}
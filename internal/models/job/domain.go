@@ -0,0 +1,49 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const (
+	StatusQueued    = "queued"
+	StatusRunning   = "running"
+	StatusSucceeded = "succeeded"
+
+	// StatusDead is the dead-letter state of a job that failed on every
+	// attempt. It stays there until staff retry it.
+	StatusDead = "dead"
+)
+
+// Statuses lists every job status, in the order the admin page shows them.
+var Statuses = []string{StatusQueued, StatusRunning, StatusDead, StatusSucceeded}
+
+const (
+	// DefaultMaxAttempts is how many times a job runs before it is moved to
+	// the dead-letter state, unless it asks for another limit.
+	DefaultMaxAttempts = 5
+	MaxMaxAttempts     = 25
+)
+
+var (
+	ErrJobNotFound      = errors.New("job not found")
+	ErrScheduleNotFound = errors.New("job schedule not found")
+	ErrUnknownKind      = errors.New("no handler registered for this job kind")
+	ErrNotDead          = errors.New("only dead jobs can be retried")
+	ErrInvalidSchedule  = errors.New("invalid job schedule")
+)
+
+// Handler runs one job. Returning an error schedules another attempt.
+type Handler func(ctx context.Context, job *JobDTO) error
+
+// RetryDelay is how long to wait before the next attempt: 30 seconds after
+// the first failure, doubling up to an hour.
+func RetryDelay(attempts int) time.Duration {
+	delay := 30 * time.Second << (attempts - 1)
+	if attempts < 1 || delay > time.Hour || delay <= 0 {
+		return time.Hour
+	}
+
+	return delay
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type JobDTO struct {
+	ID           uuid.UUID       `json:"id"`
+	Kind         string          `json:"kind"`
+	Payload      json.RawMessage `json:"payload"`
+	Status       string          `json:"status"`
+	Attempts     int             `json:"attempts"`
+	MaxAttempts  int             `json:"max_attempts"`
+	RunAt        time.Time       `json:"run_at"`
+	LockedAt     *time.Time      `json:"locked_at,omitempty"`
+	LockedBy     string          `json:"locked_by,omitempty"`
+	LastError    string          `json:"last_error"`
+	ScheduleName string          `json:"schedule_name,omitempty"`
+	FinishedAt   *time.Time      `json:"finished_at,omitempty"`
+	CreatedAt    time.Time       `json:"created_at"`
+	UpdatedAt    time.Time       `json:"updated_at"`
+}
+
+type EnqueueJobDTO struct {
+	Kind        string          `json:"kind" binding:"required"`
+	Payload     json.RawMessage `json:"payload"`
+	RunAt       *time.Time      `json:"run_at"`
+	MaxAttempts int             `json:"max_attempts" binding:"omitempty,min=1,max=25"`
+}
+
+// ScheduleDTO is a recurring job: a job of Kind with Payload is queued
+// every time Spec fires.
+type ScheduleDTO struct {
+	Name      string          `json:"name"`
+	Kind      string          `json:"kind"`
+	Payload   json.RawMessage `json:"payload"`
+	Spec      string          `json:"spec"`
+	NextRunAt time.Time       `json:"next_run_at"`
+	LastRunAt *time.Time      `json:"last_run_at,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type IJobService interface {
+	Register(kind string, handler Handler)
+	Kinds() []string
+	Schedule(ctx context.Context, name, kind, spec string) error
+	Unschedule(ctx context.Context, name string) error
+	Enqueue(ctx context.Context, job *EnqueueJobDTO) (*JobDTO, error)
+	GetJobs(ctx context.Context, status string) ([]*JobDTO, error)
+	GetJob(ctx context.Context, id uuid.UUID) (*JobDTO, error)
+	Retry(ctx context.Context, id uuid.UUID) (*JobDTO, error)
+	GetSchedules(ctx context.Context) ([]*ScheduleDTO, error)
+	RunSchedule(ctx context.Context, name string) (*JobDTO, error)
+	EnqueueDueSchedules(ctx context.Context) (int, error)
+	RunNext(ctx context.Context, workerId string) (bool, error)
+}
+
+type IJobRepository interface {
+	Enqueue(ctx context.Context, job *EnqueueJobDTO, scheduleName string) (*JobDTO, error)
+	GetJob(ctx context.Context, id uuid.UUID) (*JobDTO, error)
+	GetJobs(ctx context.Context, status string, limit int) ([]*JobDTO, error)
+	Claim(ctx context.Context, workerId string, kinds []string, staleBefore time.Time) (*JobDTO, error)
+	Complete(ctx context.Context, id uuid.UUID, workerId string) error
+	Fail(ctx context.Context, id uuid.UUID, workerId, message string, runAt time.Time, dead bool) error
+	Requeue(ctx context.Context, id uuid.UUID) (*JobDTO, error)
+	Prune(ctx context.Context, finishedBefore time.Time) (int64, error)
+	SaveSchedule(ctx context.Context, schedule *ScheduleDTO) error
+	DeleteSchedule(ctx context.Context, name string) error
+	GetSchedule(ctx context.Context, name string) (*ScheduleDTO, error)
+	GetSchedules(ctx context.Context) ([]*ScheduleDTO, error)
+	GetDueSchedules(ctx context.Context, now time.Time) ([]*ScheduleDTO, error)
+	FireSchedule(ctx context.Context, schedule *ScheduleDTO, nextRunAt time.Time) (*JobDTO, error)
+}
@@ -1,15 +1,36 @@
 package models
 
 import (
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+var (
+	ErrVersionConflict = errors.New("loan was modified by someone else, reload it and try again")
+	ErrLoanNotFound    = errors.New("loan not found")
+	ErrCopyOnLoan      = errors.New("copy is already on loan")
+	// ErrAmbiguousReturn is returned when a movie ID matches more than one
+	// active loan at the store, since a movie ID does not say which copy
+	// came back.
+	ErrAmbiguousReturn = errors.New("more than one active loan matches this movie, scan the copy barcode or return it by loan instead")
+)
+
+const (
+	// ArchiveBatchSize is how many loans one archive statement moves, so a
+	// long backlog does not hold its locks for long.
+	ArchiveBatchSize = 1000
+	// PartitionsAhead is how many years after the current one already have a
+	// loans partition.
+	PartitionsAhead = 1
+)
+
 type Loan struct {
 	ID         uuid.UUID `json:"id"`
 	MovieID    uuid.UUID `json:"movie_id"`
 	UserID     uuid.UUID `json:"user_id"`
+	StoreID    uuid.UUID `json:"store_id"`
 	BorrowedAt time.Time `json:"borrowed_at"`
 	ReturnedAt time.Time `json:"returned_at"`
 	Status     string    `json:"Status"`
@@ -21,5 +42,6 @@ func NewLoan(l *CreateLoanDTO) *Loan {
 	return &Loan{
 		MovieID: l.MovieID,
 		UserID:  l.UserID,
+		StoreID: l.StoreID,
 	}
 }
@@ -7,14 +7,19 @@ import (
 )
 
 type LoanDTO struct {
-	ID         uuid.UUID `json:"id"`
-	MovieID    uuid.UUID `json:"movie_id"`
-	UserID     uuid.UUID `json:"user_id"`
-	BorrowedAt time.Time `json:"borrowed_at"`
-	ReturnedAt time.Time `json:"returned_at"`
-	Status     string    `json:"status"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
+	ID            uuid.UUID  `json:"id"`
+	MovieID       uuid.UUID  `json:"movie_id"`
+	UserID        uuid.UUID  `json:"user_id"`
+	StoreID       uuid.UUID  `json:"store_id"`
+	ReturnStoreID *uuid.UUID `json:"return_store_id,omitempty"`
+	CopyID        *uuid.UUID `json:"copy_id,omitempty"`
+	BorrowedAt    time.Time  `json:"borrowed_at"`
+	ReturnedAt    time.Time  `json:"returned_at"`
+	Status        string     `json:"status"`
+	Version       int64      `json:"version"`
+	Archived      bool       `json:"archived,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
 }
 
 func NewLoanDTO(l *Loan) *LoanDTO {
@@ -22,6 +27,7 @@ func NewLoanDTO(l *Loan) *LoanDTO {
 		ID:         l.ID,
 		MovieID:    l.MovieID,
 		UserID:     l.UserID,
+		StoreID:    l.StoreID,
 		BorrowedAt: l.BorrowedAt,
 		ReturnedAt: l.ReturnedAt,
 		Status:     l.Status,
@@ -31,12 +37,47 @@ func NewLoanDTO(l *Loan) *LoanDTO {
 }
 
 type CreateLoanDTO struct {
-	MovieID    uuid.UUID `json:"movie_id"`
-	UserID     uuid.UUID `json:"user_id"`
-	BorrowedAt time.Time `json:"borrowed_at"`
-	Status     string    `json:"status"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
+	MovieID    uuid.UUID  `json:"movie_id"`
+	UserID     uuid.UUID  `json:"user_id"`
+	StoreID    uuid.UUID  `json:"store_id"`
+	CopyID     *uuid.UUID `json:"copy_id,omitempty"`
+	BorrowedAt time.Time  `json:"borrowed_at"`
+	Status     string     `json:"status"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// ArchiveResultDTO is what one run of the archive moved: the returned loans
+// borrowed and returned before Before, and the yearly partitions left empty.
+type ArchiveResultDTO struct {
+	Before            time.Time `json:"before"`
+	Archived          int64     `json:"archived"`
+	DroppedPartitions []string  `json:"dropped_partitions"`
+}
+
+type BarcodeReturnDTO struct {
+	Code       string    `json:"code" binding:"required"`
+	ReturnedAt time.Time `json:"returned_at"`
+}
+
+type DropBoxReturnDTO struct {
+	Codes      []string  `json:"codes" binding:"required,min=1"`
+	ReturnedAt time.Time `json:"returned_at"`
+}
+
+type ReturnResultDTO struct {
+	Code    string   `json:"code"`
+	Success bool     `json:"success"`
+	Loan    *LoanDTO `json:"loan,omitempty"`
+	Error   string   `json:"error,omitempty"`
+}
+
+type DropBoxSummaryDTO struct {
+	ReturnedAt time.Time          `json:"returned_at"`
+	Processed  int                `json:"processed"`
+	Succeeded  int                `json:"succeeded"`
+	Failed     int                `json:"failed"`
+	Results    []*ReturnResultDTO `json:"results"`
 }
 
 // func (l *CreateLoanDTO) Validate() error {
@@ -1,20 +1,38 @@
 package models
 
-import "github.com/google/uuid"
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type ILoanService interface {
-	CreateLoan(movieId, userId uuid.UUID) (*CreateLoanDTO, error)
-	ReturnMovie(loanId uuid.UUID) error
-	GetLoan(id uuid.UUID) (*LoanDTO, error)
-	GetUserLoans(userId uuid.UUID) ([]*LoanDTO, error)
-	GetAllLoans() ([]*LoanDTO, error)
+	CreateLoan(ctx context.Context, storeId, movieId, userId uuid.UUID, copyBarcode string) (*LoanDTO, error)
+	ReturnMovie(ctx context.Context, storeId, loanId uuid.UUID, version int64) (*LoanDTO, error)
+	ReturnMovieAt(ctx context.Context, storeId, loanId uuid.UUID, returnedAt time.Time) (*LoanDTO, error)
+	ReturnByCode(ctx context.Context, storeId uuid.UUID, code string, returnedAt time.Time) (*LoanDTO, error)
+	ProcessDropBox(ctx context.Context, storeId uuid.UUID, batch *DropBoxReturnDTO) *DropBoxSummaryDTO
+	GetLoan(ctx context.Context, id uuid.UUID, includeArchived bool) (*LoanDTO, error)
+	GetUserLoans(ctx context.Context, userId uuid.UUID, includeArchived bool) ([]*LoanDTO, error)
+	GetStoreLoans(ctx context.Context, storeId uuid.UUID, includeArchived bool) ([]*LoanDTO, error)
+	GetRecentActiveStoreLoans(ctx context.Context, storeId uuid.UUID, limit int) ([]*LoanDTO, error)
+	EnsurePartitions(ctx context.Context) ([]string, error)
+	ArchiveLoans(ctx context.Context, before time.Time) (*ArchiveResultDTO, error)
 }
 
 type ILoanRepository interface {
-	CreateLoan(loan *CreateLoanDTO) error
-	UpdateLoan(loan *LoanDTO) error
-	ReturnMovie(loanId uuid.UUID) error
-	GetLoan(id uuid.UUID) (*LoanDTO, error)
-	GetActiveUserLoans(userId uuid.UUID) ([]*LoanDTO, error)
-	GetAllLoans() ([]*LoanDTO, error)
+	CreateLoan(ctx context.Context, loan *CreateLoanDTO) (*LoanDTO, error)
+	UpdateLoan(ctx context.Context, loan *LoanDTO) error
+	GetLoan(ctx context.Context, id uuid.UUID) (*LoanDTO, error)
+	GetActiveUserLoans(ctx context.Context, userId uuid.UUID) ([]*LoanDTO, error)
+	GetUserLoanHistory(ctx context.Context, userId uuid.UUID) ([]*LoanDTO, error)
+	GetActiveMovieLoans(ctx context.Context, movieId uuid.UUID) ([]*LoanDTO, error)
+	GetActiveCopyLoan(ctx context.Context, copyId uuid.UUID) (*LoanDTO, error)
+	GetStoreLoans(ctx context.Context, storeId uuid.UUID, includeArchived bool) ([]*LoanDTO, error)
+	GetRecentActiveStoreLoans(ctx context.Context, storeId uuid.UUID, limit int) ([]*LoanDTO, error)
+	GetArchivedLoan(ctx context.Context, id uuid.UUID) (*LoanDTO, error)
+	CreatePartitions(ctx context.Context, firstYear, lastYear int) ([]string, error)
+	ArchiveLoans(ctx context.Context, before time.Time, limit int) (int64, error)
+	DropEmptyPartitions(ctx context.Context, before time.Time) ([]string, error)
 }
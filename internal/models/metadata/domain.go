@@ -0,0 +1,64 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	StatusPending  = "pending"
+	StatusApproved = "approved"
+	StatusRejected = "rejected"
+)
+
+const (
+	FieldDirector = "director"
+	FieldYear     = "year"
+	FieldSynopsis = "synopsis"
+	FieldCoverURL = "cover_url"
+)
+
+var (
+	ErrNoProvider       = errors.New("no metadata provider is configured")
+	ErrNoMatch          = errors.New("no metadata found for this movie")
+	ErrNothingToEnrich  = errors.New("movie has no missing metadata the provider can fill")
+	ErrProposalNotFound = errors.New("metadata proposal not found")
+	ErrProposalDecided  = errors.New("metadata proposal has already been decided")
+	ErrStaleProposal    = errors.New("movie changed since the proposal was made, run the enrichment again")
+)
+
+// Metadata is what a provider knows about one movie. Empty fields are
+// unknown to the provider.
+type Metadata struct {
+	Source     string
+	ExternalID string
+	Title      string
+	Director   string
+	Year       int64
+	Synopsis   string
+	CoverURL   string
+}
+
+type Proposal struct {
+	ID         uuid.UUID         `json:"id"`
+	MovieID    uuid.UUID         `json:"movie_id"`
+	Source     string            `json:"source"`
+	ExternalID string            `json:"external_id"`
+	Changes    []*FieldChangeDTO `json:"changes"`
+	Status     string            `json:"status"`
+	DecidedAt  *time.Time        `json:"decided_at,omitempty"`
+	CreatedAt  time.Time         `json:"created_at"`
+	UpdatedAt  time.Time         `json:"updated_at"`
+}
+
+func NewProposal(p *CreateProposalDTO) *Proposal {
+	return &Proposal{
+		MovieID:    p.MovieID,
+		Source:     p.Source,
+		ExternalID: p.ExternalID,
+		Changes:    p.Changes,
+		Status:     StatusPending,
+	}
+}
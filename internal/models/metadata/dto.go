@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FieldChangeDTO is one line of a metadata diff. Current is the value the
+// change was computed against, so a change is only applied while it holds.
+type FieldChangeDTO struct {
+	Field    string `json:"field"`
+	Current  string `json:"current"`
+	Proposed string `json:"proposed"`
+}
+
+type ProposalDTO struct {
+	ID         uuid.UUID         `json:"id"`
+	MovieID    uuid.UUID         `json:"movie_id"`
+	MovieName  string            `json:"movie_name"`
+	Source     string            `json:"source"`
+	ExternalID string            `json:"external_id"`
+	Changes    []*FieldChangeDTO `json:"changes"`
+	Status     string            `json:"status"`
+	DecidedAt  *time.Time        `json:"decided_at,omitempty"`
+	CreatedAt  time.Time         `json:"created_at"`
+	UpdatedAt  time.Time         `json:"updated_at"`
+}
+
+type CreateProposalDTO struct {
+	MovieID    uuid.UUID
+	Source     string
+	ExternalID string
+	Changes    []*FieldChangeDTO
+}
+
+// EnrichmentRunDTO summarizes a catalog-wide enrichment run.
+type EnrichmentRunDTO struct {
+	Provider string `json:"provider"`
+	Checked  int    `json:"checked"`
+	Matched  int    `json:"matched"`
+	Proposed int    `json:"proposed"`
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	movieModels "blockbustermvc/internal/models/movie"
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// MetadataProvider looks movies up in an external catalog. Lookup returns
+// ErrNoMatch when the catalog has no entry for the title and year.
+type MetadataProvider interface {
+	Name() string
+	Lookup(title string, year int64) (*Metadata, error)
+}
+
+type IMetadataService interface {
+	EnrichMovie(ctx context.Context, movieId uuid.UUID) (*movieModels.MovieDTO, error)
+	ProposeEnrichment(ctx context.Context) (*EnrichmentRunDTO, error)
+	GetProposals(ctx context.Context, status string) ([]*ProposalDTO, error)
+	GetProposal(ctx context.Context, id uuid.UUID) (*ProposalDTO, error)
+	ApproveProposal(ctx context.Context, id uuid.UUID) (*ProposalDTO, error)
+	RejectProposal(ctx context.Context, id uuid.UUID) (*ProposalDTO, error)
+}
+
+type IMetadataRepository interface {
+	ApplyChanges(ctx context.Context, movieId uuid.UUID, changes []*FieldChangeDTO) error
+	ReplacePendingProposals(ctx context.Context, proposals []*CreateProposalDTO) error
+	GetProposals(ctx context.Context, status string) ([]*ProposalDTO, error)
+	GetProposal(ctx context.Context, id uuid.UUID) (*ProposalDTO, error)
+	ApproveProposal(ctx context.Context, id uuid.UUID) (*ProposalDTO, error)
+	RejectProposal(ctx context.Context, id uuid.UUID) (*ProposalDTO, error)
+}
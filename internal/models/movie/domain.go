@@ -1,11 +1,17 @@
 package models
 
 import (
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+var (
+	ErrVersionConflict = errors.New("movie was modified by someone else, reload it and try again")
+	ErrCopyNotFound    = errors.New("copy not found")
+)
+
 type Movie struct {
 	ID        uuid.UUID `json:"id,omitempty"`
 	Name      string    `json:"name" binding:"required,min=2,max=100"`
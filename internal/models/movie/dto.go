@@ -7,13 +7,20 @@ import (
 )
 
 type MovieDTO struct {
-	ID        uuid.UUID `json:"id,omitempty"`
-	Name      string    `json:"name"`
-	Director  string    `json:"director"`
-	Year      int64     `json:"year"`
-	Quantity  int64     `json:"quantity"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID               uuid.UUID `json:"id,omitempty"`
+	Name             string    `json:"name"`
+	Director         string    `json:"director"`
+	Year             int64     `json:"year"`
+	Genre            string    `json:"genre"`
+	RentalPriceCents int64     `json:"rental_price_cents"`
+	Quantity         int64     `json:"quantity"`
+	Synopsis         string    `json:"synopsis"`
+	CoverURL         string    `json:"cover_url"`
+	AverageRating    float64   `json:"average_rating"`
+	RatingCount      int64     `json:"rating_count"`
+	Version          int64     `json:"version"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
 }
 
 func NewMovieDTO(m *Movie) *MovieDTO {
@@ -27,19 +34,48 @@ func NewMovieDTO(m *Movie) *MovieDTO {
 }
 
 type CreateMovieDTO struct {
-	Name      string    `json:"name" binding:"required,min=2,max=100"`
-	Director  string    `json:"director" binding:"required,min=2,max=100"`
-	Year      int64     `json:"year" binding:"required,number"`
-	Quantity  int64     `json:"quantity" binding:"required,min=1,max=100"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"update_at"`
+	Name             string    `json:"name" binding:"required,min=2,max=100"`
+	Director         string    `json:"director" binding:"required,min=2,max=100"`
+	Year             int64     `json:"year" binding:"required,number"`
+	Genre            string    `json:"genre" binding:"max=50"`
+	RentalPriceCents int64     `json:"rental_price_cents" binding:"min=0,max=100000"`
+	Quantity         int64     `json:"quantity" binding:"min=0,max=100"`
+	StoreID          uuid.UUID `json:"-"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"update_at"`
 }
 
+// UpdateMovieDTO replaces a movie's details. Quantity is only read to reject
+// it, stock changes go through the inventory ledger.
 type UpdateMovieDTO struct {
-	Name      string    `json:"name" binding:"required,min=2,max=100"`
-	Director  string    `json:"director" binding:"required,min=2,max=100"`
-	Year      int64     `json:"year" binding:"required,number"`
-	Quantity  int64     `json:"quantity" binding:"required,min=1,max=100"`
+	Name             string    `json:"name" binding:"required,min=2,max=100"`
+	Director         string    `json:"director" binding:"required,min=2,max=100"`
+	Year             int64     `json:"year" binding:"required,number"`
+	Genre            string    `json:"genre" binding:"max=50"`
+	RentalPriceCents int64     `json:"rental_price_cents" binding:"min=0,max=100000"`
+	Version          int64     `json:"version"`
+	Quantity         *int64    `json:"quantity"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+type PatchMovieDTO struct {
+	Name             *string `json:"name" binding:"omitempty,min=2,max=100"`
+	Director         *string `json:"director" binding:"omitempty,min=2,max=100"`
+	Year             *int64  `json:"year" binding:"omitempty,number"`
+	Genre            *string `json:"genre" binding:"omitempty,max=50"`
+	RentalPriceCents *int64  `json:"rental_price_cents" binding:"omitempty,min=0,max=100000"`
+	Version          int64   `json:"-"`
+}
+
+type CopyDTO struct {
+	ID        uuid.UUID `json:"id"`
+	MovieID   uuid.UUID `json:"movie_id"`
+	Barcode   string    `json:"barcode"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
+
+type CreateCopyDTO struct {
+	Barcode string `json:"barcode" binding:"required,min=1,max=64"`
+}
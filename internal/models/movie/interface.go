@@ -1,19 +1,36 @@
 package models
 
-import "github.com/google/uuid"
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
 
 type IMovieService interface {
-	CreateMovie(movie *CreateMovieDTO) error
-	GetMovie(id uuid.UUID) (*MovieDTO, error)
-	GetAllMovies() ([]*MovieDTO, error)
-	UpdateMovie(id uuid.UUID, movie *UpdateMovieDTO) error
-	DeleteMovie(id uuid.UUID) error
+	CreateMovie(ctx context.Context, movie *CreateMovieDTO) (*MovieDTO, error)
+	GetMovie(ctx context.Context, id uuid.UUID) (*MovieDTO, error)
+	GetAllMovies(ctx context.Context) ([]*MovieDTO, error)
+	GetStoreMovie(ctx context.Context, storeId, id uuid.UUID) (*MovieDTO, error)
+	GetStoreMovies(ctx context.Context, storeId uuid.UUID) ([]*MovieDTO, error)
+	UpdateMovie(ctx context.Context, id uuid.UUID, movie *UpdateMovieDTO) (*MovieDTO, error)
+	PatchMovie(ctx context.Context, id uuid.UUID, patch *PatchMovieDTO) (*MovieDTO, error)
+	DeleteMovie(ctx context.Context, id uuid.UUID) error
+	AddCopy(ctx context.Context, movieId uuid.UUID, movieCopy *CreateCopyDTO) (*CopyDTO, error)
+	GetMovieCopies(ctx context.Context, movieId uuid.UUID) ([]*CopyDTO, error)
+	GetCopyByBarcode(ctx context.Context, barcode string) (*CopyDTO, error)
+	ResolveMovieID(ctx context.Context, code string) (uuid.UUID, error)
 }
 
 type IMovieRepository interface {
-	CreateMovie(movie *CreateMovieDTO) error
-	GetMovieById(id uuid.UUID) (*MovieDTO, error)
-	GetAllMovies() ([]*MovieDTO, error)
-	UpdateMovie(id uuid.UUID, movie *UpdateMovieDTO) error
-	DeleteMovie(id uuid.UUID) error
+	CreateMovie(ctx context.Context, movie *CreateMovieDTO) (*MovieDTO, error)
+	GetMovieById(ctx context.Context, id uuid.UUID) (*MovieDTO, error)
+	GetAllMovies(ctx context.Context) ([]*MovieDTO, error)
+	GetStoreMovieById(ctx context.Context, storeId, id uuid.UUID) (*MovieDTO, error)
+	GetStoreMovies(ctx context.Context, storeId uuid.UUID) ([]*MovieDTO, error)
+	UpdateMovie(ctx context.Context, id uuid.UUID, movie *UpdateMovieDTO) (*MovieDTO, error)
+	PatchMovie(ctx context.Context, id uuid.UUID, patch *PatchMovieDTO) (*MovieDTO, error)
+	DeleteMovie(ctx context.Context, id uuid.UUID) error
+	CreateCopy(ctx context.Context, movieId uuid.UUID, movieCopy *CreateCopyDTO) (*CopyDTO, error)
+	GetMovieCopies(ctx context.Context, movieId uuid.UUID) ([]*CopyDTO, error)
+	GetCopyByBarcode(ctx context.Context, barcode string) (*CopyDTO, error)
 }
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	// KindMovieAvailable tells a customer a wishlisted movie is back in
+	// stock at a store.
+	KindMovieAvailable = "movie_available"
+	KindDueSoon        = "due_soon"
+	KindOverdue        = "overdue"
+	KindHoldReady      = "hold_ready"
+	KindReceipt        = "receipt"
+)
+
+// Kinds lists every kind of notification, in the order preferences are shown.
+var Kinds = []string{KindReceipt, KindDueSoon, KindOverdue, KindHoldReady, KindMovieAvailable}
+
+const (
+	StatusQueued  = "queued"
+	StatusSent    = "sent"
+	StatusFailed  = "failed"
+	StatusSkipped = "skipped"
+)
+
+const (
+	ChannelEmail = "email"
+
+	// MaxAttempts is how many times delivery is tried before the
+	// notification is marked failed.
+	MaxAttempts = 5
+)
+
+var (
+	ErrNotificationNotFound = errors.New("notification not found")
+	ErrUnknownKind          = errors.New("unknown notification kind")
+	ErrNotFailed            = errors.New("only failed notifications can be retried")
+)
+
+// RetryDelay is how long to wait before the next delivery attempt: one
+// minute after the first failure, doubling up to an hour.
+func RetryDelay(attempts int) time.Duration {
+	delay := time.Minute << (attempts - 1)
+	if attempts < 1 || delay > time.Hour || delay <= 0 {
+		return time.Hour
+	}
+
+	return delay
+}
+
+func IsKind(kind string) bool {
+	for _, k := range Kinds {
+		if k == kind {
+			return true
+		}
+	}
+
+	return false
+}
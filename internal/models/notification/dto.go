@@ -0,0 +1,84 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type NotificationDTO struct {
+	ID            uuid.UUID  `json:"id"`
+	UserID        uuid.UUID  `json:"user_id"`
+	Kind          string     `json:"kind"`
+	MovieID       *uuid.UUID `json:"movie_id,omitempty"`
+	StoreID       *uuid.UUID `json:"store_id,omitempty"`
+	LoanID        *uuid.UUID `json:"loan_id,omitempty"`
+	Note          string     `json:"note"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	LastError     string     `json:"last_error"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	SentAt        *time.Time `json:"sent_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+type QueueNotificationDTO struct {
+	UserID  uuid.UUID  `json:"user_id" binding:"required"`
+	Kind    string     `json:"kind" binding:"required"`
+	MovieID *uuid.UUID `json:"movie_id"`
+	StoreID *uuid.UUID `json:"store_id"`
+	LoanID  *uuid.UUID `json:"loan_id"`
+	Note    string     `json:"note" binding:"max=1000"`
+
+	// EventID is set when a domain event queues the notification, so a
+	// redelivered event does not queue it twice.
+	EventID *uuid.UUID `json:"-"`
+}
+
+// DeliveryDTO is one entry of the delivery log: a single attempt to send
+// a notification.
+type DeliveryDTO struct {
+	ID             uuid.UUID `json:"id"`
+	NotificationID uuid.UUID `json:"notification_id"`
+	Attempt        int       `json:"attempt"`
+	Channel        string    `json:"channel"`
+	Recipient      string    `json:"recipient"`
+	Status         string    `json:"status"`
+	Error          string    `json:"error"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type PreferenceDTO struct {
+	Kind    string `json:"kind" binding:"required"`
+	Enabled bool   `json:"enabled"`
+}
+
+// NotificationView is everything the message templates can use. The
+// movie, store and loan fields are empty when the notification has none.
+type NotificationView struct {
+	NotificationDTO
+	UserName   string
+	Email      string
+	MovieName  string
+	StoreName  string
+	BorrowedAt time.Time
+	DueAt      time.Time
+	ReturnedAt *time.Time
+}
+
+// Message is a rendered email, with a plain text and an HTML body.
+type Message struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// DispatchResultDTO summarizes a dispatch run.
+type DispatchResultDTO struct {
+	Reminders int `json:"reminders"`
+	Sent      int `json:"sent"`
+	Failed    int `json:"failed"`
+	Skipped   int `json:"skipped"`
+}
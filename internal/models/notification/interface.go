@@ -0,0 +1,41 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Notifier delivers a rendered message to a customer.
+type Notifier interface {
+	Channel() string
+	Send(message *Message) error
+}
+
+type INotificationService interface {
+	Queue(ctx context.Context, notification *QueueNotificationDTO) (*NotificationDTO, error)
+	GetNotifications(ctx context.Context, status string) ([]*NotificationDTO, error)
+	GetUserNotifications(ctx context.Context, userId uuid.UUID) ([]*NotificationDTO, error)
+	GetDeliveries(ctx context.Context, notificationId uuid.UUID) ([]*DeliveryDTO, error)
+	Retry(ctx context.Context, notificationId uuid.UUID) (*NotificationDTO, error)
+	GetPreferences(ctx context.Context, userId uuid.UUID) ([]*PreferenceDTO, error)
+	UpdatePreferences(ctx context.Context, userId uuid.UUID, preferences []*PreferenceDTO) ([]*PreferenceDTO, error)
+	QueueLoanReminders(ctx context.Context) (int, error)
+	Dispatch(ctx context.Context) (*DispatchResultDTO, error)
+}
+
+type INotificationRepository interface {
+	Queue(ctx context.Context, notification *QueueNotificationDTO) (*NotificationDTO, error)
+	GetNotification(ctx context.Context, id uuid.UUID) (*NotificationDTO, error)
+	GetNotifications(ctx context.Context, status string) ([]*NotificationDTO, error)
+	GetUserNotifications(ctx context.Context, userId uuid.UUID) ([]*NotificationDTO, error)
+	GetDeliveries(ctx context.Context, notificationId uuid.UUID) ([]*DeliveryDTO, error)
+	Requeue(ctx context.Context, id uuid.UUID) (*NotificationDTO, error)
+	GetPreferences(ctx context.Context, userId uuid.UUID) (map[string]bool, error)
+	SavePreferences(ctx context.Context, userId uuid.UUID, preferences []*PreferenceDTO) error
+	QueueLoanReminders(ctx context.Context, overdueBefore, dueSoonBefore time.Time) (int, error)
+	ClaimDue(ctx context.Context, limit int) ([]*NotificationView, error)
+	RecordDelivery(ctx context.Context, delivery *DeliveryDTO, status string, nextAttemptAt time.Time) error
+	Skip(ctx context.Context, id uuid.UUID) error
+}
@@ -0,0 +1,12 @@
+package models
+
+const (
+	// DefaultLimit is how many recommendations are returned when no limit
+	// is asked for; MaxLimit caps what can be asked for.
+	DefaultLimit = 10
+	MaxLimit     = 50
+
+	// NeighborsPerMovie is how many similar movies are kept per movie on
+	// each refresh.
+	NeighborsPerMovie = 50
+)
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RecommendationDTO is a movie suggested from loan history. SharedRenters
+// counts the customers who rented it together with the movies it was
+// recommended from, and Score ranks the suggestions.
+type RecommendationDTO struct {
+	MovieID       uuid.UUID `json:"movie_id"`
+	Name          string    `json:"name"`
+	Director      string    `json:"director"`
+	Year          int64     `json:"year"`
+	SharedRenters int64     `json:"shared_renters"`
+	Score         float64   `json:"score"`
+}
+
+// RefreshResultDTO summarizes a refresh of the co-occurrence table.
+type RefreshResultDTO struct {
+	Pairs      int64     `json:"pairs"`
+	ComputedAt time.Time `json:"computed_at"`
+}
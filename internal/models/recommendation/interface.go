@@ -0,0 +1,19 @@
+package models
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type IRecommendationService interface {
+	RefreshSimilarities(ctx context.Context) (*RefreshResultDTO, error)
+	GetSimilarMovies(ctx context.Context, movieId uuid.UUID, limit int) ([]*RecommendationDTO, error)
+	GetUserRecommendations(ctx context.Context, userId uuid.UUID, limit int) ([]*RecommendationDTO, error)
+}
+
+type IRecommendationRepository interface {
+	RefreshSimilarities(ctx context.Context, neighbors int) (*RefreshResultDTO, error)
+	GetSimilarMovies(ctx context.Context, movieId uuid.UUID, limit int) ([]*RecommendationDTO, error)
+	GetUserRecommendations(ctx context.Context, userId uuid.UUID, limit int) ([]*RecommendationDTO, error)
+}
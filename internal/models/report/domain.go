@@ -0,0 +1,95 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	IntervalDay   = "day"
+	IntervalWeek  = "week"
+	IntervalMonth = "month"
+
+	FormatJSON = "json"
+	FormatCSV  = "csv"
+
+	// MaxPeriods caps how many periods one report can span.
+	MaxPeriods = 732
+
+	// AllStores selects every store in the store_id filter.
+	AllStores = "all"
+
+	// DefaultCustomerLimit is how many customers are listed when no limit is
+	// asked for; MaxCustomerLimit caps what can be asked for.
+	DefaultCustomerLimit = 50
+	MaxCustomerLimit     = 500
+)
+
+// The materialized views the reports read from, refreshed together.
+const (
+	ViewDailyLoans       = "report_daily_loans"
+	ViewMovieUtilization = "report_movie_utilization"
+	ViewCustomerActivity = "report_customer_activity"
+)
+
+var Views = []string{ViewDailyLoans, ViewMovieUtilization, ViewCustomerActivity}
+
+// Intervals lists the supported period lengths, shortest first.
+var Intervals = []string{IntervalDay, IntervalWeek, IntervalMonth}
+
+var (
+	ErrInvalidInterval   = errors.New("invalid interval, use day, week or month")
+	ErrInvalidDate       = errors.New("invalid date, use YYYY-MM-DD or RFC 3339")
+	ErrInvalidStore      = errors.New("invalid store_id, use a store ID or all")
+	ErrInvalidRange      = errors.New("from must be before to")
+	ErrRangeTooLarge     = errors.New("the range spans too many periods, use a longer interval or a shorter range")
+	ErrUnsupportedFormat = errors.New("unsupported report format, use json or csv")
+)
+
+var (
+	ActivityColumns    = []string{"period_start", "rentals", "returns", "late_returns", "revenue_cents", "rented_days", "utilization"}
+	UtilizationColumns = []string{"movie_id", "name", "director", "genre", "loans", "rented_days", "copies", "utilization", "revenue_cents"}
+	CustomerColumns    = []string{
+		"user_id", "user_name", "email", "loans", "active_loans", "returns", "revenue_cents",
+		"average_loan_days", "first_borrowed_at", "last_borrowed_at",
+	}
+)
+
+// PeriodStart returns the start of the period t falls in: local midnight for
+// days, Monday for weeks and the 1st for months.
+func PeriodStart(interval string, t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+
+	switch interval {
+	case IntervalWeek:
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	case IntervalMonth:
+		return day.AddDate(0, 0, 1-day.Day())
+	}
+
+	return day
+}
+
+// NextPeriod returns the start of the period after the one starting at start.
+func NextPeriod(interval string, start time.Time) time.Time {
+	switch interval {
+	case IntervalWeek:
+		return start.AddDate(0, 0, 7)
+	case IntervalMonth:
+		return start.AddDate(0, 1, 0)
+	}
+
+	return start.AddDate(0, 0, 1)
+}
+
+// DefaultPeriods is how many periods a report covers when no start is given.
+func DefaultPeriods(interval string) int {
+	switch interval {
+	case IntervalWeek:
+		return 12
+	case IntervalMonth:
+		return 12
+	}
+
+	return 30
+}
@@ -0,0 +1,194 @@
+package models
+
+import (
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReportFilterDTO selects the loans a report covers. From and To bound the
+// range, To exclusive; a nil StoreID covers every store. Genre and Director
+// match whole values, ignoring case.
+type ReportFilterDTO struct {
+	Interval string     `json:"interval"`
+	From     time.Time  `json:"from"`
+	To       time.Time  `json:"to"`
+	StoreID  *uuid.UUID `json:"store_id"`
+	Genre    string     `json:"genre,omitempty"`
+	Director string     `json:"director,omitempty"`
+}
+
+// ParseFilter reads a filter from the query parameters interval, from, to,
+// store_id, genre and director. The store defaults to currentStore; dates
+// given without a time start at local midnight, and a date-only to covers
+// that whole day. Missing values are left for the service to default.
+func ParseFilter(query url.Values, currentStore uuid.UUID) (*ReportFilterDTO, error) {
+	filter := &ReportFilterDTO{
+		Interval: query.Get("interval"),
+		StoreID:  &currentStore,
+		Genre:    query.Get("genre"),
+		Director: query.Get("director"),
+	}
+
+	switch storeId := query.Get("store_id"); storeId {
+	case "":
+	case AllStores:
+		filter.StoreID = nil
+	default:
+		id, err := uuid.Parse(storeId)
+		if err != nil {
+			return nil, ErrInvalidStore
+		}
+		filter.StoreID = &id
+	}
+
+	var err error
+	if filter.From, _, err = parseDate(query.Get("from")); err != nil {
+		return nil, err
+	}
+
+	to, dateOnly, err := parseDate(query.Get("to"))
+	if err != nil {
+		return nil, err
+	}
+	if dateOnly {
+		to = to.AddDate(0, 0, 1)
+	}
+	filter.To = to
+
+	return filter, nil
+}
+
+func parseDate(value string) (time.Time, bool, error) {
+	if value == "" {
+		return time.Time{}, false, nil
+	}
+
+	if t, err := time.ParseInLocation(time.DateOnly, value, time.Local); err == nil {
+		return t, true, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false, ErrInvalidDate
+	}
+
+	return t, false, nil
+}
+
+// ActivityDTO is what happened in one period. Rentals and revenue count the
+// loans borrowed in it, returns and late returns the loans returned in it.
+// RentedDays adds up the time copies spent rented during the period, and
+// Utilization divides it by the copies in stock times the period's length.
+type ActivityDTO struct {
+	PeriodStart  time.Time `json:"period_start"`
+	Rentals      int64     `json:"rentals"`
+	Returns      int64     `json:"returns"`
+	LateReturns  int64     `json:"late_returns"`
+	RevenueCents int64     `json:"revenue_cents"`
+	RentedDays   float64   `json:"rented_days"`
+	Utilization  float64   `json:"utilization"`
+}
+
+func (a *ActivityDTO) Record() []string {
+	return []string{
+		a.PeriodStart.Format(time.RFC3339),
+		strconv.FormatInt(a.Rentals, 10),
+		strconv.FormatInt(a.Returns, 10),
+		strconv.FormatInt(a.LateReturns, 10),
+		strconv.FormatInt(a.RevenueCents, 10),
+		strconv.FormatFloat(a.RentedDays, 'f', 2, 64),
+		strconv.FormatFloat(a.Utilization, 'f', 4, 64),
+	}
+}
+
+// AsOf in the reports is when the reporting views they read were last
+// refreshed; later loans are not counted yet.
+type ActivityReportDTO struct {
+	Filter  *ReportFilterDTO `json:"filter"`
+	AsOf    time.Time        `json:"as_of"`
+	Periods []*ActivityDTO   `json:"periods"`
+	Totals  *ActivityDTO     `json:"totals"`
+}
+
+// UtilizationDTO is how much a title was rented over the report's range.
+// Copies is the current stock, so utilization of titles whose stock changed
+// during the range is an estimate.
+type UtilizationDTO struct {
+	MovieID      uuid.UUID `json:"movie_id"`
+	Name         string    `json:"name"`
+	Director     string    `json:"director"`
+	Genre        string    `json:"genre"`
+	Loans        int64     `json:"loans"`
+	RentedDays   float64   `json:"rented_days"`
+	Copies       int64     `json:"copies"`
+	Utilization  float64   `json:"utilization"`
+	RevenueCents int64     `json:"revenue_cents"`
+}
+
+func (u *UtilizationDTO) Record() []string {
+	return []string{
+		u.MovieID.String(),
+		u.Name,
+		u.Director,
+		u.Genre,
+		strconv.FormatInt(u.Loans, 10),
+		strconv.FormatFloat(u.RentedDays, 'f', 2, 64),
+		strconv.FormatInt(u.Copies, 10),
+		strconv.FormatFloat(u.Utilization, 'f', 4, 64),
+		strconv.FormatInt(u.RevenueCents, 10),
+	}
+}
+
+type UtilizationReportDTO struct {
+	Filter *ReportFilterDTO  `json:"filter"`
+	AsOf   time.Time         `json:"as_of"`
+	Titles []*UtilizationDTO `json:"titles"`
+}
+
+// CustomerActivityDTO sums up a customer's loans at the stores in the
+// filter, over all time.
+type CustomerActivityDTO struct {
+	UserID          uuid.UUID `json:"user_id"`
+	UserName        string    `json:"user_name"`
+	Email           string    `json:"email"`
+	Loans           int64     `json:"loans"`
+	ActiveLoans     int64     `json:"active_loans"`
+	Returns         int64     `json:"returns"`
+	RevenueCents    int64     `json:"revenue_cents"`
+	AverageLoanDays float64   `json:"average_loan_days"`
+	FirstBorrowedAt time.Time `json:"first_borrowed_at"`
+	LastBorrowedAt  time.Time `json:"last_borrowed_at"`
+}
+
+func (c *CustomerActivityDTO) Record() []string {
+	return []string{
+		c.UserID.String(),
+		c.UserName,
+		c.Email,
+		strconv.FormatInt(c.Loans, 10),
+		strconv.FormatInt(c.ActiveLoans, 10),
+		strconv.FormatInt(c.Returns, 10),
+		strconv.FormatInt(c.RevenueCents, 10),
+		strconv.FormatFloat(c.AverageLoanDays, 'f', 2, 64),
+		c.FirstBorrowedAt.Format(time.RFC3339),
+		c.LastBorrowedAt.Format(time.RFC3339),
+	}
+}
+
+type CustomerReportDTO struct {
+	Filter    *ReportFilterDTO       `json:"filter"`
+	AsOf      time.Time              `json:"as_of"`
+	Customers []*CustomerActivityDTO `json:"customers"`
+}
+
+// FreshnessDTO is when a reporting view was last refreshed and how long the
+// refresh took.
+type FreshnessDTO struct {
+	View        string    `json:"view"`
+	RefreshedAt time.Time `json:"refreshed_at"`
+	AgeSeconds  float64   `json:"age_seconds"`
+	DurationMs  int64     `json:"duration_ms"`
+}
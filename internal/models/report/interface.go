@@ -0,0 +1,24 @@
+package models
+
+import (
+	"context"
+	"time"
+)
+
+type IReportService interface {
+	GetActivity(ctx context.Context, filter *ReportFilterDTO) (*ActivityReportDTO, error)
+	GetUtilization(ctx context.Context, filter *ReportFilterDTO) (*UtilizationReportDTO, error)
+	GetCustomers(ctx context.Context, filter *ReportFilterDTO, limit int) (*CustomerReportDTO, error)
+	GetGenres(ctx context.Context) ([]string, error)
+	GetFreshness(ctx context.Context) ([]*FreshnessDTO, error)
+	RefreshViews(ctx context.Context) ([]*FreshnessDTO, error)
+}
+
+type IReportRepository interface {
+	GetActivity(ctx context.Context, filter *ReportFilterDTO, periodStarts, periodEnds []time.Time, lateAfter time.Duration, asOf time.Time) ([]*ActivityDTO, error)
+	GetUtilization(ctx context.Context, filter *ReportFilterDTO, asOf time.Time) ([]*UtilizationDTO, error)
+	GetCustomers(ctx context.Context, filter *ReportFilterDTO, limit int) ([]*CustomerActivityDTO, error)
+	GetGenres(ctx context.Context) ([]string, error)
+	GetFreshness(ctx context.Context) ([]*FreshnessDTO, error)
+	RefreshView(ctx context.Context, view string) (*FreshnessDTO, error)
+}
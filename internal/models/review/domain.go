@@ -0,0 +1,43 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	StatusPending  = "pending"
+	StatusApproved = "approved"
+	StatusRejected = "rejected"
+)
+
+var (
+	ErrReviewNotFound  = errors.New("review not found")
+	ErrNotRented       = errors.New("users can only review movies they have rented")
+	ErrAlreadyReviewed = errors.New("user has already reviewed this movie")
+)
+
+type Review struct {
+	ID             uuid.UUID  `json:"id"`
+	MovieID        uuid.UUID  `json:"movie_id"`
+	UserID         uuid.UUID  `json:"user_id"`
+	Rating         int64      `json:"rating"`
+	Body           string     `json:"body"`
+	Status         string     `json:"status"`
+	ModerationNote string     `json:"moderation_note"`
+	ModeratedAt    *time.Time `json:"moderated_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+func NewReview(r *CreateReviewDTO) *Review {
+	return &Review{
+		MovieID: r.MovieID,
+		UserID:  r.UserID,
+		Rating:  r.Rating,
+		Body:    r.Body,
+		Status:  StatusPending,
+	}
+}
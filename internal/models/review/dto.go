@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ReviewDTO struct {
+	ID             uuid.UUID  `json:"id"`
+	MovieID        uuid.UUID  `json:"movie_id"`
+	MovieName      string     `json:"movie_name"`
+	UserID         uuid.UUID  `json:"user_id"`
+	UserName       string     `json:"user_name"`
+	Rating         int64      `json:"rating"`
+	Body           string     `json:"body"`
+	Status         string     `json:"status"`
+	ModerationNote string     `json:"moderation_note"`
+	ModeratedAt    *time.Time `json:"moderated_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+type CreateReviewDTO struct {
+	UserID  uuid.UUID `json:"user_id" binding:"required"`
+	Rating  int64     `json:"rating" binding:"required,min=1,max=5"`
+	Body    string    `json:"body" binding:"max=2000"`
+	MovieID uuid.UUID `json:"-"`
+}
+
+type ModerateReviewDTO struct {
+	Note string `json:"note" binding:"max=500"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type IReviewService interface {
+	CreateReview(ctx context.Context, review *CreateReviewDTO) (*ReviewDTO, error)
+	GetReview(ctx context.Context, id uuid.UUID) (*ReviewDTO, error)
+	GetReviews(ctx context.Context, status string) ([]*ReviewDTO, error)
+	GetMovieReviews(ctx context.Context, movieId uuid.UUID, status string) ([]*ReviewDTO, error)
+	ApproveReview(ctx context.Context, id uuid.UUID, moderation *ModerateReviewDTO) (*ReviewDTO, error)
+	RejectReview(ctx context.Context, id uuid.UUID, moderation *ModerateReviewDTO) (*ReviewDTO, error)
+	DeleteReview(ctx context.Context, id uuid.UUID) error
+}
+
+type IReviewRepository interface {
+	CreateReview(ctx context.Context, review *CreateReviewDTO) (*ReviewDTO, error)
+	GetReview(ctx context.Context, id uuid.UUID) (*ReviewDTO, error)
+	GetUserMovieReview(ctx context.Context, userId, movieId uuid.UUID) (*ReviewDTO, error)
+	HasRented(ctx context.Context, userId, movieId uuid.UUID) (bool, error)
+	GetReviews(ctx context.Context, movieId uuid.UUID, status string) ([]*ReviewDTO, error)
+	SetReviewStatus(ctx context.Context, id uuid.UUID, status, note string) (*ReviewDTO, error)
+	DeleteReview(ctx context.Context, id uuid.UUID) error
+}
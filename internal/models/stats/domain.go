@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+const (
+	// RecentWindow is how far back most-rented titles are counted, and how
+	// long a stocked title must go without a loan to be idle.
+	RecentWindow = 30 * 24 * time.Hour
+
+	// MostRentedLimit and IdleInventoryLimit cap the lists in the stats.
+	MostRentedLimit    = 5
+	IdleInventoryLimit = 10
+)
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StatsDTO summarizes a store for the dashboard. Loans count the ones
+// borrowed at or returned to the store; the day and week start at local
+// midnight, the week on Monday.
+type StatsDTO struct {
+	StoreID         uuid.UUID        `json:"store_id"`
+	TotalMovies     int64            `json:"total_movies"`
+	AvailableMovies int64            `json:"available_movies"`
+	TotalUsers      int64            `json:"total_users"`
+	TotalLoans      int64            `json:"total_loans"`
+	ActiveLoans     int64            `json:"active_loans"`
+	LoansToday      int64            `json:"loans_today"`
+	LoansThisWeek   int64            `json:"loans_this_week"`
+	OverdueLoans    int64            `json:"overdue_loans"`
+	MostRented      []*TitleCountDTO `json:"most_rented"`
+	IdleInventory   []*IdleMovieDTO  `json:"idle_inventory"`
+	GeneratedAt     time.Time        `json:"generated_at"`
+}
+
+// CountsDTO holds the counters the repository computes in one query.
+type CountsDTO struct {
+	TotalMovies     int64
+	AvailableMovies int64
+	TotalUsers      int64
+	TotalLoans      int64
+	ActiveLoans     int64
+	LoansToday      int64
+	LoansThisWeek   int64
+	OverdueLoans    int64
+}
+
+// TitleCountDTO is a movie with the number of loans it had at the store.
+type TitleCountDTO struct {
+	MovieID uuid.UUID `json:"movie_id"`
+	Name    string    `json:"name"`
+	Loans   int64     `json:"loans"`
+}
+
+// IdleMovieDTO is a movie in stock at the store that nobody borrowed there
+// lately. LastBorrowedAt is nil when it was never borrowed there.
+type IdleMovieDTO struct {
+	MovieID        uuid.UUID  `json:"movie_id"`
+	Name           string     `json:"name"`
+	Quantity       int64      `json:"quantity"`
+	LastBorrowedAt *time.Time `json:"last_borrowed_at"`
+}
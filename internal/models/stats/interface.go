@@ -0,0 +1,18 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type IStatsService interface {
+	GetStats(ctx context.Context, storeId uuid.UUID) (*StatsDTO, error)
+}
+
+type IStatsRepository interface {
+	GetCounts(ctx context.Context, storeId uuid.UUID, todayStart, weekStart, overdueBefore time.Time) (*CountsDTO, error)
+	GetMostRented(ctx context.Context, storeId uuid.UUID, since time.Time, limit int) ([]*TitleCountDTO, error)
+	GetIdleInventory(ctx context.Context, storeId uuid.UUID, idleSince time.Time, limit int) ([]*IdleMovieDTO, error)
+}
@@ -0,0 +1,14 @@
+package models
+
+import "errors"
+
+const (
+	StatusOpen      = "open"
+	StatusApproved  = "approved"
+	StatusCancelled = "cancelled"
+)
+
+var (
+	ErrStocktakeNotOpen     = errors.New("stocktake is not open")
+	ErrStocktakeAlreadyOpen = errors.New("another stocktake is already open at this store")
+)
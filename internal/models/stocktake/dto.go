@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type StocktakeDTO struct {
+	ID        uuid.UUID           `json:"id"`
+	StoreID   uuid.UUID           `json:"store_id"`
+	Status    string              `json:"status"`
+	Note      string              `json:"note"`
+	OpenedAt  time.Time           `json:"opened_at"`
+	ClosedAt  *time.Time          `json:"closed_at,omitempty"`
+	CreatedAt time.Time           `json:"created_at"`
+	UpdatedAt time.Time           `json:"updated_at"`
+	Lines     []*StocktakeLineDTO `json:"lines,omitempty"`
+}
+
+// StocktakeLineDTO compares what was counted on the shelf of a store for a
+// movie with the on-hand quantity the system expected when it was counted.
+type StocktakeLineDTO struct {
+	MovieID          uuid.UUID `json:"movie_id"`
+	MovieName        string    `json:"movie_name"`
+	TotalQuantity    int64     `json:"total_quantity"`
+	ActiveLoans      int64     `json:"active_loans"`
+	ExpectedQuantity int64     `json:"expected_quantity"`
+	CountedQuantity  int64     `json:"counted_quantity"`
+	Difference       int64     `json:"difference"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+type CreateStocktakeDTO struct {
+	Note    string    `json:"note" binding:"max=500"`
+	StoreID uuid.UUID `json:"-"`
+}
+
+type RecordCountDTO struct {
+	MovieID         uuid.UUID `json:"movie_id" binding:"required"`
+	CountedQuantity int64     `json:"counted_quantity" binding:"min=0"`
+}
+
+type ScanDTO struct {
+	Code string `json:"code" binding:"required"`
+}
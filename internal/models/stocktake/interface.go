@@ -0,0 +1,30 @@
+package models
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type IStocktakeService interface {
+	OpenStocktake(ctx context.Context, stocktake *CreateStocktakeDTO) (*StocktakeDTO, error)
+	GetStocktake(ctx context.Context, id uuid.UUID) (*StocktakeDTO, error)
+	GetAllStocktakes(ctx context.Context, storeId uuid.UUID) ([]*StocktakeDTO, error)
+	RecordCount(ctx context.Context, id uuid.UUID, count *RecordCountDTO) (*StocktakeLineDTO, error)
+	ScanCopy(ctx context.Context, id uuid.UUID, scan *ScanDTO) (*StocktakeLineDTO, error)
+	GetDifferences(ctx context.Context, id uuid.UUID) ([]*StocktakeLineDTO, error)
+	ApproveStocktake(ctx context.Context, id uuid.UUID) (*StocktakeDTO, error)
+	CancelStocktake(ctx context.Context, id uuid.UUID) (*StocktakeDTO, error)
+}
+
+type IStocktakeRepository interface {
+	CreateStocktake(ctx context.Context, stocktake *CreateStocktakeDTO) (*StocktakeDTO, error)
+	GetStocktake(ctx context.Context, id uuid.UUID) (*StocktakeDTO, error)
+	GetOpenStocktake(ctx context.Context, storeId uuid.UUID) (*StocktakeDTO, error)
+	GetAllStocktakes(ctx context.Context, storeId uuid.UUID) ([]*StocktakeDTO, error)
+	GetStocktakeLines(ctx context.Context, id uuid.UUID) ([]*StocktakeLineDTO, error)
+	SetCount(ctx context.Context, id, movieId uuid.UUID, countedQuantity int64) error
+	IncrementCount(ctx context.Context, id, movieId uuid.UUID) error
+	ApproveStocktake(ctx context.Context, id uuid.UUID) (*StocktakeDTO, error)
+	CancelStocktake(ctx context.Context, id uuid.UUID) (*StocktakeDTO, error)
+}
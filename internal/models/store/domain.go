@@ -0,0 +1,31 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrStoreNotFound   = errors.New("store not found")
+	ErrStoreOutOfScope = errors.New("store is not served here")
+)
+
+type Store struct {
+	ID        uuid.UUID `json:"id"`
+	Code      string    `json:"code"`
+	Name      string    `json:"name"`
+	Address   string    `json:"address"`
+	IsDefault bool      `json:"is_default"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func NewStore(s *CreateStoreDTO) *Store {
+	return &Store{
+		Code:    s.Code,
+		Name:    s.Name,
+		Address: s.Address,
+	}
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type StoreDTO struct {
+	ID        uuid.UUID `json:"id"`
+	Code      string    `json:"code"`
+	Name      string    `json:"name"`
+	Address   string    `json:"address"`
+	IsDefault bool      `json:"is_default"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type CreateStoreDTO struct {
+	Code    string `json:"code" binding:"required,min=2,max=20"`
+	Name    string `json:"name" binding:"required,min=2,max=100"`
+	Address string `json:"address" binding:"max=500"`
+}
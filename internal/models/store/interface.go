@@ -0,0 +1,23 @@
+package models
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type IStoreService interface {
+	CreateStore(ctx context.Context, store *CreateStoreDTO) (*StoreDTO, error)
+	GetStore(ctx context.Context, id uuid.UUID) (*StoreDTO, error)
+	GetDefaultStore(ctx context.Context) (*StoreDTO, error)
+	GetAllStores(ctx context.Context) ([]*StoreDTO, error)
+	GetScopedStore(ctx context.Context, id uuid.UUID) (*StoreDTO, error)
+	GetScopeStores(ctx context.Context) ([]*StoreDTO, error)
+}
+
+type IStoreRepository interface {
+	CreateStore(ctx context.Context, store *CreateStoreDTO) (*StoreDTO, error)
+	GetStoreById(ctx context.Context, id uuid.UUID) (*StoreDTO, error)
+	GetDefaultStore(ctx context.Context) (*StoreDTO, error)
+	GetAllStores(ctx context.Context) ([]*StoreDTO, error)
+}
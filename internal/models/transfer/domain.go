@@ -0,0 +1,47 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	StatusRequested = "requested"
+	StatusInTransit = "in_transit"
+	StatusReceived  = "received"
+	StatusCancelled = "cancelled"
+)
+
+var (
+	ErrTransferNotFound  = errors.New("transfer not found")
+	ErrInvalidTransition = errors.New("transfer cannot move to that status")
+	ErrWrongStore        = errors.New("transfer does not belong to this store")
+)
+
+type Transfer struct {
+	ID          uuid.UUID  `json:"id"`
+	MovieID     uuid.UUID  `json:"movie_id"`
+	FromStoreID uuid.UUID  `json:"from_store_id"`
+	ToStoreID   uuid.UUID  `json:"to_store_id"`
+	Quantity    int64      `json:"quantity"`
+	Status      string     `json:"status"`
+	Note        string     `json:"note"`
+	RequestedAt time.Time  `json:"requested_at"`
+	ShippedAt   *time.Time `json:"shipped_at,omitempty"`
+	ReceivedAt  *time.Time `json:"received_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+func NewTransfer(t *CreateTransferDTO) *Transfer {
+	return &Transfer{
+		MovieID:     t.MovieID,
+		FromStoreID: t.FromStoreID,
+		ToStoreID:   t.ToStoreID,
+		Quantity:    t.Quantity,
+		Status:      StatusRequested,
+		Note:        t.Note,
+	}
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TransferDTO struct {
+	ID            uuid.UUID  `json:"id"`
+	MovieID       uuid.UUID  `json:"movie_id"`
+	MovieName     string     `json:"movie_name"`
+	FromStoreID   uuid.UUID  `json:"from_store_id"`
+	FromStoreName string     `json:"from_store_name"`
+	ToStoreID     uuid.UUID  `json:"to_store_id"`
+	ToStoreName   string     `json:"to_store_name"`
+	Quantity      int64      `json:"quantity"`
+	Status        string     `json:"status"`
+	Note          string     `json:"note"`
+	RequestedAt   time.Time  `json:"requested_at"`
+	ShippedAt     *time.Time `json:"shipped_at,omitempty"`
+	ReceivedAt    *time.Time `json:"received_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// CreateTransferDTO is a request for copies from another store; the copies
+// are sent to the store the request is made from.
+type CreateTransferDTO struct {
+	MovieID     uuid.UUID `json:"movie_id" binding:"required"`
+	FromStoreID uuid.UUID `json:"from_store_id" binding:"required"`
+	Quantity    int64     `json:"quantity" binding:"required,min=1,max=100"`
+	Note        string    `json:"note" binding:"max=500"`
+	ToStoreID   uuid.UUID `json:"-"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type ITransferService interface {
+	RequestTransfer(ctx context.Context, transfer *CreateTransferDTO) (*TransferDTO, error)
+	GetTransfer(ctx context.Context, id uuid.UUID) (*TransferDTO, error)
+	GetStoreTransfers(ctx context.Context, storeId uuid.UUID) ([]*TransferDTO, error)
+	GetPendingTransfers(ctx context.Context, storeId uuid.UUID) ([]*TransferDTO, error)
+	ShipTransfer(ctx context.Context, storeId, id uuid.UUID) (*TransferDTO, error)
+	ReceiveTransfer(ctx context.Context, storeId, id uuid.UUID) (*TransferDTO, error)
+	CancelTransfer(ctx context.Context, storeId, id uuid.UUID) (*TransferDTO, error)
+}
+
+type ITransferRepository interface {
+	CreateTransfer(ctx context.Context, transfer *CreateTransferDTO) (*TransferDTO, error)
+	GetTransfer(ctx context.Context, id uuid.UUID) (*TransferDTO, error)
+	GetStoreTransfers(ctx context.Context, storeId uuid.UUID, pendingOnly bool) ([]*TransferDTO, error)
+	UpdateTransferStatus(ctx context.Context, id uuid.UUID, from, to string) (*TransferDTO, error)
+	ReceiveTransfer(ctx context.Context, id uuid.UUID) (*TransferDTO, error)
+}
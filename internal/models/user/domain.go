@@ -1,11 +1,14 @@
 package models
 
 import (
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+var ErrVersionConflict = errors.New("user was modified by someone else, reload it and try again")
+
 type User struct {
 	ID        uuid.UUID `json:"id"`
 	UserName  string    `json:"user_name"`
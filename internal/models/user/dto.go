@@ -10,6 +10,7 @@ type UserDTO struct {
 	ID        uuid.UUID `json:"id,omitempty"`
 	UserName  string    `json:"user_name"`
 	Email     string    `json:"email"`
+	Version   int64     `json:"version"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
@@ -31,4 +32,11 @@ type CreateUserDTO struct {
 type UpdateUserDTO struct {
 	UserName string `json:"user_name" binding:"required,min=4,max=100"`
 	Email    string `json:"email" binding:"required,email"`
+	Version  int64  `json:"version"`
+}
+
+type PatchUserDTO struct {
+	UserName *string `json:"user_name" binding:"omitempty,min=4,max=100"`
+	Email    *string `json:"email" binding:"omitempty,email"`
+	Version  int64   `json:"-"`
 }
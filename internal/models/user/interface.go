@@ -1,21 +1,24 @@
 package models
 
 import (
+	"context"
 	"github.com/google/uuid"
 )
 
 type IUserService interface {
-	CreateUser(user *CreateUserDTO) error
-	GetUser(id uuid.UUID) (*UserDTO, error)
-	GetAllUsers() ([]*UserDTO, error)
-	UpdateUser(id uuid.UUID, user *UpdateUserDTO) error
-	DeleteUser(id uuid.UUID) error
+	CreateUser(ctx context.Context, user *CreateUserDTO) (*UserDTO, error)
+	GetUser(ctx context.Context, id uuid.UUID) (*UserDTO, error)
+	GetAllUsers(ctx context.Context) ([]*UserDTO, error)
+	UpdateUser(ctx context.Context, id uuid.UUID, user *UpdateUserDTO) (*UserDTO, error)
+	PatchUser(ctx context.Context, id uuid.UUID, patch *PatchUserDTO) (*UserDTO, error)
+	DeleteUser(ctx context.Context, id uuid.UUID) error
 }
 
 type IUserRepository interface {
-	CreateUser(user *CreateUserDTO) error
-	GetUserById(id uuid.UUID) (*UserDTO, error)
-	GetAllUsers() ([]*UserDTO, error)
-	UpdateUser(id uuid.UUID, user *UpdateUserDTO) error
-	DeleteUser(id uuid.UUID) error
+	CreateUser(ctx context.Context, user *CreateUserDTO) (*UserDTO, error)
+	GetUserById(ctx context.Context, id uuid.UUID) (*UserDTO, error)
+	GetAllUsers(ctx context.Context) ([]*UserDTO, error)
+	UpdateUser(ctx context.Context, id uuid.UUID, user *UpdateUserDTO) (*UserDTO, error)
+	PatchUser(ctx context.Context, id uuid.UUID, patch *PatchUserDTO) (*UserDTO, error)
+	DeleteUser(ctx context.Context, id uuid.UUID) error
 }
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	StatusQueued    = "queued"
+	StatusDelivered = "delivered"
+	StatusFailed    = "failed"
+)
+
+// Statuses lists every delivery status, in the order they are filtered by.
+var Statuses = []string{StatusQueued, StatusDelivered, StatusFailed}
+
+const (
+	// TestEventType is the type of the deliveries sent by "send test event".
+	// Webhooks get them whatever types they subscribed to.
+	TestEventType = "webhook.test"
+
+	// MaxAttempts is how many times a delivery is tried before it is marked
+	// failed.
+	MaxAttempts = 8
+)
+
+// Headers sent with every delivery. The signature is
+// "sha256=" followed by the hex HMAC-SHA256 of "<timestamp>.<body>", keyed
+// with the webhook's secret.
+const (
+	HeaderEvent     = "X-Blockbuster-Event"
+	HeaderDelivery  = "X-Blockbuster-Delivery"
+	HeaderTimestamp = "X-Blockbuster-Timestamp"
+	HeaderSignature = "X-Blockbuster-Signature"
+)
+
+var (
+	ErrWebhookNotFound  = errors.New("webhook not found")
+	ErrDeliveryNotFound = errors.New("webhook delivery not found")
+	ErrUnknownEventType = errors.New("unknown event type")
+	ErrNotFailed        = errors.New("only failed deliveries can be retried")
+)
+
+// RetryDelay is how long to wait before the next delivery attempt: 30
+// seconds after the first failure, doubling up to an hour.
+func RetryDelay(attempts int) time.Duration {
+	delay := 30 * time.Second << (attempts - 1)
+	if attempts < 1 || delay > time.Hour || delay <= 0 {
+		return time.Hour
+	}
+
+	return delay
+}
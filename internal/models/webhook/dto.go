@@ -0,0 +1,88 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WebhookDTO is a subscription of an outside URL to domain events. The
+// secret signs the deliveries and is never sent back.
+type WebhookDTO struct {
+	ID         uuid.UUID `json:"id"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"event_types"`
+	Secret     string    `json:"-"`
+	Active     bool      `json:"active"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type CreateWebhookDTO struct {
+	URL        string   `json:"url" binding:"required,http_url,max=2000"`
+	EventTypes []string `json:"event_types" binding:"required,min=1"`
+	Secret     string   `json:"secret" binding:"required,min=16,max=200"`
+}
+
+// UpdateWebhookDTO replaces a webhook's settings. An empty secret keeps
+// the current one.
+type UpdateWebhookDTO struct {
+	URL        string   `json:"url" binding:"required,http_url,max=2000"`
+	EventTypes []string `json:"event_types" binding:"required,min=1"`
+	Secret     string   `json:"secret" binding:"omitempty,min=16,max=200"`
+	Active     bool     `json:"active"`
+}
+
+// DeliveryDTO is one event to be sent to one webhook, with the outcome of
+// its latest attempt. Test deliveries have no event.
+type DeliveryDTO struct {
+	ID            uuid.UUID       `json:"id"`
+	WebhookID     uuid.UUID       `json:"webhook_id"`
+	EventID       *uuid.UUID      `json:"event_id,omitempty"`
+	EventType     string          `json:"event_type"`
+	Payload       json.RawMessage `json:"payload"`
+	Status        string          `json:"status"`
+	Attempts      int             `json:"attempts"`
+	ResponseCode  *int            `json:"response_code,omitempty"`
+	LastError     string          `json:"last_error"`
+	NextAttemptAt time.Time       `json:"next_attempt_at"`
+	DeliveredAt   *time.Time      `json:"delivered_at,omitempty"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+}
+
+// DeliveryView is a claimed delivery with where and how to send it.
+type DeliveryView struct {
+	DeliveryDTO
+	URL    string
+	Secret string
+}
+
+// AttemptDTO is one entry of the delivery log: a single request to the
+// webhook's URL. The response code is missing when no response came back.
+type AttemptDTO struct {
+	ID           uuid.UUID `json:"id"`
+	DeliveryID   uuid.UUID `json:"delivery_id"`
+	Attempt      int       `json:"attempt"`
+	ResponseCode *int      `json:"response_code,omitempty"`
+	ResponseBody string    `json:"response_body"`
+	Error        string    `json:"error"`
+	DurationMs   int64     `json:"duration_ms"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// EnvelopeDTO is the JSON body POSTed to a webhook.
+type EnvelopeDTO struct {
+	ID        uuid.UUID       `json:"id"`
+	Type      string          `json:"type"`
+	EventID   *uuid.UUID      `json:"event_id,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// DispatchResultDTO summarizes a dispatch run.
+type DispatchResultDTO struct {
+	Delivered int `json:"delivered"`
+	Failed    int `json:"failed"`
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type IWebhookService interface {
+	CreateWebhook(ctx context.Context, webhook *CreateWebhookDTO) (*WebhookDTO, error)
+	GetWebhooks(ctx context.Context) ([]*WebhookDTO, error)
+	GetWebhook(ctx context.Context, id uuid.UUID) (*WebhookDTO, error)
+	UpdateWebhook(ctx context.Context, id uuid.UUID, webhook *UpdateWebhookDTO) (*WebhookDTO, error)
+	DeleteWebhook(ctx context.Context, id uuid.UUID) error
+	GetDeliveries(ctx context.Context, webhookId uuid.UUID, status string) ([]*DeliveryDTO, error)
+	GetAttempts(ctx context.Context, deliveryId uuid.UUID) ([]*AttemptDTO, error)
+	Retry(ctx context.Context, deliveryId uuid.UUID) (*DeliveryDTO, error)
+	SendTest(ctx context.Context, webhookId uuid.UUID) (*DeliveryDTO, error)
+	QueueEvent(ctx context.Context, eventId uuid.UUID, eventType string, payload json.RawMessage) (int, error)
+	Dispatch(ctx context.Context) (*DispatchResultDTO, error)
+}
+
+type IWebhookRepository interface {
+	CreateWebhook(ctx context.Context, webhook *CreateWebhookDTO) (*WebhookDTO, error)
+	GetWebhooks(ctx context.Context) ([]*WebhookDTO, error)
+	GetWebhook(ctx context.Context, id uuid.UUID) (*WebhookDTO, error)
+	UpdateWebhook(ctx context.Context, id uuid.UUID, webhook *UpdateWebhookDTO) (*WebhookDTO, error)
+	DeleteWebhook(ctx context.Context, id uuid.UUID) error
+	QueueDeliveries(ctx context.Context, eventId uuid.UUID, eventType string, payload json.RawMessage) (int, error)
+	QueueDelivery(ctx context.Context, webhookId uuid.UUID, eventType string, payload json.RawMessage) (*DeliveryDTO, error)
+	GetDelivery(ctx context.Context, id uuid.UUID) (*DeliveryDTO, error)
+	GetDeliveries(ctx context.Context, webhookId uuid.UUID, status string, limit int) ([]*DeliveryDTO, error)
+	GetAttempts(ctx context.Context, deliveryId uuid.UUID) ([]*AttemptDTO, error)
+	Requeue(ctx context.Context, id uuid.UUID) (*DeliveryDTO, error)
+	ClaimDue(ctx context.Context, limit int) ([]*DeliveryView, error)
+	RecordAttempt(ctx context.Context, attempt *AttemptDTO, status string, nextAttemptAt time.Time) error
+}
@@ -0,0 +1,5 @@
+package models
+
+import "errors"
+
+var ErrWishlistItemNotFound = errors.New("movie is not on the wishlist")
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WishlistItemDTO is a movie a customer is waiting for. NotifiedAt is set
+// once they were told it is back in; adding the movie again re-arms it.
+type WishlistItemDTO struct {
+	ID         uuid.UUID  `json:"id"`
+	UserID     uuid.UUID  `json:"user_id"`
+	MovieID    uuid.UUID  `json:"movie_id"`
+	MovieName  string     `json:"movie_name"`
+	Director   string     `json:"director"`
+	Year       int64      `json:"year"`
+	Available  bool       `json:"available"`
+	NotifiedAt *time.Time `json:"notified_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+type AddWishlistItemDTO struct {
+	MovieID uuid.UUID `json:"movie_id" binding:"required"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type IWishlistService interface {
+	AddItem(ctx context.Context, userId uuid.UUID, item *AddWishlistItemDTO) (*WishlistItemDTO, error)
+	GetUserWishlist(ctx context.Context, userId uuid.UUID) ([]*WishlistItemDTO, error)
+	RemoveItem(ctx context.Context, userId, movieId uuid.UUID) error
+}
+
+type IWishlistRepository interface {
+	AddItem(ctx context.Context, userId, movieId uuid.UUID) (*WishlistItemDTO, error)
+	GetUserWishlist(ctx context.Context, userId uuid.UUID) ([]*WishlistItemDTO, error)
+	RemoveItem(ctx context.Context, userId, movieId uuid.UUID) error
+}
@@ -1,7 +1,10 @@
 package movies
 
 import (
+	"blockbustermvc/internal/httputil"
 	models "blockbustermvc/internal/models/movie"
+	"errors"
+	"log/slog"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -26,7 +29,10 @@ func (mc *MoviesController) RegisterRoutes(r *gin.RouterGroup) {
 		movies.GET("/:id", mc.GetMovie)
 		movies.GET("", mc.GetAllMovies)
 		movies.PUT("/:id", mc.UpdateMovie)
+		movies.PATCH("/:id", mc.PatchMovie)
 		movies.DELETE("/:id", mc.DeleteMovie)
+		movies.POST("/:id/copies", mc.AddCopy)
+		movies.GET("/:id/copies", mc.GetMovieCopies)
 	}
 }
 
@@ -39,14 +45,19 @@ func (mc *MoviesController) CreateMovie(ctx *gin.Context) {
 		return
 	}
 
-	if err := mc.movieService.CreateMovie(&movie); err != nil {
+	movie.StoreID = httputil.StoreID(ctx)
+
+	created, err := mc.movieService.CreateMovie(ctx.Request.Context(), &movie)
+	if err != nil {
 		ctx.JSON(http.StatusUnprocessableEntity, gin.H{
 			"error": err.Error(),
 		})
 		return
 	}
 
-	ctx.JSON(http.StatusOK, movie)
+	ctx.Header("Location", ctx.FullPath()+"/"+created.ID.String())
+	ctx.Header("ETag", httputil.ETag(created.Version))
+	ctx.JSON(http.StatusCreated, created)
 }
 
 func (mc *MoviesController) GetMovie(ctx *gin.Context) {
@@ -65,20 +76,23 @@ func (mc *MoviesController) GetMovie(ctx *gin.Context) {
 		return
 	}
 
-	movie, err := mc.movieService.GetMovie(id)
+	movie, err := mc.movieService.GetStoreMovie(ctx.Request.Context(), httputil.StoreID(ctx), id)
 	if err != nil {
+		slog.ErrorContext(ctx.Request.Context(), "Failed to get movie", "error", err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{
 			"error": err.Error(),
 		})
 		return
 	}
 
+	ctx.Header("ETag", httputil.ETag(movie.Version))
 	ctx.JSON(http.StatusOK, movie)
 }
 
 func (mc *MoviesController) GetAllMovies(ctx *gin.Context) {
-	movies, err := mc.movieService.GetAllMovies()
+	movies, err := mc.movieService.GetStoreMovies(ctx.Request.Context(), httputil.StoreID(ctx))
 	if err != nil {
+		slog.ErrorContext(ctx.Request.Context(), "Failed to get all movies", "error", err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{
 			"error": err.Error(),
 		})
@@ -113,14 +127,104 @@ func (mc *MoviesController) UpdateMovie(ctx *gin.Context) {
 		return
 	}
 
-	if err := mc.movieService.UpdateMovie(id, &movie); err != nil {
+	if movie.Quantity != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "quantity cannot be updated, record a stock movement with POST /api/movies/" + id.String() + "/stock/movements",
+		})
+		return
+	}
+
+	movie.Version, err = httputil.RequiredVersion(ctx, movie.Version)
+	if err != nil {
+		ctx.JSON(versionErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	updated, err := mc.movieService.UpdateMovie(ctx.Request.Context(), id, &movie)
+	if err != nil {
+		if errors.Is(err, models.ErrVersionConflict) {
+			ctx.JSON(http.StatusPreconditionFailed, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		slog.ErrorContext(ctx.Request.Context(), "Failed to update movie", "error", err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{
 			"error": err.Error(),
 		})
 		return
 	}
 
-	ctx.JSON(http.StatusOK, nil)
+	ctx.Header("ETag", httputil.ETag(updated.Version))
+	ctx.JSON(http.StatusOK, updated)
+}
+
+func (mc *MoviesController) PatchMovie(ctx *gin.Context) {
+	if err := uuid.Validate(ctx.Param("id")); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid movie ID",
+		})
+		return
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	var patch models.PatchMovieDTO
+	if err := httputil.BindMergePatch(ctx, &patch); err != nil {
+		var fieldErrors httputil.FieldErrors
+		switch {
+		case errors.As(err, &fieldErrors):
+			ctx.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":  "Invalid movie patch",
+				"fields": fieldErrors,
+			})
+		case errors.Is(err, httputil.ErrUnsupportedMediaType):
+			ctx.JSON(http.StatusUnsupportedMediaType, gin.H{
+				"error": err.Error(),
+			})
+		default:
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid request body",
+			})
+		}
+		return
+	}
+
+	patch.Version, err = httputil.RequiredVersion(ctx, 0)
+	if err != nil {
+		ctx.JSON(versionErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	movie, err := mc.movieService.PatchMovie(ctx.Request.Context(), id, &patch)
+	if err != nil {
+		if errors.Is(err, models.ErrVersionConflict) {
+			ctx.JSON(http.StatusPreconditionFailed, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		slog.ErrorContext(ctx.Request.Context(), "Failed to patch movie", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.Header("ETag", httputil.ETag(movie.Version))
+	ctx.JSON(http.StatusOK, movie)
 }
 
 func (mc *MoviesController) DeleteMovie(ctx *gin.Context) {
@@ -139,7 +243,8 @@ func (mc *MoviesController) DeleteMovie(ctx *gin.Context) {
 		return
 	}
 
-	if err := mc.movieService.DeleteMovie(id); err != nil {
+	if err := mc.movieService.DeleteMovie(ctx.Request.Context(), id); err != nil {
+		slog.ErrorContext(ctx.Request.Context(), "Failed to delete movie", "error", err)
 		ctx.JSON(http.StatusInternalServerError, gin.H{
 			"error": err.Error(),
 		})
@@ -148,3 +253,75 @@ func (mc *MoviesController) DeleteMovie(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, nil)
 }
+
+func (mc *MoviesController) AddCopy(ctx *gin.Context) {
+	if err := uuid.Validate(ctx.Param("id")); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid movie ID",
+		})
+		return
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	var movieCopy models.CreateCopyDTO
+	if err := ctx.ShouldBindJSON(&movieCopy); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body",
+		})
+		return
+	}
+
+	created, err := mc.movieService.AddCopy(ctx.Request.Context(), id, &movieCopy)
+	if err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, created)
+}
+
+func (mc *MoviesController) GetMovieCopies(ctx *gin.Context) {
+	if err := uuid.Validate(ctx.Param("id")); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid movie ID",
+		})
+		return
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	copies, err := mc.movieService.GetMovieCopies(ctx.Request.Context(), id)
+	if err != nil {
+		slog.ErrorContext(ctx.Request.Context(), "Failed to get movie copies", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, copies)
+}
+
+// versionErrorStatus answers 428 to updates that sent no version, and 400 to
+// the ones that sent an unreadable one.
+func versionErrorStatus(err error) int {
+	if errors.Is(err, httputil.ErrPreconditionRequired) {
+		return http.StatusPreconditionRequired
+	}
+	return http.StatusBadRequest
+}
@@ -0,0 +1,154 @@
+package movies
+
+import (
+	models "blockbustermvc/internal/models/movie"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type fakeMovieService struct {
+	models.IMovieService
+	updated *models.UpdateMovieDTO
+}
+
+func (f *fakeMovieService) UpdateMovie(_ context.Context, id uuid.UUID, movie *models.UpdateMovieDTO) (*models.MovieDTO, error) {
+	f.updated = movie
+	return &models.MovieDTO{ID: id, Name: movie.Name, Version: movie.Version + 1}, nil
+}
+
+func (f *fakeMovieService) PatchMovie(_ context.Context, id uuid.UUID, patch *models.PatchMovieDTO) (*models.MovieDTO, error) {
+	return &models.MovieDTO{ID: id, Version: patch.Version + 1}, nil
+}
+
+func newTestRouter(service models.IMovieService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	NewMoviesController(&service).RegisterRoutes(&router.RouterGroup)
+	return router
+}
+
+func TestUpdateMovieRejectsQuantity(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantUpdate bool
+	}{
+		{
+			name:       "details only",
+			body:       `{"name": "Heat", "director": "Michael Mann", "year": 1995, "version": 1}`,
+			wantStatus: http.StatusOK,
+			wantUpdate: true,
+		},
+		{
+			name:       "with quantity",
+			body:       `{"name": "Heat", "director": "Michael Mann", "year": 1995, "version": 1, "quantity": 4}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "with zero quantity",
+			body:       `{"name": "Heat", "director": "Michael Mann", "year": 1995, "version": 1, "quantity": 0}`,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &fakeMovieService{}
+			router := newTestRouter(service)
+
+			id := uuid.New()
+			request := httptest.NewRequest(http.MethodPut, "/movies/"+id.String(), strings.NewReader(tt.body))
+			request.Header.Set("Content-Type", "application/json")
+			response := httptest.NewRecorder()
+			router.ServeHTTP(response, request)
+
+			if response.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", response.Code, tt.wantStatus, response.Body)
+			}
+			if (service.updated != nil) != tt.wantUpdate {
+				t.Errorf("movie updated = %v, want %v", service.updated != nil, tt.wantUpdate)
+			}
+			if tt.wantStatus == http.StatusBadRequest && !strings.Contains(response.Body.String(), "/stock/movements") {
+				t.Errorf("error does not point to the stock movements endpoint: %s", response.Body)
+			}
+		})
+	}
+}
+
+func TestUpdatesRequireVersion(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		ifMatch    string
+		body       string
+		wantStatus int
+		wantETag   string
+	}{
+		{
+			name:       "put without version",
+			method:     http.MethodPut,
+			body:       `{"name": "Heat", "director": "Michael Mann", "year": 1995}`,
+			wantStatus: http.StatusPreconditionRequired,
+		},
+		{
+			name:       "put with If-Match",
+			method:     http.MethodPut,
+			ifMatch:    `"3"`,
+			body:       `{"name": "Heat", "director": "Michael Mann", "year": 1995}`,
+			wantStatus: http.StatusOK,
+			wantETag:   `"4"`,
+		},
+		{
+			name:       "patch without If-Match",
+			method:     http.MethodPatch,
+			body:       `{"genre": "Crime"}`,
+			wantStatus: http.StatusPreconditionRequired,
+		},
+		{
+			name:       "patch with If-Match",
+			method:     http.MethodPatch,
+			ifMatch:    `"3"`,
+			body:       `{"genre": "Crime"}`,
+			wantStatus: http.StatusOK,
+			wantETag:   `"4"`,
+		},
+		{
+			name:       "patch with a malformed If-Match",
+			method:     http.MethodPatch,
+			ifMatch:    `"three"`,
+			body:       `{"genre": "Crime"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newTestRouter(&fakeMovieService{})
+
+			request := httptest.NewRequest(tt.method, "/movies/"+uuid.NewString(), strings.NewReader(tt.body))
+			request.Header.Set("Content-Type", "application/json")
+			if tt.ifMatch != "" {
+				request.Header.Set("If-Match", tt.ifMatch)
+			}
+			response := httptest.NewRecorder()
+			router.ServeHTTP(response, request)
+
+			if response.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", response.Code, tt.wantStatus, response.Body)
+			}
+			if got := response.Header().Get("ETag"); got != tt.wantETag {
+				t.Errorf("ETag = %q, want %q", got, tt.wantETag)
+			}
+			if tt.wantETag != "" && !strings.Contains(response.Body.String(), `"version":4`) {
+				t.Errorf("body = %s, want the updated movie", response.Body)
+			}
+		})
+	}
+}
@@ -1,12 +1,17 @@
 package movies
 
 import (
+	"blockbustermvc/internal/inventory"
+	inventoryModels "blockbustermvc/internal/models/inventory"
 	models "blockbustermvc/internal/models/movie"
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -33,40 +38,85 @@ func NewMovieRepository(db *pgxpool.Pool) models.IMovieRepository {
 CreateMovie is a method of movieRepository struct that creates a new movie object in the postgres database.
 
 Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
 - movie (*models.CreateMovieDTO): A pointer to a CreateMovieDTO struct containing the movie data to be created.
 
 Returns:
-- error: An error if the movie creation fails, otherwise nil.
+- (*models.MovieDTO, error): A pointer to a MovieDTO struct containing the persisted movie, or an error if the movie creation fails.
 
 Behavior:
 - Inserts a new movie into the movies table in the database.
+- Books the initial quantity to movie.StoreID as a purchase through inventory.ApplyMovement, within the same transaction.
+- Returns the stored row, including the generated ID, timestamps and defaults.
 - Returns an error if the movie creation fails.
 */
-func (r *movieRepository) CreateMovie(movie *models.CreateMovieDTO) error {
+func (r *movieRepository) CreateMovie(ctx context.Context, movie *models.CreateMovieDTO) (*models.MovieDTO, error) {
 	query := `
-		INSERT INTO movies (name, director, year, quantity, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6)`
+		INSERT INTO movies (name, director, year, genre, rental_price_cents, quantity, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, 0, $6, $7)
+		RETURNING id, name, director, year, genre, rental_price_cents, quantity, synopsis, cover_url, rating_average, rating_count, version, created_at, updated_at`
+
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin movie creation: %w", err)
+	}
+	defer tx.Rollback(ctx)
 
 	now := time.Now()
-	_, err := r.DB.Exec(context.Background(), query,
+	var created models.MovieDTO
+	err = tx.QueryRow(ctx, query,
 		movie.Name,
 		movie.Director,
 		movie.Year,
-		movie.Quantity,
+		movie.Genre,
+		movie.RentalPriceCents,
 		now,
 		now,
+	).Scan(
+		&created.ID,
+		&created.Name,
+		&created.Director,
+		&created.Year,
+		&created.Genre,
+		&created.RentalPriceCents,
+		&created.Quantity,
+		&created.Synopsis,
+		&created.CoverURL,
+		&created.AverageRating,
+		&created.RatingCount,
+		&created.Version,
+		&created.CreatedAt,
+		&created.UpdatedAt,
 	)
 	if err != nil {
-		return fmt.Errorf("failed to create movie: %w", err)
+		return nil, fmt.Errorf("failed to create movie: %w", err)
 	}
 
-	return nil
+	if movie.Quantity > 0 {
+		initial, err := inventory.ApplyMovement(ctx, tx, created.ID, &inventoryModels.CreateMovementDTO{
+			Reason:        inventoryModels.ReasonPurchase,
+			QuantityDelta: movie.Quantity,
+			Note:          "Initial stock",
+			StoreID:       movie.StoreID,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to book initial stock: %w", err)
+		}
+		created.Quantity = initial.QuantityAfter
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit movie creation: %w", err)
+	}
+
+	return &created, nil
 }
 
 /*
 GetMovieById is a method of movieRepository struct that retrieves a movie object from the postgres database by its ID.
 
 Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
 - id (uuid.UUID): The ID of the movie to be retrieved.
 
 Returns:
@@ -76,19 +126,26 @@ Behavior:
 - Retrieves a movie from the movies table in the database by its ID.
 - Returns an error if the movie retrieval fails.
 */
-func (r *movieRepository) GetMovieById(id uuid.UUID) (*models.MovieDTO, error) {
+func (r *movieRepository) GetMovieById(ctx context.Context, id uuid.UUID) (*models.MovieDTO, error) {
 	query := `
-		SELECT id, name, director, year, quantity, created_at, updated_at
+		SELECT id, name, director, year, genre, rental_price_cents, quantity, synopsis, cover_url, rating_average, rating_count, version, created_at, updated_at
 		FROM movies
 		WHERE id = $1`
 
 	var movie models.MovieDTO
-	err := r.DB.QueryRow(context.Background(), query, id).Scan(
+	err := r.DB.QueryRow(ctx, query, id).Scan(
 		&movie.ID,
 		&movie.Name,
 		&movie.Director,
 		&movie.Year,
+		&movie.Genre,
+		&movie.RentalPriceCents,
 		&movie.Quantity,
+		&movie.Synopsis,
+		&movie.CoverURL,
+		&movie.AverageRating,
+		&movie.RatingCount,
+		&movie.Version,
 		&movie.CreatedAt,
 		&movie.UpdatedAt,
 	)
@@ -103,6 +160,7 @@ func (r *movieRepository) GetMovieById(id uuid.UUID) (*models.MovieDTO, error) {
 GetMovieById is a method of movieRepository struct that retrieves a movie object from the postgres database by its ID.
 
 Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
 - id (uuid.UUID): The ID of the movie to be retrieved.
 
 Returns:
@@ -112,13 +170,13 @@ Behavior:
 - Retrieves a movie from the movies table in the database by its ID.
 - Returns an error if the movie retrieval fails.
 */
-func (r *movieRepository) GetAllMovies() ([]*models.MovieDTO, error) {
+func (r *movieRepository) GetAllMovies(ctx context.Context) ([]*models.MovieDTO, error) {
 	query := `
-		SELECT id, name, director, year, quantity, created_at, updated_at
+		SELECT id, name, director, year, genre, rental_price_cents, quantity, synopsis, cover_url, rating_average, rating_count, version, created_at, updated_at
 		FROM movies
 		ORDER BY created_at DESC`
 
-	rows, err := r.DB.Query(context.Background(), query)
+	rows, err := r.DB.Query(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get all movies: %w", err)
 	}
@@ -132,7 +190,121 @@ func (r *movieRepository) GetAllMovies() ([]*models.MovieDTO, error) {
 			&movie.Name,
 			&movie.Director,
 			&movie.Year,
+			&movie.Genre,
+			&movie.RentalPriceCents,
+			&movie.Quantity,
+			&movie.Synopsis,
+			&movie.CoverURL,
+			&movie.AverageRating,
+			&movie.RatingCount,
+			&movie.Version,
+			&movie.CreatedAt,
+			&movie.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan movie: %w", err)
+		}
+		movies = append(movies, &movie)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over movies: %w", err)
+	}
+
+	return movies, nil
+}
+
+/*
+GetStoreMovieById is a method of movieRepository struct that retrieves a movie object with the stock of one store from the postgres database.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- storeId (uuid.UUID): The ID of the store whose stock should be reported.
+- id (uuid.UUID): The ID of the movie to be retrieved.
+
+Returns:
+- (*models.MovieDTO, error): A pointer to a MovieDTO struct whose Quantity is the store's on-hand stock, or an error if the movie retrieval fails.
+
+Behavior:
+- Retrieves a movie from the movies table and joins the store_stock row of the given store.
+- Reports a quantity of zero when the store has never stocked the movie.
+- Returns an error if the movie retrieval fails.
+*/
+func (r *movieRepository) GetStoreMovieById(ctx context.Context, storeId, id uuid.UUID) (*models.MovieDTO, error) {
+	query := `
+		SELECT m.id, m.name, m.director, m.year, m.genre, m.rental_price_cents, COALESCE(ss.quantity, 0), m.synopsis, m.cover_url, m.rating_average, m.rating_count, m.version, m.created_at, m.updated_at
+		FROM movies m
+		LEFT JOIN store_stock ss ON ss.movie_id = m.id AND ss.store_id = $1
+		WHERE m.id = $2`
+
+	var movie models.MovieDTO
+	err := r.DB.QueryRow(ctx, query, storeId, id).Scan(
+		&movie.ID,
+		&movie.Name,
+		&movie.Director,
+		&movie.Year,
+		&movie.Genre,
+		&movie.RentalPriceCents,
+		&movie.Quantity,
+		&movie.Synopsis,
+		&movie.CoverURL,
+		&movie.AverageRating,
+		&movie.RatingCount,
+		&movie.Version,
+		&movie.CreatedAt,
+		&movie.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get movie: %w", err)
+	}
+
+	return &movie, nil
+}
+
+/*
+GetStoreMovies is a method of movieRepository struct that retrieves all movie objects with the stock of one store from the postgres database.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- storeId (uuid.UUID): The ID of the store whose stock should be reported.
+
+Returns:
+- ([]*models.MovieDTO, error): A slice of pointers to MovieDTO structs whose Quantity is the store's on-hand stock, or an error if the retrieval fails.
+
+Behavior:
+- Retrieves the whole catalog, since every store rents from the same catalog.
+- Reports a quantity of zero for movies the store has never stocked.
+- Returns an error if the retrieval fails.
+*/
+func (r *movieRepository) GetStoreMovies(ctx context.Context, storeId uuid.UUID) ([]*models.MovieDTO, error) {
+	query := `
+		SELECT m.id, m.name, m.director, m.year, m.genre, m.rental_price_cents, COALESCE(ss.quantity, 0), m.synopsis, m.cover_url, m.rating_average, m.rating_count, m.version, m.created_at, m.updated_at
+		FROM movies m
+		LEFT JOIN store_stock ss ON ss.movie_id = m.id AND ss.store_id = $1
+		ORDER BY m.created_at DESC`
+
+	rows, err := r.DB.Query(ctx, query, storeId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get store movies: %w", err)
+	}
+	defer rows.Close()
+
+	var movies []*models.MovieDTO
+	for rows.Next() {
+		var movie models.MovieDTO
+		err := rows.Scan(
+			&movie.ID,
+			&movie.Name,
+			&movie.Director,
+			&movie.Year,
+			&movie.Genre,
+			&movie.RentalPriceCents,
 			&movie.Quantity,
+			&movie.Synopsis,
+			&movie.CoverURL,
+			&movie.AverageRating,
+			&movie.RatingCount,
+			&movie.Version,
 			&movie.CreatedAt,
 			&movie.UpdatedAt,
 		)
@@ -153,46 +325,170 @@ func (r *movieRepository) GetAllMovies() ([]*models.MovieDTO, error) {
 UpdateMovie is a method of movieRepository struct that updates a movie object in the postgres database.
 
 Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
 - id (uuid.UUID): The ID of the movie to be updated.
 - movie (*models.UpdateMovieDTO): A pointer to an UpdateMovieDTO struct containing the movie data to be updated.
 
 Returns:
-- error: An error if the movie update fails, otherwise nil.
+- (*models.MovieDTO, error): A pointer to a MovieDTO struct containing the updated movie, or an error if the movie update fails.
 
 Behavior:
-- Updates a movie in the movies table in the database.
+- Updates a movie in the movies table in the database and increments its version.
+- Leaves the stock alone; quantity only changes through the inventory ledger.
+- When movie.Version is set, the update only applies if the stored version still matches it.
+- Returns models.ErrVersionConflict if the movie was changed since that version was read.
 - Returns an error if the movie update fails.
 */
-func (r *movieRepository) UpdateMovie(id uuid.UUID, movie *models.UpdateMovieDTO) error {
+func (r *movieRepository) UpdateMovie(ctx context.Context, id uuid.UUID, movie *models.UpdateMovieDTO) (*models.MovieDTO, error) {
 	query := `
 		UPDATE movies
-		SET name = $2, director = $3, year = $4, quantity = $5, updated_at = $6
-		WHERE id = $1`
+		SET name = $2, director = $3, year = $4, genre = $5, rental_price_cents = $6, updated_at = $7, version = version + 1
+		WHERE id = $1 AND ($8 = 0 OR version = $8)
+		RETURNING id, name, director, year, genre, rental_price_cents, quantity, synopsis, cover_url, rating_average, rating_count, version, created_at, updated_at`
 
-	result, err := r.DB.Exec(context.Background(), query,
+	var updated models.MovieDTO
+	err := r.DB.QueryRow(ctx, query,
 		id,
 		movie.Name,
 		movie.Director,
 		movie.Year,
-		movie.Quantity,
+		movie.Genre,
+		movie.RentalPriceCents,
 		time.Now(),
+		movie.Version,
+	).Scan(
+		&updated.ID,
+		&updated.Name,
+		&updated.Director,
+		&updated.Year,
+		&updated.Genre,
+		&updated.RentalPriceCents,
+		&updated.Quantity,
+		&updated.Synopsis,
+		&updated.CoverURL,
+		&updated.AverageRating,
+		&updated.RatingCount,
+		&updated.Version,
+		&updated.CreatedAt,
+		&updated.UpdatedAt,
 	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		if movie.Version != 0 {
+			if _, err := r.GetMovieById(ctx, id); err == nil {
+				return nil, models.ErrVersionConflict
+			}
+		}
+		return nil, fmt.Errorf("movie with id %s not found", id)
+	}
 	if err != nil {
-		return fmt.Errorf("failed to update movie: %w", err)
+		return nil, fmt.Errorf("failed to update movie: %w", err)
 	}
 
-	rowsAffected := result.RowsAffected()
-	if rowsAffected == 0 {
-		return fmt.Errorf("movie with id %s not found", id)
+	return &updated, nil
+}
+
+/*
+PatchMovie is a method of movieRepository struct that partially updates a movie object in the postgres database.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- id (uuid.UUID): The ID of the movie to be patched.
+- patch (*models.PatchMovieDTO): A pointer to a PatchMovieDTO struct whose non-nil fields are the columns to change.
+
+Returns:
+- (*models.MovieDTO, error): A pointer to a MovieDTO struct containing the patched movie, or an error if the patch fails.
+
+Behavior:
+- Updates only the supplied columns of the movie and increments its version.
+- When patch.Version is set, the update only applies if the stored version still matches it.
+- Returns the current movie unchanged when the patch supplies no columns, after the same version check.
+- Returns models.ErrVersionConflict if the movie was changed since that version was read.
+*/
+func (r *movieRepository) PatchMovie(ctx context.Context, id uuid.UUID, patch *models.PatchMovieDTO) (*models.MovieDTO, error) {
+	args := []any{id, patch.Version}
+	var sets []string
+
+	if patch.Name != nil {
+		args = append(args, *patch.Name)
+		sets = append(sets, fmt.Sprintf("name = $%d", len(args)))
+	}
+	if patch.Director != nil {
+		args = append(args, *patch.Director)
+		sets = append(sets, fmt.Sprintf("director = $%d", len(args)))
+	}
+	if patch.Year != nil {
+		args = append(args, *patch.Year)
+		sets = append(sets, fmt.Sprintf("year = $%d", len(args)))
+	}
+	if patch.Genre != nil {
+		args = append(args, *patch.Genre)
+		sets = append(sets, fmt.Sprintf("genre = $%d", len(args)))
+	}
+	if patch.RentalPriceCents != nil {
+		args = append(args, *patch.RentalPriceCents)
+		sets = append(sets, fmt.Sprintf("rental_price_cents = $%d", len(args)))
 	}
 
-	return nil
+	// Nothing to change, but a stale version is still a conflict
+	if len(sets) == 0 {
+		movie, err := r.GetMovieById(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if patch.Version != 0 && movie.Version != patch.Version {
+			return nil, models.ErrVersionConflict
+		}
+		return movie, nil
+	}
+
+	args = append(args, time.Now())
+	sets = append(sets, fmt.Sprintf("updated_at = $%d", len(args)), "version = version + 1")
+
+	query := fmt.Sprintf(`
+		UPDATE movies
+		SET %s
+		WHERE id = $1 AND ($2 = 0 OR version = $2)
+		RETURNING id, name, director, year, genre, rental_price_cents, quantity, synopsis, cover_url, rating_average, rating_count, version, created_at, updated_at`,
+		strings.Join(sets, ", "),
+	)
+
+	var movie models.MovieDTO
+	err := r.DB.QueryRow(ctx, query, args...).Scan(
+		&movie.ID,
+		&movie.Name,
+		&movie.Director,
+		&movie.Year,
+		&movie.Genre,
+		&movie.RentalPriceCents,
+		&movie.Quantity,
+		&movie.Synopsis,
+		&movie.CoverURL,
+		&movie.AverageRating,
+		&movie.RatingCount,
+		&movie.Version,
+		&movie.CreatedAt,
+		&movie.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		if patch.Version != 0 {
+			if _, err := r.GetMovieById(ctx, id); err == nil {
+				return nil, models.ErrVersionConflict
+			}
+		}
+		return nil, fmt.Errorf("movie with id %s not found", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to patch movie: %w", err)
+	}
+
+	return &movie, nil
 }
 
 /*
 DeleteMovie is a method of movieRepository struct that deletes a movie object from the postgres database.
 
 Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
 - id (uuid.UUID): The ID of the movie to be deleted.
 
 Returns:
@@ -202,10 +498,10 @@ Behavior:
 - Deletes a movie from the movies table in the database.
 - Returns an error if the movie deletion fails.
 */
-func (r *movieRepository) DeleteMovie(id uuid.UUID) error {
+func (r *movieRepository) DeleteMovie(ctx context.Context, id uuid.UUID) error {
 	query := `DELETE FROM movies WHERE id = $1`
 
-	result, err := r.DB.Exec(context.Background(), query, id)
+	result, err := r.DB.Exec(ctx, query, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete movie: %w", err)
 	}
@@ -217,3 +513,133 @@ func (r *movieRepository) DeleteMovie(id uuid.UUID) error {
 
 	return nil
 }
+
+/*
+CreateCopy is a method of movieRepository struct that registers a physical copy of a movie in the postgres database.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- movieId (uuid.UUID): The ID of the movie the copy belongs to.
+- movieCopy (*models.CreateCopyDTO): A pointer to a CreateCopyDTO struct containing the copy barcode.
+
+Returns:
+- (*models.CopyDTO, error): A pointer to a CopyDTO struct containing the persisted copy, or an error if the copy creation fails.
+
+Behavior:
+- Inserts a new copy into the copies table in the database.
+- Returns an error if the copy creation fails, e.g. when the barcode is already in use.
+*/
+func (r *movieRepository) CreateCopy(ctx context.Context, movieId uuid.UUID, movieCopy *models.CreateCopyDTO) (*models.CopyDTO, error) {
+	query := `
+		INSERT INTO copies (movie_id, barcode, created_at, updated_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, movie_id, barcode, created_at, updated_at`
+
+	now := time.Now()
+	var created models.CopyDTO
+	err := r.DB.QueryRow(ctx, query,
+		movieId,
+		movieCopy.Barcode,
+		now,
+		now,
+	).Scan(
+		&created.ID,
+		&created.MovieID,
+		&created.Barcode,
+		&created.CreatedAt,
+		&created.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create copy: %w", err)
+	}
+
+	return &created, nil
+}
+
+/*
+GetMovieCopies is a method of movieRepository struct that retrieves all copies of a movie from the postgres database.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- movieId (uuid.UUID): The ID of the movie whose copies should be retrieved.
+
+Returns:
+- ([]*models.CopyDTO, error): A slice of pointers to CopyDTO structs, or an error if the retrieval fails.
+
+Behavior:
+- Retrieves all copies of the movie from the copies table ordered by barcode.
+- Returns an error if the retrieval fails.
+*/
+func (r *movieRepository) GetMovieCopies(ctx context.Context, movieId uuid.UUID) ([]*models.CopyDTO, error) {
+	query := `
+		SELECT id, movie_id, barcode, created_at, updated_at
+		FROM copies
+		WHERE movie_id = $1
+		ORDER BY barcode`
+
+	rows, err := r.DB.Query(ctx, query, movieId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get movie copies: %w", err)
+	}
+	defer rows.Close()
+
+	var copies []*models.CopyDTO
+	for rows.Next() {
+		var movieCopy models.CopyDTO
+		err := rows.Scan(
+			&movieCopy.ID,
+			&movieCopy.MovieID,
+			&movieCopy.Barcode,
+			&movieCopy.CreatedAt,
+			&movieCopy.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan copy: %w", err)
+		}
+		copies = append(copies, &movieCopy)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over copies: %w", err)
+	}
+
+	return copies, nil
+}
+
+/*
+GetCopyByBarcode is a method of movieRepository struct that retrieves a copy from the postgres database by its barcode.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- barcode (string): The barcode printed on the copy.
+
+Returns:
+- (*models.CopyDTO, error): A pointer to a CopyDTO struct containing the copy data, or an error if the copy retrieval fails.
+
+Behavior:
+- Retrieves a copy from the copies table in the database by its barcode.
+- Returns models.ErrCopyNotFound if no copy carries the barcode.
+*/
+func (r *movieRepository) GetCopyByBarcode(ctx context.Context, barcode string) (*models.CopyDTO, error) {
+	query := `
+		SELECT id, movie_id, barcode, created_at, updated_at
+		FROM copies
+		WHERE barcode = $1`
+
+	var movieCopy models.CopyDTO
+	err := r.DB.QueryRow(ctx, query, barcode).Scan(
+		&movieCopy.ID,
+		&movieCopy.MovieID,
+		&movieCopy.Barcode,
+		&movieCopy.CreatedAt,
+		&movieCopy.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, models.ErrCopyNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get copy with barcode %s: %w", barcode, err)
+	}
+
+	return &movieCopy, nil
+}
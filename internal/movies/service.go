@@ -2,6 +2,10 @@ package movies
 
 import (
 	models "blockbustermvc/internal/models/movie"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -17,27 +21,81 @@ func NewMovieService(movieRepo models.IMovieRepository) models.IMovieService {
 	}
 }
 
-func (m MovieService) CreateMovie(movie *models.CreateMovieDTO) error {
+func (m MovieService) CreateMovie(ctx context.Context, movie *models.CreateMovieDTO) (*models.MovieDTO, error) {
 	movie.CreatedAt = time.Now()
 	movie.UpdatedAt = time.Now()
 
-	return m.movieRepository.CreateMovie(movie)
+	return m.movieRepository.CreateMovie(ctx, movie)
 }
 
-func (m MovieService) GetMovie(id uuid.UUID) (*models.MovieDTO, error) {
-	return m.movieRepository.GetMovieById(id)
+func (m MovieService) GetMovie(ctx context.Context, id uuid.UUID) (*models.MovieDTO, error) {
+	return m.movieRepository.GetMovieById(ctx, id)
 }
 
-func (m MovieService) GetAllMovies() ([]*models.MovieDTO, error) {
-	return m.movieRepository.GetAllMovies()
+func (m MovieService) GetAllMovies(ctx context.Context) ([]*models.MovieDTO, error) {
+	return m.movieRepository.GetAllMovies(ctx)
 }
 
-func (m MovieService) UpdateMovie(id uuid.UUID, movie *models.UpdateMovieDTO) error {
+func (m MovieService) GetStoreMovie(ctx context.Context, storeId, id uuid.UUID) (*models.MovieDTO, error) {
+	return m.movieRepository.GetStoreMovieById(ctx, storeId, id)
+}
+
+func (m MovieService) GetStoreMovies(ctx context.Context, storeId uuid.UUID) ([]*models.MovieDTO, error) {
+	return m.movieRepository.GetStoreMovies(ctx, storeId)
+}
+
+func (m MovieService) UpdateMovie(ctx context.Context, id uuid.UUID, movie *models.UpdateMovieDTO) (*models.MovieDTO, error) {
 	movie.UpdatedAt = time.Now()
 
-	return m.movieRepository.UpdateMovie(id, movie)
+	return m.movieRepository.UpdateMovie(ctx, id, movie)
 }
 
-func (m MovieService) DeleteMovie(id uuid.UUID) error {
-	return m.movieRepository.DeleteMovie(id)
+func (m MovieService) PatchMovie(ctx context.Context, id uuid.UUID, patch *models.PatchMovieDTO) (*models.MovieDTO, error) {
+	return m.movieRepository.PatchMovie(ctx, id, patch)
+}
+
+func (m MovieService) DeleteMovie(ctx context.Context, id uuid.UUID) error {
+	return m.movieRepository.DeleteMovie(ctx, id)
+}
+
+func (m MovieService) AddCopy(ctx context.Context, movieId uuid.UUID, movieCopy *models.CreateCopyDTO) (*models.CopyDTO, error) {
+	if _, err := m.movieRepository.GetMovieById(ctx, movieId); err != nil {
+		return nil, err
+	}
+
+	movieCopy.Barcode = strings.TrimSpace(movieCopy.Barcode)
+	if movieCopy.Barcode == "" {
+		return nil, errors.New("barcode must not be empty")
+	}
+
+	return m.movieRepository.CreateCopy(ctx, movieId, movieCopy)
+}
+
+func (m MovieService) GetMovieCopies(ctx context.Context, movieId uuid.UUID) ([]*models.CopyDTO, error) {
+	return m.movieRepository.GetMovieCopies(ctx, movieId)
+}
+
+func (m MovieService) GetCopyByBarcode(ctx context.Context, barcode string) (*models.CopyDTO, error) {
+	return m.movieRepository.GetCopyByBarcode(ctx, strings.TrimSpace(barcode))
+}
+
+func (m MovieService) ResolveMovieID(ctx context.Context, code string) (uuid.UUID, error) {
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return uuid.Nil, errors.New("empty barcode")
+	}
+
+	if movieId, err := uuid.Parse(code); err == nil {
+		return movieId, nil
+	}
+
+	movieCopy, err := m.movieRepository.GetCopyByBarcode(ctx, code)
+	if errors.Is(err, models.ErrCopyNotFound) {
+		return uuid.Nil, fmt.Errorf("unknown barcode %s", code)
+	}
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return movieCopy.MovieID, nil
 }
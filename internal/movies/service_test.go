@@ -0,0 +1,68 @@
+package movies
+
+import (
+	models "blockbustermvc/internal/models/movie"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+type fakeMovieRepository struct {
+	models.IMovieRepository
+	copies map[string]*models.CopyDTO
+	err    error
+}
+
+func (f *fakeMovieRepository) GetCopyByBarcode(_ context.Context, barcode string) (*models.CopyDTO, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	if movieCopy, ok := f.copies[barcode]; ok {
+		return movieCopy, nil
+	}
+	return nil, models.ErrCopyNotFound
+}
+
+func TestResolveMovieID(t *testing.T) {
+	movieId := uuid.New()
+	dbErr := errors.New("connection refused")
+	copies := map[string]*models.CopyDTO{"BB-0001": {ID: uuid.New(), MovieID: movieId, Barcode: "BB-0001"}}
+
+	tests := []struct {
+		name    string
+		code    string
+		err     error
+		want    uuid.UUID
+		wantErr error
+		unknown bool
+	}{
+		{name: "movie ID", code: movieId.String(), want: movieId},
+		{name: "copy barcode", code: " BB-0001 ", want: movieId},
+		{name: "unknown barcode", code: "BB-9999", unknown: true},
+		{name: "database error is passed through", code: "BB-0001", err: dbErr, wantErr: dbErr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewMovieService(&fakeMovieRepository{copies: copies, err: tt.err})
+
+			got, err := service.ResolveMovieID(t.Context(), tt.code)
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("ResolveMovieID() error = %v, want %v", err, tt.wantErr)
+				}
+			case tt.unknown:
+				if err == nil || err.Error() != "unknown barcode BB-9999" {
+					t.Errorf("ResolveMovieID() error = %v, want unknown barcode", err)
+				}
+			case err != nil:
+				t.Fatalf("ResolveMovieID() error = %v", err)
+			case got != tt.want:
+				t.Errorf("ResolveMovieID() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
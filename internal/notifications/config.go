@@ -0,0 +1,53 @@
+package notifications
+
+import (
+	"blockbustermvc/internal/config"
+	models "blockbustermvc/internal/models/notification"
+	"os"
+	"time"
+)
+
+const (
+	defaultLoanPeriod       = 72 * time.Hour
+	defaultDispatchInterval = time.Minute
+	defaultSMTPPort         = "25"
+	defaultSMTPFrom         = "Blockbuster <no-reply@blockbuster.local>"
+)
+
+// NewConfiguredNotifier returns the SMTP notifier configured by the
+// environment, or nil when BLK_SMTP_HOST is unset.
+func NewConfiguredNotifier() (models.Notifier, error) {
+	host := os.Getenv("BLK_SMTP_HOST")
+	if host == "" {
+		return nil, nil
+	}
+
+	config := SMTPConfig{
+		Host:     host,
+		Port:     os.Getenv("BLK_SMTP_PORT"),
+		Username: os.Getenv("BLK_SMTP_USERNAME"),
+		Password: os.Getenv("BLK_SMTP_PASSWORD"),
+		From:     os.Getenv("BLK_SMTP_FROM"),
+	}
+	if config.Port == "" {
+		config.Port = defaultSMTPPort
+	}
+	if config.From == "" {
+		config.From = defaultSMTPFrom
+	}
+
+	return NewSMTPNotifier(config)
+}
+
+// ConfiguredLoanPeriod reads how long customers may keep a movie from
+// BLK_LOAN_PERIOD; receipts and reminders derive the due date from it.
+func ConfiguredLoanPeriod() (time.Duration, error) {
+	return config.Duration("BLK_LOAN_PERIOD", defaultLoanPeriod, false)
+}
+
+// ConfiguredDispatchInterval reads how often reminders are queued and
+// notifications sent from BLK_NOTIFICATIONS_INTERVAL. Zero unschedules the
+// dispatch job.
+func ConfiguredDispatchInterval() (time.Duration, error) {
+	return config.Duration("BLK_NOTIFICATIONS_INTERVAL", defaultDispatchInterval, true)
+}
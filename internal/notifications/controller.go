@@ -0,0 +1,202 @@
+package notifications
+
+import (
+	models "blockbustermvc/internal/models/notification"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type NotificationsController struct {
+	notificationService models.INotificationService
+}
+
+func NewNotificationsController(notificationService models.INotificationService) *NotificationsController {
+	return &NotificationsController{
+		notificationService: notificationService,
+	}
+}
+
+func (nc *NotificationsController) RegisterRoutes(r *gin.RouterGroup) {
+	notifications := r.Group("/notifications")
+
+	{
+		notifications.POST("", nc.QueueNotification)
+		notifications.GET("", nc.GetNotifications)
+		notifications.POST("/dispatch", nc.Dispatch)
+		notifications.GET("/:id/deliveries", nc.GetDeliveries)
+		notifications.POST("/:id/retry", nc.Retry)
+	}
+
+	r.GET("/users/:id/notifications", nc.GetUserNotifications)
+	r.GET("/users/:id/notification-preferences", nc.GetPreferences)
+	r.PUT("/users/:id/notification-preferences", nc.UpdatePreferences)
+}
+
+func (nc *NotificationsController) QueueNotification(ctx *gin.Context) {
+	var req models.QueueNotificationDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body",
+		})
+		return
+	}
+
+	notification, err := nc.notificationService.Queue(ctx.Request.Context(), &req)
+	if err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, notification)
+}
+
+func (nc *NotificationsController) GetNotifications(ctx *gin.Context) {
+	notifications, err := nc.notificationService.GetNotifications(ctx.Request.Context(), ctx.Query("status"))
+	if err != nil {
+		slog.ErrorContext(ctx.Request.Context(), "Failed to get notifications", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, notifications)
+}
+
+func (nc *NotificationsController) Dispatch(ctx *gin.Context) {
+	result, err := nc.notificationService.Dispatch(ctx.Request.Context())
+	if err != nil {
+		slog.ErrorContext(ctx.Request.Context(), "Failed to dispatch notifications", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+func (nc *NotificationsController) GetDeliveries(ctx *gin.Context) {
+	id, ok := parseID(ctx, "Invalid notification ID")
+	if !ok {
+		return
+	}
+
+	deliveries, err := nc.notificationService.GetDeliveries(ctx.Request.Context(), id)
+	if err != nil {
+		respondWithNotificationError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, deliveries)
+}
+
+func (nc *NotificationsController) Retry(ctx *gin.Context) {
+	id, ok := parseID(ctx, "Invalid notification ID")
+	if !ok {
+		return
+	}
+
+	notification, err := nc.notificationService.Retry(ctx.Request.Context(), id)
+	if err != nil {
+		respondWithNotificationError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, notification)
+}
+
+func (nc *NotificationsController) GetUserNotifications(ctx *gin.Context) {
+	userId, ok := parseID(ctx, "Invalid user ID")
+	if !ok {
+		return
+	}
+
+	notifications, err := nc.notificationService.GetUserNotifications(ctx.Request.Context(), userId)
+	if err != nil {
+		slog.ErrorContext(ctx.Request.Context(), "Failed to get user notifications", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, notifications)
+}
+
+func (nc *NotificationsController) GetPreferences(ctx *gin.Context) {
+	userId, ok := parseID(ctx, "Invalid user ID")
+	if !ok {
+		return
+	}
+
+	preferences, err := nc.notificationService.GetPreferences(ctx.Request.Context(), userId)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, preferences)
+}
+
+func (nc *NotificationsController) UpdatePreferences(ctx *gin.Context) {
+	userId, ok := parseID(ctx, "Invalid user ID")
+	if !ok {
+		return
+	}
+
+	var req []*models.PreferenceDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body",
+		})
+		return
+	}
+
+	preferences, err := nc.notificationService.UpdatePreferences(ctx.Request.Context(), userId, req)
+	if err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, preferences)
+}
+
+func parseID(ctx *gin.Context, message string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": message,
+		})
+		return uuid.Nil, false
+	}
+
+	return id, true
+}
+
+func respondWithNotificationError(ctx *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, models.ErrNotificationNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, models.ErrNotFailed):
+		status = http.StatusConflict
+	}
+
+	if status == http.StatusInternalServerError {
+		slog.ErrorContext(ctx.Request.Context(), "Notification request failed", "error", err)
+	}
+	ctx.JSON(status, gin.H{
+		"error": err.Error(),
+	})
+}
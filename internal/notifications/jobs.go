@@ -0,0 +1,41 @@
+package notifications
+
+import (
+	"blockbustermvc/internal/jobs"
+	jobModels "blockbustermvc/internal/models/job"
+	models "blockbustermvc/internal/models/notification"
+	"context"
+	"log/slog"
+	"time"
+)
+
+// DispatchJob is the job kind that queues loan reminders and sends due
+// notifications.
+const DispatchJob = "notifications.dispatch"
+
+// RegisterJobs lets the job worker dispatch notifications, and schedules it
+// every interval. Zero removes the schedule.
+func RegisterJobs(ctx context.Context, jobService jobModels.IJobService, service models.INotificationService, interval time.Duration) error {
+	jobService.Register(DispatchJob, func(ctx context.Context, _ *jobModels.JobDTO) error {
+		result, err := service.Dispatch(ctx)
+		if err != nil {
+			return err
+		}
+
+		if result.Reminders+result.Sent+result.Failed+result.Skipped > 0 {
+			slog.InfoContext(ctx, "Dispatched notifications",
+				"reminders", result.Reminders,
+				"sent", result.Sent,
+				"failed", result.Failed,
+				"skipped", result.Skipped,
+			)
+		}
+		return nil
+	})
+
+	if interval == 0 {
+		return jobService.Unschedule(ctx, DispatchJob)
+	}
+
+	return jobService.Schedule(ctx, DispatchJob, DispatchJob, jobs.Every(interval))
+}
@@ -0,0 +1,70 @@
+package notifications
+
+import (
+	models "blockbustermvc/internal/models/notification"
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+)
+
+//go:embed templates
+var templateFiles embed.FS
+
+// renderer turns a notification into an email. Every kind has a
+// templates/<kind>.txt defining "subject" and "text", and a
+// templates/<kind>.html defining the "content" of the shared HTML layout.
+type renderer struct {
+	text map[string]*texttemplate.Template
+	html map[string]*htmltemplate.Template
+}
+
+func newRenderer() (*renderer, error) {
+	r := &renderer{
+		text: make(map[string]*texttemplate.Template),
+		html: make(map[string]*htmltemplate.Template),
+	}
+
+	for _, kind := range models.Kinds {
+		text, err := texttemplate.ParseFS(templateFiles, "templates/"+kind+".txt")
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s text template: %w", kind, err)
+		}
+		r.text[kind] = text
+
+		html, err := htmltemplate.ParseFS(templateFiles, "templates/layout.html", "templates/"+kind+".html")
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s html template: %w", kind, err)
+		}
+		r.html[kind] = html
+	}
+
+	return r, nil
+}
+
+func (r *renderer) Render(view *models.NotificationView) (*models.Message, error) {
+	text, ok := r.text[view.Kind]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", models.ErrUnknownKind, view.Kind)
+	}
+
+	var subject, body, html bytes.Buffer
+	if err := text.ExecuteTemplate(&subject, "subject", view); err != nil {
+		return nil, fmt.Errorf("failed to render %s subject: %w", view.Kind, err)
+	}
+	if err := text.ExecuteTemplate(&body, "text", view); err != nil {
+		return nil, fmt.Errorf("failed to render %s text: %w", view.Kind, err)
+	}
+	if err := r.html[view.Kind].ExecuteTemplate(&html, "layout", view); err != nil {
+		return nil, fmt.Errorf("failed to render %s html: %w", view.Kind, err)
+	}
+
+	return &models.Message{
+		To:      view.Email,
+		Subject: strings.TrimSpace(subject.String()),
+		Text:    strings.TrimLeft(body.String(), "\n"),
+		HTML:    html.String(),
+	}, nil
+}
@@ -0,0 +1,556 @@
+package notifications
+
+import (
+	models "blockbustermvc/internal/models/notification"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// claimLease is how long a claimed notification is hidden from other
+// dispatchers while it is being sent.
+const claimLease = 5 * time.Minute
+
+const notificationColumns = `id, user_id, kind, movie_id, store_id, loan_id, note, status, attempts, last_error,
+	next_attempt_at, sent_at, created_at, updated_at`
+
+/*
+notificationRepository is a struct that represents a Postgres database for storing customer notifications.
+
+Fields:
+- DB (*pgxpool.Pool): A pointer to a Postgres database connection pool.
+
+Behavior:
+- Provides methods for interacting with the notifications, notification_preferences and notification_deliveries tables.
+- Hands queued notifications out to dispatchers with FOR UPDATE SKIP LOCKED, so several servers never send the same one.
+*/
+type notificationRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewNotificationRepository(db *pgxpool.Pool) models.INotificationRepository {
+	return &notificationRepository{
+		DB: db,
+	}
+}
+
+/*
+Queue is a method of notificationRepository struct that queues a notification in the postgres database.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- notification (*models.QueueNotificationDTO): A pointer to a QueueNotificationDTO struct containing the user, kind and related records.
+
+Returns:
+- (*models.NotificationDTO, error): A pointer to a NotificationDTO struct containing the queued notification, or an error if the insertion fails.
+
+Behavior:
+- A notification for an event that already has one is not queued again; the existing notification is returned instead.
+*/
+func (r *notificationRepository) Queue(ctx context.Context, notification *models.QueueNotificationDTO) (*models.NotificationDTO, error) {
+	query := `
+		INSERT INTO notifications (user_id, kind, movie_id, store_id, loan_id, note, event_id, status, next_attempt_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $9, $9)
+		ON CONFLICT (event_id) WHERE event_id IS NOT NULL DO UPDATE SET updated_at = notifications.updated_at
+		RETURNING ` + notificationColumns
+
+	return scanNotification(r.DB.QueryRow(ctx, query,
+		notification.UserID,
+		notification.Kind,
+		notification.MovieID,
+		notification.StoreID,
+		notification.LoanID,
+		notification.Note,
+		notification.EventID,
+		models.StatusQueued,
+		time.Now(),
+	))
+}
+
+/*
+GetNotification is a method of notificationRepository struct that retrieves a notification from the postgres database by its ID.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- id (uuid.UUID): The ID of the notification.
+
+Returns:
+- (*models.NotificationDTO, error): A pointer to a NotificationDTO struct, or models.ErrNotificationNotFound if there is none.
+*/
+func (r *notificationRepository) GetNotification(ctx context.Context, id uuid.UUID) (*models.NotificationDTO, error) {
+	return scanNotification(r.DB.QueryRow(ctx, `SELECT `+notificationColumns+` FROM notifications WHERE id = $1`, id))
+}
+
+/*
+GetNotifications is a method of notificationRepository struct that retrieves notifications from the postgres database.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- status (string): Only notifications with this status are returned; an empty status returns all of them.
+
+Returns:
+- ([]*models.NotificationDTO, error): A slice of NotificationDTO structs, newest first, or an error if the retrieval fails.
+*/
+func (r *notificationRepository) GetNotifications(ctx context.Context, status string) ([]*models.NotificationDTO, error) {
+	rows, err := r.DB.Query(ctx, `
+		SELECT `+notificationColumns+`
+		FROM notifications
+		WHERE $1 = '' OR status = $1
+		ORDER BY created_at DESC`,
+		status,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notifications: %w", err)
+	}
+
+	return scanNotifications(rows)
+}
+
+/*
+GetUserNotifications is a method of notificationRepository struct that retrieves the notifications of a user from the postgres database.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- userId (uuid.UUID): The ID of the user.
+
+Returns:
+- ([]*models.NotificationDTO, error): A slice of NotificationDTO structs, newest first, or an error if the retrieval fails.
+*/
+func (r *notificationRepository) GetUserNotifications(ctx context.Context, userId uuid.UUID) ([]*models.NotificationDTO, error) {
+	rows, err := r.DB.Query(ctx, `
+		SELECT `+notificationColumns+`
+		FROM notifications
+		WHERE user_id = $1
+		ORDER BY created_at DESC`,
+		userId,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user notifications: %w", err)
+	}
+
+	return scanNotifications(rows)
+}
+
+/*
+GetDeliveries is a method of notificationRepository struct that retrieves the delivery log of a notification from the postgres database.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- notificationId (uuid.UUID): The ID of the notification.
+
+Returns:
+- ([]*models.DeliveryDTO, error): A slice of DeliveryDTO structs, first attempt first, or an error if the retrieval fails.
+*/
+func (r *notificationRepository) GetDeliveries(ctx context.Context, notificationId uuid.UUID) ([]*models.DeliveryDTO, error) {
+	rows, err := r.DB.Query(ctx, `
+		SELECT id, notification_id, attempt, channel, recipient, status, error, created_at
+		FROM notification_deliveries
+		WHERE notification_id = $1
+		ORDER BY attempt`,
+		notificationId,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := []*models.DeliveryDTO{}
+	for rows.Next() {
+		var delivery models.DeliveryDTO
+		err := rows.Scan(
+			&delivery.ID,
+			&delivery.NotificationID,
+			&delivery.Attempt,
+			&delivery.Channel,
+			&delivery.Recipient,
+			&delivery.Status,
+			&delivery.Error,
+			&delivery.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan notification delivery: %w", err)
+		}
+		deliveries = append(deliveries, &delivery)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over notification deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+/*
+Requeue is a method of notificationRepository struct that puts a failed notification back in the queue.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- id (uuid.UUID): The ID of the notification.
+
+Returns:
+- (*models.NotificationDTO, error): A pointer to a NotificationDTO struct containing the requeued notification, or an error if the update fails.
+
+Behavior:
+- Resets the attempt counter, so the notification gets the full number of attempts again.
+- Returns models.ErrNotificationNotFound if there is no such notification, or models.ErrNotFailed if it has not failed.
+*/
+func (r *notificationRepository) Requeue(ctx context.Context, id uuid.UUID) (*models.NotificationDTO, error) {
+	now := time.Now()
+	notification, err := scanNotification(r.DB.QueryRow(ctx, `
+		UPDATE notifications
+		SET status = $2, attempts = 0, next_attempt_at = $4, updated_at = $4
+		WHERE id = $1 AND status = $3
+		RETURNING `+notificationColumns,
+		id,
+		models.StatusQueued,
+		models.StatusFailed,
+		now,
+	))
+	if errors.Is(err, models.ErrNotificationNotFound) {
+		if _, err := r.GetNotification(ctx, id); err != nil {
+			return nil, err
+		}
+		return nil, models.ErrNotFailed
+	}
+
+	return notification, err
+}
+
+/*
+GetPreferences is a method of notificationRepository struct that retrieves the stored notification preferences of a user.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- userId (uuid.UUID): The ID of the user.
+
+Returns:
+- (map[string]bool, error): Whether each stored kind is enabled; kinds without a stored preference are missing from the map.
+*/
+func (r *notificationRepository) GetPreferences(ctx context.Context, userId uuid.UUID) (map[string]bool, error) {
+	rows, err := r.DB.Query(ctx, `SELECT kind, enabled FROM notification_preferences WHERE user_id = $1`, userId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification preferences: %w", err)
+	}
+	defer rows.Close()
+
+	preferences := make(map[string]bool)
+	for rows.Next() {
+		var kind string
+		var enabled bool
+		if err := rows.Scan(&kind, &enabled); err != nil {
+			return nil, fmt.Errorf("failed to scan notification preference: %w", err)
+		}
+		preferences[kind] = enabled
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over notification preferences: %w", err)
+	}
+
+	return preferences, nil
+}
+
+/*
+SavePreferences is a method of notificationRepository struct that stores notification preferences of a user.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- userId (uuid.UUID): The ID of the user.
+- preferences ([]*models.PreferenceDTO): The preferences to store; kinds left out keep their current setting.
+
+Returns:
+- (error): An error if the preferences cannot be stored.
+*/
+func (r *notificationRepository) SavePreferences(ctx context.Context, userId uuid.UUID, preferences []*models.PreferenceDTO) error {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin preferences update: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	now := time.Now()
+	for _, preference := range preferences {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO notification_preferences (user_id, kind, enabled, updated_at)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (user_id, kind) DO UPDATE SET enabled = EXCLUDED.enabled, updated_at = EXCLUDED.updated_at`,
+			userId,
+			preference.Kind,
+			preference.Enabled,
+			now,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to save notification preference: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit preferences update: %w", err)
+	}
+
+	return nil
+}
+
+/*
+QueueLoanReminders is a method of notificationRepository struct that queues due-soon and overdue reminders for active loans.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- overdueBefore (time.Time): Loans borrowed at or before this time are overdue.
+- dueSoonBefore (time.Time): Loans borrowed at or before this time, but not overdue, are due soon.
+
+Returns:
+- (int, error): The number of reminders queued, or an error if queueing fails.
+
+Behavior:
+- Each loan gets each kind of reminder at most once, however often this runs.
+*/
+func (r *notificationRepository) QueueLoanReminders(ctx context.Context, overdueBefore, dueSoonBefore time.Time) (int, error) {
+	tag, err := r.DB.Exec(ctx, `
+		INSERT INTO notifications (user_id, kind, movie_id, store_id, loan_id, status, next_attempt_at, created_at, updated_at)
+		SELECT user_id,
+			CASE WHEN borrowed_at <= $1 THEN $3 ELSE $4 END,
+			movie_id, store_id, id, $5, $6, $6, $6
+		FROM loans
+		WHERE status = 'active' AND borrowed_at <= $2
+		ON CONFLICT (loan_id, kind) WHERE kind IN ('due_soon', 'overdue') DO NOTHING`,
+		overdueBefore,
+		dueSoonBefore,
+		models.KindOverdue,
+		models.KindDueSoon,
+		models.StatusQueued,
+		time.Now(),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to queue loan reminders: %w", err)
+	}
+
+	return int(tag.RowsAffected()), nil
+}
+
+/*
+ClaimDue is a method of notificationRepository struct that hands out queued notifications whose next attempt is due.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- limit (int): The maximum number of notifications claimed.
+
+Returns:
+- ([]*models.NotificationView, error): The claimed notifications with the user, movie, store and loan they refer to, or an error if claiming fails.
+
+Behavior:
+- Pushes the next attempt of each claimed notification back by a lease, so other dispatchers skip it while it is sent.
+- A notification whose dispatcher dies is picked up again once the lease runs out.
+*/
+func (r *notificationRepository) ClaimDue(ctx context.Context, limit int) ([]*models.NotificationView, error) {
+	now := time.Now()
+	rows, err := r.DB.Query(ctx, `
+		WITH claimed AS (
+			UPDATE notifications
+			SET next_attempt_at = $3
+			WHERE id IN (
+				SELECT id FROM notifications
+				WHERE status = $1 AND next_attempt_at <= $2
+				ORDER BY next_attempt_at
+				LIMIT $4
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING `+notificationColumns+`
+		)
+		SELECT c.id, c.user_id, c.kind, c.movie_id, c.store_id, c.loan_id, c.note, c.status, c.attempts, c.last_error,
+			c.next_attempt_at, c.sent_at, c.created_at, c.updated_at,
+			u.user_name, u.email, COALESCE(m.name, ''), COALESCE(s.name, ''), l.borrowed_at, l.returned_at
+		FROM claimed c
+		JOIN users u ON u.id = c.user_id
+		LEFT JOIN movies m ON m.id = c.movie_id
+		LEFT JOIN stores s ON s.id = c.store_id
+		LEFT JOIN loans l ON l.id = c.loan_id
+		ORDER BY c.created_at`,
+		models.StatusQueued,
+		now,
+		now.Add(claimLease),
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim notifications: %w", err)
+	}
+	defer rows.Close()
+
+	var views []*models.NotificationView
+	for rows.Next() {
+		var view models.NotificationView
+		var borrowedAt *time.Time
+		err := rows.Scan(
+			&view.ID,
+			&view.UserID,
+			&view.Kind,
+			&view.MovieID,
+			&view.StoreID,
+			&view.LoanID,
+			&view.Note,
+			&view.Status,
+			&view.Attempts,
+			&view.LastError,
+			&view.NextAttemptAt,
+			&view.SentAt,
+			&view.CreatedAt,
+			&view.UpdatedAt,
+			&view.UserName,
+			&view.Email,
+			&view.MovieName,
+			&view.StoreName,
+			&borrowedAt,
+			&view.ReturnedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan claimed notification: %w", err)
+		}
+		if borrowedAt != nil {
+			view.BorrowedAt = *borrowedAt
+		}
+		views = append(views, &view)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over claimed notifications: %w", err)
+	}
+
+	return views, nil
+}
+
+/*
+RecordDelivery is a method of notificationRepository struct that logs a delivery attempt and updates its notification.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- delivery (*models.DeliveryDTO): A pointer to a DeliveryDTO struct describing the attempt.
+- status (string): The notification's status after the attempt.
+- nextAttemptAt (time.Time): When a still queued notification is tried again.
+
+Returns:
+- (error): An error if the attempt cannot be recorded.
+
+Behavior:
+- Inserts the delivery log entry and updates the notification in one transaction.
+*/
+func (r *notificationRepository) RecordDelivery(ctx context.Context, delivery *models.DeliveryDTO, status string, nextAttemptAt time.Time) error {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin delivery log: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	now := time.Now()
+	_, err = tx.Exec(ctx, `
+		INSERT INTO notification_deliveries (notification_id, attempt, channel, recipient, status, error, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		delivery.NotificationID,
+		delivery.Attempt,
+		delivery.Channel,
+		delivery.Recipient,
+		delivery.Status,
+		delivery.Error,
+		now,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to log notification delivery: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE notifications
+		SET status = $2, attempts = $3, last_error = $4, next_attempt_at = $5,
+			sent_at = CASE WHEN $2 = 'sent' THEN $6::TIMESTAMPTZ ELSE sent_at END,
+			updated_at = $6
+		WHERE id = $1`,
+		delivery.NotificationID,
+		status,
+		delivery.Attempt,
+		delivery.Error,
+		nextAttemptAt,
+		now,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update notification: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit delivery log: %w", err)
+	}
+
+	return nil
+}
+
+/*
+Skip is a method of notificationRepository struct that marks a notification the user opted out of.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- id (uuid.UUID): The ID of the notification.
+
+Returns:
+- (error): An error if the update fails.
+*/
+func (r *notificationRepository) Skip(ctx context.Context, id uuid.UUID) error {
+	_, err := r.DB.Exec(ctx, `UPDATE notifications SET status = $2, updated_at = $3 WHERE id = $1`,
+		id,
+		models.StatusSkipped,
+		time.Now(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to skip notification: %w", err)
+	}
+
+	return nil
+}
+
+func scanNotifications(rows pgx.Rows) ([]*models.NotificationDTO, error) {
+	defer rows.Close()
+
+	notifications := []*models.NotificationDTO{}
+	for rows.Next() {
+		notification, err := scanNotification(rows)
+		if err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, notification)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over notifications: %w", err)
+	}
+
+	return notifications, nil
+}
+
+func scanNotification(row pgx.Row) (*models.NotificationDTO, error) {
+	var notification models.NotificationDTO
+	err := row.Scan(
+		&notification.ID,
+		&notification.UserID,
+		&notification.Kind,
+		&notification.MovieID,
+		&notification.StoreID,
+		&notification.LoanID,
+		&notification.Note,
+		&notification.Status,
+		&notification.Attempts,
+		&notification.LastError,
+		&notification.NextAttemptAt,
+		&notification.SentAt,
+		&notification.CreatedAt,
+		&notification.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, models.ErrNotificationNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan notification: %w", err)
+	}
+
+	return &notification, nil
+}
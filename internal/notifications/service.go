@@ -0,0 +1,206 @@
+package notifications
+
+import (
+	models "blockbustermvc/internal/models/notification"
+	userService "blockbustermvc/internal/models/user"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// dueSoonWindow is how long before the due date the reminder goes out.
+	dueSoonWindow = 24 * time.Hour
+
+	// dispatchBatch is how many notifications are claimed at a time.
+	dispatchBatch = 50
+)
+
+type NotificationService struct {
+	notificationRepository models.INotificationRepository
+	userService            userService.IUserService
+	notifier               models.Notifier
+	renderer               *renderer
+	loanPeriod             time.Duration
+}
+
+// NewNotificationService builds the service around a notifier; a nil
+// notifier keeps notifications queued until one is configured.
+func NewNotificationService(
+	notificationRepo models.INotificationRepository,
+	userService userService.IUserService,
+	notifier models.Notifier,
+	loanPeriod time.Duration,
+) (models.INotificationService, error) {
+	renderer, err := newRenderer()
+	if err != nil {
+		return nil, err
+	}
+
+	return &NotificationService{
+		notificationRepository: notificationRepo,
+		userService:            userService,
+		notifier:               notifier,
+		renderer:               renderer,
+		loanPeriod:             loanPeriod,
+	}, nil
+}
+
+func (n NotificationService) Queue(ctx context.Context, notification *models.QueueNotificationDTO) (*models.NotificationDTO, error) {
+	if !models.IsKind(notification.Kind) {
+		return nil, fmt.Errorf("%w: %s", models.ErrUnknownKind, notification.Kind)
+	}
+
+	if _, err := n.userService.GetUser(ctx, notification.UserID); err != nil {
+		return nil, err
+	}
+
+	return n.notificationRepository.Queue(ctx, notification)
+}
+
+func (n NotificationService) GetNotifications(ctx context.Context, status string) ([]*models.NotificationDTO, error) {
+	return n.notificationRepository.GetNotifications(ctx, status)
+}
+
+func (n NotificationService) GetUserNotifications(ctx context.Context, userId uuid.UUID) ([]*models.NotificationDTO, error) {
+	return n.notificationRepository.GetUserNotifications(ctx, userId)
+}
+
+func (n NotificationService) GetDeliveries(ctx context.Context, notificationId uuid.UUID) ([]*models.DeliveryDTO, error) {
+	if _, err := n.notificationRepository.GetNotification(ctx, notificationId); err != nil {
+		return nil, err
+	}
+
+	return n.notificationRepository.GetDeliveries(ctx, notificationId)
+}
+
+func (n NotificationService) Retry(ctx context.Context, notificationId uuid.UUID) (*models.NotificationDTO, error) {
+	return n.notificationRepository.Requeue(ctx, notificationId)
+}
+
+// GetPreferences lists every kind for the user; kinds they never changed
+// are enabled.
+func (n NotificationService) GetPreferences(ctx context.Context, userId uuid.UUID) ([]*models.PreferenceDTO, error) {
+	if _, err := n.userService.GetUser(ctx, userId); err != nil {
+		return nil, err
+	}
+
+	stored, err := n.notificationRepository.GetPreferences(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	preferences := make([]*models.PreferenceDTO, 0, len(models.Kinds))
+	for _, kind := range models.Kinds {
+		enabled, ok := stored[kind]
+		preferences = append(preferences, &models.PreferenceDTO{Kind: kind, Enabled: !ok || enabled})
+	}
+
+	return preferences, nil
+}
+
+func (n NotificationService) UpdatePreferences(ctx context.Context, userId uuid.UUID, preferences []*models.PreferenceDTO) ([]*models.PreferenceDTO, error) {
+	if _, err := n.userService.GetUser(ctx, userId); err != nil {
+		return nil, err
+	}
+
+	for _, preference := range preferences {
+		if !models.IsKind(preference.Kind) {
+			return nil, fmt.Errorf("%w: %s", models.ErrUnknownKind, preference.Kind)
+		}
+	}
+
+	if err := n.notificationRepository.SavePreferences(ctx, userId, preferences); err != nil {
+		return nil, err
+	}
+
+	return n.GetPreferences(ctx, userId)
+}
+
+func (n NotificationService) QueueLoanReminders(ctx context.Context) (int, error) {
+	overdueBefore := time.Now().Add(-n.loanPeriod)
+
+	return n.notificationRepository.QueueLoanReminders(ctx, overdueBefore, overdueBefore.Add(dueSoonWindow))
+}
+
+// Dispatch queues the loan reminders that became due and sends every
+// queued notification whose next attempt is due. Failed sends are retried
+// with a growing delay until models.MaxAttempts.
+func (n NotificationService) Dispatch(ctx context.Context) (*models.DispatchResultDTO, error) {
+	result := &models.DispatchResultDTO{}
+
+	reminders, err := n.QueueLoanReminders(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result.Reminders = reminders
+
+	if n.notifier == nil {
+		return result, nil
+	}
+
+	for {
+		views, err := n.notificationRepository.ClaimDue(ctx, dispatchBatch)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, view := range views {
+			if err := n.deliver(ctx, view, result); err != nil {
+				return nil, err
+			}
+		}
+
+		if len(views) < dispatchBatch {
+			return result, nil
+		}
+	}
+}
+
+func (n NotificationService) deliver(ctx context.Context, view *models.NotificationView, result *models.DispatchResultDTO) error {
+	preferences, err := n.notificationRepository.GetPreferences(ctx, view.UserID)
+	if err != nil {
+		return err
+	}
+	if enabled, ok := preferences[view.Kind]; ok && !enabled {
+		result.Skipped++
+		return n.notificationRepository.Skip(ctx, view.ID)
+	}
+
+	if !view.BorrowedAt.IsZero() {
+		view.DueAt = view.BorrowedAt.Add(n.loanPeriod)
+	}
+
+	delivery := &models.DeliveryDTO{
+		NotificationID: view.ID,
+		Attempt:        view.Attempts + 1,
+		Channel:        n.notifier.Channel(),
+		Recipient:      view.Email,
+		Status:         models.StatusSent,
+	}
+
+	message, err := n.renderer.Render(view)
+	if err == nil {
+		err = n.notifier.Send(message)
+	}
+
+	status, nextAttemptAt := models.StatusSent, time.Now()
+	if err != nil {
+		delivery.Status = models.StatusFailed
+		delivery.Error = err.Error()
+
+		status = models.StatusQueued
+		nextAttemptAt = nextAttemptAt.Add(models.RetryDelay(delivery.Attempt))
+		if delivery.Attempt >= models.MaxAttempts {
+			status = models.StatusFailed
+		}
+		result.Failed++
+	} else {
+		result.Sent++
+	}
+
+	// A notification that was sent is recorded even when the dispatcher is stopping
+	return n.notificationRepository.RecordDelivery(context.WithoutCancel(ctx), delivery, status, nextAttemptAt)
+}
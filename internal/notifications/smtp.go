@@ -0,0 +1,103 @@
+package notifications
+
+import (
+	models "blockbustermvc/internal/models/notification"
+	"bytes"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"time"
+)
+
+// SMTPConfig is where and as whom emails are sent. Username and Password
+// may be empty for servers without authentication, such as a local fake
+// SMTP server.
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+type smtpNotifier struct {
+	addr string
+	from *mail.Address
+	auth smtp.Auth
+}
+
+func NewSMTPNotifier(config SMTPConfig) (models.Notifier, error) {
+	from, err := mail.ParseAddress(config.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender address %q: %w", config.From, err)
+	}
+
+	notifier := &smtpNotifier{
+		addr: net.JoinHostPort(config.Host, config.Port),
+		from: from,
+	}
+	if config.Username != "" {
+		notifier.auth = smtp.PlainAuth("", config.Username, config.Password, config.Host)
+	}
+
+	return notifier, nil
+}
+
+func (n *smtpNotifier) Channel() string {
+	return models.ChannelEmail
+}
+
+// Send delivers the message as multipart/alternative, so mail clients show
+// the HTML body and fall back to the plain text one.
+func (n *smtpNotifier) Send(message *models.Message) error {
+	to, err := mail.ParseAddress(message.To)
+	if err != nil {
+		return fmt.Errorf("invalid recipient address %q: %w", message.To, err)
+	}
+
+	var body bytes.Buffer
+	parts := multipart.NewWriter(&body)
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain; charset=utf-8", message.Text},
+		{"text/html; charset=utf-8", message.HTML},
+	} {
+		writer, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to build email: %w", err)
+		}
+
+		encoder := quotedprintable.NewWriter(writer)
+		if _, err := encoder.Write([]byte(part.content)); err != nil {
+			return fmt.Errorf("failed to build email: %w", err)
+		}
+		if err := encoder.Close(); err != nil {
+			return fmt.Errorf("failed to build email: %w", err)
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return fmt.Errorf("failed to build email: %w", err)
+	}
+
+	var email bytes.Buffer
+	fmt.Fprintf(&email, "From: %s\r\n", n.from.String())
+	fmt.Fprintf(&email, "To: %s\r\n", to.String())
+	fmt.Fprintf(&email, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", message.Subject))
+	fmt.Fprintf(&email, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&email, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&email, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", parts.Boundary())
+	email.Write(body.Bytes())
+
+	if err := smtp.SendMail(n.addr, n.auth, n.from.Address, []string{to.Address}, email.Bytes()); err != nil {
+		return fmt.Errorf("failed to send email to %s: %w", to.Address, err)
+	}
+
+	return nil
+}
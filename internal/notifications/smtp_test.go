@@ -0,0 +1,155 @@
+package notifications
+
+import (
+	models "blockbustermvc/internal/models/notification"
+	"bufio"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"testing"
+)
+
+// smtpEnvelope is what the fake server received in one session.
+type smtpEnvelope struct {
+	from string
+	to   []string
+	data string
+}
+
+// serveSMTP accepts one session on listener, answers it the way a server
+// without extensions or authentication would, and sends what it received.
+// The channel is closed without a value if the session breaks off.
+func serveSMTP(listener net.Listener, received chan<- smtpEnvelope) {
+	defer close(received)
+
+	conn, err := listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	text := textproto.NewConn(conn)
+	var envelope smtpEnvelope
+
+	text.PrintfLine("220 localhost fake SMTP")
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+
+		verb, argument, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "EHLO", "HELO":
+			text.PrintfLine("250 localhost")
+		case "MAIL":
+			envelope.from = strings.TrimPrefix(argument, "FROM:")
+			text.PrintfLine("250 OK")
+		case "RCPT":
+			envelope.to = append(envelope.to, strings.TrimPrefix(argument, "TO:"))
+			text.PrintfLine("250 OK")
+		case "DATA":
+			text.PrintfLine("354 End data with <CR><LF>.<CR><LF>")
+			data, err := io.ReadAll(text.DotReader())
+			if err != nil {
+				return
+			}
+			envelope.data = string(data)
+			text.PrintfLine("250 OK")
+		case "QUIT":
+			text.PrintfLine("221 Bye")
+			received <- envelope
+			return
+		default:
+			text.PrintfLine("502 Command not implemented")
+		}
+	}
+}
+
+func TestSMTPNotifierSendsMultipartEmail(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer listener.Close()
+
+	received := make(chan smtpEnvelope, 1)
+	go serveSMTP(listener, received)
+
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	notifier, err := NewSMTPNotifier(SMTPConfig{Host: host, Port: port, From: "Blockbuster <no-reply@blockbuster.local>"})
+	if err != nil {
+		t.Fatalf("NewSMTPNotifier() error = %v", err)
+	}
+
+	err = notifier.Send(&models.Message{
+		To:      "Jane Doe <jane@example.com>",
+		Subject: "Heat is back in stock ✨",
+		Text:    "Heat is available at Downtown.",
+		HTML:    "<p><strong>Heat</strong> is available at Downtown.</p>",
+	})
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	envelope, ok := <-received
+	if !ok {
+		t.Fatal("fake SMTP server did not receive a complete session")
+	}
+
+	if envelope.from != "<no-reply@blockbuster.local>" {
+		t.Errorf("MAIL FROM = %q, want %q", envelope.from, "<no-reply@blockbuster.local>")
+	}
+	if len(envelope.to) != 1 || envelope.to[0] != "<jane@example.com>" {
+		t.Errorf("RCPT TO = %q, want [<jane@example.com>]", envelope.to)
+	}
+
+	email, err := mail.ReadMessage(bufio.NewReader(strings.NewReader(envelope.data)))
+	if err != nil {
+		t.Fatalf("received email does not parse: %v", err)
+	}
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(email.Header.Get("Subject"))
+	if err != nil || subject != "Heat is back in stock ✨" {
+		t.Errorf("Subject = %q (%v), want %q", subject, err, "Heat is back in stock ✨")
+	}
+	if to := email.Header.Get("To"); !strings.Contains(to, "jane@example.com") {
+		t.Errorf("To = %q, want the recipient", to)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(email.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("Content-Type = %q (%v), want multipart/alternative", email.Header.Get("Content-Type"), err)
+	}
+
+	want := []struct{ contentType, body string }{
+		{"text/plain; charset=utf-8", "Heat is available at Downtown."},
+		{"text/html; charset=utf-8", "<p><strong>Heat</strong> is available at Downtown.</p>"},
+	}
+
+	parts := multipart.NewReader(email.Body, params["boundary"])
+	for _, w := range want {
+		part, err := parts.NextPart()
+		if err != nil {
+			t.Fatalf("missing %s part: %v", w.contentType, err)
+		}
+		// The reader undoes the quoted-printable encoding
+		body, err := io.ReadAll(part)
+		if err != nil {
+			t.Fatalf("failed to read %s part: %v", w.contentType, err)
+		}
+		if got := part.Header.Get("Content-Type"); got != w.contentType {
+			t.Errorf("part Content-Type = %q, want %q", got, w.contentType)
+		}
+		if string(body) != w.body {
+			t.Errorf("%s body = %q, want %q", w.contentType, body, w.body)
+		}
+	}
+	if _, err := parts.NextPart(); err != io.EOF {
+		t.Errorf("extra part after the HTML one: %v", err)
+	}
+}
@@ -0,0 +1,35 @@
+package notifications
+
+import (
+	eventModels "blockbustermvc/internal/models/event"
+	models "blockbustermvc/internal/models/notification"
+	"blockbustermvc/internal/outbox"
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// loanEvent holds the fields loan.created and movie.returned have in
+// common.
+type loanEvent struct {
+	LoanID  uuid.UUID `json:"loan_id"`
+	MovieID uuid.UUID `json:"movie_id"`
+	UserID  uuid.UUID `json:"user_id"`
+	StoreID uuid.UUID `json:"store_id"`
+}
+
+// Subscribe queues a receipt for every checkout and return. The event ID
+// keeps a redelivered event from queueing a second receipt.
+func Subscribe(eventService eventModels.IEventService, service models.INotificationService) {
+	eventService.Subscribe("notifications.receipts", outbox.Typed(func(ctx context.Context, event *eventModels.EventDTO, loan *loanEvent) error {
+		_, err := service.Queue(ctx, &models.QueueNotificationDTO{
+			UserID:  loan.UserID,
+			Kind:    models.KindReceipt,
+			MovieID: &loan.MovieID,
+			StoreID: &loan.StoreID,
+			LoanID:  &loan.LoanID,
+			EventID: &event.ID,
+		})
+		return err
+	}), eventModels.TypeLoanCreated, eventModels.TypeMovieReturned)
+}
@@ -0,0 +1,111 @@
+package outbox
+
+import (
+	models "blockbustermvc/internal/models/event"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type EventsController struct {
+	eventService models.IEventService
+}
+
+func NewEventsController(eventService models.IEventService) *EventsController {
+	return &EventsController{
+		eventService: eventService,
+	}
+}
+
+func (ec *EventsController) RegisterRoutes(r *gin.RouterGroup) {
+	events := r.Group("/events")
+
+	{
+		events.GET("", ec.GetEvents)
+		events.POST("/dispatch", ec.Dispatch)
+		events.GET("/:id", ec.GetEvent)
+		events.POST("/:id/redeliver", ec.Redeliver)
+	}
+}
+
+func (ec *EventsController) GetEvents(ctx *gin.Context) {
+	events, err := ec.eventService.GetEvents(ctx.Request.Context(), ctx.Query("type"), ctx.Query("status"))
+	if err != nil {
+		respondWithEventError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, events)
+}
+
+func (ec *EventsController) Dispatch(ctx *gin.Context) {
+	result, err := ec.eventService.Dispatch(ctx.Request.Context())
+	if err != nil {
+		respondWithEventError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+func (ec *EventsController) GetEvent(ctx *gin.Context) {
+	eventId, ok := parseEventID(ctx)
+	if !ok {
+		return
+	}
+
+	event, err := ec.eventService.GetEvent(ctx.Request.Context(), eventId)
+	if err != nil {
+		respondWithEventError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, event)
+}
+
+func (ec *EventsController) Redeliver(ctx *gin.Context) {
+	eventId, ok := parseEventID(ctx)
+	if !ok {
+		return
+	}
+
+	event, err := ec.eventService.Redeliver(ctx.Request.Context(), eventId)
+	if err != nil {
+		respondWithEventError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, event)
+}
+
+func parseEventID(ctx *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid event ID",
+		})
+		return uuid.Nil, false
+	}
+
+	return id, true
+}
+
+func respondWithEventError(ctx *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, models.ErrEventNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, models.ErrNotFailed):
+		status = http.StatusConflict
+	}
+
+	if status == http.StatusInternalServerError {
+		slog.ErrorContext(ctx.Request.Context(), "Event request failed", "error", err)
+	}
+	ctx.JSON(status, gin.H{
+		"error": err.Error(),
+	})
+}
@@ -0,0 +1,39 @@
+package outbox
+
+import (
+	"blockbustermvc/internal/config"
+	models "blockbustermvc/internal/models/event"
+	"context"
+	"log/slog"
+	"time"
+)
+
+const defaultPollInterval = time.Second
+
+// ConfiguredPollInterval reads how often the dispatcher looks for new
+// events from BLK_OUTBOX_POLL.
+func ConfiguredPollInterval() (time.Duration, error) {
+	return config.Duration("BLK_OUTBOX_POLL", defaultPollInterval, false)
+}
+
+// RunDispatcher delivers due events to the subscribers every interval
+// until ctx is cancelled.
+func RunDispatcher(ctx context.Context, service models.IEventService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		result, err := service.Dispatch(ctx)
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to dispatch events", "error", err)
+		} else if result.Failed > 0 {
+			slog.InfoContext(ctx, "Dispatched events", "events", result.Events, "delivered", result.Delivered, "failed", result.Failed)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
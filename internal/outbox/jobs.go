@@ -0,0 +1,40 @@
+package outbox
+
+import (
+	"blockbustermvc/internal/jobs"
+	models "blockbustermvc/internal/models/event"
+	jobModels "blockbustermvc/internal/models/job"
+	"context"
+	"log/slog"
+	"time"
+)
+
+const (
+	// PruneJob is the job kind that deletes old dispatched events.
+	PruneJob = "outbox.prune"
+
+	pruneSpec       = "30 4 * * *"
+	defaultKeepDays = 7
+)
+
+// RegisterJobs lets the job worker delete dispatched events older than
+// the payload's keep_days, every night.
+func RegisterJobs(ctx context.Context, jobService jobModels.IJobService, service models.IEventService) error {
+	jobService.Register(PruneJob, jobs.Typed(func(ctx context.Context, payload *jobs.PrunePayload) error {
+		keepDays := payload.KeepDays
+		if keepDays <= 0 {
+			keepDays = defaultKeepDays
+		}
+
+		pruned, err := service.Prune(ctx, time.Duration(keepDays)*24*time.Hour)
+		if err != nil {
+			return err
+		}
+		if pruned > 0 {
+			slog.InfoContext(ctx, "Pruned dispatched events", "pruned", pruned)
+		}
+		return nil
+	}))
+
+	return jobService.Schedule(ctx, PruneJob, PruneJob, pruneSpec)
+}
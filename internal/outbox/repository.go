@@ -0,0 +1,317 @@
+package outbox
+
+import (
+	models "blockbustermvc/internal/models/event"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// claimLease is how long a claimed event is hidden from other dispatchers
+// while it is delivered.
+const claimLease = time.Minute
+
+const eventColumns = `id, type, aggregate_id, payload::TEXT, status, attempts, last_error, delivered_to,
+	next_attempt_at, dispatched_at, created_at`
+
+/*
+eventRepository is a struct that represents a Postgres database for storing the outbox of domain events.
+
+Fields:
+- DB (*pgxpool.Pool): A pointer to a Postgres database connection pool.
+
+Behavior:
+- Provides methods for interacting with the outbox_events table in the database. Events are written by Write, inside the transaction of the change they describe.
+- Hands pending events out to dispatchers with FOR UPDATE SKIP LOCKED, so several processes never deliver the same event at once.
+*/
+type eventRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewEventRepository(db *pgxpool.Pool) models.IEventRepository {
+	return &eventRepository{
+		DB: db,
+	}
+}
+
+/*
+GetEvents is a method of eventRepository struct that retrieves domain events from the postgres database.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- eventType (string): Only events of this type are returned; an empty type returns all of them.
+- status (string): Only events with this status are returned; an empty status returns all of them.
+- limit (int): The maximum number of events returned.
+
+Returns:
+- ([]*models.EventDTO, error): A slice of EventDTO structs, newest first, or an error if the retrieval fails.
+*/
+func (r *eventRepository) GetEvents(ctx context.Context, eventType, status string, limit int) ([]*models.EventDTO, error) {
+	rows, err := r.DB.Query(ctx, `
+		SELECT `+eventColumns+`
+		FROM outbox_events
+		WHERE ($1 = '' OR type = $1) AND ($2 = '' OR status = $2)
+		ORDER BY created_at DESC
+		LIMIT $3`,
+		eventType,
+		status,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get events: %w", err)
+	}
+
+	return scanEvents(rows)
+}
+
+/*
+GetEvent is a method of eventRepository struct that retrieves a domain event from the postgres database by its ID.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- id (uuid.UUID): The ID of the event.
+
+Returns:
+- (*models.EventDTO, error): A pointer to an EventDTO struct, or models.ErrEventNotFound if there is none.
+*/
+func (r *eventRepository) GetEvent(ctx context.Context, id uuid.UUID) (*models.EventDTO, error) {
+	return scanEvent(r.DB.QueryRow(ctx, `SELECT `+eventColumns+` FROM outbox_events WHERE id = $1`, id))
+}
+
+/*
+Requeue is a method of eventRepository struct that puts a failed event back in the outbox.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- id (uuid.UUID): The ID of the event.
+
+Returns:
+- (*models.EventDTO, error): A pointer to an EventDTO struct containing the pending event, or an error if the update fails.
+
+Behavior:
+- Subscribers that already acknowledged the event do not get it again.
+- Returns models.ErrNotFailed if the event has not failed.
+*/
+func (r *eventRepository) Requeue(ctx context.Context, id uuid.UUID) (*models.EventDTO, error) {
+	event, err := scanEvent(r.DB.QueryRow(ctx, `
+		UPDATE outbox_events
+		SET status = $2, attempts = 0, next_attempt_at = $4
+		WHERE id = $1 AND status = $3
+		RETURNING `+eventColumns,
+		id,
+		models.StatusPending,
+		models.StatusFailed,
+		time.Now(),
+	))
+	if errors.Is(err, models.ErrEventNotFound) {
+		if _, err := r.GetEvent(ctx, id); err != nil {
+			return nil, err
+		}
+		return nil, models.ErrNotFailed
+	}
+
+	return event, err
+}
+
+/*
+ClaimDue is a method of eventRepository struct that hands out pending events whose next attempt is due.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- limit (int): The maximum number of events claimed.
+
+Returns:
+- ([]*models.EventDTO, error): The claimed events, oldest first, or an error if claiming fails.
+
+Behavior:
+- Pushes the next attempt of each claimed event back by a lease, so other dispatchers skip it while it is delivered.
+- An event whose dispatcher dies is picked up again once the lease runs out.
+*/
+func (r *eventRepository) ClaimDue(ctx context.Context, limit int) ([]*models.EventDTO, error) {
+	now := time.Now()
+	rows, err := r.DB.Query(ctx, `
+		WITH claimed AS (
+			UPDATE outbox_events
+			SET next_attempt_at = $3
+			WHERE id IN (
+				SELECT id FROM outbox_events
+				WHERE status = $1 AND next_attempt_at <= $2
+				ORDER BY created_at
+				LIMIT $4
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING `+eventColumns+`
+		)
+		SELECT * FROM claimed ORDER BY created_at`,
+		models.StatusPending,
+		now,
+		now.Add(claimLease),
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim events: %w", err)
+	}
+
+	return scanEvents(rows)
+}
+
+/*
+MarkDelivered is a method of eventRepository struct that records that a subscriber handled an event.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- id (uuid.UUID): The ID of the event.
+- subscriber (string): The name of the subscriber.
+
+Returns:
+- (error): An error if the update fails.
+*/
+func (r *eventRepository) MarkDelivered(ctx context.Context, id uuid.UUID, subscriber string) error {
+	_, err := r.DB.Exec(ctx, `
+		UPDATE outbox_events
+		SET delivered_to = array_append(delivered_to, $2)
+		WHERE id = $1 AND NOT ($2 = ANY(delivered_to))`,
+		id,
+		subscriber,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record event delivery: %w", err)
+	}
+
+	return nil
+}
+
+/*
+Finish is a method of eventRepository struct that marks an event as delivered to all of its subscribers.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- id (uuid.UUID): The ID of the event.
+
+Returns:
+- (error): An error if the update fails.
+*/
+func (r *eventRepository) Finish(ctx context.Context, id uuid.UUID) error {
+	_, err := r.DB.Exec(ctx, `
+		UPDATE outbox_events
+		SET status = $2, last_error = '', dispatched_at = $3
+		WHERE id = $1`,
+		id,
+		models.StatusDispatched,
+		time.Now(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to finish event: %w", err)
+	}
+
+	return nil
+}
+
+/*
+Fail is a method of eventRepository struct that records a delivery round in which a subscriber failed.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- id (uuid.UUID): The ID of the event.
+- message (string): The errors the subscribers failed with.
+- nextAttemptAt (time.Time): When the event is offered again.
+- failed (bool): Whether the event ran out of attempts and is marked failed instead.
+
+Returns:
+- (error): An error if the update fails.
+*/
+func (r *eventRepository) Fail(ctx context.Context, id uuid.UUID, message string, nextAttemptAt time.Time, failed bool) error {
+	status := models.StatusPending
+	if failed {
+		status = models.StatusFailed
+	}
+
+	_, err := r.DB.Exec(ctx, `
+		UPDATE outbox_events
+		SET status = $2, attempts = attempts + 1, last_error = $3, next_attempt_at = $4
+		WHERE id = $1`,
+		id,
+		status,
+		message,
+		nextAttemptAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record event failure: %w", err)
+	}
+
+	return nil
+}
+
+/*
+Prune is a method of eventRepository struct that deletes old dispatched events from the postgres database.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- dispatchedBefore (time.Time): Events dispatched before this time are deleted.
+
+Returns:
+- (int64, error): The number of events deleted, or an error if the deletion fails.
+
+Behavior:
+- Pending and failed events are kept.
+*/
+func (r *eventRepository) Prune(ctx context.Context, dispatchedBefore time.Time) (int64, error) {
+	tag, err := r.DB.Exec(ctx, `DELETE FROM outbox_events WHERE status = $1 AND dispatched_at < $2`, models.StatusDispatched, dispatchedBefore)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune events: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+func scanEvents(rows pgx.Rows) ([]*models.EventDTO, error) {
+	defer rows.Close()
+
+	events := []*models.EventDTO{}
+	for rows.Next() {
+		event, err := scanEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over events: %w", err)
+	}
+
+	return events, nil
+}
+
+func scanEvent(row pgx.Row) (*models.EventDTO, error) {
+	var event models.EventDTO
+	var payload string
+	err := row.Scan(
+		&event.ID,
+		&event.Type,
+		&event.AggregateID,
+		&payload,
+		&event.Status,
+		&event.Attempts,
+		&event.LastError,
+		&event.DeliveredTo,
+		&event.NextAttemptAt,
+		&event.DispatchedAt,
+		&event.CreatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, models.ErrEventNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan event: %w", err)
+	}
+
+	event.Payload = json.RawMessage(payload)
+	return &event, nil
+}
@@ -0,0 +1,158 @@
+package outbox
+
+import (
+	"blockbustermvc/internal/logging"
+	models "blockbustermvc/internal/models/event"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// dispatchBatch is how many events are claimed at a time.
+	dispatchBatch = 100
+
+	// listLimit caps how many events are listed at once.
+	listLimit = 200
+)
+
+type subscription struct {
+	name       string
+	handler    models.Subscriber
+	eventTypes []string
+}
+
+func (s *subscription) wants(eventType string) bool {
+	return len(s.eventTypes) == 0 || slices.Contains(s.eventTypes, eventType)
+}
+
+type EventService struct {
+	eventRepository models.IEventRepository
+	subscriptions   *[]*subscription
+}
+
+func NewEventService(eventRepo models.IEventRepository) models.IEventService {
+	return &EventService{
+		eventRepository: eventRepo,
+		subscriptions:   &[]*subscription{},
+	}
+}
+
+// Typed wraps a subscriber that takes the event payload decoded into T.
+func Typed[T any](handle func(ctx context.Context, event *models.EventDTO, payload *T) error) models.Subscriber {
+	return func(ctx context.Context, event *models.EventDTO) error {
+		var payload T
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return fmt.Errorf("invalid %s payload: %w", event.Type, err)
+		}
+
+		return handle(ctx, event, &payload)
+	}
+}
+
+// Subscribe registers an in-process subscriber for the given event types,
+// or for every type when none are given. The name identifies the
+// subscriber in the delivery record, so it must stay stable across
+// restarts. Subscribers must be registered before the dispatcher starts.
+func (e EventService) Subscribe(name string, handler models.Subscriber, eventTypes ...string) {
+	*e.subscriptions = append(*e.subscriptions, &subscription{name: name, handler: handler, eventTypes: eventTypes})
+}
+
+func (e EventService) GetEvents(ctx context.Context, eventType, status string) ([]*models.EventDTO, error) {
+	return e.eventRepository.GetEvents(ctx, eventType, status, listLimit)
+}
+
+func (e EventService) GetEvent(ctx context.Context, id uuid.UUID) (*models.EventDTO, error) {
+	return e.eventRepository.GetEvent(ctx, id)
+}
+
+func (e EventService) Redeliver(ctx context.Context, id uuid.UUID) (*models.EventDTO, error) {
+	return e.eventRepository.Requeue(ctx, id)
+}
+
+// Dispatch delivers every due event to the subscribers that have not
+// acknowledged it yet. An event is finished once all of them succeed;
+// otherwise it is offered again later, only to those that failed.
+func (e EventService) Dispatch(ctx context.Context) (*models.DispatchResultDTO, error) {
+	result := &models.DispatchResultDTO{}
+
+	for {
+		events, err := e.eventRepository.ClaimDue(ctx, dispatchBatch)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, event := range events {
+			if err := e.deliver(ctx, event, result); err != nil {
+				return nil, err
+			}
+		}
+		result.Events += len(events)
+
+		if len(events) < dispatchBatch {
+			return result, nil
+		}
+	}
+}
+
+func (e EventService) deliver(ctx context.Context, event *models.EventDTO, result *models.DispatchResultDTO) error {
+	var failures []string
+
+	for _, subscription := range *e.subscriptions {
+		if !subscription.wants(event.Type) || slices.Contains(event.DeliveredTo, subscription.name) {
+			continue
+		}
+
+		if err := subscription.call(ctx, event); err != nil {
+			failures = append(failures, subscription.name+": "+err.Error())
+			continue
+		}
+
+		if err := e.eventRepository.MarkDelivered(ctx, event.ID, subscription.name); err != nil {
+			return err
+		}
+		result.Delivered++
+	}
+
+	if len(failures) == 0 {
+		return e.eventRepository.Finish(ctx, event.ID)
+	}
+
+	result.Failed++
+	attempts := event.Attempts + 1
+	failed := attempts >= models.MaxAttempts
+	if failed {
+		slog.ErrorContext(ctx, "Giving up on event", "event_id", event.ID, "event_type", event.Type, "attempts", attempts, "error", strings.Join(failures, "; "))
+	}
+
+	return e.eventRepository.Fail(ctx, event.ID, strings.Join(failures, "; "), time.Now().Add(models.RetryDelay(attempts)), failed)
+}
+
+func (s *subscription) call(ctx context.Context, event *models.EventDTO) (err error) {
+	// A panicking subscriber fails its delivery instead of the dispatcher
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("panic: %v", recovered)
+		}
+	}()
+
+	// Everything a subscriber logs says which event it was handling
+	ctx = logging.WithAttrs(ctx,
+		slog.String("event_id", event.ID.String()),
+		slog.String("event_type", event.Type),
+		slog.String("subscriber", s.name),
+	)
+
+	return s.handler(ctx, event)
+}
+
+// Prune deletes events dispatched longer ago than olderThan.
+func (e EventService) Prune(ctx context.Context, olderThan time.Duration) (int64, error) {
+	return e.eventRepository.Prune(ctx, time.Now().Add(-olderThan))
+}
@@ -0,0 +1,59 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// NotifyChannel is the Postgres channel every event is announced on when
+// its transaction commits, for live listeners in any process.
+const NotifyChannel = "outbox_events"
+
+// notifyPayloadLimit keeps announcements under Postgres' 8000 byte limit;
+// bigger payloads are announced without them.
+const notifyPayloadLimit = 7000
+
+// Write records a domain event in the outbox. It runs inside the
+// transaction of the state change it describes, so the event exists if and
+// only if the change is committed; the dispatcher delivers it afterwards.
+// The event is also announced on NotifyChannel, which Postgres holds back
+// until the commit as well.
+func Write(ctx context.Context, tx pgx.Tx, eventType string, aggregateId uuid.UUID, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s event: %w", eventType, err)
+	}
+
+	now := time.Now()
+	_, err = tx.Exec(ctx, `
+		WITH event AS (
+			INSERT INTO outbox_events (type, aggregate_id, payload, next_attempt_at, created_at)
+			VALUES ($1, $2, $3::JSONB, $4, $4)
+			RETURNING id, type, aggregate_id, payload, created_at
+		)
+		SELECT pg_notify($5, json_build_object(
+			'id', id,
+			'type', type,
+			'aggregate_id', aggregate_id,
+			'payload', CASE WHEN octet_length(payload::TEXT) <= $6 THEN payload END,
+			'created_at', created_at
+		)::TEXT)
+		FROM event`,
+		eventType,
+		aggregateId,
+		string(body),
+		now,
+		NotifyChannel,
+		notifyPayloadLimit,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to write %s event: %w", eventType, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package recommendations
+
+import (
+	models "blockbustermvc/internal/models/recommendation"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type RecommendationsController struct {
+	recommendationService models.IRecommendationService
+}
+
+func NewRecommendationsController(recommendationService models.IRecommendationService) *RecommendationsController {
+	return &RecommendationsController{
+		recommendationService: recommendationService,
+	}
+}
+
+func (rc *RecommendationsController) RegisterRoutes(r *gin.RouterGroup) {
+	r.GET("/movies/:id/similar", rc.GetSimilarMovies)
+	r.GET("/users/:id/recommendations", rc.GetUserRecommendations)
+	r.POST("/recommendations/refresh", rc.RefreshSimilarities)
+}
+
+func (rc *RecommendationsController) GetSimilarMovies(ctx *gin.Context) {
+	movieId, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid movie ID",
+		})
+		return
+	}
+
+	limit, ok := parseLimit(ctx)
+	if !ok {
+		return
+	}
+
+	movies, err := rc.recommendationService.GetSimilarMovies(ctx.Request.Context(), movieId, limit)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, movies)
+}
+
+func (rc *RecommendationsController) GetUserRecommendations(ctx *gin.Context) {
+	userId, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid user ID",
+		})
+		return
+	}
+
+	limit, ok := parseLimit(ctx)
+	if !ok {
+		return
+	}
+
+	movies, err := rc.recommendationService.GetUserRecommendations(ctx.Request.Context(), userId, limit)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, movies)
+}
+
+func (rc *RecommendationsController) RefreshSimilarities(ctx *gin.Context) {
+	result, err := rc.recommendationService.RefreshSimilarities(ctx.Request.Context())
+	if err != nil {
+		slog.ErrorContext(ctx.Request.Context(), "Failed to refresh similarities", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// parseLimit reads the optional ?limit= query parameter; zero lets the
+// service pick its default.
+func parseLimit(ctx *gin.Context) (int, bool) {
+	value := ctx.Query("limit")
+	if value == "" {
+		return 0, true
+	}
+
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 1 {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "limit must be a positive number",
+		})
+		return 0, false
+	}
+
+	return limit, true
+}
@@ -0,0 +1,44 @@
+package recommendations
+
+import (
+	"blockbustermvc/internal/config"
+	"blockbustermvc/internal/jobs"
+	jobModels "blockbustermvc/internal/models/job"
+	models "blockbustermvc/internal/models/recommendation"
+	"context"
+	"log/slog"
+	"time"
+)
+
+// defaultRefreshInterval is used when BLK_RECOMMENDATIONS_REFRESH is unset.
+const defaultRefreshInterval = time.Hour
+
+// ConfiguredRefreshInterval reads how often the similarities are recomputed
+// from the environment. Zero disables the periodic refresh.
+func ConfiguredRefreshInterval() (time.Duration, error) {
+	return config.Duration("BLK_RECOMMENDATIONS_REFRESH", defaultRefreshInterval, true)
+}
+
+// RefreshJob is the job kind that recomputes the similarities.
+const RefreshJob = "recommendations.refresh"
+
+// RegisterJobs lets the job worker recompute the similarities, and
+// schedules it every interval. Zero removes the schedule, leaving only
+// on-demand refreshes.
+func RegisterJobs(ctx context.Context, jobService jobModels.IJobService, service models.IRecommendationService, interval time.Duration) error {
+	jobService.Register(RefreshJob, func(ctx context.Context, _ *jobModels.JobDTO) error {
+		result, err := service.RefreshSimilarities(ctx)
+		if err != nil {
+			return err
+		}
+
+		slog.InfoContext(ctx, "Refreshed recommendations", "movie_pairs", result.Pairs)
+		return nil
+	})
+
+	if interval == 0 {
+		return jobService.Unschedule(ctx, RefreshJob)
+	}
+
+	return jobService.Schedule(ctx, RefreshJob, RefreshJob, jobs.Every(interval))
+}
@@ -0,0 +1,195 @@
+package recommendations
+
+import (
+	models "blockbustermvc/internal/models/recommendation"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+/*
+recommendationRepository is a struct that represents a Postgres database for storing movie similarities.
+
+Fields:
+- DB (*pgxpool.Pool): A pointer to a Postgres database connection pool.
+
+Behavior:
+- Computes item-to-item co-occurrence from loan_history, archived loans included, into the movie_similarities table.
+- Reads recommendations back from the precomputed table.
+*/
+type recommendationRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewRecommendationRepository(db *pgxpool.Pool) models.IRecommendationRepository {
+	return &recommendationRepository{
+		DB: db,
+	}
+}
+
+/*
+RefreshSimilarities is a method of recommendationRepository struct that recomputes the movie similarities from the loan history.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- neighbors (int): How many of the most similar movies are kept per movie.
+
+Returns:
+- (*models.RefreshResultDTO, error): A pointer to a RefreshResultDTO struct with the number of pairs stored, or an error if the refresh fails.
+
+Behavior:
+- Two movies are related when the same customer rented both, however many times.
+- The score is the cosine similarity of their renters: shared renters / sqrt(renters of one * renters of the other).
+- Replaces the whole table in one transaction, so readers never see a half-built table.
+*/
+func (r *recommendationRepository) RefreshSimilarities(ctx context.Context, neighbors int) (*models.RefreshResultDTO, error) {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin similarities refresh: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM movie_similarities`); err != nil {
+		return nil, fmt.Errorf("failed to clear movie similarities: %w", err)
+	}
+
+	computedAt := time.Now()
+	tag, err := tx.Exec(ctx, `
+		WITH rentals AS (
+			SELECT DISTINCT user_id, movie_id FROM loan_history
+		),
+		renters AS (
+			SELECT movie_id, COUNT(*) AS renters FROM rentals GROUP BY movie_id
+		),
+		pairs AS (
+			SELECT a.movie_id, b.movie_id AS similar_movie_id, COUNT(*) AS shared_renters
+			FROM rentals a
+			JOIN rentals b ON b.user_id = a.user_id AND b.movie_id <> a.movie_id
+			GROUP BY a.movie_id, b.movie_id
+		),
+		ranked AS (
+			SELECT p.movie_id, p.similar_movie_id, p.shared_renters,
+				p.shared_renters / sqrt(ra.renters * rb.renters) AS score
+			FROM pairs p
+			JOIN renters ra ON ra.movie_id = p.movie_id
+			JOIN renters rb ON rb.movie_id = p.similar_movie_id
+		)
+		INSERT INTO movie_similarities (movie_id, similar_movie_id, shared_renters, score, computed_at)
+		SELECT movie_id, similar_movie_id, shared_renters, score, $2
+		FROM (
+			SELECT ranked.*, ROW_NUMBER() OVER (PARTITION BY movie_id ORDER BY score DESC, shared_renters DESC) AS neighbor_rank
+			FROM ranked
+		) neighbors
+		WHERE neighbor_rank <= $1`,
+		neighbors,
+		computedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute movie similarities: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit similarities refresh: %w", err)
+	}
+
+	return &models.RefreshResultDTO{
+		Pairs:      tag.RowsAffected(),
+		ComputedAt: computedAt,
+	}, nil
+}
+
+/*
+GetSimilarMovies is a method of recommendationRepository struct that retrieves the movies most often rented together with a movie.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- movieId (uuid.UUID): The ID of the movie.
+- limit (int): The maximum number of movies returned.
+
+Returns:
+- ([]*models.RecommendationDTO, error): A slice of RecommendationDTO structs, most similar first, or an error if the retrieval fails.
+*/
+func (r *recommendationRepository) GetSimilarMovies(ctx context.Context, movieId uuid.UUID, limit int) ([]*models.RecommendationDTO, error) {
+	query := `
+		SELECT m.id, m.name, m.director, m.year, s.shared_renters, s.score::FLOAT8
+		FROM movie_similarities s
+		JOIN movies m ON m.id = s.similar_movie_id
+		WHERE s.movie_id = $1
+		ORDER BY s.score DESC, s.shared_renters DESC, m.name
+		LIMIT $2`
+
+	rows, err := r.DB.Query(ctx, query, movieId, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get similar movies: %w", err)
+	}
+
+	return scanRecommendations(rows)
+}
+
+/*
+GetUserRecommendations is a method of recommendationRepository struct that retrieves the movies a user is likely to rent next.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- userId (uuid.UUID): The ID of the user.
+- limit (int): The maximum number of movies returned.
+
+Returns:
+- ([]*models.RecommendationDTO, error): A slice of RecommendationDTO structs, best match first, or an error if the retrieval fails.
+
+Behavior:
+- Adds up the similarities of every movie the user rented, so titles related to several of them rank higher.
+- Never recommends a movie the user already rented, counting archived loans through loan_history.
+*/
+func (r *recommendationRepository) GetUserRecommendations(ctx context.Context, userId uuid.UUID, limit int) ([]*models.RecommendationDTO, error) {
+	query := `
+		WITH rented AS (
+			SELECT DISTINCT movie_id FROM loan_history WHERE user_id = $1
+		)
+		SELECT m.id, m.name, m.director, m.year, SUM(s.shared_renters)::BIGINT, SUM(s.score)::FLOAT8 AS total_score
+		FROM movie_similarities s
+		JOIN rented ON rented.movie_id = s.movie_id
+		JOIN movies m ON m.id = s.similar_movie_id
+		WHERE s.similar_movie_id NOT IN (SELECT movie_id FROM rented)
+		GROUP BY m.id, m.name, m.director, m.year
+		ORDER BY total_score DESC, m.name
+		LIMIT $2`
+
+	rows, err := r.DB.Query(ctx, query, userId, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user recommendations: %w", err)
+	}
+
+	return scanRecommendations(rows)
+}
+
+func scanRecommendations(rows pgx.Rows) ([]*models.RecommendationDTO, error) {
+	defer rows.Close()
+
+	recommendations := []*models.RecommendationDTO{}
+	for rows.Next() {
+		var recommendation models.RecommendationDTO
+		err := rows.Scan(
+			&recommendation.MovieID,
+			&recommendation.Name,
+			&recommendation.Director,
+			&recommendation.Year,
+			&recommendation.SharedRenters,
+			&recommendation.Score,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan recommendation: %w", err)
+		}
+		recommendations = append(recommendations, &recommendation)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over recommendations: %w", err)
+	}
+
+	return recommendations, nil
+}
@@ -0,0 +1,35 @@
+package recommendations
+
+import (
+	"blockbustermvc/internal/database/dbtest"
+	"testing"
+)
+
+func TestRecommendationsCountArchivedLoans(t *testing.T) {
+	pool := dbtest.Open(t)
+	ctx := t.Context()
+	repo := NewRecommendationRepository(pool)
+
+	storeId := dbtest.Store(t, pool)
+	first := dbtest.Movie(t, pool)
+	second := dbtest.Movie(t, pool)
+	oldCustomer := dbtest.User(t, pool)
+	customer := dbtest.User(t, pool)
+
+	// Only archived loans tie the two movies together
+	dbtest.ArchivedLoan(t, pool, storeId, first, oldCustomer)
+	dbtest.ArchivedLoan(t, pool, storeId, second, oldCustomer)
+	dbtest.ArchivedLoan(t, pool, storeId, first, customer)
+
+	if _, err := repo.RefreshSimilarities(ctx, 10); err != nil {
+		t.Fatalf("RefreshSimilarities() error = %v", err)
+	}
+
+	recommendations, err := repo.GetUserRecommendations(ctx, customer, 10)
+	if err != nil {
+		t.Fatalf("GetUserRecommendations() error = %v", err)
+	}
+	if len(recommendations) != 1 || recommendations[0].MovieID != second {
+		t.Errorf("GetUserRecommendations() = %+v, want only movie %s", recommendations, second)
+	}
+}
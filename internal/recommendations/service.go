@@ -0,0 +1,59 @@
+package recommendations
+
+import (
+	movieService "blockbustermvc/internal/models/movie"
+	models "blockbustermvc/internal/models/recommendation"
+	userService "blockbustermvc/internal/models/user"
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type RecommendationService struct {
+	recommendationRepository models.IRecommendationRepository
+	movieService             movieService.IMovieService
+	userService              userService.IUserService
+}
+
+func NewRecommendationService(
+	recommendationRepo models.IRecommendationRepository,
+	movieService movieService.IMovieService,
+	userService userService.IUserService,
+) models.IRecommendationService {
+	return &RecommendationService{
+		recommendationRepository: recommendationRepo,
+		movieService:             movieService,
+		userService:              userService,
+	}
+}
+
+func (r RecommendationService) RefreshSimilarities(ctx context.Context) (*models.RefreshResultDTO, error) {
+	return r.recommendationRepository.RefreshSimilarities(ctx, models.NeighborsPerMovie)
+}
+
+func (r RecommendationService) GetSimilarMovies(ctx context.Context, movieId uuid.UUID, limit int) ([]*models.RecommendationDTO, error) {
+	if _, err := r.movieService.GetMovie(ctx, movieId); err != nil {
+		return nil, err
+	}
+
+	return r.recommendationRepository.GetSimilarMovies(ctx, movieId, clampLimit(limit))
+}
+
+func (r RecommendationService) GetUserRecommendations(ctx context.Context, userId uuid.UUID, limit int) ([]*models.RecommendationDTO, error) {
+	if _, err := r.userService.GetUser(ctx, userId); err != nil {
+		return nil, err
+	}
+
+	return r.recommendationRepository.GetUserRecommendations(ctx, userId, clampLimit(limit))
+}
+
+func clampLimit(limit int) int {
+	switch {
+	case limit <= 0:
+		return models.DefaultLimit
+	case limit > models.MaxLimit:
+		return models.MaxLimit
+	}
+
+	return limit
+}
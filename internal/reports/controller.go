@@ -0,0 +1,200 @@
+package reports
+
+import (
+	"blockbustermvc/internal/httputil"
+	models "blockbustermvc/internal/models/report"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReportsController struct {
+	reportService models.IReportService
+}
+
+func NewReportsController(reportService models.IReportService) *ReportsController {
+	return &ReportsController{
+		reportService: reportService,
+	}
+}
+
+func (rc *ReportsController) RegisterRoutes(r *gin.RouterGroup) {
+	reports := r.Group("/reports")
+
+	{
+		reports.GET("/activity", rc.GetActivity)
+		reports.GET("/utilization", rc.GetUtilization)
+		reports.GET("/customers", rc.GetCustomers)
+		reports.GET("/freshness", rc.GetFreshness)
+		reports.POST("/refresh", rc.RefreshViews)
+	}
+}
+
+func (rc *ReportsController) GetActivity(ctx *gin.Context) {
+	format, filter, ok := parseReportRequest(ctx)
+	if !ok {
+		return
+	}
+
+	report, err := rc.reportService.GetActivity(ctx.Request.Context(), filter)
+	if err != nil {
+		respondWithReportError(ctx, err)
+		return
+	}
+
+	if format == models.FormatCSV {
+		records := make([]record, len(report.Periods))
+		for i, period := range report.Periods {
+			records[i] = period
+		}
+		writeCSV(ctx, "activity", models.ActivityColumns, records)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, report)
+}
+
+func (rc *ReportsController) GetUtilization(ctx *gin.Context) {
+	format, filter, ok := parseReportRequest(ctx)
+	if !ok {
+		return
+	}
+
+	report, err := rc.reportService.GetUtilization(ctx.Request.Context(), filter)
+	if err != nil {
+		respondWithReportError(ctx, err)
+		return
+	}
+
+	if format == models.FormatCSV {
+		records := make([]record, len(report.Titles))
+		for i, title := range report.Titles {
+			records[i] = title
+		}
+		writeCSV(ctx, "utilization", models.UtilizationColumns, records)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, report)
+}
+
+func (rc *ReportsController) GetCustomers(ctx *gin.Context) {
+	format, filter, ok := parseReportRequest(ctx)
+	if !ok {
+		return
+	}
+
+	limit := 0
+	if value := ctx.Query("limit"); value != "" {
+		var err error
+		if limit, err = strconv.Atoi(value); err != nil || limit < 1 {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error": "limit must be a positive number",
+			})
+			return
+		}
+	}
+
+	report, err := rc.reportService.GetCustomers(ctx.Request.Context(), filter, limit)
+	if err != nil {
+		respondWithReportError(ctx, err)
+		return
+	}
+
+	if format == models.FormatCSV {
+		records := make([]record, len(report.Customers))
+		for i, customer := range report.Customers {
+			records[i] = customer
+		}
+		writeCSV(ctx, "customers", models.CustomerColumns, records)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, report)
+}
+
+func (rc *ReportsController) GetFreshness(ctx *gin.Context) {
+	views, err := rc.reportService.GetFreshness(ctx.Request.Context())
+	if err != nil {
+		respondWithReportError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, views)
+}
+
+func (rc *ReportsController) RefreshViews(ctx *gin.Context) {
+	views, err := rc.reportService.RefreshViews(ctx.Request.Context())
+	if err != nil {
+		respondWithReportError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, views)
+}
+
+// parseReportRequest reads the format, JSON by default, and the filter.
+func parseReportRequest(ctx *gin.Context) (string, *models.ReportFilterDTO, bool) {
+	format := ctx.DefaultQuery("format", models.FormatJSON)
+	if format != models.FormatJSON && format != models.FormatCSV {
+		ctx.JSON(http.StatusNotAcceptable, gin.H{
+			"error": models.ErrUnsupportedFormat.Error(),
+		})
+		return "", nil, false
+	}
+
+	filter, err := models.ParseFilter(ctx.Request.URL.Query(), httputil.StoreID(ctx))
+	if err != nil {
+		respondWithReportError(ctx, err)
+		return "", nil, false
+	}
+
+	return format, filter, true
+}
+
+type record interface {
+	Record() []string
+}
+
+func writeCSV(ctx *gin.Context, name string, columns []string, records []record) {
+	filename := fmt.Sprintf("%s-%s.csv", name, time.Now().Format("20060102"))
+	ctx.Header("Content-Type", "text/csv; charset=utf-8")
+	ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	ctx.Status(http.StatusOK)
+
+	writer := csv.NewWriter(ctx.Writer)
+	writer.Write(columns)
+	for _, record := range records {
+		writer.Write(record.Record())
+	}
+	writer.Flush()
+
+	if err := writer.Error(); err != nil {
+		ctx.Error(err)
+	}
+}
+
+func respondWithReportError(ctx *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, models.ErrInvalidInterval),
+		errors.Is(err, models.ErrInvalidDate),
+		errors.Is(err, models.ErrInvalidStore),
+		errors.Is(err, models.ErrInvalidRange),
+		errors.Is(err, models.ErrRangeTooLarge):
+		status = http.StatusBadRequest
+	}
+
+	if status == http.StatusInternalServerError {
+		slog.ErrorContext(ctx.Request.Context(), "Report request failed", "error", err)
+	}
+	ctx.JSON(status, gin.H{
+		"error": err.Error(),
+	})
+}
@@ -0,0 +1,48 @@
+package reports
+
+import (
+	"blockbustermvc/internal/config"
+	"blockbustermvc/internal/jobs"
+	jobModels "blockbustermvc/internal/models/job"
+	models "blockbustermvc/internal/models/report"
+	"context"
+	"log/slog"
+	"time"
+)
+
+// defaultRefreshInterval is used when BLK_REPORTS_REFRESH is unset.
+const defaultRefreshInterval = 15 * time.Minute
+
+// ConfiguredRefreshInterval reads how often the reporting views are
+// refreshed from BLK_REPORTS_REFRESH. Zero disables the periodic refresh.
+func ConfiguredRefreshInterval() (time.Duration, error) {
+	return config.Duration("BLK_REPORTS_REFRESH", defaultRefreshInterval, true)
+}
+
+// RefreshJob is the job kind that refreshes the reporting views.
+const RefreshJob = "reports.refresh"
+
+// RegisterJobs lets the job worker refresh the reporting views, and
+// schedules it every interval. Zero removes the schedule, leaving only
+// on-demand refreshes.
+func RegisterJobs(ctx context.Context, jobService jobModels.IJobService, service models.IReportService, interval time.Duration) error {
+	jobService.Register(RefreshJob, func(ctx context.Context, _ *jobModels.JobDTO) error {
+		views, err := service.RefreshViews(ctx)
+		if err != nil {
+			return err
+		}
+
+		var took int64
+		for _, view := range views {
+			took += view.DurationMs
+		}
+		slog.InfoContext(ctx, "Refreshed reporting views", "views", len(views), "duration_ms", took)
+		return nil
+	})
+
+	if interval == 0 {
+		return jobService.Unschedule(ctx, RefreshJob)
+	}
+
+	return jobService.Schedule(ctx, RefreshJob, RefreshJob, jobs.Every(interval))
+}
@@ -0,0 +1,407 @@
+package reports
+
+import (
+	models "blockbustermvc/internal/models/report"
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+/*
+reportRepository is a struct that represents a Postgres database for reading rental reports.
+
+Fields:
+- DB (*pgxpool.Pool): A pointer to a Postgres database connection pool.
+
+Behavior:
+- Reads the reporting materialized views rather than the loans table, so reports do not compete with the counters.
+- Joins movies for the genre and director filters and store_stock for the copies.
+- Days are the views' days, which follow the database TimeZone setting.
+*/
+type reportRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewReportRepository(db *pgxpool.Pool) models.IReportRepository {
+	return &reportRepository{
+		DB: db,
+	}
+}
+
+/*
+GetActivity is a method of reportRepository struct that computes the rentals, returns, revenue and utilization of each period.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- filter (*models.ReportFilterDTO): The store, genre and director the loans are limited to.
+- periodStarts ([]time.Time): The start of each period, in order.
+- periodEnds ([]time.Time): The exclusive end of each period, in the same order.
+- lateAfter (time.Duration): Loans returned this long or longer after being borrowed are late, to the hour.
+- asOf (time.Time): When the views were refreshed; the part of a period after it has no data yet.
+
+Returns:
+- ([]*models.ActivityDTO, error): One ActivityDTO struct per period, in order, or an error if the query fails.
+
+Behavior:
+- Rentals, revenue and rented days count the loans borrowed at the store; returns count the loans returned to it.
+- Utilization divides the rented days by the current copies in stock times the part of the period up to asOf.
+*/
+func (r *reportRepository) GetActivity(ctx context.Context, filter *models.ReportFilterDTO, periodStarts, periodEnds []time.Time, lateAfter time.Duration, asOf time.Time) ([]*models.ActivityDTO, error) {
+	query := `
+		WITH periods AS (
+			SELECT period_start, period_end
+			FROM unnest($1::TIMESTAMPTZ[], $2::TIMESTAMPTZ[]) AS p(period_start, period_end)
+		),
+		titles AS (
+			SELECT id
+			FROM movies
+			WHERE ($4 = '' OR lower(genre) = lower($4))
+				AND ($5 = '' OR lower(director) = lower($5))
+		),
+		events AS (
+			SELECT p.period_start,
+				COALESCE(SUM(f.rentals), 0)::BIGINT AS rentals,
+				COALESCE(SUM(f.returns), 0)::BIGINT AS returns,
+				COALESCE(SUM(f.returns) FILTER (WHERE f.loan_hours >= $6), 0)::BIGINT AS late_returns,
+				COALESCE(SUM(f.revenue_cents), 0)::BIGINT AS revenue_cents
+			FROM periods p
+			LEFT JOIN report_daily_loans f
+				ON f.day >= p.period_start::DATE AND f.day < p.period_end::DATE
+				AND ($3::UUID IS NULL OR f.store_id = $3::UUID)
+				AND f.movie_id IN (SELECT id FROM titles)
+			GROUP BY p.period_start
+		),
+		usage AS (
+			SELECT p.period_start, COALESCE(SUM(u.rented_seconds), 0) / 86400 AS rented_days
+			FROM periods p
+			LEFT JOIN report_movie_utilization u
+				ON u.day >= p.period_start::DATE AND u.day < p.period_end::DATE
+				AND ($3::UUID IS NULL OR u.store_id = $3::UUID)
+				AND u.movie_id IN (SELECT id FROM titles)
+			GROUP BY p.period_start
+		),
+		copies AS (
+			SELECT COALESCE(SUM(quantity), 0)::FLOAT8 AS copies
+			FROM store_stock
+			WHERE ($3::UUID IS NULL OR store_id = $3::UUID)
+				AND movie_id IN (SELECT id FROM titles)
+		)
+		SELECT p.period_start, e.rentals, e.returns, e.late_returns, e.revenue_cents, u.rented_days,
+			COALESCE(u.rented_days / NULLIF(
+				c.copies * GREATEST(EXTRACT(EPOCH FROM LEAST(p.period_end, $7) - p.period_start)::FLOAT8, 0) / 86400, 0
+			), 0)
+		FROM periods p
+		JOIN events e ON e.period_start = p.period_start
+		JOIN usage u ON u.period_start = p.period_start
+		CROSS JOIN copies c
+		ORDER BY p.period_start`
+
+	rows, err := r.DB.Query(ctx, query,
+		periodStarts,
+		periodEnds,
+		filter.StoreID,
+		filter.Genre,
+		filter.Director,
+		int(math.Ceil(lateAfter.Hours())),
+		asOf,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get activity report: %w", err)
+	}
+	defer rows.Close()
+
+	periods := []*models.ActivityDTO{}
+	for rows.Next() {
+		var period models.ActivityDTO
+		err := rows.Scan(
+			&period.PeriodStart,
+			&period.Rentals,
+			&period.Returns,
+			&period.LateReturns,
+			&period.RevenueCents,
+			&period.RentedDays,
+			&period.Utilization,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan activity period: %w", err)
+		}
+		periods = append(periods, &period)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate activity periods: %w", err)
+	}
+
+	return periods, nil
+}
+
+/*
+GetUtilization is a method of reportRepository struct that computes how much each title was rented over a range.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- filter (*models.ReportFilterDTO): The range, store, genre and director the report covers.
+- asOf (time.Time): When the views were refreshed; the part of the range after it has no data yet.
+
+Returns:
+- ([]*models.UtilizationDTO, error): A slice of UtilizationDTO structs, most utilized first, or an error if the query fails.
+
+Behavior:
+- Lists the titles in stock at the store, and those rented there during the range even if no longer stocked.
+- Loans and revenue count the loans borrowed during the range; rented days also count the part of earlier loans that falls within it.
+- Utilization divides the rented days by the current copies times the part of the range up to asOf.
+*/
+func (r *reportRepository) GetUtilization(ctx context.Context, filter *models.ReportFilterDTO, asOf time.Time) ([]*models.UtilizationDTO, error) {
+	query := `
+		WITH copies AS (
+			SELECT movie_id, SUM(quantity) AS copies
+			FROM store_stock
+			WHERE $1::UUID IS NULL OR store_id = $1::UUID
+			GROUP BY movie_id
+		),
+		usage AS (
+			SELECT movie_id, SUM(rented_seconds) / 86400 AS rented_days
+			FROM report_movie_utilization
+			WHERE ($1::UUID IS NULL OR store_id = $1::UUID)
+				AND day >= $2::TIMESTAMPTZ::DATE AND day < $3::TIMESTAMPTZ::DATE
+			GROUP BY movie_id
+		),
+		rentals AS (
+			SELECT movie_id, SUM(rentals)::BIGINT AS loans, SUM(revenue_cents)::BIGINT AS revenue_cents
+			FROM report_daily_loans
+			WHERE ($1::UUID IS NULL OR store_id = $1::UUID)
+				AND day >= $2::TIMESTAMPTZ::DATE AND day < $3::TIMESTAMPTZ::DATE
+				AND loan_hours = -1
+			GROUP BY movie_id
+		)
+		SELECT m.id, m.name, m.director, m.genre,
+			COALESCE(l.loans, 0),
+			COALESCE(u.rented_days, 0),
+			COALESCE(c.copies, 0),
+			COALESCE(u.rented_days / NULLIF(
+				c.copies * GREATEST(EXTRACT(EPOCH FROM LEAST($3::TIMESTAMPTZ, $4::TIMESTAMPTZ) - $2::TIMESTAMPTZ)::FLOAT8, 0) / 86400, 0
+			), 0) AS utilization,
+			COALESCE(l.revenue_cents, 0)
+		FROM movies m
+		LEFT JOIN copies c ON c.movie_id = m.id
+		LEFT JOIN usage u ON u.movie_id = m.id
+		LEFT JOIN rentals l ON l.movie_id = m.id
+		WHERE (c.copies > 0 OR u.movie_id IS NOT NULL OR l.movie_id IS NOT NULL)
+			AND ($5 = '' OR lower(m.genre) = lower($5))
+			AND ($6 = '' OR lower(m.director) = lower($6))
+		ORDER BY utilization DESC, 5 DESC, m.name`
+
+	rows, err := r.DB.Query(ctx, query,
+		filter.StoreID,
+		filter.From,
+		filter.To,
+		asOf,
+		filter.Genre,
+		filter.Director,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get utilization report: %w", err)
+	}
+	defer rows.Close()
+
+	titles := []*models.UtilizationDTO{}
+	for rows.Next() {
+		var title models.UtilizationDTO
+		err := rows.Scan(
+			&title.MovieID,
+			&title.Name,
+			&title.Director,
+			&title.Genre,
+			&title.Loans,
+			&title.RentedDays,
+			&title.Copies,
+			&title.Utilization,
+			&title.RevenueCents,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan title utilization: %w", err)
+		}
+		titles = append(titles, &title)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate title utilization: %w", err)
+	}
+
+	return titles, nil
+}
+
+/*
+GetCustomers is a method of reportRepository struct that retrieves the customers who borrowed the most.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- filter (*models.ReportFilterDTO): The store the loans are limited to; the range, genre and director do not apply.
+- limit (int): The maximum number of customers returned.
+
+Returns:
+- ([]*models.CustomerActivityDTO, error): A slice of CustomerActivityDTO structs, most loans first, or an error if the retrieval fails.
+*/
+func (r *reportRepository) GetCustomers(ctx context.Context, filter *models.ReportFilterDTO, limit int) ([]*models.CustomerActivityDTO, error) {
+	query := `
+		SELECT u.id, u.user_name, u.email,
+			SUM(a.loans)::BIGINT AS loans,
+			SUM(a.active_loans)::BIGINT,
+			SUM(a.returns)::BIGINT,
+			SUM(a.revenue_cents)::BIGINT AS revenue_cents,
+			COALESCE(SUM(a.returned_seconds) / NULLIF(SUM(a.returns), 0) / 86400, 0)::FLOAT8,
+			MIN(a.first_borrowed_at),
+			MAX(a.last_borrowed_at)
+		FROM report_customer_activity a
+		JOIN users u ON u.id = a.user_id
+		WHERE $1::UUID IS NULL OR a.store_id = $1::UUID
+		GROUP BY u.id, u.user_name, u.email
+		ORDER BY loans DESC, revenue_cents DESC, u.user_name
+		LIMIT $2`
+
+	rows, err := r.DB.Query(ctx, query, filter.StoreID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get customer report: %w", err)
+	}
+	defer rows.Close()
+
+	customers := []*models.CustomerActivityDTO{}
+	for rows.Next() {
+		var customer models.CustomerActivityDTO
+		err := rows.Scan(
+			&customer.UserID,
+			&customer.UserName,
+			&customer.Email,
+			&customer.Loans,
+			&customer.ActiveLoans,
+			&customer.Returns,
+			&customer.RevenueCents,
+			&customer.AverageLoanDays,
+			&customer.FirstBorrowedAt,
+			&customer.LastBorrowedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan customer activity: %w", err)
+		}
+		customers = append(customers, &customer)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate customer activity: %w", err)
+	}
+
+	return customers, nil
+}
+
+/*
+GetGenres is a method of reportRepository struct that retrieves the genres used in the catalog.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+
+Returns:
+- ([]string, error): The distinct non-empty genres in alphabetical order, or an error if the retrieval fails.
+*/
+func (r *reportRepository) GetGenres(ctx context.Context) ([]string, error) {
+	rows, err := r.DB.Query(ctx, `SELECT DISTINCT genre FROM movies WHERE genre <> '' ORDER BY genre`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get genres: %w", err)
+	}
+	defer rows.Close()
+
+	genres := []string{}
+	for rows.Next() {
+		var genre string
+		if err := rows.Scan(&genre); err != nil {
+			return nil, fmt.Errorf("failed to scan genre: %w", err)
+		}
+		genres = append(genres, genre)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate genres: %w", err)
+	}
+
+	return genres, nil
+}
+
+/*
+GetFreshness is a method of reportRepository struct that retrieves when each reporting view was last refreshed.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+
+Returns:
+- ([]*models.FreshnessDTO, error): One FreshnessDTO struct per view, by name, without the age, or an error if the retrieval fails.
+*/
+func (r *reportRepository) GetFreshness(ctx context.Context) ([]*models.FreshnessDTO, error) {
+	rows, err := r.DB.Query(ctx, `
+		SELECT view_name, refreshed_at, duration_ms
+		FROM report_refreshes
+		ORDER BY view_name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get report freshness: %w", err)
+	}
+	defer rows.Close()
+
+	views := []*models.FreshnessDTO{}
+	for rows.Next() {
+		var view models.FreshnessDTO
+		if err := rows.Scan(&view.View, &view.RefreshedAt, &view.DurationMs); err != nil {
+			return nil, fmt.Errorf("failed to scan report freshness: %w", err)
+		}
+		views = append(views, &view)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate report freshness: %w", err)
+	}
+
+	return views, nil
+}
+
+/*
+RefreshView is a method of reportRepository struct that recomputes a reporting view.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- view (string): The name of the materialized view, one of models.Views.
+
+Returns:
+- (*models.FreshnessDTO, error): A pointer to a FreshnessDTO struct with the refresh time and duration, or an error if the refresh fails.
+
+Behavior:
+- Refreshes concurrently, so reports keep reading the previous contents meanwhile.
+- Records the time the refresh started, since the view holds the loans as of then.
+*/
+func (r *reportRepository) RefreshView(ctx context.Context, view string) (*models.FreshnessDTO, error) {
+	startedAt := time.Now()
+	if _, err := r.DB.Exec(ctx, "REFRESH MATERIALIZED VIEW CONCURRENTLY "+pgx.Identifier{view}.Sanitize()); err != nil {
+		return nil, fmt.Errorf("failed to refresh %s: %w", view, err)
+	}
+
+	freshness := &models.FreshnessDTO{
+		View:        view,
+		RefreshedAt: startedAt,
+		DurationMs:  time.Since(startedAt).Milliseconds(),
+	}
+
+	_, err := r.DB.Exec(ctx, `
+		INSERT INTO report_refreshes (view_name, refreshed_at, duration_ms)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (view_name) DO UPDATE
+		SET refreshed_at = EXCLUDED.refreshed_at, duration_ms = EXCLUDED.duration_ms`,
+		freshness.View,
+		freshness.RefreshedAt,
+		freshness.DurationMs,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record refresh of %s: %w", view, err)
+	}
+
+	return freshness, nil
+}
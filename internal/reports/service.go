@@ -0,0 +1,205 @@
+package reports
+
+import (
+	models "blockbustermvc/internal/models/report"
+	"context"
+	"slices"
+	"time"
+)
+
+type ReportService struct {
+	reportRepository models.IReportRepository
+	loanPeriod       time.Duration
+}
+
+// NewReportService needs the loan period to tell which returns were late,
+// the same one the reminders use.
+func NewReportService(reportRepo models.IReportRepository, loanPeriod time.Duration) models.IReportService {
+	return &ReportService{
+		reportRepository: reportRepo,
+		loanPeriod:       loanPeriod,
+	}
+}
+
+func (r ReportService) GetActivity(ctx context.Context, filter *models.ReportFilterDTO) (*models.ActivityReportDTO, error) {
+	now := time.Now()
+	if err := normalizeFilter(filter, now); err != nil {
+		return nil, err
+	}
+
+	var periodStarts, periodEnds []time.Time
+	for start := filter.From; start.Before(filter.To); start = models.NextPeriod(filter.Interval, start) {
+		if len(periodStarts) == models.MaxPeriods {
+			return nil, models.ErrRangeTooLarge
+		}
+		periodStarts = append(periodStarts, start)
+		periodEnds = append(periodEnds, models.NextPeriod(filter.Interval, start))
+	}
+
+	asOf, err := r.asOf(ctx, models.ViewDailyLoans, models.ViewMovieUtilization)
+	if err != nil {
+		return nil, err
+	}
+
+	periods, err := r.reportRepository.GetActivity(ctx, filter, periodStarts, periodEnds, r.loanPeriod, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	// Copies are the same in every period, so weighting each period's
+	// utilization by its elapsed length gives the utilization of the range
+	totals := &models.ActivityDTO{PeriodStart: filter.From}
+	var elapsedDays float64
+	for i, period := range periods {
+		totals.Rentals += period.Rentals
+		totals.Returns += period.Returns
+		totals.LateReturns += period.LateReturns
+		totals.RevenueCents += period.RevenueCents
+		totals.RentedDays += period.RentedDays
+
+		end := periodEnds[i]
+		if asOf.Before(end) {
+			end = asOf
+		}
+		days := max(end.Sub(periodStarts[i]).Hours()/24, 0)
+		totals.Utilization += period.Utilization * days
+		elapsedDays += days
+	}
+	if elapsedDays > 0 {
+		totals.Utilization /= elapsedDays
+	}
+
+	return &models.ActivityReportDTO{
+		Filter:  filter,
+		AsOf:    asOf,
+		Periods: periods,
+		Totals:  totals,
+	}, nil
+}
+
+func (r ReportService) GetUtilization(ctx context.Context, filter *models.ReportFilterDTO) (*models.UtilizationReportDTO, error) {
+	now := time.Now()
+	if err := normalizeFilter(filter, now); err != nil {
+		return nil, err
+	}
+
+	asOf, err := r.asOf(ctx, models.ViewDailyLoans, models.ViewMovieUtilization)
+	if err != nil {
+		return nil, err
+	}
+
+	titles, err := r.reportRepository.GetUtilization(ctx, filter, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.UtilizationReportDTO{
+		Filter: filter,
+		AsOf:   asOf,
+		Titles: titles,
+	}, nil
+}
+
+func (r ReportService) GetCustomers(ctx context.Context, filter *models.ReportFilterDTO, limit int) (*models.CustomerReportDTO, error) {
+	switch {
+	case limit <= 0:
+		limit = models.DefaultCustomerLimit
+	case limit > models.MaxCustomerLimit:
+		limit = models.MaxCustomerLimit
+	}
+
+	asOf, err := r.asOf(ctx, models.ViewCustomerActivity)
+	if err != nil {
+		return nil, err
+	}
+
+	customers, err := r.reportRepository.GetCustomers(ctx, filter, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.CustomerReportDTO{
+		Filter:    filter,
+		AsOf:      asOf,
+		Customers: customers,
+	}, nil
+}
+
+func (r ReportService) GetGenres(ctx context.Context) ([]string, error) {
+	return r.reportRepository.GetGenres(ctx)
+}
+
+func (r ReportService) GetFreshness(ctx context.Context) ([]*models.FreshnessDTO, error) {
+	views, err := r.reportRepository.GetFreshness(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for _, view := range views {
+		view.AgeSeconds = now.Sub(view.RefreshedAt).Seconds()
+	}
+
+	return views, nil
+}
+
+// RefreshViews refreshes every reporting view in turn. A failure stops the
+// refresh, leaving the remaining views as they were.
+func (r ReportService) RefreshViews(ctx context.Context) ([]*models.FreshnessDTO, error) {
+	for _, view := range models.Views {
+		if _, err := r.reportRepository.RefreshView(ctx, view); err != nil {
+			return nil, err
+		}
+	}
+
+	return r.GetFreshness(ctx)
+}
+
+// asOf returns when the oldest of the views was refreshed, which is how
+// current a report reading them is.
+func (r ReportService) asOf(ctx context.Context, views ...string) (time.Time, error) {
+	freshness, err := r.reportRepository.GetFreshness(ctx)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	var asOf time.Time
+	for _, view := range freshness {
+		if slices.Contains(views, view.View) && (asOf.IsZero() || view.RefreshedAt.Before(asOf)) {
+			asOf = view.RefreshedAt
+		}
+	}
+
+	return asOf, nil
+}
+
+// normalizeFilter fills in the defaults, monthly periods up to now, and
+// widens the range to whole periods.
+func normalizeFilter(filter *models.ReportFilterDTO, now time.Time) error {
+	if filter.Interval == "" {
+		filter.Interval = models.IntervalMonth
+	}
+	if !slices.Contains(models.Intervals, filter.Interval) {
+		return models.ErrInvalidInterval
+	}
+
+	if filter.To.IsZero() {
+		filter.To = now
+	}
+	if filter.From.IsZero() {
+		filter.From = models.PeriodStart(filter.Interval, filter.To)
+		for range models.DefaultPeriods(filter.Interval) - 1 {
+			filter.From = models.PeriodStart(filter.Interval, filter.From.Add(-time.Nanosecond))
+		}
+	}
+	if !filter.From.Before(filter.To) {
+		return models.ErrInvalidRange
+	}
+
+	filter.From = models.PeriodStart(filter.Interval, filter.From)
+	if end := models.PeriodStart(filter.Interval, filter.To); end.Before(filter.To) {
+		filter.To = models.NextPeriod(filter.Interval, end)
+	}
+
+	return nil
+}
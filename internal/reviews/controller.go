@@ -0,0 +1,203 @@
+package reviews
+
+import (
+	models "blockbustermvc/internal/models/review"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ReviewsController struct {
+	reviewService models.IReviewService
+}
+
+func NewReviewsController(reviewService models.IReviewService) *ReviewsController {
+	return &ReviewsController{
+		reviewService: reviewService,
+	}
+}
+
+func (rc *ReviewsController) RegisterRoutes(r *gin.RouterGroup) {
+	r.POST("/movies/:id/reviews", rc.CreateReview)
+	r.GET("/movies/:id/reviews", rc.GetMovieReviews)
+
+	reviews := r.Group("/reviews")
+
+	{
+		reviews.GET("", rc.GetReviews)
+		reviews.GET("/:id", rc.GetReview)
+		reviews.POST("/:id/approve", rc.ApproveReview)
+		reviews.POST("/:id/reject", rc.RejectReview)
+		reviews.DELETE("/:id", rc.DeleteReview)
+	}
+}
+
+func (rc *ReviewsController) CreateReview(ctx *gin.Context) {
+	movieId, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid movie ID",
+		})
+		return
+	}
+
+	var req models.CreateReviewDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	req.MovieID = movieId
+
+	review, err := rc.reviewService.CreateReview(ctx.Request.Context(), &req)
+	if err != nil {
+		respondWithReviewError(ctx, err)
+		return
+	}
+
+	ctx.Header("Location", "/api/reviews/"+review.ID.String())
+	ctx.JSON(http.StatusCreated, review)
+}
+
+// GetMovieReviews lists the approved reviews of a movie unless another
+// status is asked for; status=all returns every review.
+func (rc *ReviewsController) GetMovieReviews(ctx *gin.Context) {
+	movieId, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid movie ID",
+		})
+		return
+	}
+
+	reviews, err := rc.reviewService.GetMovieReviews(ctx.Request.Context(), movieId, statusFilter(ctx, models.StatusApproved))
+	if err != nil {
+		slog.ErrorContext(ctx.Request.Context(), "Failed to get movie reviews", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, reviews)
+}
+
+// GetReviews is the moderation queue: pending reviews unless another
+// status is asked for.
+func (rc *ReviewsController) GetReviews(ctx *gin.Context) {
+	reviews, err := rc.reviewService.GetReviews(ctx.Request.Context(), statusFilter(ctx, models.StatusPending))
+	if err != nil {
+		slog.ErrorContext(ctx.Request.Context(), "Failed to get reviews", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, reviews)
+}
+
+func (rc *ReviewsController) GetReview(ctx *gin.Context) {
+	id, ok := parseReviewID(ctx)
+	if !ok {
+		return
+	}
+
+	review, err := rc.reviewService.GetReview(ctx.Request.Context(), id)
+	if err != nil {
+		respondWithReviewError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, review)
+}
+
+func (rc *ReviewsController) ApproveReview(ctx *gin.Context) {
+	rc.moderateReview(ctx, rc.reviewService.ApproveReview)
+}
+
+func (rc *ReviewsController) RejectReview(ctx *gin.Context) {
+	rc.moderateReview(ctx, rc.reviewService.RejectReview)
+}
+
+func (rc *ReviewsController) moderateReview(ctx *gin.Context, decide func(context.Context, uuid.UUID, *models.ModerateReviewDTO) (*models.ReviewDTO, error)) {
+	id, ok := parseReviewID(ctx)
+	if !ok {
+		return
+	}
+
+	// The note is optional, so an empty body is accepted.
+	var req models.ModerateReviewDTO
+	if ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+	}
+
+	review, err := decide(ctx.Request.Context(), id, &req)
+	if err != nil {
+		respondWithReviewError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, review)
+}
+
+func (rc *ReviewsController) DeleteReview(ctx *gin.Context) {
+	id, ok := parseReviewID(ctx)
+	if !ok {
+		return
+	}
+
+	if err := rc.reviewService.DeleteReview(ctx.Request.Context(), id); err != nil {
+		respondWithReviewError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func statusFilter(ctx *gin.Context, fallback string) string {
+	status := ctx.DefaultQuery("status", fallback)
+	if status == "all" {
+		return ""
+	}
+
+	return status
+}
+
+func parseReviewID(ctx *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid review ID",
+		})
+		return uuid.Nil, false
+	}
+
+	return id, true
+}
+
+func respondWithReviewError(ctx *gin.Context, err error) {
+	status := http.StatusUnprocessableEntity
+	switch {
+	case errors.Is(err, models.ErrReviewNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, models.ErrAlreadyReviewed):
+		status = http.StatusConflict
+	case errors.Is(err, models.ErrNotRented):
+		status = http.StatusForbidden
+	}
+
+	ctx.JSON(status, gin.H{
+		"error": err.Error(),
+	})
+}
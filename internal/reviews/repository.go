@@ -0,0 +1,316 @@
+package reviews
+
+import (
+	models "blockbustermvc/internal/models/review"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const selectReview = `
+	SELECT r.id, r.movie_id, m.name, r.user_id, u.user_name, r.rating, r.body, r.status,
+		r.moderation_note, r.moderated_at, r.created_at, r.updated_at
+	FROM reviews r
+	JOIN movies m ON m.id = r.movie_id
+	JOIN users u ON u.id = r.user_id`
+
+/*
+reviewRepository is a struct that represents a Postgres database for storing customer reviews.
+
+Fields:
+- DB (*pgxpool.Pool): A pointer to a Postgres database connection pool.
+
+Behavior:
+- Provides methods for interacting with the reviews table in the database.
+- Keeps the rating aggregate on the movies table in step with the approved reviews.
+*/
+type reviewRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewReviewRepository(db *pgxpool.Pool) models.IReviewRepository {
+	return &reviewRepository{
+		DB: db,
+	}
+}
+
+/*
+CreateReview is a method of reviewRepository struct that stores a new review in the postgres database.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- review (*models.CreateReviewDTO): A pointer to a CreateReviewDTO struct containing the movie, user, rating and text.
+
+Returns:
+- (*models.ReviewDTO, error): A pointer to a ReviewDTO struct containing the persisted review, or an error if the creation fails.
+
+Behavior:
+- Inserts the review as pending, so it does not count towards the movie rating until it is approved.
+*/
+func (r *reviewRepository) CreateReview(ctx context.Context, review *models.CreateReviewDTO) (*models.ReviewDTO, error) {
+	query := `
+		INSERT INTO reviews (movie_id, user_id, rating, body, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $6)
+		RETURNING id`
+
+	var id uuid.UUID
+	err := r.DB.QueryRow(ctx, query,
+		review.MovieID,
+		review.UserID,
+		review.Rating,
+		review.Body,
+		models.StatusPending,
+		time.Now(),
+	).Scan(&id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create review: %w", err)
+	}
+
+	return r.GetReview(ctx, id)
+}
+
+/*
+GetReview is a method of reviewRepository struct that retrieves a review from the postgres database by its ID.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- id (uuid.UUID): The ID of the review.
+
+Returns:
+- (*models.ReviewDTO, error): A pointer to a ReviewDTO struct, or an error if the retrieval fails.
+
+Behavior:
+- Returns models.ErrReviewNotFound if there is no review with that ID.
+*/
+func (r *reviewRepository) GetReview(ctx context.Context, id uuid.UUID) (*models.ReviewDTO, error) {
+	return scanReview(r.DB.QueryRow(ctx, selectReview+` WHERE r.id = $1`, id))
+}
+
+/*
+GetUserMovieReview is a method of reviewRepository struct that retrieves the review a user wrote about a movie from the postgres database.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- userId (uuid.UUID): The ID of the user.
+- movieId (uuid.UUID): The ID of the movie.
+
+Returns:
+- (*models.ReviewDTO, error): A pointer to a ReviewDTO struct, or an error if the retrieval fails.
+
+Behavior:
+- Returns models.ErrReviewNotFound if the user has not reviewed the movie.
+*/
+func (r *reviewRepository) GetUserMovieReview(ctx context.Context, userId, movieId uuid.UUID) (*models.ReviewDTO, error) {
+	return scanReview(r.DB.QueryRow(ctx, selectReview+` WHERE r.user_id = $1 AND r.movie_id = $2`, userId, movieId))
+}
+
+/*
+HasRented is a method of reviewRepository struct that reports whether a user has ever rented a movie.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- userId (uuid.UUID): The ID of the user.
+- movieId (uuid.UUID): The ID of the movie.
+
+Returns:
+- (bool, error): Whether the user has rented the movie, or an error if the check fails.
+
+Behavior:
+- Reads loan_history, so loans moved to the archive still count.
+- Stops at the first matching loan instead of loading the user's loans.
+*/
+func (r *reviewRepository) HasRented(ctx context.Context, userId, movieId uuid.UUID) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM loan_history WHERE user_id = $1 AND movie_id = $2)`
+
+	var rented bool
+	if err := r.DB.QueryRow(ctx, query, userId, movieId).Scan(&rented); err != nil {
+		return false, fmt.Errorf("failed to check rental history: %w", err)
+	}
+
+	return rented, nil
+}
+
+/*
+GetReviews is a method of reviewRepository struct that retrieves reviews from the postgres database.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- movieId (uuid.UUID): Only reviews of this movie are returned; uuid.Nil returns the reviews of every movie.
+- status (string): Only reviews with this status are returned; an empty status returns all of them.
+
+Returns:
+- ([]*models.ReviewDTO, error): A slice of ReviewDTO structs, newest first, or an error if the retrieval fails.
+*/
+func (r *reviewRepository) GetReviews(ctx context.Context, movieId uuid.UUID, status string) ([]*models.ReviewDTO, error) {
+	query := selectReview + `
+		WHERE ($1 = '00000000-0000-0000-0000-000000000000'::uuid OR r.movie_id = $1) AND ($2 = '' OR r.status = $2)
+		ORDER BY r.created_at DESC`
+
+	rows, err := r.DB.Query(ctx, query, movieId, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reviews: %w", err)
+	}
+	defer rows.Close()
+
+	var reviews []*models.ReviewDTO
+	for rows.Next() {
+		review, err := scanReview(rows)
+		if err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, review)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over reviews: %w", err)
+	}
+
+	return reviews, nil
+}
+
+/*
+SetReviewStatus is a method of reviewRepository struct that records a moderation decision in the postgres database.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- id (uuid.UUID): The ID of the review.
+- status (string): The new moderation status.
+- note (string): The moderator's note, shown with the decision.
+
+Returns:
+- (*models.ReviewDTO, error): A pointer to a ReviewDTO struct containing the moderated review, or an error if the update fails.
+
+Behavior:
+- Updates the review and recomputes the rating aggregate of its movie in one transaction.
+- Returns models.ErrReviewNotFound if there is no review with that ID.
+*/
+func (r *reviewRepository) SetReviewStatus(ctx context.Context, id uuid.UUID, status, note string) (*models.ReviewDTO, error) {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin review moderation: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	now := time.Now()
+	var movieId uuid.UUID
+	err = tx.QueryRow(ctx, `
+		UPDATE reviews
+		SET status = $2, moderation_note = $3, moderated_at = $4, updated_at = $4
+		WHERE id = $1
+		RETURNING movie_id`,
+		id,
+		status,
+		note,
+		now,
+	).Scan(&movieId)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, models.ErrReviewNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to moderate review: %w", err)
+	}
+
+	if err := refreshRating(ctx, tx, movieId); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit review moderation: %w", err)
+	}
+
+	return r.GetReview(ctx, id)
+}
+
+/*
+DeleteReview is a method of reviewRepository struct that removes a review from the postgres database.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- id (uuid.UUID): The ID of the review.
+
+Returns:
+- (error): An error if the deletion fails.
+
+Behavior:
+- Deletes the review and recomputes the rating aggregate of its movie in one transaction.
+- Returns models.ErrReviewNotFound if there is no review with that ID.
+*/
+func (r *reviewRepository) DeleteReview(ctx context.Context, id uuid.UUID) error {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin review deletion: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var movieId uuid.UUID
+	err = tx.QueryRow(ctx, `DELETE FROM reviews WHERE id = $1 RETURNING movie_id`, id).Scan(&movieId)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.ErrReviewNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete review: %w", err)
+	}
+
+	if err := refreshRating(ctx, tx, movieId); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit review deletion: %w", err)
+	}
+
+	return nil
+}
+
+// refreshRating recomputes a movie's rating aggregate from its approved
+// reviews. The movie version is left alone, as with stock.
+func refreshRating(ctx context.Context, tx pgx.Tx, movieId uuid.UUID) error {
+	_, err := tx.Exec(ctx, `
+		UPDATE movies
+		SET rating_average = COALESCE(agg.average, 0), rating_count = agg.count
+		FROM (
+			SELECT AVG(rating) AS average, COUNT(*) AS count
+			FROM reviews
+			WHERE movie_id = $1 AND status = $2
+		) agg
+		WHERE id = $1`,
+		movieId,
+		models.StatusApproved,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update movie rating: %w", err)
+	}
+
+	return nil
+}
+
+func scanReview(row pgx.Row) (*models.ReviewDTO, error) {
+	var review models.ReviewDTO
+	err := row.Scan(
+		&review.ID,
+		&review.MovieID,
+		&review.MovieName,
+		&review.UserID,
+		&review.UserName,
+		&review.Rating,
+		&review.Body,
+		&review.Status,
+		&review.ModerationNote,
+		&review.ModeratedAt,
+		&review.CreatedAt,
+		&review.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, models.ErrReviewNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan review: %w", err)
+	}
+
+	return &review, nil
+}
@@ -0,0 +1,41 @@
+package reviews
+
+import (
+	"blockbustermvc/internal/database/dbtest"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestHasRentedCountsArchivedLoans(t *testing.T) {
+	pool := dbtest.Open(t)
+	repo := NewReviewRepository(pool)
+
+	storeId := dbtest.Store(t, pool)
+	rentedMovie := dbtest.Movie(t, pool)
+	otherMovie := dbtest.Movie(t, pool)
+	userId := dbtest.User(t, pool)
+
+	dbtest.ArchivedLoan(t, pool, storeId, rentedMovie, userId)
+
+	tests := []struct {
+		name    string
+		movieId uuid.UUID
+		want    bool
+	}{
+		{name: "archived loan", movieId: rentedMovie, want: true},
+		{name: "never rented", movieId: otherMovie, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.HasRented(t.Context(), userId, tt.movieId)
+			if err != nil {
+				t.Fatalf("HasRented() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("HasRented() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package reviews
+
+import (
+	movieService "blockbustermvc/internal/models/movie"
+	models "blockbustermvc/internal/models/review"
+	userService "blockbustermvc/internal/models/user"
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type ReviewService struct {
+	reviewRepository models.IReviewRepository
+	movieService     movieService.IMovieService
+	userService      userService.IUserService
+}
+
+func NewReviewService(
+	reviewRepo models.IReviewRepository,
+	movieService movieService.IMovieService,
+	userService userService.IUserService,
+) models.IReviewService {
+	return &ReviewService{
+		reviewRepository: reviewRepo,
+		movieService:     movieService,
+		userService:      userService,
+	}
+}
+
+func (r ReviewService) CreateReview(ctx context.Context, review *models.CreateReviewDTO) (*models.ReviewDTO, error) {
+	if _, err := r.movieService.GetMovie(ctx, review.MovieID); err != nil {
+		return nil, err
+	}
+
+	if _, err := r.userService.GetUser(ctx, review.UserID); err != nil {
+		return nil, err
+	}
+
+	rented, err := r.reviewRepository.HasRented(ctx, review.UserID, review.MovieID)
+	if err != nil {
+		return nil, err
+	}
+	if !rented {
+		return nil, models.ErrNotRented
+	}
+
+	_, err = r.reviewRepository.GetUserMovieReview(ctx, review.UserID, review.MovieID)
+	if err == nil {
+		return nil, models.ErrAlreadyReviewed
+	}
+	if !errors.Is(err, models.ErrReviewNotFound) {
+		return nil, err
+	}
+
+	review.Body = strings.TrimSpace(review.Body)
+
+	return r.reviewRepository.CreateReview(ctx, review)
+}
+
+func (r ReviewService) GetReview(ctx context.Context, id uuid.UUID) (*models.ReviewDTO, error) {
+	return r.reviewRepository.GetReview(ctx, id)
+}
+
+func (r ReviewService) GetReviews(ctx context.Context, status string) ([]*models.ReviewDTO, error) {
+	return r.reviewRepository.GetReviews(ctx, uuid.Nil, status)
+}
+
+func (r ReviewService) GetMovieReviews(ctx context.Context, movieId uuid.UUID, status string) ([]*models.ReviewDTO, error) {
+	return r.reviewRepository.GetReviews(ctx, movieId, status)
+}
+
+func (r ReviewService) ApproveReview(ctx context.Context, id uuid.UUID, moderation *models.ModerateReviewDTO) (*models.ReviewDTO, error) {
+	return r.reviewRepository.SetReviewStatus(ctx, id, models.StatusApproved, strings.TrimSpace(moderation.Note))
+}
+
+func (r ReviewService) RejectReview(ctx context.Context, id uuid.UUID, moderation *models.ModerateReviewDTO) (*models.ReviewDTO, error) {
+	return r.reviewRepository.SetReviewStatus(ctx, id, models.StatusRejected, strings.TrimSpace(moderation.Note))
+}
+
+func (r ReviewService) DeleteReview(ctx context.Context, id uuid.UUID) error {
+	return r.reviewRepository.DeleteReview(ctx, id)
+}
@@ -0,0 +1,74 @@
+package reviews
+
+import (
+	movieModels "blockbustermvc/internal/models/movie"
+	models "blockbustermvc/internal/models/review"
+	userModels "blockbustermvc/internal/models/user"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+type fakeReviewRepository struct {
+	models.IReviewRepository
+	rented  bool
+	created bool
+}
+
+func (f *fakeReviewRepository) HasRented(context.Context, uuid.UUID, uuid.UUID) (bool, error) {
+	return f.rented, nil
+}
+
+func (f *fakeReviewRepository) GetUserMovieReview(context.Context, uuid.UUID, uuid.UUID) (*models.ReviewDTO, error) {
+	return nil, models.ErrReviewNotFound
+}
+
+func (f *fakeReviewRepository) CreateReview(_ context.Context, review *models.CreateReviewDTO) (*models.ReviewDTO, error) {
+	f.created = true
+	return &models.ReviewDTO{MovieID: review.MovieID, UserID: review.UserID}, nil
+}
+
+type fakeMovieService struct {
+	movieModels.IMovieService
+}
+
+func (f *fakeMovieService) GetMovie(_ context.Context, id uuid.UUID) (*movieModels.MovieDTO, error) {
+	return &movieModels.MovieDTO{ID: id}, nil
+}
+
+type fakeUserService struct {
+	userModels.IUserService
+}
+
+func (f *fakeUserService) GetUser(_ context.Context, id uuid.UUID) (*userModels.UserDTO, error) {
+	return &userModels.UserDTO{ID: id}, nil
+}
+
+func TestCreateReviewRequiresARental(t *testing.T) {
+	tests := []struct {
+		name        string
+		rented      bool
+		wantErr     error
+		wantCreated bool
+	}{
+		{name: "rented", rented: true, wantCreated: true},
+		{name: "never rented", rented: false, wantErr: models.ErrNotRented},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeReviewRepository{rented: tt.rented}
+			service := NewReviewService(repo, &fakeMovieService{}, &fakeUserService{})
+
+			_, err := service.CreateReview(t.Context(), &models.CreateReviewDTO{MovieID: uuid.New(), UserID: uuid.New(), Rating: 4})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CreateReview() error = %v, want %v", err, tt.wantErr)
+			}
+			if repo.created != tt.wantCreated {
+				t.Errorf("review created = %v, want %v", repo.created, tt.wantCreated)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package stats
+
+import (
+	"blockbustermvc/internal/httputil"
+	models "blockbustermvc/internal/models/stats"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type StatsController struct {
+	statsService models.IStatsService
+}
+
+func NewStatsController(statsService models.IStatsService) *StatsController {
+	return &StatsController{
+		statsService: statsService,
+	}
+}
+
+func (sc *StatsController) RegisterRoutes(r *gin.RouterGroup) {
+	r.GET("/stats", sc.GetStats)
+}
+
+func (sc *StatsController) GetStats(ctx *gin.Context) {
+	stats, err := sc.statsService.GetStats(ctx.Request.Context(), httputil.StoreID(ctx))
+	if err != nil {
+		slog.ErrorContext(ctx.Request.Context(), "Failed to get stats", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, stats)
+}
@@ -0,0 +1,178 @@
+package stats
+
+import (
+	models "blockbustermvc/internal/models/stats"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+/*
+statsRepository is a struct that represents a Postgres database for reading store statistics.
+
+Fields:
+- DB (*pgxpool.Pool): A pointer to a Postgres database connection pool.
+
+Behavior:
+- Aggregates the movies, users, store_stock and loans tables in SQL, so no rows are loaded just to be counted.
+*/
+type statsRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewStatsRepository(db *pgxpool.Pool) models.IStatsRepository {
+	return &statsRepository{
+		DB: db,
+	}
+}
+
+/*
+GetCounts is a method of statsRepository struct that computes the dashboard counters of a store.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- storeId (uuid.UUID): The ID of the store.
+- todayStart (time.Time): Loans borrowed at or after this time count as today's.
+- weekStart (time.Time): Loans borrowed at or after this time count as this week's.
+- overdueBefore (time.Time): Active loans borrowed at or before this time are overdue.
+
+Returns:
+- (*models.CountsDTO, error): A pointer to a CountsDTO struct, or an error if the query fails.
+
+Behavior:
+- Runs every count in one round trip.
+- Total loans cover the loans borrowed at or returned to the store, as the store's loan list does; the other loan counts cover the loans borrowed there.
+- Counts over past loans read loan_history so archiving does not shrink them; active and overdue counts read loans, since only returned loans are archived.
+*/
+func (r *statsRepository) GetCounts(ctx context.Context, storeId uuid.UUID, todayStart, weekStart, overdueBefore time.Time) (*models.CountsDTO, error) {
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM movies),
+			(SELECT COUNT(*) FROM store_stock WHERE store_id = $1 AND quantity > 0),
+			(SELECT COUNT(*) FROM users),
+			(SELECT COUNT(*) FROM loan_history WHERE store_id = $1 OR return_store_id = $1),
+			(SELECT COUNT(*) FROM loans WHERE store_id = $1 AND status = 'active'),
+			(SELECT COUNT(*) FROM loan_history WHERE store_id = $1 AND borrowed_at >= $2),
+			(SELECT COUNT(*) FROM loan_history WHERE store_id = $1 AND borrowed_at >= $3),
+			(SELECT COUNT(*) FROM loans WHERE store_id = $1 AND status = 'active' AND borrowed_at <= $4)`
+
+	var counts models.CountsDTO
+	err := r.DB.QueryRow(ctx, query, storeId, todayStart, weekStart, overdueBefore).Scan(
+		&counts.TotalMovies,
+		&counts.AvailableMovies,
+		&counts.TotalUsers,
+		&counts.TotalLoans,
+		&counts.ActiveLoans,
+		&counts.LoansToday,
+		&counts.LoansThisWeek,
+		&counts.OverdueLoans,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get store counts: %w", err)
+	}
+
+	return &counts, nil
+}
+
+/*
+GetMostRented is a method of statsRepository struct that retrieves the movies borrowed most often at a store.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- storeId (uuid.UUID): The ID of the store.
+- since (time.Time): Only loans borrowed at or after this time are counted.
+- limit (int): The maximum number of movies returned.
+
+Returns:
+- ([]*models.TitleCountDTO, error): A slice of TitleCountDTO structs, most borrowed first, or an error if the retrieval fails.
+
+Behavior:
+- Reads loan_history, so archived loans still count.
+*/
+func (r *statsRepository) GetMostRented(ctx context.Context, storeId uuid.UUID, since time.Time, limit int) ([]*models.TitleCountDTO, error) {
+	query := `
+		SELECT m.id, m.name, COUNT(*) AS loans
+		FROM loan_history l
+		JOIN movies m ON m.id = l.movie_id
+		WHERE l.store_id = $1 AND l.borrowed_at >= $2
+		GROUP BY m.id, m.name
+		ORDER BY loans DESC, m.name
+		LIMIT $3`
+
+	rows, err := r.DB.Query(ctx, query, storeId, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get most rented movies: %w", err)
+	}
+	defer rows.Close()
+
+	titles := []*models.TitleCountDTO{}
+	for rows.Next() {
+		var title models.TitleCountDTO
+		if err := rows.Scan(&title.MovieID, &title.Name, &title.Loans); err != nil {
+			return nil, fmt.Errorf("failed to scan most rented movie: %w", err)
+		}
+		titles = append(titles, &title)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate most rented movies: %w", err)
+	}
+
+	return titles, nil
+}
+
+/*
+GetIdleInventory is a method of statsRepository struct that retrieves the movies a store has in stock but nobody borrowed there lately.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- storeId (uuid.UUID): The ID of the store.
+- idleSince (time.Time): Movies borrowed at the store at or after this time are not idle.
+- limit (int): The maximum number of movies returned.
+
+Returns:
+- ([]*models.IdleMovieDTO, error): A slice of IdleMovieDTO structs, or an error if the retrieval fails.
+
+Behavior:
+- Movies never borrowed at the store come first, then the ones idle the longest; more copies on the shelf come first among equals.
+- Reads loan_history, so a movie whose loans were all archived is not mistaken for one never borrowed.
+*/
+func (r *statsRepository) GetIdleInventory(ctx context.Context, storeId uuid.UUID, idleSince time.Time, limit int) ([]*models.IdleMovieDTO, error) {
+	query := `
+		SELECT m.id, m.name, ss.quantity, last.borrowed_at
+		FROM store_stock ss
+		JOIN movies m ON m.id = ss.movie_id
+		LEFT JOIN LATERAL (
+			SELECT MAX(l.borrowed_at) AS borrowed_at
+			FROM loan_history l
+			WHERE l.store_id = ss.store_id AND l.movie_id = ss.movie_id
+		) last ON TRUE
+		WHERE ss.store_id = $1 AND ss.quantity > 0
+			AND (last.borrowed_at IS NULL OR last.borrowed_at < $2)
+		ORDER BY last.borrowed_at ASC NULLS FIRST, ss.quantity DESC, m.name
+		LIMIT $3`
+
+	rows, err := r.DB.Query(ctx, query, storeId, idleSince, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get idle inventory: %w", err)
+	}
+	defer rows.Close()
+
+	movies := []*models.IdleMovieDTO{}
+	for rows.Next() {
+		var movie models.IdleMovieDTO
+		if err := rows.Scan(&movie.MovieID, &movie.Name, &movie.Quantity, &movie.LastBorrowedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan idle movie: %w", err)
+		}
+		movies = append(movies, &movie)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate idle inventory: %w", err)
+	}
+
+	return movies, nil
+}
@@ -0,0 +1,30 @@
+package stats
+
+import (
+	"blockbustermvc/internal/database/dbtest"
+	"testing"
+	"time"
+)
+
+func TestCountsKeepArchivedLoans(t *testing.T) {
+	pool := dbtest.Open(t)
+	repo := NewStatsRepository(pool)
+
+	storeId := dbtest.Store(t, pool)
+	movieId := dbtest.Movie(t, pool)
+	userId := dbtest.User(t, pool)
+
+	dbtest.ArchivedLoan(t, pool, storeId, movieId, userId)
+
+	now := time.Now()
+	counts, err := repo.GetCounts(t.Context(), storeId, now, now, now)
+	if err != nil {
+		t.Fatalf("GetCounts() error = %v", err)
+	}
+	if counts.TotalLoans != 1 {
+		t.Errorf("TotalLoans = %d, want 1", counts.TotalLoans)
+	}
+	if counts.ActiveLoans != 0 {
+		t.Errorf("ActiveLoans = %d, want 0", counts.ActiveLoans)
+	}
+}
@@ -0,0 +1,61 @@
+package stats
+
+import (
+	models "blockbustermvc/internal/models/stats"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type StatsService struct {
+	statsRepository models.IStatsRepository
+	loanPeriod      time.Duration
+}
+
+// NewStatsService needs the loan period to tell which active loans are
+// overdue, the same one the reminders use.
+func NewStatsService(statsRepo models.IStatsRepository, loanPeriod time.Duration) models.IStatsService {
+	return &StatsService{
+		statsRepository: statsRepo,
+		loanPeriod:      loanPeriod,
+	}
+}
+
+func (s StatsService) GetStats(ctx context.Context, storeId uuid.UUID) (*models.StatsDTO, error) {
+	now := time.Now()
+	todayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	// Weeks start on Monday; time.Weekday counts from Sunday
+	weekStart := todayStart.AddDate(0, 0, -(int(now.Weekday())+6)%7)
+	recentSince := now.Add(-models.RecentWindow)
+
+	counts, err := s.statsRepository.GetCounts(ctx, storeId, todayStart, weekStart, now.Add(-s.loanPeriod))
+	if err != nil {
+		return nil, err
+	}
+
+	mostRented, err := s.statsRepository.GetMostRented(ctx, storeId, recentSince, models.MostRentedLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	idleInventory, err := s.statsRepository.GetIdleInventory(ctx, storeId, recentSince, models.IdleInventoryLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.StatsDTO{
+		StoreID:         storeId,
+		TotalMovies:     counts.TotalMovies,
+		AvailableMovies: counts.AvailableMovies,
+		TotalUsers:      counts.TotalUsers,
+		TotalLoans:      counts.TotalLoans,
+		ActiveLoans:     counts.ActiveLoans,
+		LoansToday:      counts.LoansToday,
+		LoansThisWeek:   counts.LoansThisWeek,
+		OverdueLoans:    counts.OverdueLoans,
+		MostRented:      mostRented,
+		IdleInventory:   idleInventory,
+		GeneratedAt:     now,
+	}, nil
+}
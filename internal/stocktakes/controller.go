@@ -0,0 +1,216 @@
+package stocktakes
+
+import (
+	"blockbustermvc/internal/httputil"
+	models "blockbustermvc/internal/models/stocktake"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type StocktakesController struct {
+	stocktakeService models.IStocktakeService
+}
+
+func NewStocktakesController(stocktakeService models.IStocktakeService) *StocktakesController {
+	return &StocktakesController{
+		stocktakeService: stocktakeService,
+	}
+}
+
+func (sc *StocktakesController) RegisterRoutes(r *gin.RouterGroup) {
+	stocktakes := r.Group("/stocktakes")
+
+	{
+		stocktakes.POST("", sc.OpenStocktake)
+		stocktakes.GET("", sc.GetAllStocktakes)
+		stocktakes.GET("/:id", sc.GetStocktake)
+		stocktakes.PUT("/:id/counts", sc.RecordCount)
+		stocktakes.POST("/:id/scans", sc.ScanCopy)
+		stocktakes.GET("/:id/differences", sc.GetDifferences)
+		stocktakes.POST("/:id/approve", sc.ApproveStocktake)
+		stocktakes.POST("/:id/cancel", sc.CancelStocktake)
+	}
+}
+
+func (sc *StocktakesController) OpenStocktake(ctx *gin.Context) {
+	var stocktake models.CreateStocktakeDTO
+	if err := ctx.ShouldBindJSON(&stocktake); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body",
+		})
+		return
+	}
+
+	stocktake.StoreID = httputil.StoreID(ctx)
+
+	created, err := sc.stocktakeService.OpenStocktake(ctx.Request.Context(), &stocktake)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, models.ErrStocktakeAlreadyOpen) {
+			status = http.StatusConflict
+		}
+
+		if status == http.StatusInternalServerError {
+			slog.ErrorContext(ctx.Request.Context(), "Failed to open stocktake", "error", err)
+		}
+		ctx.JSON(status, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.Header("Location", ctx.FullPath()+"/"+created.ID.String())
+	ctx.JSON(http.StatusCreated, created)
+}
+
+func (sc *StocktakesController) GetAllStocktakes(ctx *gin.Context) {
+	stocktakes, err := sc.stocktakeService.GetAllStocktakes(ctx.Request.Context(), httputil.StoreID(ctx))
+	if err != nil {
+		slog.ErrorContext(ctx.Request.Context(), "Failed to get all stocktakes", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, stocktakes)
+}
+
+func (sc *StocktakesController) GetStocktake(ctx *gin.Context) {
+	id, ok := parseStocktakeID(ctx)
+	if !ok {
+		return
+	}
+
+	stocktake, err := sc.stocktakeService.GetStocktake(ctx.Request.Context(), id)
+	if err != nil {
+		slog.ErrorContext(ctx.Request.Context(), "Failed to get stocktake", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, stocktake)
+}
+
+func (sc *StocktakesController) RecordCount(ctx *gin.Context) {
+	id, ok := parseStocktakeID(ctx)
+	if !ok {
+		return
+	}
+
+	var count models.RecordCountDTO
+	if err := ctx.ShouldBindJSON(&count); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body",
+		})
+		return
+	}
+
+	line, err := sc.stocktakeService.RecordCount(ctx.Request.Context(), id, &count)
+	if err != nil {
+		respondWithStocktakeError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, line)
+}
+
+func (sc *StocktakesController) ScanCopy(ctx *gin.Context) {
+	id, ok := parseStocktakeID(ctx)
+	if !ok {
+		return
+	}
+
+	var scan models.ScanDTO
+	if err := ctx.ShouldBindJSON(&scan); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body",
+		})
+		return
+	}
+
+	line, err := sc.stocktakeService.ScanCopy(ctx.Request.Context(), id, &scan)
+	if err != nil {
+		respondWithStocktakeError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, line)
+}
+
+func (sc *StocktakesController) GetDifferences(ctx *gin.Context) {
+	id, ok := parseStocktakeID(ctx)
+	if !ok {
+		return
+	}
+
+	differences, err := sc.stocktakeService.GetDifferences(ctx.Request.Context(), id)
+	if err != nil {
+		slog.ErrorContext(ctx.Request.Context(), "Failed to get differences", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, differences)
+}
+
+func (sc *StocktakesController) ApproveStocktake(ctx *gin.Context) {
+	id, ok := parseStocktakeID(ctx)
+	if !ok {
+		return
+	}
+
+	stocktake, err := sc.stocktakeService.ApproveStocktake(ctx.Request.Context(), id)
+	if err != nil {
+		respondWithStocktakeError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, stocktake)
+}
+
+func (sc *StocktakesController) CancelStocktake(ctx *gin.Context) {
+	id, ok := parseStocktakeID(ctx)
+	if !ok {
+		return
+	}
+
+	stocktake, err := sc.stocktakeService.CancelStocktake(ctx.Request.Context(), id)
+	if err != nil {
+		respondWithStocktakeError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, stocktake)
+}
+
+func parseStocktakeID(ctx *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid stocktake ID",
+		})
+		return uuid.Nil, false
+	}
+
+	return id, true
+}
+
+func respondWithStocktakeError(ctx *gin.Context, err error) {
+	status := http.StatusUnprocessableEntity
+	if errors.Is(err, models.ErrStocktakeNotOpen) {
+		status = http.StatusConflict
+	}
+
+	ctx.JSON(status, gin.H{
+		"error": err.Error(),
+	})
+}
@@ -0,0 +1,524 @@
+package stocktakes
+
+import (
+	"blockbustermvc/internal/inventory"
+	inventoryModels "blockbustermvc/internal/models/inventory"
+	models "blockbustermvc/internal/models/stocktake"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+/*
+stocktakeRepository is a struct that represents a Postgres database for storing stocktake sessions.
+
+Fields:
+- DB (*pgxpool.Pool): A pointer to a Postgres database connection pool.
+
+Behavior:
+- Provides methods for interacting with the stocktakes and stocktake_counts tables in the database.
+- Posts approved count differences to the inventory ledger.
+*/
+type stocktakeRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewStocktakeRepository(db *pgxpool.Pool) models.IStocktakeRepository {
+	return &stocktakeRepository{
+		DB: db,
+	}
+}
+
+/*
+CreateStocktake is a method of stocktakeRepository struct that opens a new stocktake in the postgres database.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- stocktake (*models.CreateStocktakeDTO): A pointer to a CreateStocktakeDTO struct containing the store and the stocktake note.
+
+Returns:
+- (*models.StocktakeDTO, error): A pointer to a StocktakeDTO struct containing the persisted stocktake, or an error if the creation fails.
+
+Behavior:
+- Inserts a new open stocktake for the store into the stocktakes table in the database.
+- Returns an error if the creation fails, e.g. when another stocktake is already open at the store.
+*/
+func (r *stocktakeRepository) CreateStocktake(ctx context.Context, stocktake *models.CreateStocktakeDTO) (*models.StocktakeDTO, error) {
+	query := `
+		INSERT INTO stocktakes (store_id, status, note, opened_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $4, $4)
+		RETURNING id, store_id, status, note, opened_at, closed_at, created_at, updated_at`
+
+	var created models.StocktakeDTO
+	err := r.DB.QueryRow(ctx, query,
+		stocktake.StoreID,
+		models.StatusOpen,
+		stocktake.Note,
+		time.Now(),
+	).Scan(
+		&created.ID,
+		&created.StoreID,
+		&created.Status,
+		&created.Note,
+		&created.OpenedAt,
+		&created.ClosedAt,
+		&created.CreatedAt,
+		&created.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stocktake: %w", err)
+	}
+
+	return &created, nil
+}
+
+/*
+GetStocktake is a method of stocktakeRepository struct that retrieves a stocktake from the postgres database by its ID.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- id (uuid.UUID): The ID of the stocktake to be retrieved.
+
+Returns:
+- (*models.StocktakeDTO, error): A pointer to a StocktakeDTO struct containing the stocktake data, or an error if the retrieval fails.
+
+Behavior:
+- Retrieves a stocktake from the stocktakes table in the database by its ID.
+- Returns an error if the retrieval fails.
+*/
+func (r *stocktakeRepository) GetStocktake(ctx context.Context, id uuid.UUID) (*models.StocktakeDTO, error) {
+	query := `
+		SELECT id, store_id, status, note, opened_at, closed_at, created_at, updated_at
+		FROM stocktakes
+		WHERE id = $1`
+
+	var stocktake models.StocktakeDTO
+	err := r.DB.QueryRow(ctx, query, id).Scan(
+		&stocktake.ID,
+		&stocktake.StoreID,
+		&stocktake.Status,
+		&stocktake.Note,
+		&stocktake.OpenedAt,
+		&stocktake.ClosedAt,
+		&stocktake.CreatedAt,
+		&stocktake.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stocktake: %w", err)
+	}
+
+	return &stocktake, nil
+}
+
+/*
+GetOpenStocktake is a method of stocktakeRepository struct that retrieves the stocktake currently being counted at a store.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- storeId (uuid.UUID): The ID of the store.
+
+Returns:
+- (*models.StocktakeDTO, error): A pointer to a StocktakeDTO struct, nil if no stocktake is open, or an error if the retrieval fails.
+
+Behavior:
+- Retrieves the open stocktake of the store from the stocktakes table, if there is one.
+*/
+func (r *stocktakeRepository) GetOpenStocktake(ctx context.Context, storeId uuid.UUID) (*models.StocktakeDTO, error) {
+	query := `
+		SELECT id, store_id, status, note, opened_at, closed_at, created_at, updated_at
+		FROM stocktakes
+		WHERE store_id = $1 AND status = $2`
+
+	var stocktake models.StocktakeDTO
+	err := r.DB.QueryRow(ctx, query, storeId, models.StatusOpen).Scan(
+		&stocktake.ID,
+		&stocktake.StoreID,
+		&stocktake.Status,
+		&stocktake.Note,
+		&stocktake.OpenedAt,
+		&stocktake.ClosedAt,
+		&stocktake.CreatedAt,
+		&stocktake.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get open stocktake: %w", err)
+	}
+
+	return &stocktake, nil
+}
+
+/*
+GetAllStocktakes is a method of stocktakeRepository struct that retrieves the stocktakes of a store from the postgres database.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- storeId (uuid.UUID): The ID of the store.
+
+Returns:
+- ([]*models.StocktakeDTO, error): A slice of pointers to StocktakeDTO structs, newest first, or an error if the retrieval fails.
+
+Behavior:
+- Retrieves the store's stocktakes from the stocktakes table in the database.
+- Returns an error if the retrieval fails.
+*/
+func (r *stocktakeRepository) GetAllStocktakes(ctx context.Context, storeId uuid.UUID) ([]*models.StocktakeDTO, error) {
+	query := `
+		SELECT id, store_id, status, note, opened_at, closed_at, created_at, updated_at
+		FROM stocktakes
+		WHERE store_id = $1
+		ORDER BY opened_at DESC`
+
+	rows, err := r.DB.Query(ctx, query, storeId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get all stocktakes: %w", err)
+	}
+	defer rows.Close()
+
+	var stocktakes []*models.StocktakeDTO
+	for rows.Next() {
+		var stocktake models.StocktakeDTO
+		err := rows.Scan(
+			&stocktake.ID,
+			&stocktake.StoreID,
+			&stocktake.Status,
+			&stocktake.Note,
+			&stocktake.OpenedAt,
+			&stocktake.ClosedAt,
+			&stocktake.CreatedAt,
+			&stocktake.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan stocktake: %w", err)
+		}
+		stocktakes = append(stocktakes, &stocktake)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over stocktakes: %w", err)
+	}
+
+	return stocktakes, nil
+}
+
+/*
+GetStocktakeLines is a method of stocktakeRepository struct that retrieves the counted lines of a stocktake.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- id (uuid.UUID): The ID of the stocktake.
+
+Returns:
+- ([]*models.StocktakeLineDTO, error): A slice of pointers to StocktakeLineDTO structs ordered by movie name, or an error if the retrieval fails.
+
+Behavior:
+- Joins every count with its movie, the store's stock and the number of the store's copies currently out on loan.
+- The expected quantity is the store's on-hand stock (total minus active loans) when the line was counted.
+*/
+func (r *stocktakeRepository) GetStocktakeLines(ctx context.Context, id uuid.UUID) ([]*models.StocktakeLineDTO, error) {
+	query := `
+		SELECT sc.movie_id, m.name, COALESCE(ss.quantity, 0) + COALESCE(al.active_loans, 0), COALESCE(al.active_loans, 0),
+			sc.expected_quantity, sc.counted_quantity, sc.updated_at
+		FROM stocktake_counts sc
+		JOIN stocktakes st ON st.id = sc.stocktake_id
+		JOIN movies m ON m.id = sc.movie_id
+		LEFT JOIN store_stock ss ON ss.store_id = st.store_id AND ss.movie_id = sc.movie_id
+		LEFT JOIN (
+			SELECT store_id, movie_id, COUNT(*) AS active_loans
+			FROM loans
+			WHERE status = 'active'
+			GROUP BY store_id, movie_id
+		) al ON al.store_id = st.store_id AND al.movie_id = sc.movie_id
+		WHERE sc.stocktake_id = $1
+		ORDER BY m.name`
+
+	rows, err := r.DB.Query(ctx, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stocktake lines: %w", err)
+	}
+	defer rows.Close()
+
+	var lines []*models.StocktakeLineDTO
+	for rows.Next() {
+		var line models.StocktakeLineDTO
+		err := rows.Scan(
+			&line.MovieID,
+			&line.MovieName,
+			&line.TotalQuantity,
+			&line.ActiveLoans,
+			&line.ExpectedQuantity,
+			&line.CountedQuantity,
+			&line.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan stocktake line: %w", err)
+		}
+
+		line.Difference = line.CountedQuantity - line.ExpectedQuantity
+		lines = append(lines, &line)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over stocktake lines: %w", err)
+	}
+
+	return lines, nil
+}
+
+/*
+SetCount is a method of stocktakeRepository struct that records the counted quantity of a movie in a stocktake.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- id (uuid.UUID): The ID of the stocktake.
+- movieId (uuid.UUID): The ID of the counted movie.
+- countedQuantity (int64): The number of copies found on the shelf.
+
+Returns:
+- error: An error if the count cannot be recorded, otherwise nil.
+
+Behavior:
+- Inserts or replaces the count of the movie and snapshots the store's current on-hand stock as the expected quantity.
+- Returns models.ErrStocktakeNotOpen if the stocktake was already approved or cancelled.
+*/
+func (r *stocktakeRepository) SetCount(ctx context.Context, id, movieId uuid.UUID, countedQuantity int64) error {
+	query := `
+		INSERT INTO stocktake_counts (stocktake_id, movie_id, counted_quantity, expected_quantity, created_at, updated_at)
+		SELECT $1, m.id, $3, COALESCE(ss.quantity, 0), $4, $4
+		FROM movies m
+		JOIN stocktakes st ON st.id = $1
+		LEFT JOIN store_stock ss ON ss.store_id = st.store_id AND ss.movie_id = m.id
+		WHERE m.id = $2
+		ON CONFLICT (stocktake_id, movie_id) DO UPDATE
+		SET counted_quantity = EXCLUDED.counted_quantity,
+			expected_quantity = EXCLUDED.expected_quantity,
+			updated_at = EXCLUDED.updated_at`
+
+	return r.writeCount(ctx, id, movieId, query, countedQuantity)
+}
+
+/*
+IncrementCount is a method of stocktakeRepository struct that adds one scanned copy to the count of a movie.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- id (uuid.UUID): The ID of the stocktake.
+- movieId (uuid.UUID): The ID of the scanned movie.
+
+Returns:
+- error: An error if the scan cannot be recorded, otherwise nil.
+
+Behavior:
+- Starts the count at one on the first scan, snapshotting the store's on-hand stock as the expected quantity.
+- Adds one to the existing count on every further scan.
+- Returns models.ErrStocktakeNotOpen if the stocktake was already approved or cancelled.
+*/
+func (r *stocktakeRepository) IncrementCount(ctx context.Context, id, movieId uuid.UUID) error {
+	query := `
+		INSERT INTO stocktake_counts (stocktake_id, movie_id, counted_quantity, expected_quantity, created_at, updated_at)
+		SELECT $1, m.id, $3, COALESCE(ss.quantity, 0), $4, $4
+		FROM movies m
+		JOIN stocktakes st ON st.id = $1
+		LEFT JOIN store_stock ss ON ss.store_id = st.store_id AND ss.movie_id = m.id
+		WHERE m.id = $2
+		ON CONFLICT (stocktake_id, movie_id) DO UPDATE
+		SET counted_quantity = stocktake_counts.counted_quantity + EXCLUDED.counted_quantity,
+			updated_at = EXCLUDED.updated_at`
+
+	return r.writeCount(ctx, id, movieId, query, 1)
+}
+
+func (r *stocktakeRepository) writeCount(ctx context.Context, id, movieId uuid.UUID, query string, quantity int64) error {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin stocktake count: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := lockOpenStocktake(ctx, tx, id); err != nil {
+		return err
+	}
+
+	result, err := tx.Exec(ctx, query, id, movieId, quantity, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to record stocktake count: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("movie with id %s not found", movieId)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit stocktake count: %w", err)
+	}
+
+	return nil
+}
+
+/*
+ApproveStocktake is a method of stocktakeRepository struct that closes a stocktake and posts its differences to the inventory ledger.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- id (uuid.UUID): The ID of the stocktake to approve.
+
+Returns:
+- (*models.StocktakeDTO, error): A pointer to a StocktakeDTO struct containing the approved stocktake, or an error if the approval fails.
+
+Behavior:
+- Runs in a single transaction so either every adjustment is posted or none is.
+- For each counted movie whose count differs from the expected quantity, applies the difference through inventory.ApplyMovement.
+- Records every adjustment as a manual_correction movement linked to the stocktake, with its stock.changed event.
+- Applies the difference rather than the counted value, so loans and returns made after counting are preserved.
+- Returns models.ErrStocktakeNotOpen if the stocktake was already approved or cancelled.
+*/
+func (r *stocktakeRepository) ApproveStocktake(ctx context.Context, id uuid.UUID) (*models.StocktakeDTO, error) {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin stocktake approval: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	storeId, err := lockOpenStocktake(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.Query(ctx, `
+		SELECT movie_id, counted_quantity - expected_quantity
+		FROM stocktake_counts
+		WHERE stocktake_id = $1 AND counted_quantity <> expected_quantity`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stocktake differences: %w", err)
+	}
+
+	differences := map[uuid.UUID]int64{}
+	for rows.Next() {
+		var movieId uuid.UUID
+		var difference int64
+		if err := rows.Scan(&movieId, &difference); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan stocktake difference: %w", err)
+		}
+		differences[movieId] = difference
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over stocktake differences: %w", err)
+	}
+
+	for _, movieId := range inventory.SortedMovieIDs(differences) {
+		_, err := inventory.ApplyMovement(ctx, tx, movieId, &inventoryModels.CreateMovementDTO{
+			Reason:        inventoryModels.ReasonManualCorrection,
+			QuantityDelta: differences[movieId],
+			Note:          "Stocktake adjustment",
+			StoreID:       storeId,
+			StocktakeID:   &id,
+		})
+		if errors.Is(err, inventoryModels.ErrInsufficientStock) {
+			return nil, fmt.Errorf("adjustment for movie %s would leave negative stock: %w", movieId, err)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	stocktake, err := closeStocktake(ctx, tx, id, models.StatusApproved, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit stocktake approval: %w", err)
+	}
+
+	return stocktake, nil
+}
+
+/*
+CancelStocktake is a method of stocktakeRepository struct that abandons an open stocktake without touching stock.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- id (uuid.UUID): The ID of the stocktake to cancel.
+
+Returns:
+- (*models.StocktakeDTO, error): A pointer to a StocktakeDTO struct containing the cancelled stocktake, or an error if the cancellation fails.
+
+Behavior:
+- Marks the stocktake as cancelled; its counts are kept for reference.
+- Returns models.ErrStocktakeNotOpen if the stocktake was already approved or cancelled.
+*/
+func (r *stocktakeRepository) CancelStocktake(ctx context.Context, id uuid.UUID) (*models.StocktakeDTO, error) {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin stocktake cancellation: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := lockOpenStocktake(ctx, tx, id); err != nil {
+		return nil, err
+	}
+
+	stocktake, err := closeStocktake(ctx, tx, id, models.StatusCancelled, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit stocktake cancellation: %w", err)
+	}
+
+	return stocktake, nil
+}
+
+func lockOpenStocktake(ctx context.Context, tx pgx.Tx, id uuid.UUID) (uuid.UUID, error) {
+	var storeId uuid.UUID
+	var status string
+	err := tx.QueryRow(ctx, `SELECT store_id, status FROM stocktakes WHERE id = $1 FOR UPDATE`, id).Scan(&storeId, &status)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return uuid.Nil, fmt.Errorf("stocktake with id %s not found", id)
+	}
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to lock stocktake: %w", err)
+	}
+
+	if status != models.StatusOpen {
+		return uuid.Nil, models.ErrStocktakeNotOpen
+	}
+
+	return storeId, nil
+}
+
+func closeStocktake(ctx context.Context, tx pgx.Tx, id uuid.UUID, status string, closedAt time.Time) (*models.StocktakeDTO, error) {
+	query := `
+		UPDATE stocktakes
+		SET status = $2, closed_at = $3, updated_at = $3
+		WHERE id = $1
+		RETURNING id, store_id, status, note, opened_at, closed_at, created_at, updated_at`
+
+	var stocktake models.StocktakeDTO
+	err := tx.QueryRow(ctx, query, id, status, closedAt).Scan(
+		&stocktake.ID,
+		&stocktake.StoreID,
+		&stocktake.Status,
+		&stocktake.Note,
+		&stocktake.OpenedAt,
+		&stocktake.ClosedAt,
+		&stocktake.CreatedAt,
+		&stocktake.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to close stocktake: %w", err)
+	}
+
+	return &stocktake, nil
+}
@@ -0,0 +1,50 @@
+package stocktakes
+
+import (
+	"blockbustermvc/internal/database/dbtest"
+	"blockbustermvc/internal/inventory"
+	eventModels "blockbustermvc/internal/models/event"
+	inventoryModels "blockbustermvc/internal/models/inventory"
+	models "blockbustermvc/internal/models/stocktake"
+	"testing"
+)
+
+func TestApproveStocktakePostsAdjustmentsThroughTheLedger(t *testing.T) {
+	pool := dbtest.Open(t)
+	ctx := t.Context()
+	repo := NewStocktakeRepository(pool)
+
+	storeId := dbtest.Store(t, pool)
+	movieId := dbtest.Movie(t, pool)
+
+	_, err := inventory.NewInventoryRepository(pool).CreateMovement(ctx, movieId, &inventoryModels.CreateMovementDTO{
+		Reason:        inventoryModels.ReasonPurchase,
+		QuantityDelta: 5,
+		StoreID:       storeId,
+	})
+	if err != nil {
+		t.Fatalf("CreateMovement() error = %v", err)
+	}
+
+	stocktake, err := repo.CreateStocktake(ctx, &models.CreateStocktakeDTO{StoreID: storeId})
+	if err != nil {
+		t.Fatalf("CreateStocktake() error = %v", err)
+	}
+	if err := repo.SetCount(ctx, stocktake.ID, movieId, 3); err != nil {
+		t.Fatalf("SetCount() error = %v", err)
+	}
+	if _, err := repo.ApproveStocktake(ctx, stocktake.ID); err != nil {
+		t.Fatalf("ApproveStocktake() error = %v", err)
+	}
+
+	if got := dbtest.Int(t, pool, `SELECT quantity FROM store_stock WHERE store_id = $1 AND movie_id = $2`, storeId, movieId); got != 3 {
+		t.Errorf("store stock = %d, want 3", got)
+	}
+	if got := dbtest.Int(t, pool, `SELECT quantity_delta FROM inventory_movements WHERE stocktake_id = $1`, stocktake.ID); got != -2 {
+		t.Errorf("stocktake adjustment = %d, want -2", got)
+	}
+	// The purchase and the adjustment
+	if got := dbtest.Int(t, pool, `SELECT COUNT(*) FROM outbox_events WHERE aggregate_id = $1 AND type = $2`, movieId, eventModels.TypeStockChanged); got != 2 {
+		t.Errorf("stock.changed events = %d, want 2", got)
+	}
+}
@@ -0,0 +1,117 @@
+package stocktakes
+
+import (
+	movieService "blockbustermvc/internal/models/movie"
+	models "blockbustermvc/internal/models/stocktake"
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type StocktakeService struct {
+	stocktakeRepository models.IStocktakeRepository
+	movieService        movieService.IMovieService
+}
+
+func NewStocktakeService(
+	stocktakeRepo models.IStocktakeRepository,
+	movieService movieService.IMovieService,
+) models.IStocktakeService {
+	return &StocktakeService{
+		stocktakeRepository: stocktakeRepo,
+		movieService:        movieService,
+	}
+}
+
+func (s StocktakeService) OpenStocktake(ctx context.Context, stocktake *models.CreateStocktakeDTO) (*models.StocktakeDTO, error) {
+	open, err := s.stocktakeRepository.GetOpenStocktake(ctx, stocktake.StoreID)
+	if err != nil {
+		return nil, err
+	}
+	if open != nil {
+		return nil, models.ErrStocktakeAlreadyOpen
+	}
+
+	stocktake.Note = strings.TrimSpace(stocktake.Note)
+
+	return s.stocktakeRepository.CreateStocktake(ctx, stocktake)
+}
+
+func (s StocktakeService) GetStocktake(ctx context.Context, id uuid.UUID) (*models.StocktakeDTO, error) {
+	stocktake, err := s.stocktakeRepository.GetStocktake(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	stocktake.Lines, err = s.stocktakeRepository.GetStocktakeLines(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return stocktake, nil
+}
+
+func (s StocktakeService) GetAllStocktakes(ctx context.Context, storeId uuid.UUID) ([]*models.StocktakeDTO, error) {
+	return s.stocktakeRepository.GetAllStocktakes(ctx, storeId)
+}
+
+func (s StocktakeService) RecordCount(ctx context.Context, id uuid.UUID, count *models.RecordCountDTO) (*models.StocktakeLineDTO, error) {
+	if err := s.stocktakeRepository.SetCount(ctx, id, count.MovieID, count.CountedQuantity); err != nil {
+		return nil, err
+	}
+
+	return s.findLine(ctx, id, count.MovieID)
+}
+
+func (s StocktakeService) ScanCopy(ctx context.Context, id uuid.UUID, scan *models.ScanDTO) (*models.StocktakeLineDTO, error) {
+	movieId, err := s.movieService.ResolveMovieID(ctx, scan.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.stocktakeRepository.IncrementCount(ctx, id, movieId); err != nil {
+		return nil, err
+	}
+
+	return s.findLine(ctx, id, movieId)
+}
+
+func (s StocktakeService) GetDifferences(ctx context.Context, id uuid.UUID) ([]*models.StocktakeLineDTO, error) {
+	lines, err := s.stocktakeRepository.GetStocktakeLines(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	differences := []*models.StocktakeLineDTO{}
+	for _, line := range lines {
+		if line.Difference != 0 {
+			differences = append(differences, line)
+		}
+	}
+
+	return differences, nil
+}
+
+func (s StocktakeService) ApproveStocktake(ctx context.Context, id uuid.UUID) (*models.StocktakeDTO, error) {
+	return s.stocktakeRepository.ApproveStocktake(ctx, id)
+}
+
+func (s StocktakeService) CancelStocktake(ctx context.Context, id uuid.UUID) (*models.StocktakeDTO, error) {
+	return s.stocktakeRepository.CancelStocktake(ctx, id)
+}
+
+func (s StocktakeService) findLine(ctx context.Context, id, movieId uuid.UUID) (*models.StocktakeLineDTO, error) {
+	lines, err := s.stocktakeRepository.GetStocktakeLines(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, line := range lines {
+		if line.MovieID == movieId {
+			return line, nil
+		}
+	}
+
+	return nil, nil
+}
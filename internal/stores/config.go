@@ -0,0 +1,30 @@
+package stores
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// ConfiguredScope reads from BLK_STORE_IDS the comma-separated IDs of the
+// stores requests may act on. Unset, requests can only act on the default
+// store.
+func ConfiguredScope() ([]uuid.UUID, error) {
+	value := os.Getenv("BLK_STORE_IDS")
+	if value == "" {
+		return nil, nil
+	}
+
+	var scope []uuid.UUID
+	for _, field := range strings.Split(value, ",") {
+		id, err := uuid.Parse(strings.TrimSpace(field))
+		if err != nil {
+			return nil, fmt.Errorf("invalid BLK_STORE_IDS %q: expected comma-separated store IDs", value)
+		}
+		scope = append(scope, id)
+	}
+
+	return scope, nil
+}
@@ -0,0 +1,45 @@
+package stores
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestConfiguredScope(t *testing.T) {
+	main, branch := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name    string
+		value   string
+		want    []uuid.UUID
+		wantErr bool
+	}{
+		{name: "unset", value: ""},
+		{name: "one store", value: main.String(), want: []uuid.UUID{main}},
+		{name: "keeps the order", value: branch.String() + ", " + main.String(), want: []uuid.UUID{branch, main}},
+		{name: "not an ID", value: main.String() + ",downtown", wantErr: true},
+		{name: "trailing comma", value: main.String() + ",", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("BLK_STORE_IDS", tt.value)
+
+			got, err := ConfiguredScope()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ConfiguredScope() = %v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ConfiguredScope() error = %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ConfiguredScope() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
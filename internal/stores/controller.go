@@ -0,0 +1,110 @@
+package stores
+
+import (
+	"blockbustermvc/internal/httputil"
+	models "blockbustermvc/internal/models/store"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type StoresController struct {
+	storeService models.IStoreService
+}
+
+func NewStoresController(storeService models.IStoreService) *StoresController {
+	return &StoresController{
+		storeService: storeService,
+	}
+}
+
+func (sc *StoresController) RegisterRoutes(r *gin.RouterGroup) {
+	stores := r.Group("/stores")
+
+	{
+		stores.POST("", sc.CreateStore)
+		stores.GET("", sc.GetAllStores)
+		stores.GET("/current", sc.GetCurrentStore)
+		stores.GET("/:id", sc.GetStore)
+	}
+}
+
+func (sc *StoresController) CreateStore(ctx *gin.Context) {
+	var store models.CreateStoreDTO
+	if err := ctx.ShouldBindJSON(&store); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body",
+		})
+		return
+	}
+
+	created, err := sc.storeService.CreateStore(ctx.Request.Context(), &store)
+	if err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.Header("Location", ctx.FullPath()+"/"+created.ID.String())
+	ctx.JSON(http.StatusCreated, created)
+}
+
+func (sc *StoresController) GetAllStores(ctx *gin.Context) {
+	stores, err := sc.storeService.GetAllStores(ctx.Request.Context())
+	if err != nil {
+		slog.ErrorContext(ctx.Request.Context(), "Failed to get all stores", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, stores)
+}
+
+func (sc *StoresController) GetCurrentStore(ctx *gin.Context) {
+	sc.respondWithStore(ctx, httputil.StoreID(ctx))
+}
+
+func (sc *StoresController) GetStore(ctx *gin.Context) {
+	if err := uuid.Validate(ctx.Param("id")); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid store ID",
+		})
+		return
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	sc.respondWithStore(ctx, id)
+}
+
+func (sc *StoresController) respondWithStore(ctx *gin.Context, id uuid.UUID) {
+	store, err := sc.storeService.GetStore(ctx.Request.Context(), id)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, models.ErrStoreNotFound) {
+			status = http.StatusNotFound
+		}
+
+		if status == http.StatusInternalServerError {
+			slog.ErrorContext(ctx.Request.Context(), "Failed to get store", "error", err)
+		}
+		ctx.JSON(status, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, store)
+}
@@ -0,0 +1,76 @@
+package stores
+
+import (
+	"blockbustermvc/internal/httputil"
+	models "blockbustermvc/internal/models/store"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// StoreScope resolves the store every request acts on: the X-Store-ID header
+// for API clients, then the store picked on the web interface, then the
+// first store in scope. An explicit store that does not exist is rejected
+// rather than silently falling back, so stock is never booked to the wrong
+// branch, and so is one outside the scope set by BLK_STORE_IDS, so a client
+// cannot act as a branch this deployment does not serve.
+func StoreScope(storeService models.IStoreService) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if header := ctx.GetHeader(httputil.StoreHeader); header != "" {
+			storeId, err := uuid.Parse(header)
+			if err != nil {
+				ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+					"error": "Invalid store ID",
+				})
+				return
+			}
+
+			if _, err := storeService.GetScopedStore(ctx.Request.Context(), storeId); err != nil {
+				status := http.StatusInternalServerError
+				switch {
+				case errors.Is(err, models.ErrStoreNotFound):
+					status = http.StatusBadRequest
+				case errors.Is(err, models.ErrStoreOutOfScope):
+					status = http.StatusForbidden
+				}
+
+				if status == http.StatusInternalServerError {
+					slog.ErrorContext(ctx.Request.Context(), "Failed to resolve the store scope", "error", err)
+				}
+				ctx.AbortWithStatusJSON(status, gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+
+			httputil.SetStoreID(ctx, storeId)
+			ctx.Next()
+			return
+		}
+
+		if cookie, err := ctx.Cookie(httputil.StoreCookie); err == nil {
+			if storeId, err := uuid.Parse(cookie); err == nil {
+				if _, err := storeService.GetScopedStore(ctx.Request.Context(), storeId); err == nil {
+					httputil.SetStoreID(ctx, storeId)
+					ctx.Next()
+					return
+				}
+			}
+		}
+
+		stores, err := storeService.GetScopeStores(ctx.Request.Context())
+		if err != nil {
+			slog.ErrorContext(ctx.Request.Context(), "Failed to get the stores in scope", "error", err)
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		httputil.SetStoreID(ctx, stores[0].ID)
+		ctx.Next()
+	}
+}
@@ -0,0 +1,96 @@
+package stores
+
+import (
+	"blockbustermvc/internal/httputil"
+	models "blockbustermvc/internal/models/store"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type fakeStoreRepository struct {
+	models.IStoreRepository
+	stores map[uuid.UUID]*models.StoreDTO
+}
+
+func (f *fakeStoreRepository) GetStoreById(_ context.Context, id uuid.UUID) (*models.StoreDTO, error) {
+	store, ok := f.stores[id]
+	if !ok {
+		return nil, models.ErrStoreNotFound
+	}
+	return store, nil
+}
+
+func (f *fakeStoreRepository) GetDefaultStore(_ context.Context) (*models.StoreDTO, error) {
+	for _, store := range f.stores {
+		if store.IsDefault {
+			return store, nil
+		}
+	}
+	return nil, models.ErrStoreNotFound
+}
+
+func TestStoreScope(t *testing.T) {
+	main := &models.StoreDTO{ID: uuid.New(), IsDefault: true}
+	branch := &models.StoreDTO{ID: uuid.New()}
+	other := &models.StoreDTO{ID: uuid.New()}
+	repo := &fakeStoreRepository{
+		stores: map[uuid.UUID]*models.StoreDTO{main.ID: main, branch.ID: branch, other.ID: other},
+	}
+
+	tests := []struct {
+		name       string
+		scope      []uuid.UUID
+		header     string
+		cookie     string
+		wantStatus int
+		wantStore  uuid.UUID
+	}{
+		{name: "default store", wantStatus: http.StatusOK, wantStore: main.ID},
+		{name: "header", scope: []uuid.UUID{main.ID, branch.ID}, header: branch.ID.String(), wantStatus: http.StatusOK, wantStore: branch.ID},
+		{name: "cookie", scope: []uuid.UUID{main.ID, branch.ID}, cookie: branch.ID.String(), wantStatus: http.StatusOK, wantStore: branch.ID},
+		{name: "header wins over cookie", scope: []uuid.UUID{main.ID, branch.ID}, header: main.ID.String(), cookie: branch.ID.String(), wantStatus: http.StatusOK, wantStore: main.ID},
+		{name: "malformed header", header: "branch", wantStatus: http.StatusBadRequest},
+		{name: "unknown store in header", header: uuid.NewString(), wantStatus: http.StatusBadRequest},
+		{name: "unknown store in cookie falls back", cookie: uuid.NewString(), wantStatus: http.StatusOK, wantStore: main.ID},
+		{name: "first store in scope serves requests without a store", scope: []uuid.UUID{branch.ID}, wantStatus: http.StatusOK, wantStore: branch.ID},
+		{name: "header outside the default scope", header: branch.ID.String(), wantStatus: http.StatusForbidden},
+		{name: "header outside the scope", scope: []uuid.UUID{main.ID, branch.ID}, header: other.ID.String(), wantStatus: http.StatusForbidden},
+		{name: "default store outside the scope", scope: []uuid.UUID{branch.ID}, header: main.ID.String(), wantStatus: http.StatusForbidden},
+		{name: "cookie outside the scope falls back", scope: []uuid.UUID{main.ID, branch.ID}, cookie: other.ID.String(), wantStatus: http.StatusOK, wantStore: main.ID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.Use(StoreScope(NewStoreService(repo, tt.scope)))
+
+			var scoped uuid.UUID
+			router.GET("/", func(ctx *gin.Context) {
+				scoped = httputil.StoreID(ctx)
+			})
+
+			request := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				request.Header.Set(httputil.StoreHeader, tt.header)
+			}
+			if tt.cookie != "" {
+				request.AddCookie(&http.Cookie{Name: httputil.StoreCookie, Value: tt.cookie})
+			}
+			response := httptest.NewRecorder()
+			router.ServeHTTP(response, request)
+
+			if response.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", response.Code, tt.wantStatus)
+			}
+			if scoped != tt.wantStore {
+				t.Errorf("store = %s, want %s", scoped, tt.wantStore)
+			}
+		})
+	}
+}
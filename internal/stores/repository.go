@@ -0,0 +1,195 @@
+package stores
+
+import (
+	models "blockbustermvc/internal/models/store"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+/*
+storeRepository is a struct that represents a Postgres database for storing store objects.
+
+Fields:
+- DB (*pgxpool.Pool): A pointer to a Postgres database connection pool.
+
+Behavior:
+- Provides methods for interacting with the stores table in the database.
+*/
+type storeRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewStoreRepository(db *pgxpool.Pool) models.IStoreRepository {
+	return &storeRepository{
+		DB: db,
+	}
+}
+
+/*
+CreateStore is a method of storeRepository struct that creates a new store object in the postgres database.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- store (*models.CreateStoreDTO): A pointer to a CreateStoreDTO struct containing the store data to be created.
+
+Returns:
+- (*models.StoreDTO, error): A pointer to a StoreDTO struct containing the persisted store, or an error if the store creation fails.
+
+Behavior:
+- Inserts a new store into the stores table in the database, storing its code in upper case.
+- New stores are never the default store.
+- Returns an error if the store creation fails, e.g. when the code is already taken.
+*/
+func (r *storeRepository) CreateStore(ctx context.Context, store *models.CreateStoreDTO) (*models.StoreDTO, error) {
+	query := `
+		INSERT INTO stores (code, name, address, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, code, name, address, is_default, created_at, updated_at`
+
+	now := time.Now()
+
+	var created models.StoreDTO
+	err := r.DB.QueryRow(ctx, query,
+		strings.ToUpper(store.Code),
+		store.Name,
+		store.Address,
+		now,
+		now,
+	).Scan(
+		&created.ID,
+		&created.Code,
+		&created.Name,
+		&created.Address,
+		&created.IsDefault,
+		&created.CreatedAt,
+		&created.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create store: %w", err)
+	}
+
+	return &created, nil
+}
+
+/*
+GetStoreById is a method of storeRepository struct that retrieves a store object from the postgres database by its ID.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- id (uuid.UUID): The ID of the store to be retrieved.
+
+Returns:
+- (*models.StoreDTO, error): A pointer to a StoreDTO struct containing the store data, or an error if the store retrieval fails.
+
+Behavior:
+- Retrieves a store from the stores table in the database by its ID.
+- Returns models.ErrStoreNotFound if no store has that ID.
+*/
+func (r *storeRepository) GetStoreById(ctx context.Context, id uuid.UUID) (*models.StoreDTO, error) {
+	query := `
+		SELECT id, code, name, address, is_default, created_at, updated_at
+		FROM stores
+		WHERE id = $1`
+
+	return r.getStore(ctx, query, id)
+}
+
+/*
+GetDefaultStore is a method of storeRepository struct that retrieves the default store from the postgres database.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+
+Returns:
+- (*models.StoreDTO, error): A pointer to a StoreDTO struct containing the default store, or an error if the retrieval fails.
+
+Behavior:
+- Retrieves the store flagged as default, which serves requests that do not pick a store.
+- Returns models.ErrStoreNotFound if no store is flagged as default.
+*/
+func (r *storeRepository) GetDefaultStore(ctx context.Context) (*models.StoreDTO, error) {
+	query := `
+		SELECT id, code, name, address, is_default, created_at, updated_at
+		FROM stores
+		WHERE is_default`
+
+	return r.getStore(ctx, query)
+}
+
+/*
+GetAllStores is a method of storeRepository struct that retrieves all store objects from the postgres database.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+
+Returns:
+- ([]*models.StoreDTO, error): A slice of pointers to StoreDTO structs, default store first, or an error if the retrieval fails.
+
+Behavior:
+- Retrieves all stores from the stores table in the database.
+- Returns an error if the retrieval fails.
+*/
+func (r *storeRepository) GetAllStores(ctx context.Context) ([]*models.StoreDTO, error) {
+	query := `
+		SELECT id, code, name, address, is_default, created_at, updated_at
+		FROM stores
+		ORDER BY is_default DESC, name`
+
+	rows, err := r.DB.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get all stores: %w", err)
+	}
+	defer rows.Close()
+
+	var stores []*models.StoreDTO
+	for rows.Next() {
+		var store models.StoreDTO
+		err := rows.Scan(
+			&store.ID,
+			&store.Code,
+			&store.Name,
+			&store.Address,
+			&store.IsDefault,
+			&store.CreatedAt,
+			&store.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan store: %w", err)
+		}
+		stores = append(stores, &store)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over stores: %w", err)
+	}
+
+	return stores, nil
+}
+
+func (r *storeRepository) getStore(ctx context.Context, query string, args ...any) (*models.StoreDTO, error) {
+	var store models.StoreDTO
+	err := r.DB.QueryRow(ctx, query, args...).Scan(
+		&store.ID,
+		&store.Code,
+		&store.Name,
+		&store.Address,
+		&store.IsDefault,
+		&store.CreatedAt,
+		&store.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, models.ErrStoreNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get store: %w", err)
+	}
+
+	return &store, nil
+}
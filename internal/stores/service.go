@@ -0,0 +1,87 @@
+package stores
+
+import (
+	models "blockbustermvc/internal/models/store"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type StoreService struct {
+	storeRepository models.IStoreRepository
+	// scope lists the stores requests may act on, the first one serving the
+	// requests that pick none. Empty scopes requests to the default store.
+	scope []uuid.UUID
+}
+
+func NewStoreService(storeRepo models.IStoreRepository, scope []uuid.UUID) models.IStoreService {
+	return &StoreService{
+		storeRepository: storeRepo,
+		scope:           scope,
+	}
+}
+
+func (s StoreService) CreateStore(ctx context.Context, store *models.CreateStoreDTO) (*models.StoreDTO, error) {
+	store.Code = strings.TrimSpace(store.Code)
+	store.Name = strings.TrimSpace(store.Name)
+	store.Address = strings.TrimSpace(store.Address)
+
+	return s.storeRepository.CreateStore(ctx, store)
+}
+
+func (s StoreService) GetStore(ctx context.Context, id uuid.UUID) (*models.StoreDTO, error) {
+	return s.storeRepository.GetStoreById(ctx, id)
+}
+
+func (s StoreService) GetDefaultStore(ctx context.Context) (*models.StoreDTO, error) {
+	return s.storeRepository.GetDefaultStore(ctx)
+}
+
+func (s StoreService) GetAllStores(ctx context.Context) ([]*models.StoreDTO, error) {
+	return s.storeRepository.GetAllStores(ctx)
+}
+
+// GetScopedStore returns the store with the given ID if requests may act on
+// it, and models.ErrStoreOutOfScope if the store exists but is not served here.
+func (s StoreService) GetScopedStore(ctx context.Context, id uuid.UUID) (*models.StoreDTO, error) {
+	store, err := s.storeRepository.GetStoreById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	inScope := store.IsDefault
+	if len(s.scope) > 0 {
+		inScope = slices.Contains(s.scope, id)
+	}
+	if !inScope {
+		return nil, models.ErrStoreOutOfScope
+	}
+
+	return store, nil
+}
+
+// GetScopeStores returns the stores requests may act on, in the configured
+// order, so the first one is where requests that pick no store go.
+func (s StoreService) GetScopeStores(ctx context.Context) ([]*models.StoreDTO, error) {
+	if len(s.scope) == 0 {
+		store, err := s.storeRepository.GetDefaultStore(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return []*models.StoreDTO{store}, nil
+	}
+
+	stores := make([]*models.StoreDTO, 0, len(s.scope))
+	for _, id := range s.scope {
+		store, err := s.storeRepository.GetStoreById(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get scoped store %s: %w", id, err)
+		}
+		stores = append(stores, store)
+	}
+
+	return stores, nil
+}
@@ -0,0 +1,166 @@
+package transfers
+
+import (
+	"blockbustermvc/internal/httputil"
+	inventoryModels "blockbustermvc/internal/models/inventory"
+	models "blockbustermvc/internal/models/transfer"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type TransfersController struct {
+	transferService models.ITransferService
+}
+
+func NewTransfersController(transferService models.ITransferService) *TransfersController {
+	return &TransfersController{
+		transferService: transferService,
+	}
+}
+
+func (tc *TransfersController) RegisterRoutes(r *gin.RouterGroup) {
+	transfers := r.Group("/transfers")
+
+	{
+		transfers.POST("", tc.RequestTransfer)
+		transfers.GET("", tc.GetStoreTransfers)
+		transfers.GET("/:id", tc.GetTransfer)
+		transfers.POST("/:id/ship", tc.ShipTransfer)
+		transfers.POST("/:id/receive", tc.ReceiveTransfer)
+		transfers.POST("/:id/cancel", tc.CancelTransfer)
+	}
+}
+
+func (tc *TransfersController) RequestTransfer(ctx *gin.Context) {
+	var transfer models.CreateTransferDTO
+	if err := ctx.ShouldBindJSON(&transfer); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body",
+		})
+		return
+	}
+
+	transfer.ToStoreID = httputil.StoreID(ctx)
+
+	created, err := tc.transferService.RequestTransfer(ctx.Request.Context(), &transfer)
+	if err != nil {
+		respondWithTransferError(ctx, err)
+		return
+	}
+
+	ctx.Header("Location", ctx.FullPath()+"/"+created.ID.String())
+	ctx.JSON(http.StatusCreated, created)
+}
+
+func (tc *TransfersController) GetStoreTransfers(ctx *gin.Context) {
+	storeId := httputil.StoreID(ctx)
+
+	var transfers []*models.TransferDTO
+	var err error
+	if ctx.Query("status") == "pending" {
+		transfers, err = tc.transferService.GetPendingTransfers(ctx.Request.Context(), storeId)
+	} else {
+		transfers, err = tc.transferService.GetStoreTransfers(ctx.Request.Context(), storeId)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx.Request.Context(), "Failed to get store transfers", "error", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, transfers)
+}
+
+func (tc *TransfersController) GetTransfer(ctx *gin.Context) {
+	id, ok := parseTransferID(ctx)
+	if !ok {
+		return
+	}
+
+	transfer, err := tc.transferService.GetTransfer(ctx.Request.Context(), id)
+	if err != nil {
+		respondWithTransferError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, transfer)
+}
+
+func (tc *TransfersController) ShipTransfer(ctx *gin.Context) {
+	id, ok := parseTransferID(ctx)
+	if !ok {
+		return
+	}
+
+	transfer, err := tc.transferService.ShipTransfer(ctx.Request.Context(), httputil.StoreID(ctx), id)
+	if err != nil {
+		respondWithTransferError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, transfer)
+}
+
+func (tc *TransfersController) ReceiveTransfer(ctx *gin.Context) {
+	id, ok := parseTransferID(ctx)
+	if !ok {
+		return
+	}
+
+	transfer, err := tc.transferService.ReceiveTransfer(ctx.Request.Context(), httputil.StoreID(ctx), id)
+	if err != nil {
+		respondWithTransferError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, transfer)
+}
+
+func (tc *TransfersController) CancelTransfer(ctx *gin.Context) {
+	id, ok := parseTransferID(ctx)
+	if !ok {
+		return
+	}
+
+	transfer, err := tc.transferService.CancelTransfer(ctx.Request.Context(), httputil.StoreID(ctx), id)
+	if err != nil {
+		respondWithTransferError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, transfer)
+}
+
+func parseTransferID(ctx *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid transfer ID",
+		})
+		return uuid.Nil, false
+	}
+
+	return id, true
+}
+
+func respondWithTransferError(ctx *gin.Context, err error) {
+	status := http.StatusUnprocessableEntity
+	switch {
+	case errors.Is(err, models.ErrTransferNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, models.ErrWrongStore):
+		status = http.StatusForbidden
+	case errors.Is(err, models.ErrInvalidTransition), errors.Is(err, inventoryModels.ErrInsufficientStock):
+		status = http.StatusConflict
+	}
+
+	ctx.JSON(status, gin.H{
+		"error": err.Error(),
+	})
+}
@@ -0,0 +1,299 @@
+package transfers
+
+import (
+	"blockbustermvc/internal/inventory"
+	inventoryModels "blockbustermvc/internal/models/inventory"
+	models "blockbustermvc/internal/models/transfer"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const selectTransfer = `
+	SELECT t.id, t.movie_id, m.name, t.from_store_id, fs.name, t.to_store_id, ts.name,
+		t.quantity, t.status, t.note, t.requested_at, t.shipped_at, t.received_at, t.created_at, t.updated_at
+	FROM stock_transfers t
+	JOIN movies m ON m.id = t.movie_id
+	JOIN stores fs ON fs.id = t.from_store_id
+	JOIN stores ts ON ts.id = t.to_store_id`
+
+/*
+transferRepository is a struct that represents a Postgres database for storing stock transfers between stores.
+
+Fields:
+- DB (*pgxpool.Pool): A pointer to a Postgres database connection pool.
+
+Behavior:
+- Provides methods for interacting with the stock_transfers table in the database.
+- Moves store stock and records the ledger movements when a transfer is received.
+*/
+type transferRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewTransferRepository(db *pgxpool.Pool) models.ITransferRepository {
+	return &transferRepository{
+		DB: db,
+	}
+}
+
+/*
+CreateTransfer is a method of transferRepository struct that records a transfer request in the postgres database.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- transfer (*models.CreateTransferDTO): A pointer to a CreateTransferDTO struct containing the movie, stores and quantity.
+
+Returns:
+- (*models.TransferDTO, error): A pointer to a TransferDTO struct containing the persisted transfer, or an error if the creation fails.
+
+Behavior:
+- Inserts a new requested transfer into the stock_transfers table; no stock moves yet.
+- Returns an error if the creation fails.
+*/
+func (r *transferRepository) CreateTransfer(ctx context.Context, transfer *models.CreateTransferDTO) (*models.TransferDTO, error) {
+	query := `
+		INSERT INTO stock_transfers (movie_id, from_store_id, to_store_id, quantity, status, note, requested_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $7, $7)
+		RETURNING id`
+
+	var id uuid.UUID
+	err := r.DB.QueryRow(ctx, query,
+		transfer.MovieID,
+		transfer.FromStoreID,
+		transfer.ToStoreID,
+		transfer.Quantity,
+		models.StatusRequested,
+		transfer.Note,
+		time.Now(),
+	).Scan(&id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transfer: %w", err)
+	}
+
+	return r.GetTransfer(ctx, id)
+}
+
+/*
+GetTransfer is a method of transferRepository struct that retrieves a transfer from the postgres database by its ID.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- id (uuid.UUID): The ID of the transfer to be retrieved.
+
+Returns:
+- (*models.TransferDTO, error): A pointer to a TransferDTO struct containing the transfer with movie and store names, or an error if the retrieval fails.
+
+Behavior:
+- Retrieves a transfer from the stock_transfers table in the database by its ID.
+- Returns models.ErrTransferNotFound if no transfer has that ID.
+*/
+func (r *transferRepository) GetTransfer(ctx context.Context, id uuid.UUID) (*models.TransferDTO, error) {
+	transfer, err := scanTransfer(r.DB.QueryRow(ctx, selectTransfer+` WHERE t.id = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, models.ErrTransferNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transfer: %w", err)
+	}
+
+	return transfer, nil
+}
+
+/*
+GetStoreTransfers is a method of transferRepository struct that retrieves the transfers into or out of a store.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- storeId (uuid.UUID): The ID of the store.
+- pendingOnly (bool): Whether to leave out received and cancelled transfers.
+
+Returns:
+- ([]*models.TransferDTO, error): A slice of pointers to TransferDTO structs, newest first, or an error if the retrieval fails.
+
+Behavior:
+- Retrieves every transfer where the store is the sender or the receiver.
+- Returns an error if the retrieval fails.
+*/
+func (r *transferRepository) GetStoreTransfers(ctx context.Context, storeId uuid.UUID, pendingOnly bool) ([]*models.TransferDTO, error) {
+	query := selectTransfer + `
+		WHERE (t.from_store_id = $1 OR t.to_store_id = $1)
+			AND (NOT $2 OR t.status IN ('requested', 'in_transit'))
+		ORDER BY t.requested_at DESC`
+
+	rows, err := r.DB.Query(ctx, query, storeId, pendingOnly)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get store transfers: %w", err)
+	}
+	defer rows.Close()
+
+	var transfers []*models.TransferDTO
+	for rows.Next() {
+		transfer, err := scanTransfer(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan transfer: %w", err)
+		}
+		transfers = append(transfers, transfer)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over transfers: %w", err)
+	}
+
+	return transfers, nil
+}
+
+/*
+UpdateTransferStatus is a method of transferRepository struct that moves a transfer from one status to another.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- id (uuid.UUID): The ID of the transfer.
+- from (string): The status the transfer must currently have.
+- to (string): The new status of the transfer.
+
+Returns:
+- (*models.TransferDTO, error): A pointer to a TransferDTO struct containing the updated transfer, or an error if the update fails.
+
+Behavior:
+- Updates the status only if the transfer still has the expected status, so concurrent actions cannot both apply.
+- Stamps shipped_at when the transfer goes in transit.
+- Returns models.ErrInvalidTransition if the transfer no longer has the expected status.
+- Returns models.ErrTransferNotFound if no transfer has that ID.
+*/
+func (r *transferRepository) UpdateTransferStatus(ctx context.Context, id uuid.UUID, from, to string) (*models.TransferDTO, error) {
+	query := `
+		UPDATE stock_transfers
+		SET status = $3,
+			shipped_at = CASE WHEN $3 = 'in_transit' THEN $4 ELSE shipped_at END,
+			updated_at = $4
+		WHERE id = $1 AND status = $2`
+
+	result, err := r.DB.Exec(ctx, query, id, from, to, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to update transfer: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		if _, err := r.GetTransfer(ctx, id); err != nil {
+			return nil, err
+		}
+		return nil, models.ErrInvalidTransition
+	}
+
+	return r.GetTransfer(ctx, id)
+}
+
+/*
+ReceiveTransfer is a method of transferRepository struct that books an in-transit transfer into the receiving store.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- id (uuid.UUID): The ID of the transfer to receive.
+
+Returns:
+- (*models.TransferDTO, error): A pointer to a TransferDTO struct containing the received transfer, or an error if receiving fails.
+
+Behavior:
+- Runs in a single transaction so the copies leave the sending store and arrive at the receiving store together.
+- Records a transfer_out and a transfer_in movement through inventory.ApplyMovement, each with its stock.changed event, and marks the transfer as received.
+- Leaves movies.quantity unchanged once both movements are applied, since the copies stay within the chain.
+- Returns inventoryModels.ErrInsufficientStock if the sending store no longer has enough copies.
+- Returns models.ErrInvalidTransition if the transfer is not in transit.
+*/
+func (r *transferRepository) ReceiveTransfer(ctx context.Context, id uuid.UUID) (*models.TransferDTO, error) {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transfer receipt: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var movieId, fromStoreId, toStoreId uuid.UUID
+	var quantity int64
+	var status string
+	err = tx.QueryRow(ctx, `
+		SELECT movie_id, from_store_id, to_store_id, quantity, status
+		FROM stock_transfers
+		WHERE id = $1
+		FOR UPDATE`, id).Scan(&movieId, &fromStoreId, &toStoreId, &quantity, &status)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, models.ErrTransferNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock transfer: %w", err)
+	}
+
+	if status != models.StatusInTransit {
+		return nil, models.ErrInvalidTransition
+	}
+
+	// The copies leave one store and arrive at the other, so the chain's
+	// stock is the same once both movements are applied
+	out := &inventoryModels.CreateMovementDTO{
+		Reason:        inventoryModels.ReasonTransferOut,
+		QuantityDelta: -quantity,
+		Note:          "Stock transfer",
+		StoreID:       fromStoreId,
+		TransferID:    &id,
+	}
+	if _, err := inventory.ApplyMovement(ctx, tx, movieId, out); err != nil {
+		return nil, err
+	}
+
+	in := &inventoryModels.CreateMovementDTO{
+		Reason:        inventoryModels.ReasonTransferIn,
+		QuantityDelta: quantity,
+		Note:          "Stock transfer",
+		StoreID:       toStoreId,
+		TransferID:    &id,
+	}
+	if _, err := inventory.ApplyMovement(ctx, tx, movieId, in); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	_, err = tx.Exec(ctx, `
+		UPDATE stock_transfers
+		SET status = $2, received_at = $3, updated_at = $3
+		WHERE id = $1`, id, models.StatusReceived, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update transfer: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transfer receipt: %w", err)
+	}
+
+	return r.GetTransfer(ctx, id)
+}
+
+func scanTransfer(row pgx.Row) (*models.TransferDTO, error) {
+	var transfer models.TransferDTO
+	err := row.Scan(
+		&transfer.ID,
+		&transfer.MovieID,
+		&transfer.MovieName,
+		&transfer.FromStoreID,
+		&transfer.FromStoreName,
+		&transfer.ToStoreID,
+		&transfer.ToStoreName,
+		&transfer.Quantity,
+		&transfer.Status,
+		&transfer.Note,
+		&transfer.RequestedAt,
+		&transfer.ShippedAt,
+		&transfer.ReceivedAt,
+		&transfer.CreatedAt,
+		&transfer.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &transfer, nil
+}
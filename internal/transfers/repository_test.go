@@ -0,0 +1,58 @@
+package transfers
+
+import (
+	"blockbustermvc/internal/database/dbtest"
+	"blockbustermvc/internal/inventory"
+	eventModels "blockbustermvc/internal/models/event"
+	inventoryModels "blockbustermvc/internal/models/inventory"
+	models "blockbustermvc/internal/models/transfer"
+	"testing"
+)
+
+func TestReceiveTransferMovesStockThroughTheLedger(t *testing.T) {
+	pool := dbtest.Open(t)
+	ctx := t.Context()
+	repo := NewTransferRepository(pool)
+
+	from := dbtest.Store(t, pool)
+	to := dbtest.Store(t, pool)
+	movieId := dbtest.Movie(t, pool)
+
+	_, err := inventory.NewInventoryRepository(pool).CreateMovement(ctx, movieId, &inventoryModels.CreateMovementDTO{
+		Reason:        inventoryModels.ReasonPurchase,
+		QuantityDelta: 5,
+		StoreID:       from,
+	})
+	if err != nil {
+		t.Fatalf("CreateMovement() error = %v", err)
+	}
+
+	transfer, err := repo.CreateTransfer(ctx, &models.CreateTransferDTO{MovieID: movieId, FromStoreID: from, ToStoreID: to, Quantity: 2})
+	if err != nil {
+		t.Fatalf("CreateTransfer() error = %v", err)
+	}
+	if _, err := repo.UpdateTransferStatus(ctx, transfer.ID, models.StatusRequested, models.StatusInTransit); err != nil {
+		t.Fatalf("UpdateTransferStatus() error = %v", err)
+	}
+	if _, err := repo.ReceiveTransfer(ctx, transfer.ID); err != nil {
+		t.Fatalf("ReceiveTransfer() error = %v", err)
+	}
+
+	stock := `SELECT quantity FROM store_stock WHERE store_id = $1 AND movie_id = $2`
+	if got := dbtest.Int(t, pool, stock, from, movieId); got != 3 {
+		t.Errorf("sending store stock = %d, want 3", got)
+	}
+	if got := dbtest.Int(t, pool, stock, to, movieId); got != 2 {
+		t.Errorf("receiving store stock = %d, want 2", got)
+	}
+	if got := dbtest.Int(t, pool, `SELECT quantity FROM movies WHERE id = $1`, movieId); got != 5 {
+		t.Errorf("movies.quantity = %d, want 5", got)
+	}
+	if got := dbtest.Int(t, pool, `SELECT COUNT(*) FROM inventory_movements WHERE transfer_id = $1`, transfer.ID); got != 2 {
+		t.Errorf("transfer movements = %d, want 2", got)
+	}
+	// The purchase and both legs of the transfer
+	if got := dbtest.Int(t, pool, `SELECT COUNT(*) FROM outbox_events WHERE aggregate_id = $1 AND type = $2`, movieId, eventModels.TypeStockChanged); got != 3 {
+		t.Errorf("stock.changed events = %d, want 3", got)
+	}
+}
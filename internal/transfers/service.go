@@ -0,0 +1,120 @@
+package transfers
+
+import (
+	inventoryModels "blockbustermvc/internal/models/inventory"
+	movieService "blockbustermvc/internal/models/movie"
+	storeService "blockbustermvc/internal/models/store"
+	models "blockbustermvc/internal/models/transfer"
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type TransferService struct {
+	transferRepository models.ITransferRepository
+	movieService       movieService.IMovieService
+	storeService       storeService.IStoreService
+}
+
+func NewTransferService(
+	transferRepo models.ITransferRepository,
+	movieService movieService.IMovieService,
+	storeService storeService.IStoreService,
+) models.ITransferService {
+	return &TransferService{
+		transferRepository: transferRepo,
+		movieService:       movieService,
+		storeService:       storeService,
+	}
+}
+
+func (t TransferService) RequestTransfer(ctx context.Context, transfer *models.CreateTransferDTO) (*models.TransferDTO, error) {
+	if transfer.FromStoreID == transfer.ToStoreID {
+		return nil, errors.New("cannot transfer stock to the same store")
+	}
+
+	if _, err := t.storeService.GetStore(ctx, transfer.FromStoreID); err != nil {
+		return nil, err
+	}
+
+	if err := t.checkStock(ctx, transfer.FromStoreID, transfer.MovieID, transfer.Quantity); err != nil {
+		return nil, err
+	}
+
+	transfer.Note = strings.TrimSpace(transfer.Note)
+
+	return t.transferRepository.CreateTransfer(ctx, transfer)
+}
+
+func (t TransferService) GetTransfer(ctx context.Context, id uuid.UUID) (*models.TransferDTO, error) {
+	return t.transferRepository.GetTransfer(ctx, id)
+}
+
+func (t TransferService) GetStoreTransfers(ctx context.Context, storeId uuid.UUID) ([]*models.TransferDTO, error) {
+	return t.transferRepository.GetStoreTransfers(ctx, storeId, false)
+}
+
+func (t TransferService) GetPendingTransfers(ctx context.Context, storeId uuid.UUID) ([]*models.TransferDTO, error) {
+	return t.transferRepository.GetStoreTransfers(ctx, storeId, true)
+}
+
+func (t TransferService) ShipTransfer(ctx context.Context, storeId, id uuid.UUID) (*models.TransferDTO, error) {
+	transfer, err := t.transferRepository.GetTransfer(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if transfer.FromStoreID != storeId {
+		return nil, models.ErrWrongStore
+	}
+
+	if err := t.checkStock(ctx, transfer.FromStoreID, transfer.MovieID, transfer.Quantity); err != nil {
+		return nil, err
+	}
+
+	return t.transferRepository.UpdateTransferStatus(ctx, id, models.StatusRequested, models.StatusInTransit)
+}
+
+func (t TransferService) ReceiveTransfer(ctx context.Context, storeId, id uuid.UUID) (*models.TransferDTO, error) {
+	transfer, err := t.transferRepository.GetTransfer(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if transfer.ToStoreID != storeId {
+		return nil, models.ErrWrongStore
+	}
+
+	return t.transferRepository.ReceiveTransfer(ctx, id)
+}
+
+// CancelTransfer also cancels a shipped transfer, since its stock only moves
+// when it is received. That lets a transfer the sender can no longer cover,
+// which ReceiveTransfer refuses with ErrInsufficientStock, be closed instead
+// of staying in transit.
+func (t TransferService) CancelTransfer(ctx context.Context, storeId, id uuid.UUID) (*models.TransferDTO, error) {
+	transfer, err := t.transferRepository.GetTransfer(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if transfer.FromStoreID != storeId && transfer.ToStoreID != storeId {
+		return nil, models.ErrWrongStore
+	}
+	if transfer.Status != models.StatusRequested && transfer.Status != models.StatusInTransit {
+		return nil, models.ErrInvalidTransition
+	}
+
+	return t.transferRepository.UpdateTransferStatus(ctx, id, transfer.Status, models.StatusCancelled)
+}
+
+func (t TransferService) checkStock(ctx context.Context, storeId, movieId uuid.UUID, quantity int64) error {
+	movie, err := t.movieService.GetStoreMovie(ctx, storeId, movieId)
+	if err != nil {
+		return err
+	}
+	if movie.Quantity < quantity {
+		return inventoryModels.ErrInsufficientStock
+	}
+
+	return nil
+}
@@ -0,0 +1,116 @@
+package transfers
+
+import (
+	inventoryModels "blockbustermvc/internal/models/inventory"
+	models "blockbustermvc/internal/models/transfer"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// fakeTransferRepository keeps one transfer and the sending store's stock,
+// and moves the transfer between statuses the way the conditional updates
+// of the real repository do.
+type fakeTransferRepository struct {
+	models.ITransferRepository
+	transfer *models.TransferDTO
+	stock    int64
+}
+
+func (f *fakeTransferRepository) GetTransfer(_ context.Context, id uuid.UUID) (*models.TransferDTO, error) {
+	if f.transfer.ID != id {
+		return nil, models.ErrTransferNotFound
+	}
+	copied := *f.transfer
+	return &copied, nil
+}
+
+func (f *fakeTransferRepository) UpdateTransferStatus(ctx context.Context, id uuid.UUID, from, to string) (*models.TransferDTO, error) {
+	if f.transfer.Status != from {
+		return nil, models.ErrInvalidTransition
+	}
+	f.transfer.Status = to
+	return f.GetTransfer(ctx, id)
+}
+
+func (f *fakeTransferRepository) ReceiveTransfer(ctx context.Context, id uuid.UUID) (*models.TransferDTO, error) {
+	if f.transfer.Status != models.StatusInTransit {
+		return nil, models.ErrInvalidTransition
+	}
+	if f.stock < f.transfer.Quantity {
+		return nil, inventoryModels.ErrInsufficientStock
+	}
+	return f.UpdateTransferStatus(ctx, id, models.StatusInTransit, models.StatusReceived)
+}
+
+func TestCancelTransfer(t *testing.T) {
+	from := uuid.New()
+	to := uuid.New()
+
+	tests := []struct {
+		name    string
+		status  string
+		storeId uuid.UUID
+		wantErr error
+	}{
+		{name: "requested", status: models.StatusRequested, storeId: from},
+		{name: "in transit, by the receiving store", status: models.StatusInTransit, storeId: to},
+		{name: "received", status: models.StatusReceived, storeId: to, wantErr: models.ErrInvalidTransition},
+		{name: "already cancelled", status: models.StatusCancelled, storeId: from, wantErr: models.ErrInvalidTransition},
+		{name: "another store", status: models.StatusRequested, storeId: uuid.New(), wantErr: models.ErrWrongStore},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transfer := &models.TransferDTO{ID: uuid.New(), FromStoreID: from, ToStoreID: to, Quantity: 2, Status: tt.status}
+			repo := &fakeTransferRepository{transfer: transfer}
+			service := NewTransferService(repo, nil, nil)
+
+			cancelled, err := service.CancelTransfer(t.Context(), tt.storeId, transfer.ID)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("CancelTransfer() error = %v, want %v", err, tt.wantErr)
+				}
+				if repo.transfer.Status != tt.status {
+					t.Errorf("status = %q, want it left at %q", repo.transfer.Status, tt.status)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("CancelTransfer() error = %v", err)
+			}
+			if cancelled.Status != models.StatusCancelled {
+				t.Errorf("status = %q, want %q", cancelled.Status, models.StatusCancelled)
+			}
+		})
+	}
+}
+
+func TestTransferTheSenderCannotCoverCanBeCancelled(t *testing.T) {
+	from := uuid.New()
+	to := uuid.New()
+
+	// Shipped while the sender had the copies, which were lent out before it arrived
+	transfer := &models.TransferDTO{ID: uuid.New(), FromStoreID: from, ToStoreID: to, Quantity: 2, Status: models.StatusInTransit}
+	repo := &fakeTransferRepository{transfer: transfer, stock: 1}
+	service := NewTransferService(repo, nil, nil)
+
+	if _, err := service.ReceiveTransfer(t.Context(), to, transfer.ID); !errors.Is(err, inventoryModels.ErrInsufficientStock) {
+		t.Fatalf("ReceiveTransfer() error = %v, want %v", err, inventoryModels.ErrInsufficientStock)
+	}
+
+	cancelled, err := service.CancelTransfer(t.Context(), to, transfer.ID)
+	if err != nil {
+		t.Fatalf("CancelTransfer() error = %v", err)
+	}
+	if cancelled.Status != models.StatusCancelled {
+		t.Errorf("status = %q, want %q", cancelled.Status, models.StatusCancelled)
+	}
+
+	if _, err := service.ReceiveTransfer(t.Context(), to, transfer.ID); !errors.Is(err, models.ErrInvalidTransition) {
+		t.Errorf("ReceiveTransfer() after cancelling error = %v, want %v", err, models.ErrInvalidTransition)
+	}
+}
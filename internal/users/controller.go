@@ -1,7 +1,9 @@
 package users
 
 import (
+	"blockbustermvc/internal/httputil"
 	models "blockbustermvc/internal/models/user"
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -26,6 +28,7 @@ func (uc *UserController) RegisterRoutes(r *gin.RouterGroup) {
 		users.GET("/:id", uc.GetUser)
 		users.GET("", uc.GetAllUsers)
 		users.PUT("/:id", uc.UpdateUser)
+		users.PATCH("/:id", uc.PatchUser)
 		users.DELETE("/:id", uc.DeleteUser)
 	}
 }
@@ -40,13 +43,15 @@ func (uc *UserController) CreateUser(ctx *gin.Context) {
 		return
 	}
 
-	err := uc.userService.CreateUser(&user)
+	created, err := uc.userService.CreateUser(ctx.Request.Context(), &user)
 	if err != nil {
 		ctx.JSON(http.StatusUnprocessableEntity, err.Error())
 		return
 	}
 
-	ctx.JSON(http.StatusCreated, user)
+	ctx.Header("Location", ctx.FullPath()+"/"+created.ID.String())
+	ctx.Header("ETag", httputil.ETag(created.Version))
+	ctx.JSON(http.StatusCreated, created)
 }
 
 func (uc *UserController) GetUser(ctx *gin.Context) {
@@ -65,7 +70,7 @@ func (uc *UserController) GetUser(ctx *gin.Context) {
 		return
 	}
 
-	user, err := uc.userService.GetUser(id)
+	user, err := uc.userService.GetUser(ctx.Request.Context(), id)
 	if err != nil {
 		ctx.JSON(http.StatusUnprocessableEntity, gin.H{
 			"error": "Error while finding user",
@@ -73,11 +78,12 @@ func (uc *UserController) GetUser(ctx *gin.Context) {
 		return
 	}
 
+	ctx.Header("ETag", httputil.ETag(user.Version))
 	ctx.JSON(http.StatusOK, user)
 }
 
 func (uc *UserController) GetAllUsers(ctx *gin.Context) {
-	users, err := uc.userService.GetAllUsers()
+	users, err := uc.userService.GetAllUsers(ctx.Request.Context())
 	if err != nil {
 		ctx.JSON(http.StatusUnprocessableEntity, gin.H{
 			"error": err.Error(),
@@ -113,15 +119,95 @@ func (uc *UserController) UpdateUser(ctx *gin.Context) {
 		return
 	}
 
-	err = uc.userService.UpdateUser(id, &user)
+	user.Version, err = httputil.RequiredVersion(ctx, user.Version)
 	if err != nil {
+		ctx.JSON(versionErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	updated, err := uc.userService.UpdateUser(ctx.Request.Context(), id, &user)
+	if err != nil {
+		if errors.Is(err, models.ErrVersionConflict) {
+			ctx.JSON(http.StatusPreconditionFailed, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
 		ctx.JSON(http.StatusUnprocessableEntity, gin.H{
 			"error": err.Error(),
 		})
 		return
 	}
 
-	ctx.JSON(http.StatusOK, nil)
+	ctx.Header("ETag", httputil.ETag(updated.Version))
+	ctx.JSON(http.StatusOK, updated)
+}
+
+func (uc *UserController) PatchUser(ctx *gin.Context) {
+	if err := uuid.Validate(ctx.Param("id")); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid user id",
+		})
+		return
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	var patch models.PatchUserDTO
+	if err := httputil.BindMergePatch(ctx, &patch); err != nil {
+		var fieldErrors httputil.FieldErrors
+		switch {
+		case errors.As(err, &fieldErrors):
+			ctx.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":  "Invalid user patch",
+				"fields": fieldErrors,
+			})
+		case errors.Is(err, httputil.ErrUnsupportedMediaType):
+			ctx.JSON(http.StatusUnsupportedMediaType, gin.H{
+				"error": err.Error(),
+			})
+		default:
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid request body",
+			})
+		}
+		return
+	}
+
+	patch.Version, err = httputil.RequiredVersion(ctx, 0)
+	if err != nil {
+		ctx.JSON(versionErrorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	user, err := uc.userService.PatchUser(ctx.Request.Context(), id, &patch)
+	if err != nil {
+		if errors.Is(err, models.ErrVersionConflict) {
+			ctx.JSON(http.StatusPreconditionFailed, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.Header("ETag", httputil.ETag(user.Version))
+	ctx.JSON(http.StatusOK, user)
 }
 
 func (uc *UserController) DeleteUser(ctx *gin.Context) {
@@ -139,7 +225,7 @@ func (uc *UserController) DeleteUser(ctx *gin.Context) {
 		return
 	}
 
-	err = uc.userService.DeleteUser(id)
+	err = uc.userService.DeleteUser(ctx.Request.Context(), id)
 	if err != nil {
 		ctx.JSON(http.StatusUnprocessableEntity, gin.H{
 			"error": err.Error(),
@@ -149,3 +235,12 @@ func (uc *UserController) DeleteUser(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, nil)
 }
+
+// versionErrorStatus answers 428 to updates that sent no version, and 400 to
+// the ones that sent an unreadable one.
+func versionErrorStatus(err error) int {
+	if errors.Is(err, httputil.ErrPreconditionRequired) {
+		return http.StatusPreconditionRequired
+	}
+	return http.StatusBadRequest
+}
@@ -1,12 +1,17 @@
 package users
 
 import (
+	eventModels "blockbustermvc/internal/models/event"
 	models "blockbustermvc/internal/models/user"
+	"blockbustermvc/internal/outbox"
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -32,35 +37,68 @@ func NewUserRepository(db *pgxpool.Pool) models.IUserRepository {
 CreateUser is a method of userRepository struct that creates a new user object in the postgres database.
 
 Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
 - user (*models.CreateUserDTO): A pointer to a CreateUserDTO struct containing the user data to be created.
 
 Returns:
-- error: An error if the user creation fails, otherwise nil.
+- (*models.UserDTO, error): A pointer to a UserDTO struct containing the persisted user, or an error if the user creation fails.
 
 Behavior:
 - Inserts a new user into the users table in the database.
+- Writes a user.registered event to the outbox in the same transaction.
+- Returns the stored row, including the generated ID and timestamps.
 - Returns an error if the user creation fails.
 */
-func (r *userRepository) CreateUser(user *models.CreateUserDTO) error {
+func (r *userRepository) CreateUser(ctx context.Context, user *models.CreateUserDTO) (*models.UserDTO, error) {
 	query := `
 		INSERT INTO users (user_name, email)
-		VALUES ($1, $2)`
+		VALUES ($1, $2)
+		RETURNING id, user_name, email, version, created_at, updated_at`
 
-	_, err := r.DB.Exec(context.Background(), query,
+
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin user creation: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var created models.UserDTO
+	err = tx.QueryRow(ctx, query,
 		user.UserName,
 		user.Email,
+	).Scan(
+		&created.ID,
+		&created.UserName,
+		&created.Email,
+		&created.Version,
+		&created.CreatedAt,
+		&created.UpdatedAt,
 	)
 	if err != nil {
-		return fmt.Errorf("failed to create user: %w", err)
+		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
 
-	return nil
+	err = outbox.Write(ctx, tx, eventModels.TypeUserRegistered, created.ID, &eventModels.UserRegisteredPayload{
+		UserID:   created.ID,
+		UserName: created.UserName,
+		Email:    created.Email,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit user creation: %w", err)
+	}
+
+	return &created, nil
 }
 
 /*
 GetUserById is a method of userRepository struct that retrieves a user object from the postgres database by its ID.
 
 Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
 - id (uuid.UUID): The ID of the user to be retrieved.
 
 Returns:
@@ -70,17 +108,18 @@ Behavior:
 - Retrieves a user from the users table in the database by its ID.
 - Returns an error if the user retrieval fails.
 */
-func (r *userRepository) GetUserById(id uuid.UUID) (*models.UserDTO, error) {
+func (r *userRepository) GetUserById(ctx context.Context, id uuid.UUID) (*models.UserDTO, error) {
 	query := `
-		SELECT id, user_name, email, created_at, updated_at
+		SELECT id, user_name, email, version, created_at, updated_at
 		FROM users
 		WHERE id = $1`
 
 	var user models.UserDTO
-	err := r.DB.QueryRow(context.Background(), query, id).Scan(
+	err := r.DB.QueryRow(ctx, query, id).Scan(
 		&user.ID,
 		&user.UserName,
 		&user.Email,
+		&user.Version,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -95,6 +134,7 @@ func (r *userRepository) GetUserById(id uuid.UUID) (*models.UserDTO, error) {
 GetAllUsers is a method of userRepository struct that retrieves all user objects from the postgres database.
 
 Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
 - None
 
 Returns:
@@ -104,13 +144,13 @@ Behavior:
 - Retrieves all users from the users table in the database.
 - Returns an error if the user retrieval fails.
 */
-func (r *userRepository) GetAllUsers() ([]*models.UserDTO, error) {
+func (r *userRepository) GetAllUsers(ctx context.Context) ([]*models.UserDTO, error) {
 	query := `
-		SELECT id, user_name, email, created_at
+		SELECT id, user_name, email, version, created_at, updated_at
 		FROM users
 		ORDER BY created_at DESC`
 
-	rows, err := r.DB.Query(context.Background(), query)
+	rows, err := r.DB.Query(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get all users: %w", err)
 	}
@@ -123,7 +163,9 @@ func (r *userRepository) GetAllUsers() ([]*models.UserDTO, error) {
 			&user.ID,
 			&user.UserName,
 			&user.Email,
+			&user.Version,
 			&user.CreatedAt,
+			&user.UpdatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan user: %w", err)
@@ -142,45 +184,138 @@ func (r *userRepository) GetAllUsers() ([]*models.UserDTO, error) {
 UpdateUser is a method of userRepository struct that updates a user object in the postgres database.
 
 Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
 - id (uuid.UUID): The ID of the user to be updated.
 - user (*models.UpdateUserDTO): A pointer to an UpdateUserDTO struct containing the user data to be updated.
 
 Returns:
-- error: An error if the user update fails, otherwise nil.
+- (*models.UserDTO, error): A pointer to a UserDTO struct containing the updated user, or an error if the user update fails.
 
 Behavior:
-- Updates a user in the users table in the database.
+- Updates a user in the users table in the database and increments its version.
+- When user.Version is set, the update only applies if the stored version still matches it.
+- Returns models.ErrVersionConflict if the user was changed since that version was read.
 - Returns an error if the user update fails.
 */
-func (r *userRepository) UpdateUser(id uuid.UUID, user *models.UpdateUserDTO) error {
+func (r *userRepository) UpdateUser(ctx context.Context, id uuid.UUID, user *models.UpdateUserDTO) (*models.UserDTO, error) {
 	query := `
 		UPDATE users
-		SET user_name = $2, email = $3, updated_at = $4
-		WHERE id = $1`
+		SET user_name = $2, email = $3, updated_at = $4, version = version + 1
+		WHERE id = $1 AND ($5 = 0 OR version = $5)
+		RETURNING id, user_name, email, version, created_at, updated_at`
 
-	now := time.Now()
-	result, err := r.DB.Exec(context.Background(), query,
+	var updated models.UserDTO
+	err := r.DB.QueryRow(ctx, query,
 		id,
 		user.UserName,
 		user.Email,
-		now,
+		time.Now(),
+		user.Version,
+	).Scan(
+		&updated.ID,
+		&updated.UserName,
+		&updated.Email,
+		&updated.Version,
+		&updated.CreatedAt,
+		&updated.UpdatedAt,
 	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		if user.Version != 0 {
+			if _, err := r.GetUserById(ctx, id); err == nil {
+				return nil, models.ErrVersionConflict
+			}
+		}
+		return nil, fmt.Errorf("user with id %s not found", id)
+	}
 	if err != nil {
-		return fmt.Errorf("failed to update user: %w", err)
+		return nil, fmt.Errorf("failed to update user: %w", err)
 	}
 
-	rowsAffected := result.RowsAffected()
-	if rowsAffected == 0 {
-		return fmt.Errorf("user with id %s not found", id)
+	return &updated, nil
+}
+
+/*
+PatchUser is a method of userRepository struct that partially updates a user object in the postgres database.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- id (uuid.UUID): The ID of the user to be patched.
+- patch (*models.PatchUserDTO): A pointer to a PatchUserDTO struct whose non-nil fields are the columns to change.
+
+Returns:
+- (*models.UserDTO, error): A pointer to a UserDTO struct containing the patched user, or an error if the patch fails.
+
+Behavior:
+- Updates only the supplied columns of the user and increments its version.
+- When patch.Version is set, the update only applies if the stored version still matches it.
+- Returns the current user unchanged when the patch supplies no columns, after the same version check.
+- Returns models.ErrVersionConflict if the user was changed since that version was read.
+*/
+func (r *userRepository) PatchUser(ctx context.Context, id uuid.UUID, patch *models.PatchUserDTO) (*models.UserDTO, error) {
+	args := []any{id, patch.Version}
+	var sets []string
+
+	if patch.UserName != nil {
+		args = append(args, *patch.UserName)
+		sets = append(sets, fmt.Sprintf("user_name = $%d", len(args)))
+	}
+	if patch.Email != nil {
+		args = append(args, *patch.Email)
+		sets = append(sets, fmt.Sprintf("email = $%d", len(args)))
 	}
 
-	return nil
+	// Nothing to change, but a stale version is still a conflict
+	if len(sets) == 0 {
+		user, err := r.GetUserById(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if patch.Version != 0 && user.Version != patch.Version {
+			return nil, models.ErrVersionConflict
+		}
+		return user, nil
+	}
+
+	args = append(args, time.Now())
+	sets = append(sets, fmt.Sprintf("updated_at = $%d", len(args)), "version = version + 1")
+
+	query := fmt.Sprintf(`
+		UPDATE users
+		SET %s
+		WHERE id = $1 AND ($2 = 0 OR version = $2)
+		RETURNING id, user_name, email, version, created_at, updated_at`,
+		strings.Join(sets, ", "),
+	)
+
+	var user models.UserDTO
+	err := r.DB.QueryRow(ctx, query, args...).Scan(
+		&user.ID,
+		&user.UserName,
+		&user.Email,
+		&user.Version,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		if patch.Version != 0 {
+			if _, err := r.GetUserById(ctx, id); err == nil {
+				return nil, models.ErrVersionConflict
+			}
+		}
+		return nil, fmt.Errorf("user with id %s not found", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to patch user: %w", err)
+	}
+
+	return &user, nil
 }
 
 /*
 DeleteUser is a method of userRepository struct that deletes a user object from the postgres database.
 
 Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
 - id (uuid.UUID): The ID of the user to be deleted.
 
 Returns:
@@ -190,10 +325,10 @@ Behavior:
 - Deletes a user from the users table in the database.
 - Returns an error if the user deletion fails.
 */
-func (r *userRepository) DeleteUser(id uuid.UUID) error {
+func (r *userRepository) DeleteUser(ctx context.Context, id uuid.UUID) error {
 	query := `DELETE FROM users WHERE id = $1`
 
-	result, err := r.DB.Exec(context.Background(), query, id)
+	result, err := r.DB.Exec(ctx, query, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete user: %w", err)
 	}
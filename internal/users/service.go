@@ -2,6 +2,7 @@ package users
 
 import (
 	models "blockbustermvc/internal/models/user"
+	"context"
 
 	"github.com/google/uuid"
 )
@@ -16,22 +17,26 @@ func NewUserService(userRepo models.IUserRepository) models.IUserService {
 	}
 }
 
-func (u UserService) CreateUser(user *models.CreateUserDTO) error {
-	return u.userRepository.CreateUser(user)
+func (u UserService) CreateUser(ctx context.Context, user *models.CreateUserDTO) (*models.UserDTO, error) {
+	return u.userRepository.CreateUser(ctx, user)
 }
 
-func (u UserService) GetUser(id uuid.UUID) (*models.UserDTO, error) {
-	return u.userRepository.GetUserById(id)
+func (u UserService) GetUser(ctx context.Context, id uuid.UUID) (*models.UserDTO, error) {
+	return u.userRepository.GetUserById(ctx, id)
 }
 
-func (u UserService) GetAllUsers() ([]*models.UserDTO, error) {
-	return u.userRepository.GetAllUsers()
+func (u UserService) GetAllUsers(ctx context.Context) ([]*models.UserDTO, error) {
+	return u.userRepository.GetAllUsers(ctx)
 }
 
-func (u UserService) UpdateUser(id uuid.UUID, user *models.UpdateUserDTO) error {
-	return u.userRepository.UpdateUser(id, user)
+func (u UserService) UpdateUser(ctx context.Context, id uuid.UUID, user *models.UpdateUserDTO) (*models.UserDTO, error) {
+	return u.userRepository.UpdateUser(ctx, id, user)
 }
 
-func (u UserService) DeleteUser(id uuid.UUID) error {
-	return u.userRepository.DeleteUser(id)
+func (u UserService) PatchUser(ctx context.Context, id uuid.UUID, patch *models.PatchUserDTO) (*models.UserDTO, error) {
+	return u.userRepository.PatchUser(ctx, id, patch)
+}
+
+func (u UserService) DeleteUser(ctx context.Context, id uuid.UUID) error {
+	return u.userRepository.DeleteUser(ctx, id)
 }
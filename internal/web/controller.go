@@ -1,49 +1,141 @@
 package web
 
 import (
+	"blockbustermvc/internal/httputil"
+	eventModels "blockbustermvc/internal/models/event"
+	inventoryModels "blockbustermvc/internal/models/inventory"
+	jobModels "blockbustermvc/internal/models/job"
 	loanModels "blockbustermvc/internal/models/loans"
+	metadataModels "blockbustermvc/internal/models/metadata"
 	movieModels "blockbustermvc/internal/models/movie"
+	notificationModels "blockbustermvc/internal/models/notification"
+	recommendationModels "blockbustermvc/internal/models/recommendation"
+	reportModels "blockbustermvc/internal/models/report"
+	reviewModels "blockbustermvc/internal/models/review"
+	statsModels "blockbustermvc/internal/models/stats"
+	stocktakeModels "blockbustermvc/internal/models/stocktake"
+	storeModels "blockbustermvc/internal/models/store"
+	transferModels "blockbustermvc/internal/models/transfer"
 	userModels "blockbustermvc/internal/models/user"
+	webhookModels "blockbustermvc/internal/models/webhook"
+	wishlistModels "blockbustermvc/internal/models/wishlist"
+	"bytes"
+	"errors"
+	"fmt"
 	"html/template"
+	"log/slog"
 	"net/http"
-	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
+// dashboardLoans is how many of the latest active loans the dashboard lists.
+const dashboardLoans = 10
+
+var templateFuncs = template.FuncMap{
+	// money formats an amount in cents, such as a rental price
+	"money": func(cents int64) string {
+		return fmt.Sprintf("%d.%02d", cents/100, cents%100)
+	},
+	// percent formats a ratio, such as a utilization, as a percentage
+	"percent": func(ratio float64) string {
+		return fmt.Sprintf("%.0f%%", ratio*100)
+	},
+}
+
 type WebController struct {
-	templates    *template.Template
-	movieService movieModels.IMovieService
-	userService  userModels.IUserService
-	loanService  loanModels.ILoanService
+	templates             *template.Template
+	movieService          movieModels.IMovieService
+	userService           userModels.IUserService
+	loanService           loanModels.ILoanService
+	inventoryService      inventoryModels.IInventoryService
+	stocktakeService      stocktakeModels.IStocktakeService
+	storeService          storeModels.IStoreService
+	transferService       transferModels.ITransferService
+	metadataService       metadataModels.IMetadataService
+	reviewService         reviewModels.IReviewService
+	recommendationService recommendationModels.IRecommendationService
+	wishlistService       wishlistModels.IWishlistService
+	notificationService   notificationModels.INotificationService
+	jobService            jobModels.IJobService
+	webhookService        webhookModels.IWebhookService
+	statsService          statsModels.IStatsService
+	reportService         reportModels.IReportService
+	eventStream           eventModels.IEventStream
+	liveStats             *liveStats
 }
 
 func NewWebController(
 	movieService movieModels.IMovieService,
 	userService userModels.IUserService,
 	loanService loanModels.ILoanService,
+	inventoryService inventoryModels.IInventoryService,
+	stocktakeService stocktakeModels.IStocktakeService,
+	storeService storeModels.IStoreService,
+	transferService transferModels.ITransferService,
+	metadataService metadataModels.IMetadataService,
+	reviewService reviewModels.IReviewService,
+	recommendationService recommendationModels.IRecommendationService,
+	wishlistService wishlistModels.IWishlistService,
+	notificationService notificationModels.INotificationService,
+	jobService jobModels.IJobService,
+	webhookService webhookModels.IWebhookService,
+	statsService statsModels.IStatsService,
+	reportService reportModels.IReportService,
+	eventStream eventModels.IEventStream,
 ) *WebController {
-	tmpl := template.Must(template.ParseGlob("templates/*.html"))
+	tmpl := template.Must(template.New("").Funcs(templateFuncs).ParseGlob("templates/*.html"))
 
 	return &WebController{
-		templates:    tmpl,
-		movieService: movieService,
-		userService:  userService,
-		loanService:  loanService,
+		templates:             tmpl,
+		movieService:          movieService,
+		userService:           userService,
+		loanService:           loanService,
+		inventoryService:      inventoryService,
+		stocktakeService:      stocktakeService,
+		storeService:          storeService,
+		transferService:       transferService,
+		metadataService:       metadataService,
+		reviewService:         reviewService,
+		recommendationService: recommendationService,
+		wishlistService:       wishlistService,
+		notificationService:   notificationService,
+		jobService:            jobService,
+		webhookService:        webhookService,
+		statsService:          statsService,
+		reportService:         reportService,
+		eventStream:           eventStream,
+		liveStats:             newLiveStats(statsService),
 	}
 }
 
 func (wc *WebController) RegisterRoutes(router *gin.Engine) {
 	router.GET("/", wc.ServeHome)
+	router.GET("/live", wc.ServeLive)
 	router.GET("/users", wc.ServeUsers)
 	router.GET("/movies", wc.ServeMovies)
 	router.GET("/loans", wc.ServeLoans)
 
 	router.GET("/users/:id/edit", wc.EditUserForm)
+	router.GET("/users/:id/loans", wc.ServeUserLoans)
+	router.GET("/users/:id/wishlist", wc.ServeWishlist)
+	router.GET("/users/:id/notifications", wc.ServeNotifications)
 	router.GET("/movies/:id/edit", wc.EditMovieForm)
+	router.GET("/movies/:id/stock", wc.ServeStockHistory)
 	router.GET("/loans/:id/edit", wc.EditLoanForm)
+	router.GET("/loans/scan", wc.ScanReturnsForm)
+	router.GET("/stocktakes", wc.ServeStocktakes)
+	router.GET("/stocktakes/:id", wc.ServeStocktake)
+	router.GET("/transfers", wc.ServeTransfers)
+	router.GET("/metadata", wc.ServeMetadataProposals)
+	router.GET("/movies/:id", wc.ServeMovie)
+	router.GET("/jobs", wc.ServeJobs)
+	router.GET("/webhooks", wc.ServeWebhooks)
+	router.GET("/webhooks/:id", wc.ServeWebhook)
+	router.GET("/reports", wc.ServeReports)
+	router.POST("/reports/refresh", wc.RefreshReports)
 
 	router.GET("/users/search", wc.SearchUsers)
 	router.GET("/movies/search", wc.SearchMovies)
@@ -53,563 +145,134 @@ func (wc *WebController) RegisterRoutes(router *gin.Engine) {
 	router.POST("/users/:id/edit", wc.UpdateUser)
 	router.POST("/movies", wc.CreateMovie)
 	router.POST("/movies/:id/edit", wc.UpdateMovie)
+	router.POST("/movies/:id/stock", wc.AdjustStock)
 	router.POST("/loans", wc.CreateLoan)
 	router.POST("loans/:id/return", wc.ReturnMovie)
+	router.POST("/loans/scan", wc.ScanReturns)
+	router.POST("/stocktakes", wc.OpenStocktake)
+	router.POST("/stocktakes/:id/counts", wc.RecordStocktakeCount)
+	router.POST("/stocktakes/:id/scans", wc.ScanStocktakeCopy)
+	router.POST("/stocktakes/:id/approve", wc.ApproveStocktake)
+	router.POST("/stocktakes/:id/cancel", wc.CancelStocktake)
+	router.POST("/stores/select", wc.SelectStore)
+	router.POST("/transfers", wc.RequestTransfer)
+	router.POST("/transfers/:id/ship", wc.ShipTransfer)
+	router.POST("/transfers/:id/receive", wc.ReceiveTransfer)
+	router.POST("/transfers/:id/cancel", wc.CancelTransfer)
+	router.POST("/movies/:id/enrich", wc.EnrichMovie)
+	router.POST("/metadata/run", wc.RunEnrichment)
+	router.POST("/metadata/proposals/:id/approve", wc.ApproveProposal)
+	router.POST("/metadata/proposals/:id/reject", wc.RejectProposal)
+	router.POST("/movies/:id/reviews", wc.CreateReview)
+	router.POST("/reviews/:id/approve", wc.ApproveReview)
+	router.POST("/reviews/:id/reject", wc.RejectReview)
+	router.POST("/users/:id/wishlist", wc.AddWishlistItem)
+	router.POST("/users/:id/wishlist/:movieId/remove", wc.RemoveWishlistItem)
+	router.POST("/users/:id/notifications/preferences", wc.UpdateNotificationPreferences)
+	router.POST("/notifications/:id/retry", wc.RetryNotification)
+	router.POST("/jobs/:id/retry", wc.RetryJob)
+	router.POST("/jobs/schedules/:name/run", wc.RunJobSchedule)
+	router.POST("/webhooks", wc.CreateWebhook)
+	router.POST("/webhooks/:id/test", wc.SendWebhookTest)
+	router.POST("/webhooks/:id/toggle", wc.ToggleWebhook)
+	router.POST("/webhooks/:id/delete", wc.DeleteWebhook)
+	router.POST("/webhooks/deliveries/:id/retry", wc.RetryWebhookDelivery)
 
 	router.POST("users/:id/delete", wc.DeleteUser)
 	router.POST("movies/:id/delete", wc.DeleteMovie)
 }
 
+// ServeHome renders the dashboard from the store's stats and its latest
+// active loans, so it costs the same however large the catalog gets.
 func (wc *WebController) ServeHome(c *gin.Context) {
-	movies, _ := wc.movieService.GetAllMovies()
-	users, _ := wc.userService.GetAllUsers()
-	loans, _ := wc.loanService.GetAllLoans()
-
-	activeLoans := 0
-	for _, loan := range loans {
-		if loan.Status == "active" {
-			activeLoans++
-		}
-	}
-
-	availableMovies := 0
-	for _, movie := range movies {
-		if movie.Quantity > 0 {
-			availableMovies++
-		}
-	}
-
-	flashMessage, flashType := wc.getFlashMessage(c)
-
-	data := map[string]any{
-		"Title":         "BlockBuster Management",
-		"Movies":        movies,
-		"Users":         users,
-		"Loans":         loans,
-		"ActiveSection": "dashboard",
-		"FlashMessage":  flashMessage,
-		"FlashType":     flashType,
-		"Stats": map[string]any{
-			"TotalMovies":     len(movies),
-			"TotalUsers":      len(users),
-			"TotalLoans":      len(loans),
-			"ActiveLoans":     activeLoans,
-			"AvailableMovies": availableMovies,
-		},
-	}
-
-	err := wc.templates.ExecuteTemplate(c.Writer, "layout", data)
+	stats, err := wc.statsService.GetStats(c.Request.Context(), httputil.StoreID(c))
 	if err != nil {
-		c.String(http.StatusInternalServerError, "Error while trying to render template: %v", err)
+		slog.ErrorContext(c.Request.Context(), "Failed to serve home", "error", err)
+		c.String(http.StatusInternalServerError, "Error while trying to load stats: %v", err)
 		return
 	}
-}
-
-func (wc *WebController) ServeUsers(c *gin.Context) {
-	users, _ := wc.userService.GetAllUsers()
 
-	flashMessage, flashType := wc.getFlashMessage(c)
-	data := map[string]any{
-		"Title":         "User Management",
-		"Users":         users,
-		"ActiveSection": "users",
-		"FlashMessage":  flashMessage,
-		"FlashType":     flashType,
-	}
-	err := wc.templates.ExecuteTemplate(c.Writer, "layout", data)
+	loans, err := wc.loanService.GetRecentActiveStoreLoans(c.Request.Context(), httputil.StoreID(c), dashboardLoans)
 	if err != nil {
-		c.String(http.StatusInternalServerError, "Error while trying to render template: %v", err)
+		slog.ErrorContext(c.Request.Context(), "Failed to serve home", "error", err)
+		c.String(http.StatusInternalServerError, "Error while trying to load loans: %v", err)
 		return
 	}
-}
-
-func (wc *WebController) ServeMovies(c *gin.Context) {
-	movies, _ := wc.movieService.GetAllMovies()
 
 	flashMessage, flashType := wc.getFlashMessage(c)
-	data := map[string]any{
-		"Title":         "Movies Management",
-		"Movies":        movies,
-		"ActiveSection": "movies",
-		"FlashMessage":  flashMessage,
-		"FlashType":     flashType,
-	}
-	err := wc.templates.ExecuteTemplate(c.Writer, "layout", data)
-	if err != nil {
-		c.String(http.StatusInternalServerError, "Error while trying to render template: %v", err)
-		return
-	}
-}
-
-func (wc *WebController) ServeLoans(c *gin.Context) {
-	movies, _ := wc.movieService.GetAllMovies()
-	users, _ := wc.userService.GetAllUsers()
-	loans, _ := wc.loanService.GetAllLoans()
 
-	flashMessage, flashType := wc.getFlashMessage(c)
 	data := map[string]any{
-		"Title":         "Loans Management",
-		"Movies":        movies,
+		"Title":         "BlockBuster Management",
 		"Loans":         loans,
-		"Users":         users,
-		"ActiveSection": "loans",
-		"FlashMessage":  flashMessage,
-		"FlashType":     flashType,
-	}
-	err := wc.templates.ExecuteTemplate(c.Writer, "layout", data)
-	if err != nil {
-		c.String(http.StatusInternalServerError, "Error while trying to render template: %v", err)
-		return
-	}
-}
-
-func (wc *WebController) CreateUser(c *gin.Context) {
-	name := c.PostForm("name")
-	email := c.PostForm("email")
-
-	user := &userModels.CreateUserDTO{
-		UserName: name,
-		Email:    email,
-	}
-
-	err := wc.userService.CreateUser(user)
-	if err != nil {
-		wc.addFlashMessage(c, "Error creating user "+err.Error(), "error")
-	}
-
-	wc.addFlashMessage(c, "User created successfully", "success")
-
-	c.Redirect(http.StatusSeeOther, "/users")
-}
-
-func (wc *WebController) CreateMovie(c *gin.Context) {
-	name := c.PostForm("name")
-	director := c.PostForm("director")
-
-	year, err := strconv.ParseInt(c.PostForm("year"), 10, 64)
-	if err != nil {
-		wc.addFlashMessage(c, "Error parsing release year", "error")
-		c.Redirect(http.StatusSeeOther, "/movies")
-		return
-	}
-
-	quantity, err := strconv.ParseInt(c.PostForm("quantity"), 10, 64)
-	if err != nil {
-		wc.addFlashMessage(c, "Error parsing quantity", "error")
-		c.Redirect(http.StatusSeeOther, "/movies")
-		return
-	}
-
-	movie := &movieModels.CreateMovieDTO{
-		Name:     name,
-		Director: director,
-		Year:     year,
-		Quantity: quantity,
-	}
-
-	err = wc.movieService.CreateMovie(movie)
-	if err != nil {
-		wc.addFlashMessage(c, "Error creating movie "+err.Error(), "error")
-	}
-
-	wc.addFlashMessage(c, "Movie created successfully", "success")
-
-	c.Redirect(http.StatusSeeOther, "/movies")
-}
-
-func (wc *WebController) CreateLoan(c *gin.Context) {
-	movieId, err := uuid.Parse(c.PostForm("movie_id"))
-	if err != nil {
-		wc.addFlashMessage(c, "Error parsing movie ID", "error")
-	}
-
-	userId, err := uuid.Parse(c.PostForm("user_id"))
-	if err != nil {
-		wc.addFlashMessage(c, "Error parsing user ID", "error")
-	}
-
-	_, err = wc.loanService.CreateLoan(movieId, userId)
-	if err != nil {
-		wc.addFlashMessage(c, "Error creating loan "+err.Error(), "error")
-	}
-
-	wc.addFlashMessage(c, "Loan created successfully", "success")
-
-	c.Redirect(http.StatusSeeOther, "/loans")
-}
-
-func (wc *WebController) EditUserForm(c *gin.Context) {
-	userId, err := uuid.Parse(c.Param("id"))
-	if err != nil {
-		wc.addFlashMessage(c, "Invalid User ID", "error")
-		c.Redirect(http.StatusSeeOther, "/users")
-		return
-	}
-
-	user, err := wc.userService.GetUser(userId)
-	if err != nil {
-		wc.addFlashMessage(c, "User not found", "error")
-		c.Redirect(http.StatusSeeOther, "/user")
-		return
-	}
-
-	flashMessage, flashType := wc.getFlashMessage(c)
-
-	data := map[string]any{
-		"Title":         "Edit User",
-		"User":          user,
-		"ActiveSection": "users",
-		"FlashMessage":  flashMessage,
-		"FlashType":     flashType,
-		"IsEdit":        true,
-	}
-	err = wc.templates.ExecuteTemplate(c.Writer, "layout", data)
-	if err != nil {
-		c.String(http.StatusInternalServerError, "Error while trying to render template: %v", err)
-		return
-	}
-}
-
-func (wc *WebController) EditMovieForm(c *gin.Context) {
-	movieId, err := uuid.Parse(c.Param("id"))
-	if err != nil {
-		wc.addFlashMessage(c, "Invalid Movie ID", "error")
-		c.Redirect(http.StatusSeeOther, "/movies")
-		return
-	}
-
-	movie, err := wc.movieService.GetMovie(movieId)
-	if err != nil {
-		wc.addFlashMessage(c, "Movie not found", "error")
-		c.Redirect(http.StatusSeeOther, "/movies")
-		return
-	}
-
-	flashMessage, flashType := wc.getFlashMessage(c)
-
-	data := map[string]any{
-		"Title":         "Edit Movie",
-		"Movie":         movie,
-		"ActiveSection": "movies",
+		"LoansLimit":    dashboardLoans,
+		"ActiveSection": "dashboard",
 		"FlashMessage":  flashMessage,
 		"FlashType":     flashType,
-		"IsEdit":        true,
-	}
-	err = wc.templates.ExecuteTemplate(c.Writer, "layout", data)
-	if err != nil {
-		c.String(http.StatusInternalServerError, "Error while trying to render template: %v", err)
-		return
+		"Stats":         stats,
 	}
-}
-
-func (wc *WebController) EditLoanForm(c *gin.Context) {
-	loanId, err := uuid.Parse(c.Param("id"))
-	if err != nil {
-		wc.addFlashMessage(c, "Invalid Loan ID", "error")
-		c.Redirect(http.StatusSeeOther, "/loans")
-		return
-	}
-
-	loan, err := wc.loanService.GetLoan(loanId)
-	if err != nil {
-		wc.addFlashMessage(c, "Loan not found", "error")
-		c.Redirect(http.StatusSeeOther, "/loans")
-		return
-	}
-
-	flashMessage, flashType := wc.getFlashMessage(c)
 
-	data := map[string]any{
-		"Title":         "Edit Loan",
-		"Loan":          loan,
-		"ActiveSection": "Loans",
-		"FlashMessage":  flashMessage,
-		"FlashType":     flashType,
-		"IsEdit":        true,
-	}
-	err = wc.templates.ExecuteTemplate(c.Writer, "layout", data)
+	err = wc.renderLayout(c, data)
 	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Failed to serve home", "error", err)
 		c.String(http.StatusInternalServerError, "Error while trying to render template: %v", err)
 		return
 	}
 }
 
-func (wc *WebController) UpdateUser(c *gin.Context) {
-	userId, err := uuid.Parse(c.Param("id"))
-	if err != nil {
-		wc.addFlashMessage(c, "Invalid User ID", "error")
-		c.Redirect(http.StatusSeeOther, "/users")
-		return
+func (wc *WebController) SelectStore(c *gin.Context) {
+	redirectTo := c.PostForm("redirect_to")
+	if !strings.HasPrefix(redirectTo, "/") || strings.HasPrefix(redirectTo, "//") {
+		redirectTo = "/"
 	}
 
-	user, err := wc.userService.GetUser(userId)
+	storeId, err := uuid.Parse(c.PostForm("store_id"))
 	if err != nil {
-		wc.addFlashMessage(c, "User not found", "error")
-		c.Redirect(http.StatusSeeOther, "/users")
-		return
-	}
-
-	name := c.PostForm("name")
-	email := c.PostForm("email")
-	user.UserName = name
-	user.Email = email
-
-	updateUser := &userModels.UpdateUserDTO{
-		UserName: user.UserName,
-		Email:    user.Email,
-	}
-
-	if err = wc.userService.UpdateUser(userId, updateUser); err != nil {
-		wc.addFlashMessage(c, "Error trying to update user", "error")
-		c.Redirect(http.StatusSeeOther, "/users")
+		wc.addFlashMessage(c, "Invalid Store ID", "error")
+		c.Redirect(http.StatusSeeOther, redirectTo)
 		return
 	}
 
-	c.Redirect(http.StatusSeeOther, "/users")
-}
-
-func (wc *WebController) UpdateMovie(c *gin.Context) {
-	movieId, err := uuid.Parse(c.Param("id"))
-	if err != nil {
-		wc.addFlashMessage(c, "Invalid Movie ID", "error")
-		c.Redirect(http.StatusSeeOther, "/movies")
+	store, err := wc.storeService.GetScopedStore(c.Request.Context(), storeId)
+	if errors.Is(err, storeModels.ErrStoreOutOfScope) {
+		wc.addFlashMessage(c, "This store is not served here", "error")
+		c.Redirect(http.StatusSeeOther, redirectTo)
 		return
 	}
-
-	movie, err := wc.movieService.GetMovie(movieId)
 	if err != nil {
-		wc.addFlashMessage(c, "Movie not found", "error")
-		c.Redirect(http.StatusSeeOther, "/movies")
+		wc.addFlashMessage(c, "Store not found", "error")
+		c.Redirect(http.StatusSeeOther, redirectTo)
 		return
 	}
 
-	name := c.PostForm("name")
-	director := c.PostForm("director")
-
-	year, err := strconv.ParseInt(c.PostForm("year"), 10, 64)
-	if err != nil {
-		wc.addFlashMessage(c, "Error parsing release year", "error")
-	}
-
-	quantity, err := strconv.ParseInt(c.PostForm("quantity"), 10, 64)
-	if err != nil {
-		wc.addFlashMessage(c, "Error parsing quantity", "error")
-	}
-
-	movie.Name = name
-	movie.Director = director
-	movie.Year = year
-	movie.Quantity = quantity
-
-	updateMovie := &movieModels.UpdateMovieDTO{
-		Name:     movie.Name,
-		Director: movie.Director,
-		Year:     movie.Year,
-		Quantity: movie.Quantity,
-	}
-
-	if err = wc.movieService.UpdateMovie(movieId, updateMovie); err != nil {
-		wc.addFlashMessage(c, "Error trying to update movie", "error")
-		c.Redirect(http.StatusSeeOther, "/movies")
-		return
-	}
-
-	c.Redirect(http.StatusSeeOther, "/movies")
+	c.SetCookie(httputil.StoreCookie, store.ID.String(), 60*60*24*365, "/", "", false, true)
+	wc.addFlashMessage(c, "Now working at "+store.Name, "success")
+	c.Redirect(http.StatusSeeOther, redirectTo)
 }
 
-func (wc *WebController) SearchUsers(c *gin.Context) {
-	query := c.Query("q")
-
-	var users []*userModels.UserDTO
-	var err error
-
-	if query != "" {
-
-		allUsers, err := wc.userService.GetAllUsers()
-		if err != nil {
-			wc.addFlashMessage(c, "Error finding users: "+err.Error(), "error")
-			c.Redirect(http.StatusInternalServerError, "/users")
-			return
-		}
-
-		for _, user := range allUsers {
-			if strings.Contains(user.UserName, query) || strings.Contains(user.Email, query) {
-				users = append(users, user)
-			}
-		}
-	} else {
-		users, err = wc.userService.GetAllUsers()
+// renderFragment renders a single template, such as a loan card pushed to
+// live pages.
+func (wc *WebController) renderFragment(name string, data any) (string, error) {
+	var fragment bytes.Buffer
+	if err := wc.templates.ExecuteTemplate(&fragment, name, data); err != nil {
+		return "", err
 	}
 
-	if err != nil {
-		wc.addFlashMessage(c, "Error finding users: "+err.Error(), "error")
-		c.Redirect(http.StatusInternalServerError, "/users")
-
-	}
-
-	flashMessage, flashType := wc.getFlashMessage(c)
-
-	data := map[string]any{
-		"Title":         "Find users",
-		"Users":         users,
-		"ActiveSection": "users",
-		"FlashMessage":  flashMessage,
-		"FlashType":     flashType,
-		"SearchQuery":   query,
-	}
-
-	err = wc.templates.ExecuteTemplate(c.Writer, "layout", data)
-	if err != nil {
-		c.String(http.StatusInternalServerError, "Error rendering template: %v", err)
-		return
-	}
+	return fragment.String(), nil
 }
 
-func (wc *WebController) SearchMovies(c *gin.Context) {
-	query := c.Query("q")
-
-	var movies []*movieModels.MovieDTO
-	var err error
-
-	if query != "" {
-		allMovies, err := wc.movieService.GetAllMovies()
-		if err != nil {
-			wc.addFlashMessage(c, "Error finding movies: "+err.Error(), "error")
-			c.Redirect(http.StatusInternalServerError, "/movies")
-			return
-		}
+func (wc *WebController) renderLayout(c *gin.Context, data map[string]any) error {
+	stores, _ := wc.storeService.GetScopeStores(c.Request.Context())
+	currentStoreId := httputil.StoreID(c)
 
-		for _, movie := range allMovies {
-			if strings.Contains(movie.Name, query) || strings.Contains(movie.Director, query) {
-				movies = append(movies, movie)
-			}
+	for _, store := range stores {
+		if store.ID == currentStoreId {
+			data["CurrentStore"] = store
 		}
-	} else {
-		movies, err = wc.movieService.GetAllMovies()
-	}
-	if err != nil {
-		wc.addFlashMessage(c, "Error finding users: "+err.Error(), "error")
-		c.Redirect(http.StatusInternalServerError, "/movies")
-	}
-
-	flashMessage, flashType := wc.getFlashMessage(c)
-
-	data := map[string]any{
-		"Title":         "Find movies",
-		"Movies":        movies,
-		"ActiveSection": "movies",
-		"FlashMessage":  flashMessage,
-		"FlashType":     flashType,
-		"SearchQuery":   query,
-	}
-
-	err = wc.templates.ExecuteTemplate(c.Writer, "layout", data)
-	if err != nil {
-		c.String(http.StatusInternalServerError, "Error rendering template: %v", err)
-		return
-	}
-}
-
-func (wc *WebController) SearchLoans(c *gin.Context) {
-	query := c.Query("q")
-	status := c.Query("status")
-
-	var loans []*loanModels.LoanDTO
-	var err error
-
-	loans, err = wc.loanService.GetAllLoans()
-	if err != nil {
-		wc.addFlashMessage(c, "Error finding loans: "+err.Error(), "error")
-		c.Redirect(http.StatusSeeOther, "/loans")
-		return
-	}
-
-	if status != "" {
-		var filteredLoans []*loanModels.LoanDTO
-		for _, loan := range loans {
-			if loan.Status == status {
-				filteredLoans = append(filteredLoans, loan)
-			}
-		}
-
-		loans = filteredLoans
-	}
-
-	movies, _ := wc.movieService.GetAllMovies()
-	users, _ := wc.userService.GetAllUsers()
-
-	flashMessage, flashType := wc.getFlashMessage(c)
-
-	data := map[string]any{
-		"Title":         "Find loans",
-		"Loans":         loans,
-		"Movies":        movies,
-		"Users":         users,
-		"ActiveSection": "loans",
-		"FlashMessage":  flashMessage,
-		"FlashType":     flashType,
-		"SearchQuery":   query,
-		"StatusFilter":  status,
-	}
-
-	err = wc.templates.ExecuteTemplate(c.Writer, "layout", data)
-	if err != nil {
-		c.String(http.StatusInternalServerError, "Error rendering template: %v", err)
-	}
-}
-
-func (wc *WebController) ReturnMovie(c *gin.Context) {
-	loanId, err := uuid.Parse(c.Param("id"))
-	if err != nil {
-		wc.addFlashMessage(c, "Error parsing loan ID", "error")
-		c.Redirect(http.StatusSeeOther, "/loans")
-		return
-	}
-
-	if err = wc.loanService.ReturnMovie(loanId); err != nil {
-		wc.addFlashMessage(c, "Error trying to return movie", "error")
-		c.Redirect(http.StatusSeeOther, "/loans")
-		return
-	}
-
-	wc.addFlashMessage(c, "Returned movie successfully", "success")
-	c.Redirect(http.StatusSeeOther, "/loans")
-}
-
-func (wc *WebController) DeleteUser(c *gin.Context) {
-	userId, err := uuid.Parse(c.Param("id"))
-	if err != nil {
-		wc.addFlashMessage(c, "Invalid User ID", "error")
-		c.Redirect(http.StatusSeeOther, "/users")
-		return
-	}
-
-	if err = wc.userService.DeleteUser(userId); err != nil {
-		wc.addFlashMessage(c, "Error while deleting user", "error")
-		c.Redirect(http.StatusSeeOther, "/users")
-		return
-	}
-
-	wc.addFlashMessage(c, "User deleted successfully", "success")
-	c.Redirect(http.StatusSeeOther, "/users")
-}
-
-func (wc *WebController) DeleteMovie(c *gin.Context) {
-	movieId, err := uuid.Parse(c.Param("id"))
-	if err != nil {
-		wc.addFlashMessage(c, "Invalid Movie ID", "error")
-		c.Redirect(http.StatusSeeOther, "/movies")
-		return
-	}
-
-	if err = wc.movieService.DeleteMovie(movieId); err != nil {
-		wc.addFlashMessage(c, "Error while deleting movie", "error")
-		c.Redirect(http.StatusSeeOther, "/movies")
-		return
 	}
+	data["Stores"] = stores
+	data["CurrentPath"] = c.Request.URL.Path
 
-	wc.addFlashMessage(c, "Movie deleted successfully", "success")
-	c.Redirect(http.StatusSeeOther, "/movies")
+	return wc.templates.ExecuteTemplate(c.Writer, "layout", data)
 }
 
 func (wc *WebController) addFlashMessage(c *gin.Context, message, messageType string) {
@@ -0,0 +1,84 @@
+package web
+
+import (
+	"blockbustermvc/internal/httputil"
+	inventoryModels "blockbustermvc/internal/models/inventory"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func (wc *WebController) ServeStockHistory(c *gin.Context) {
+	movieId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		wc.addFlashMessage(c, "Invalid Movie ID", "error")
+		c.Redirect(http.StatusSeeOther, "/movies")
+		return
+	}
+
+	movie, err := wc.movieService.GetStoreMovie(c.Request.Context(), httputil.StoreID(c), movieId)
+	if err != nil {
+		wc.addFlashMessage(c, "Movie not found", "error")
+		c.Redirect(http.StatusSeeOther, "/movies")
+		return
+	}
+
+	movements, _ := wc.inventoryService.GetMovieMovements(c.Request.Context(), movieId)
+	stock, _ := wc.inventoryService.GetMovieStock(c.Request.Context(), movieId)
+	availability, _ := wc.inventoryService.GetMovieAvailability(c.Request.Context(), movieId)
+
+	flashMessage, flashType := wc.getFlashMessage(c)
+
+	data := map[string]any{
+		"Title":         "Stock History",
+		"Movie":         movie,
+		"Movements":     movements,
+		"Stock":         stock,
+		"Availability":  availability,
+		"ActiveSection": "stock",
+		"FlashMessage":  flashMessage,
+		"FlashType":     flashType,
+	}
+	err = wc.renderLayout(c, data)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Failed to serve stock history", "error", err)
+		c.String(http.StatusInternalServerError, "Error while trying to render template: %v", err)
+		return
+	}
+}
+
+func (wc *WebController) AdjustStock(c *gin.Context) {
+	movieId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		wc.addFlashMessage(c, "Invalid Movie ID", "error")
+		c.Redirect(http.StatusSeeOther, "/movies")
+		return
+	}
+
+	redirectTo := "/movies/" + movieId.String() + "/stock"
+
+	quantityDelta, err := strconv.ParseInt(c.PostForm("quantity_delta"), 10, 64)
+	if err != nil {
+		wc.addFlashMessage(c, "Error parsing quantity change", "error")
+		c.Redirect(http.StatusSeeOther, redirectTo)
+		return
+	}
+
+	movement := &inventoryModels.CreateMovementDTO{
+		Reason:        c.PostForm("reason"),
+		QuantityDelta: quantityDelta,
+		Note:          c.PostForm("note"),
+	}
+
+	if _, err = wc.inventoryService.AdjustStock(c.Request.Context(), httputil.StoreID(c), movieId, movement); err != nil {
+		wc.addFlashMessage(c, "Error adjusting stock: "+err.Error(), "error")
+		c.Redirect(http.StatusSeeOther, redirectTo)
+		return
+	}
+
+	wc.addFlashMessage(c, "Stock adjusted successfully", "success")
+	c.Redirect(http.StatusSeeOther, redirectTo)
+}
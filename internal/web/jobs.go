@@ -0,0 +1,66 @@
+package web
+
+import (
+	jobModels "blockbustermvc/internal/models/job"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func (wc *WebController) ServeJobs(c *gin.Context) {
+	status := c.Query("status")
+	jobs, _ := wc.jobService.GetJobs(c.Request.Context(), status)
+	schedules, _ := wc.jobService.GetSchedules(c.Request.Context())
+
+	flashMessage, flashType := wc.getFlashMessage(c)
+
+	data := map[string]any{
+		"Title":         "Background Jobs",
+		"Jobs":          jobs,
+		"Schedules":     schedules,
+		"Status":        status,
+		"Statuses":      jobModels.Statuses,
+		"ActiveSection": "jobs",
+		"FlashMessage":  flashMessage,
+		"FlashType":     flashType,
+	}
+	err := wc.renderLayout(c, data)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Failed to serve jobs", "error", err)
+		c.String(http.StatusInternalServerError, "Error while trying to render template: %v", err)
+		return
+	}
+}
+
+func (wc *WebController) RetryJob(c *gin.Context) {
+	jobId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		wc.addFlashMessage(c, "Invalid Job ID", "error")
+		c.Redirect(http.StatusSeeOther, "/jobs")
+		return
+	}
+
+	job, err := wc.jobService.Retry(c.Request.Context(), jobId)
+	if err != nil {
+		wc.addFlashMessage(c, "Error retrying job: "+err.Error(), "error")
+		c.Redirect(http.StatusSeeOther, "/jobs?status="+jobModels.StatusDead)
+		return
+	}
+
+	wc.addFlashMessage(c, job.Kind+" job queued for another attempt", "success")
+	c.Redirect(http.StatusSeeOther, "/jobs?status="+jobModels.StatusDead)
+}
+
+func (wc *WebController) RunJobSchedule(c *gin.Context) {
+	job, err := wc.jobService.RunSchedule(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		wc.addFlashMessage(c, "Error running schedule: "+err.Error(), "error")
+		c.Redirect(http.StatusSeeOther, "/jobs")
+		return
+	}
+
+	wc.addFlashMessage(c, job.Kind+" job queued", "success")
+	c.Redirect(http.StatusSeeOther, "/jobs")
+}
@@ -0,0 +1,200 @@
+package web
+
+import (
+	"blockbustermvc/internal/httputil"
+	eventModels "blockbustermvc/internal/models/event"
+	loanModels "blockbustermvc/internal/models/loans"
+	statsModels "blockbustermvc/internal/models/stats"
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	// liveHeartbeat is how often an idle live stream sends a ping, so
+	// proxies keep it open.
+	liveHeartbeat = 25 * time.Second
+
+	// liveStatsDelay is how long the live stream gathers events before
+	// recomputing the stats.
+	liveStatsDelay = 500 * time.Millisecond
+
+	// liveStatsTimeout bounds one computation of a store's stats, which
+	// outlives the stream that started it.
+	liveStatsTimeout = 10 * time.Second
+)
+
+// liveEvent holds the payload fields the live views need.
+type liveEvent struct {
+	LoanID        uuid.UUID `json:"loan_id"`
+	MovieID       uuid.UUID `json:"movie_id"`
+	StoreID       uuid.UUID `json:"store_id"`
+	StoreQuantity int64     `json:"store_quantity"`
+}
+
+// liveStats computes a store's stats once per burst of events and hands
+// the result to every live stream of the store, however many are open.
+type liveStats struct {
+	statsService statsModels.IStatsService
+
+	mu   sync.Mutex
+	runs map[uuid.UUID]*liveStatsRun
+}
+
+// liveStatsRun is one computation of a store's stats; done is closed once
+// stats and err are set.
+type liveStatsRun struct {
+	started time.Time
+	done    chan struct{}
+	stats   *statsModels.StatsDTO
+	err     error
+}
+
+func newLiveStats(statsService statsModels.IStatsService) *liveStats {
+	return &liveStats{
+		statsService: statsService,
+		runs:         make(map[uuid.UUID]*liveStatsRun),
+	}
+}
+
+// Get returns the store's stats as computed after changedAt. The first
+// stream to ask after a change starts the computation; the streams asking
+// while it runs, or later for the same change, share its result.
+func (s *liveStats) Get(ctx context.Context, storeId uuid.UUID, changedAt time.Time) (*statsModels.StatsDTO, error) {
+	s.mu.Lock()
+	run := s.runs[storeId]
+	if run == nil || run.started.Before(changedAt) {
+		run = &liveStatsRun{started: time.Now(), done: make(chan struct{})}
+		s.runs[storeId] = run
+
+		// The other streams wait for it too, so it must not end with this one
+		computeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), liveStatsTimeout)
+		go func() {
+			defer cancel()
+
+			run.stats, run.err = s.statsService.GetStats(computeCtx, storeId)
+			if run.err != nil {
+				s.mu.Lock()
+				if s.runs[storeId] == run {
+					delete(s.runs, storeId)
+				}
+				s.mu.Unlock()
+			}
+			close(run.done)
+		}()
+	}
+	s.mu.Unlock()
+
+	select {
+	case <-run.done:
+		return run.stats, run.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// ServeLive streams the changes to the current store as Server-Sent
+// Events, so the dashboard and the loans page stay current: "loan" carries
+// a re-rendered loan, "stock" a movie's new quantity and "stats" the
+// dashboard counters, computed once per burst of events for all the
+// streams of the store.
+func (wc *WebController) ServeLive(c *gin.Context) {
+	storeId := httputil.StoreID(c)
+	events, unsubscribe := wc.eventStream.Subscribe()
+	defer unsubscribe()
+
+	// Proxies must pass every event on as soon as it is written
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+
+	heartbeat := time.NewTicker(liveHeartbeat)
+	defer heartbeat.Stop()
+
+	// Sending the stats right away catches up after a reconnect
+	statsDue := time.After(0)
+	changedAt := time.Now()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case event, ok := <-events:
+			if !ok {
+				return false
+			}
+			if wc.pushLiveEvent(c, storeId, event) {
+				changedAt = time.Now()
+				if statsDue == nil {
+					statsDue = time.After(liveStatsDelay)
+				}
+			}
+		case <-statsDue:
+			statsDue = nil
+			if stats, err := wc.liveStats.Get(c.Request.Context(), storeId, changedAt); err == nil {
+				c.SSEvent("stats", stats)
+			}
+		case <-heartbeat.C:
+			c.SSEvent("ping", time.Now().Unix())
+		}
+		return true
+	})
+}
+
+// pushLiveEvent sends what an event changed for the store, and reports
+// whether the dashboard stats may have changed too.
+func (wc *WebController) pushLiveEvent(c *gin.Context, storeId uuid.UUID, event *eventModels.EventDTO) bool {
+	var payload liveEvent
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		return false
+	}
+
+	switch event.Type {
+	case eventModels.TypeLoanCreated, eventModels.TypeMovieReturned:
+		loan, err := wc.loanService.GetLoan(c.Request.Context(), payload.LoanID, false)
+		if err != nil || !concernsStore(loan, storeId) {
+			return false
+		}
+
+		card, err := wc.renderFragment("loanCard", loan)
+		if err != nil {
+			return true
+		}
+		row, err := wc.renderFragment("activeLoanRow", loan)
+		if err != nil {
+			return true
+		}
+
+		c.SSEvent("loan", gin.H{
+			"id":     loan.ID,
+			"status": loan.Status,
+			"card":   card,
+			"row":    row,
+		})
+		return true
+	case eventModels.TypeStockChanged:
+		if payload.StoreID != storeId {
+			return false
+		}
+
+		c.SSEvent("stock", gin.H{
+			"movie_id": payload.MovieID,
+			"quantity": payload.StoreQuantity,
+		})
+		return true
+	case eventModels.TypeUserRegistered:
+		return true
+	}
+
+	return false
+}
+
+// concernsStore reports whether a loan was made or returned at the store, so
+// a return at another branch reaches both the lending and the returning one.
+func concernsStore(loan *loanModels.LoanDTO, storeId uuid.UUID) bool {
+	return loan.StoreID == storeId || (loan.ReturnStoreID != nil && *loan.ReturnStoreID == storeId)
+}
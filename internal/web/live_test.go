@@ -0,0 +1,151 @@
+package web
+
+import (
+	eventModels "blockbustermvc/internal/models/event"
+	loanModels "blockbustermvc/internal/models/loans"
+	statsModels "blockbustermvc/internal/models/stats"
+	"context"
+	"encoding/json"
+	"errors"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type fakeLoanService struct {
+	loanModels.ILoanService
+	loans map[uuid.UUID]*loanModels.LoanDTO
+}
+
+func (f *fakeLoanService) GetLoan(_ context.Context, id uuid.UUID, _ bool) (*loanModels.LoanDTO, error) {
+	loan, ok := f.loans[id]
+	if !ok {
+		return nil, errors.New("loan not found")
+	}
+	return loan, nil
+}
+
+func TestPushLiveEventReachesLendingAndReturningStores(t *testing.T) {
+	lending, returning, other := uuid.New(), uuid.New(), uuid.New()
+	loan := &loanModels.LoanDTO{
+		ID:            uuid.New(),
+		MovieID:       uuid.New(),
+		StoreID:       lending,
+		ReturnStoreID: &returning,
+		Status:        "returned",
+	}
+
+	wc := &WebController{
+		templates:   template.Must(template.New("").Funcs(templateFuncs).ParseGlob("../../templates/*.html")),
+		loanService: &fakeLoanService{loans: map[uuid.UUID]*loanModels.LoanDTO{loan.ID: loan}},
+	}
+
+	payload, err := json.Marshal(map[string]any{"loan_id": loan.ID, "movie_id": loan.MovieID, "store_id": returning})
+	if err != nil {
+		t.Fatal(err)
+	}
+	event := &eventModels.EventDTO{Type: eventModels.TypeMovieReturned, Payload: payload}
+
+	tests := []struct {
+		name     string
+		storeId  uuid.UUID
+		wantPush bool
+	}{
+		{name: "lending store", storeId: lending, wantPush: true},
+		{name: "returning store", storeId: returning, wantPush: true},
+		{name: "unrelated store", storeId: other, wantPush: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(response)
+			c.Request = httptest.NewRequest(http.MethodGet, "/live", nil)
+
+			pushed := wc.pushLiveEvent(c, tt.storeId, event)
+			if pushed != tt.wantPush {
+				t.Fatalf("pushLiveEvent() = %v, want %v", pushed, tt.wantPush)
+			}
+			if sent := strings.Contains(response.Body.String(), "event:loan"); sent != tt.wantPush {
+				t.Errorf("loan event sent = %v, want %v: %q", sent, tt.wantPush, response.Body)
+			}
+		})
+	}
+}
+
+// fakeStatsService counts its computations; each waits for release.
+type fakeStatsService struct {
+	statsModels.IStatsService
+	release  chan struct{}
+	computed atomic.Int64
+	fail     atomic.Bool
+}
+
+func (f *fakeStatsService) GetStats(_ context.Context, storeId uuid.UUID) (*statsModels.StatsDTO, error) {
+	<-f.release
+	run := f.computed.Add(1)
+	if f.fail.Load() {
+		return nil, errors.New("database is down")
+	}
+	return &statsModels.StatsDTO{StoreID: storeId, ActiveLoans: run}, nil
+}
+
+func TestLiveStatsAreComputedOncePerBurst(t *testing.T) {
+	storeId := uuid.New()
+	service := &fakeStatsService{release: make(chan struct{})}
+	computed, fail, release := &service.computed, &service.fail, service.release
+
+	stats := newLiveStats(service)
+
+	// Every stream of the store asks after the same burst
+	changedAt := time.Now()
+	var wg sync.WaitGroup
+	results := make([]*statsModels.StatsDTO, 5)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], _ = stats.Get(t.Context(), storeId, changedAt)
+		}()
+	}
+	// Let the streams queue up behind the first computation
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := computed.Load(); got != 1 {
+		t.Fatalf("stats computed %d times for one burst, want 1", got)
+	}
+	for i, result := range results {
+		if result != results[0] {
+			t.Errorf("stream %d got %v, want the shared %v", i, result, results[0])
+		}
+	}
+
+	// A stream asking again for the same burst reuses the result
+	if _, err := stats.Get(t.Context(), storeId, changedAt); err != nil || computed.Load() != 1 {
+		t.Errorf("Get() for the same burst error = %v, computed %d times, want 1", err, computed.Load())
+	}
+
+	// A later change needs fresh stats, and a failure is not kept
+	fail.Store(true)
+	if _, err := stats.Get(t.Context(), storeId, time.Now()); err == nil {
+		t.Fatal("Get() error = nil, want the compute error")
+	}
+	fail.Store(false)
+	result, err := stats.Get(t.Context(), storeId, changedAt)
+	if err != nil {
+		t.Fatalf("Get() after a failure error = %v", err)
+	}
+	if got := computed.Load(); got != 3 || result.ActiveLoans != 3 {
+		t.Errorf("computed %d times, stats from run %d, want 3 and 3", got, result.ActiveLoans)
+	}
+}
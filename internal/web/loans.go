@@ -0,0 +1,236 @@
+package web
+
+import (
+	"blockbustermvc/internal/httputil"
+	loanModels "blockbustermvc/internal/models/loans"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func (wc *WebController) ServeLoans(c *gin.Context) {
+	movies, _ := wc.movieService.GetStoreMovies(c.Request.Context(), httputil.StoreID(c))
+	users, _ := wc.userService.GetAllUsers(c.Request.Context())
+	loans, _ := wc.loanService.GetStoreLoans(c.Request.Context(), httputil.StoreID(c), false)
+
+	flashMessage, flashType := wc.getFlashMessage(c)
+	data := map[string]any{
+		"Title":         "Loans Management",
+		"Movies":        movies,
+		"Loans":         loans,
+		"Users":         users,
+		"ActiveSection": "loans",
+		"FlashMessage":  flashMessage,
+		"FlashType":     flashType,
+	}
+	err := wc.renderLayout(c, data)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Failed to serve loans", "error", err)
+		c.String(http.StatusInternalServerError, "Error while trying to render template: %v", err)
+		return
+	}
+}
+
+func (wc *WebController) CreateLoan(c *gin.Context) {
+	movieId, err := uuid.Parse(c.PostForm("movie_id"))
+	if err != nil {
+		wc.addFlashMessage(c, "Error parsing movie ID", "error")
+	}
+
+	userId, err := uuid.Parse(c.PostForm("user_id"))
+	if err != nil {
+		wc.addFlashMessage(c, "Error parsing user ID", "error")
+	}
+
+	_, err = wc.loanService.CreateLoan(c.Request.Context(), httputil.StoreID(c), movieId, userId, c.PostForm("copy_barcode"))
+	if err != nil {
+		wc.addFlashMessage(c, "Error creating loan "+err.Error(), "error")
+	}
+
+	wc.addFlashMessage(c, "Loan created successfully", "success")
+
+	c.Redirect(http.StatusSeeOther, "/loans")
+}
+
+func (wc *WebController) EditLoanForm(c *gin.Context) {
+	loanId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		wc.addFlashMessage(c, "Invalid Loan ID", "error")
+		c.Redirect(http.StatusSeeOther, "/loans")
+		return
+	}
+
+	loan, err := wc.loanService.GetLoan(c.Request.Context(), loanId, false)
+	if err != nil {
+		wc.addFlashMessage(c, "Loan not found", "error")
+		c.Redirect(http.StatusSeeOther, "/loans")
+		return
+	}
+
+	flashMessage, flashType := wc.getFlashMessage(c)
+
+	data := map[string]any{
+		"Title":         "Edit Loan",
+		"Loan":          loan,
+		"ActiveSection": "Loans",
+		"FlashMessage":  flashMessage,
+		"FlashType":     flashType,
+		"IsEdit":        true,
+	}
+	err = wc.renderLayout(c, data)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Failed to serve loan form", "error", err)
+		c.String(http.StatusInternalServerError, "Error while trying to render template: %v", err)
+		return
+	}
+}
+
+func (wc *WebController) SearchLoans(c *gin.Context) {
+	query := c.Query("q")
+	status := c.Query("status")
+	includeArchived := c.Query("include_archived") == "true"
+
+	var loans []*loanModels.LoanDTO
+	var err error
+
+	loans, err = wc.loanService.GetStoreLoans(c.Request.Context(), httputil.StoreID(c), includeArchived)
+	if err != nil {
+		wc.addFlashMessage(c, "Error finding loans: "+err.Error(), "error")
+		c.Redirect(http.StatusSeeOther, "/loans")
+		return
+	}
+
+	if status != "" {
+		var filteredLoans []*loanModels.LoanDTO
+		for _, loan := range loans {
+			if loan.Status == status {
+				filteredLoans = append(filteredLoans, loan)
+			}
+		}
+
+		loans = filteredLoans
+	}
+
+	movies, _ := wc.movieService.GetStoreMovies(c.Request.Context(), httputil.StoreID(c))
+	users, _ := wc.userService.GetAllUsers(c.Request.Context())
+
+	flashMessage, flashType := wc.getFlashMessage(c)
+
+	data := map[string]any{
+		"Title":           "Find loans",
+		"Loans":           loans,
+		"Movies":          movies,
+		"Users":           users,
+		"ActiveSection":   "loans",
+		"FlashMessage":    flashMessage,
+		"FlashType":       flashType,
+		"SearchQuery":     query,
+		"StatusFilter":    status,
+		"IncludeArchived": includeArchived,
+	}
+
+	err = wc.renderLayout(c, data)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Failed to search loans", "error", err)
+		c.String(http.StatusInternalServerError, "Error rendering template: %v", err)
+	}
+}
+
+func (wc *WebController) ReturnMovie(c *gin.Context) {
+	loanId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		wc.addFlashMessage(c, "Error parsing loan ID", "error")
+		c.Redirect(http.StatusSeeOther, "/loans")
+		return
+	}
+
+	version, err := strconv.ParseInt(c.PostForm("version"), 10, 64)
+	if err != nil {
+		wc.addFlashMessage(c, "Error parsing loan version", "error")
+		c.Redirect(http.StatusSeeOther, "/loans")
+		return
+	}
+
+	if _, err = wc.loanService.ReturnMovie(c.Request.Context(), httputil.StoreID(c), loanId, version); err != nil {
+		if errors.Is(err, loanModels.ErrVersionConflict) {
+			wc.addFlashMessage(c, "This loan was changed by someone else, for example returned at another desk. Review it and try again.", "warning")
+			c.Redirect(http.StatusSeeOther, "/loans")
+			return
+		}
+
+		wc.addFlashMessage(c, "Error trying to return movie", "error")
+		c.Redirect(http.StatusSeeOther, "/loans")
+		return
+	}
+
+	wc.addFlashMessage(c, "Returned movie successfully", "success")
+	c.Redirect(http.StatusSeeOther, "/loans")
+}
+
+func (wc *WebController) ScanReturnsForm(c *gin.Context) {
+	flashMessage, flashType := wc.getFlashMessage(c)
+
+	data := map[string]any{
+		"Title":         "Scan Returns",
+		"ActiveSection": "scan",
+		"FlashMessage":  flashMessage,
+		"FlashType":     flashType,
+		"ReturnedAt":    time.Now().Format("2006-01-02T15:04"),
+	}
+
+	err := wc.renderLayout(c, data)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Failed to serve scan returns form", "error", err)
+		c.String(http.StatusInternalServerError, "Error while trying to render template: %v", err)
+		return
+	}
+}
+
+func (wc *WebController) ScanReturns(c *gin.Context) {
+	returnedAtInput := c.PostForm("returned_at")
+
+	var returnedAt time.Time
+	if returnedAtInput != "" {
+		parsed, err := time.ParseInLocation("2006-01-02T15:04", returnedAtInput, time.Local)
+		if err != nil {
+			wc.addFlashMessage(c, "Error parsing drop-box time", "error")
+			c.Redirect(http.StatusSeeOther, "/loans/scan")
+			return
+		}
+		returnedAt = parsed
+	}
+
+	batch := &loanModels.DropBoxReturnDTO{
+		Codes:      strings.Split(c.PostForm("codes"), "\n"),
+		ReturnedAt: returnedAt,
+	}
+
+	summary := wc.loanService.ProcessDropBox(c.Request.Context(), httputil.StoreID(c), batch)
+
+	flashMessage, flashType := "", ""
+	if summary.Processed == 0 {
+		flashMessage, flashType = "No barcodes were scanned", "warning"
+	}
+
+	data := map[string]any{
+		"Title":         "Scan Returns",
+		"ActiveSection": "scan",
+		"FlashMessage":  flashMessage,
+		"FlashType":     flashType,
+		"ReturnedAt":    summary.ReturnedAt.Format("2006-01-02T15:04"),
+		"Summary":       summary,
+	}
+
+	err := wc.renderLayout(c, data)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Failed to scan returns", "error", err)
+		c.String(http.StatusInternalServerError, "Error while trying to render template: %v", err)
+		return
+	}
+}
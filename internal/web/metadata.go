@@ -0,0 +1,93 @@
+package web
+
+import (
+	metadataModels "blockbustermvc/internal/models/metadata"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func (wc *WebController) EnrichMovie(c *gin.Context) {
+	movieId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		wc.addFlashMessage(c, "Invalid Movie ID", "error")
+		c.Redirect(http.StatusSeeOther, "/movies")
+		return
+	}
+
+	movie, err := wc.metadataService.EnrichMovie(c.Request.Context(), movieId)
+	if err != nil {
+		wc.addFlashMessage(c, "Error enriching movie: "+err.Error(), "error")
+		c.Redirect(http.StatusSeeOther, "/movies")
+		return
+	}
+
+	wc.addFlashMessage(c, "Metadata of "+movie.Name+" filled in", "success")
+	c.Redirect(http.StatusSeeOther, "/movies")
+}
+
+func (wc *WebController) ServeMetadataProposals(c *gin.Context) {
+	proposals, _ := wc.metadataService.GetProposals(c.Request.Context(), metadataModels.StatusPending)
+
+	flashMessage, flashType := wc.getFlashMessage(c)
+
+	data := map[string]any{
+		"Title":         "Metadata Proposals",
+		"Proposals":     proposals,
+		"ActiveSection": "metadata",
+		"FlashMessage":  flashMessage,
+		"FlashType":     flashType,
+	}
+	err := wc.renderLayout(c, data)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Failed to serve metadata proposals", "error", err)
+		c.String(http.StatusInternalServerError, "Error while trying to render template: %v", err)
+		return
+	}
+}
+
+func (wc *WebController) RunEnrichment(c *gin.Context) {
+	run, err := wc.metadataService.ProposeEnrichment(c.Request.Context())
+	if err != nil {
+		wc.addFlashMessage(c, "Error enriching catalog: "+err.Error(), "error")
+		c.Redirect(http.StatusSeeOther, "/metadata")
+		return
+	}
+
+	wc.addFlashMessage(c, fmt.Sprintf("Checked %d movies, %d found, %d proposals to review", run.Checked, run.Matched, run.Proposed), "success")
+	c.Redirect(http.StatusSeeOther, "/metadata")
+}
+
+func (wc *WebController) ApproveProposal(c *gin.Context) {
+	wc.decideProposal(c, wc.metadataService.ApproveProposal, "Proposal approved and movie updated")
+}
+
+func (wc *WebController) RejectProposal(c *gin.Context) {
+	wc.decideProposal(c, wc.metadataService.RejectProposal, "Proposal rejected")
+}
+
+func (wc *WebController) decideProposal(
+	c *gin.Context,
+	decide func(ctx context.Context, id uuid.UUID) (*metadataModels.ProposalDTO, error),
+	successMessage string,
+) {
+	proposalId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		wc.addFlashMessage(c, "Invalid Proposal ID", "error")
+		c.Redirect(http.StatusSeeOther, "/metadata")
+		return
+	}
+
+	if _, err = decide(c.Request.Context(), proposalId); err != nil {
+		wc.addFlashMessage(c, "Error deciding proposal: "+err.Error(), "error")
+		c.Redirect(http.StatusSeeOther, "/metadata")
+		return
+	}
+
+	wc.addFlashMessage(c, successMessage, "success")
+	c.Redirect(http.StatusSeeOther, "/metadata")
+}
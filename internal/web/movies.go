@@ -0,0 +1,303 @@
+package web
+
+import (
+	"blockbustermvc/internal/httputil"
+	movieModels "blockbustermvc/internal/models/movie"
+	recommendationModels "blockbustermvc/internal/models/recommendation"
+	reviewModels "blockbustermvc/internal/models/review"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func (wc *WebController) ServeMovies(c *gin.Context) {
+	movies, _ := wc.movieService.GetStoreMovies(c.Request.Context(), httputil.StoreID(c))
+
+	flashMessage, flashType := wc.getFlashMessage(c)
+	data := map[string]any{
+		"Title":         "Movies Management",
+		"Movies":        movies,
+		"ActiveSection": "movies",
+		"FlashMessage":  flashMessage,
+		"FlashType":     flashType,
+	}
+	err := wc.renderLayout(c, data)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Failed to serve movies", "error", err)
+		c.String(http.StatusInternalServerError, "Error while trying to render template: %v", err)
+		return
+	}
+}
+
+func (wc *WebController) CreateMovie(c *gin.Context) {
+	name := c.PostForm("name")
+	director := c.PostForm("director")
+
+	year, err := strconv.ParseInt(c.PostForm("year"), 10, 64)
+	if err != nil {
+		wc.addFlashMessage(c, "Error parsing release year", "error")
+		c.Redirect(http.StatusSeeOther, "/movies")
+		return
+	}
+
+	quantity, err := strconv.ParseInt(c.PostForm("quantity"), 10, 64)
+	if err != nil {
+		wc.addFlashMessage(c, "Error parsing quantity", "error")
+		c.Redirect(http.StatusSeeOther, "/movies")
+		return
+	}
+
+	rentalPrice, err := parsePriceCents(c.PostForm("rental_price"))
+	if err != nil {
+		wc.addFlashMessage(c, "Error parsing rental price", "error")
+		c.Redirect(http.StatusSeeOther, "/movies")
+		return
+	}
+
+	movie := &movieModels.CreateMovieDTO{
+		Name:             name,
+		Director:         director,
+		Year:             year,
+		Genre:            strings.TrimSpace(c.PostForm("genre")),
+		RentalPriceCents: rentalPrice,
+		Quantity:         quantity,
+		StoreID:          httputil.StoreID(c),
+	}
+
+	_, err = wc.movieService.CreateMovie(c.Request.Context(), movie)
+	if err != nil {
+		wc.addFlashMessage(c, "Error creating movie "+err.Error(), "error")
+	}
+
+	wc.addFlashMessage(c, "Movie created successfully", "success")
+
+	c.Redirect(http.StatusSeeOther, "/movies")
+}
+
+func (wc *WebController) EditMovieForm(c *gin.Context) {
+	movieId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		wc.addFlashMessage(c, "Invalid Movie ID", "error")
+		c.Redirect(http.StatusSeeOther, "/movies")
+		return
+	}
+
+	movie, err := wc.movieService.GetStoreMovie(c.Request.Context(), httputil.StoreID(c), movieId)
+	if err != nil {
+		wc.addFlashMessage(c, "Movie not found", "error")
+		c.Redirect(http.StatusSeeOther, "/movies")
+		return
+	}
+
+	flashMessage, flashType := wc.getFlashMessage(c)
+
+	data := map[string]any{
+		"Title":         "Edit Movie",
+		"Movie":         movie,
+		"ActiveSection": "movies",
+		"FlashMessage":  flashMessage,
+		"FlashType":     flashType,
+		"IsEdit":        true,
+	}
+	err = wc.renderLayout(c, data)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Failed to serve movie form", "error", err)
+		c.String(http.StatusInternalServerError, "Error while trying to render template: %v", err)
+		return
+	}
+}
+
+func (wc *WebController) UpdateMovie(c *gin.Context) {
+	movieId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		wc.addFlashMessage(c, "Invalid Movie ID", "error")
+		c.Redirect(http.StatusSeeOther, "/movies")
+		return
+	}
+
+	movie, err := wc.movieService.GetMovie(c.Request.Context(), movieId)
+	if err != nil {
+		wc.addFlashMessage(c, "Movie not found", "error")
+		c.Redirect(http.StatusSeeOther, "/movies")
+		return
+	}
+
+	name := c.PostForm("name")
+	director := c.PostForm("director")
+
+	year, err := strconv.ParseInt(c.PostForm("year"), 10, 64)
+	if err != nil {
+		wc.addFlashMessage(c, "Error parsing release year", "error")
+	}
+
+	movie.Name = name
+	movie.Director = director
+	movie.Year = year
+
+	version, err := strconv.ParseInt(c.PostForm("version"), 10, 64)
+	if err != nil {
+		wc.addFlashMessage(c, "Error parsing movie version", "error")
+		c.Redirect(http.StatusSeeOther, "/movies/"+movieId.String()+"/edit")
+		return
+	}
+
+	rentalPrice, err := parsePriceCents(c.PostForm("rental_price"))
+	if err != nil {
+		wc.addFlashMessage(c, "Error parsing rental price", "error")
+		c.Redirect(http.StatusSeeOther, "/movies/"+movieId.String()+"/edit")
+		return
+	}
+
+	updateMovie := &movieModels.UpdateMovieDTO{
+		Name:             movie.Name,
+		Director:         movie.Director,
+		Year:             movie.Year,
+		Genre:            strings.TrimSpace(c.PostForm("genre")),
+		RentalPriceCents: rentalPrice,
+		Version:          version,
+	}
+
+	if _, err = wc.movieService.UpdateMovie(c.Request.Context(), movieId, updateMovie); err != nil {
+		if errors.Is(err, movieModels.ErrVersionConflict) {
+			wc.addFlashMessage(c, "This movie was changed by someone else while you were editing. Review the latest values and save again.", "warning")
+			c.Redirect(http.StatusSeeOther, "/movies/"+movieId.String()+"/edit")
+			return
+		}
+
+		wc.addFlashMessage(c, "Error trying to update movie", "error")
+		c.Redirect(http.StatusSeeOther, "/movies")
+		return
+	}
+
+	c.Redirect(http.StatusSeeOther, "/movies")
+}
+
+func (wc *WebController) ServeMovie(c *gin.Context) {
+	movieId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		wc.addFlashMessage(c, "Invalid Movie ID", "error")
+		c.Redirect(http.StatusSeeOther, "/movies")
+		return
+	}
+
+	movie, err := wc.movieService.GetStoreMovie(c.Request.Context(), httputil.StoreID(c), movieId)
+	if err != nil {
+		wc.addFlashMessage(c, "Movie not found", "error")
+		c.Redirect(http.StatusSeeOther, "/movies")
+		return
+	}
+
+	reviews, _ := wc.reviewService.GetMovieReviews(c.Request.Context(), movieId, reviewModels.StatusApproved)
+	pendingReviews, _ := wc.reviewService.GetMovieReviews(c.Request.Context(), movieId, reviewModels.StatusPending)
+	similarMovies, _ := wc.recommendationService.GetSimilarMovies(c.Request.Context(), movieId, recommendationModels.DefaultLimit)
+	users, _ := wc.userService.GetAllUsers(c.Request.Context())
+
+	flashMessage, flashType := wc.getFlashMessage(c)
+
+	data := map[string]any{
+		"Title":          movie.Name,
+		"Movie":          movie,
+		"Reviews":        reviews,
+		"PendingReviews": pendingReviews,
+		"SimilarMovies":  similarMovies,
+		"Users":          users,
+		"ActiveSection":  "movie",
+		"FlashMessage":   flashMessage,
+		"FlashType":      flashType,
+	}
+	err = wc.renderLayout(c, data)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Failed to serve movie", "error", err)
+		c.String(http.StatusInternalServerError, "Error while trying to render template: %v", err)
+		return
+	}
+}
+
+func (wc *WebController) SearchMovies(c *gin.Context) {
+	query := c.Query("q")
+
+	var movies []*movieModels.MovieDTO
+	var err error
+
+	if query != "" {
+		allMovies, err := wc.movieService.GetStoreMovies(c.Request.Context(), httputil.StoreID(c))
+		if err != nil {
+			wc.addFlashMessage(c, "Error finding movies: "+err.Error(), "error")
+			slog.ErrorContext(c.Request.Context(), "Failed to search movies", "error", err)
+			c.Redirect(http.StatusInternalServerError, "/movies")
+			return
+		}
+
+		for _, movie := range allMovies {
+			if strings.Contains(movie.Name, query) || strings.Contains(movie.Director, query) {
+				movies = append(movies, movie)
+			}
+		}
+	} else {
+		movies, err = wc.movieService.GetStoreMovies(c.Request.Context(), httputil.StoreID(c))
+	}
+	if err != nil {
+		wc.addFlashMessage(c, "Error finding users: "+err.Error(), "error")
+		slog.ErrorContext(c.Request.Context(), "Failed to search movies", "error", err)
+		c.Redirect(http.StatusInternalServerError, "/movies")
+	}
+
+	flashMessage, flashType := wc.getFlashMessage(c)
+
+	data := map[string]any{
+		"Title":         "Find movies",
+		"Movies":        movies,
+		"ActiveSection": "movies",
+		"FlashMessage":  flashMessage,
+		"FlashType":     flashType,
+		"SearchQuery":   query,
+	}
+
+	err = wc.renderLayout(c, data)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Failed to search movies", "error", err)
+		c.String(http.StatusInternalServerError, "Error rendering template: %v", err)
+		return
+	}
+}
+
+func (wc *WebController) DeleteMovie(c *gin.Context) {
+	movieId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		wc.addFlashMessage(c, "Invalid Movie ID", "error")
+		c.Redirect(http.StatusSeeOther, "/movies")
+		return
+	}
+
+	if err = wc.movieService.DeleteMovie(c.Request.Context(), movieId); err != nil {
+		wc.addFlashMessage(c, "Error while deleting movie", "error")
+		c.Redirect(http.StatusSeeOther, "/movies")
+		return
+	}
+
+	wc.addFlashMessage(c, "Movie deleted successfully", "success")
+	c.Redirect(http.StatusSeeOther, "/movies")
+}
+
+// parsePriceCents reads a price such as "3.50" into cents; an empty price
+// is free.
+func parsePriceCents(value string) (int64, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+
+	price, err := strconv.ParseFloat(value, 64)
+	if err != nil || price < 0 || math.IsInf(price, 0) {
+		return 0, fmt.Errorf("invalid price %q", value)
+	}
+
+	return int64(math.Round(price * 100)), nil
+}
@@ -0,0 +1,96 @@
+package web
+
+import (
+	notificationModels "blockbustermvc/internal/models/notification"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func (wc *WebController) ServeNotifications(c *gin.Context) {
+	userId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		wc.addFlashMessage(c, "Invalid User ID", "error")
+		c.Redirect(http.StatusSeeOther, "/users")
+		return
+	}
+
+	user, err := wc.userService.GetUser(c.Request.Context(), userId)
+	if err != nil {
+		wc.addFlashMessage(c, "User not found", "error")
+		c.Redirect(http.StatusSeeOther, "/users")
+		return
+	}
+
+	preferences, _ := wc.notificationService.GetPreferences(c.Request.Context(), userId)
+	notifications, _ := wc.notificationService.GetUserNotifications(c.Request.Context(), userId)
+
+	flashMessage, flashType := wc.getFlashMessage(c)
+
+	data := map[string]any{
+		"Title":         "Notifications",
+		"User":          user,
+		"Preferences":   preferences,
+		"Notifications": notifications,
+		"ActiveSection": "notifications",
+		"FlashMessage":  flashMessage,
+		"FlashType":     flashType,
+	}
+	err = wc.renderLayout(c, data)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Failed to serve notifications", "error", err)
+		c.String(http.StatusInternalServerError, "Error while trying to render template: %v", err)
+		return
+	}
+}
+
+func (wc *WebController) UpdateNotificationPreferences(c *gin.Context) {
+	userId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		wc.addFlashMessage(c, "Invalid User ID", "error")
+		c.Redirect(http.StatusSeeOther, "/users")
+		return
+	}
+	notificationsPage := "/users/" + userId.String() + "/notifications"
+
+	// Unchecked boxes are not submitted, so every kind is listed explicitly
+	enabled := map[string]bool{}
+	for _, kind := range c.PostFormArray("kinds") {
+		enabled[kind] = true
+	}
+
+	preferences := make([]*notificationModels.PreferenceDTO, 0, len(notificationModels.Kinds))
+	for _, kind := range notificationModels.Kinds {
+		preferences = append(preferences, &notificationModels.PreferenceDTO{Kind: kind, Enabled: enabled[kind]})
+	}
+
+	if _, err = wc.notificationService.UpdatePreferences(c.Request.Context(), userId, preferences); err != nil {
+		wc.addFlashMessage(c, "Error saving preferences: "+err.Error(), "error")
+		c.Redirect(http.StatusSeeOther, notificationsPage)
+		return
+	}
+
+	wc.addFlashMessage(c, "Notification preferences saved", "success")
+	c.Redirect(http.StatusSeeOther, notificationsPage)
+}
+
+func (wc *WebController) RetryNotification(c *gin.Context) {
+	notificationId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		wc.addFlashMessage(c, "Invalid Notification ID", "error")
+		c.Redirect(http.StatusSeeOther, "/users")
+		return
+	}
+
+	notification, err := wc.notificationService.Retry(c.Request.Context(), notificationId)
+	if err != nil {
+		wc.addFlashMessage(c, "Error retrying notification: "+err.Error(), "error")
+		c.Redirect(http.StatusSeeOther, "/users")
+		return
+	}
+
+	wc.addFlashMessage(c, "Notification queued for another attempt", "success")
+	c.Redirect(http.StatusSeeOther, "/users/"+notification.UserID.String()+"/notifications")
+}
@@ -0,0 +1,135 @@
+package web
+
+import (
+	"blockbustermvc/internal/httputil"
+	reportModels "blockbustermvc/internal/models/report"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// reportCustomers is how many of the top customers the reports page lists
+const reportCustomers = 10
+
+// reportBar is one period of the report charts, with bar heights in percent
+// of the tallest bar.
+type reportBar struct {
+	Label         string
+	Period        *reportModels.ActivityDTO
+	RentalsHeight int
+	ReturnsHeight int
+	RevenueHeight int
+}
+
+func (wc *WebController) ServeReports(c *gin.Context) {
+	flashMessage, flashType := wc.getFlashMessage(c)
+
+	filter, err := reportModels.ParseFilter(c.Request.URL.Query(), httputil.StoreID(c))
+	if err != nil {
+		flashMessage, flashType = err.Error(), "error"
+		filter, _ = reportModels.ParseFilter(url.Values{}, httputil.StoreID(c))
+	}
+
+	activity, err := wc.reportService.GetActivity(c.Request.Context(), filter)
+	if err != nil {
+		flashMessage, flashType = err.Error(), "error"
+		filter, _ = reportModels.ParseFilter(url.Values{}, httputil.StoreID(c))
+		activity, _ = wc.reportService.GetActivity(c.Request.Context(), filter)
+	}
+	utilization, _ := wc.reportService.GetUtilization(c.Request.Context(), filter)
+	customers, _ := wc.reportService.GetCustomers(c.Request.Context(), filter, reportCustomers)
+	genres, _ := wc.reportService.GetGenres(c.Request.Context())
+
+	var bars []reportBar
+	if activity != nil {
+		bars = reportBars(filter.Interval, activity.Periods)
+	}
+
+	// The downloads cover the same range as the page, whole periods included
+	query := url.Values{}
+	query.Set("interval", filter.Interval)
+	query.Set("from", filter.From.Format(time.RFC3339))
+	query.Set("to", filter.To.Format(time.RFC3339))
+	query.Set("genre", filter.Genre)
+	query.Set("director", filter.Director)
+	query.Set("store_id", reportModels.AllStores)
+	if filter.StoreID != nil {
+		query.Set("store_id", filter.StoreID.String())
+	}
+	query.Set("format", reportModels.FormatCSV)
+
+	data := map[string]any{
+		"Title":          "Reports",
+		"Filter":         filter,
+		"FromDate":       filter.From.Format(time.DateOnly),
+		"ToDate":         filter.To.AddDate(0, 0, -1).Format(time.DateOnly),
+		"AllStores":      filter.StoreID == nil,
+		"Intervals":      reportModels.Intervals,
+		"Genres":         genres,
+		"Activity":       activity,
+		"Bars":           bars,
+		"Utilization":    utilization,
+		"Customers":      customers,
+		"ActivityCSV":    template.URL("/api/reports/activity?" + query.Encode()),
+		"UtilizationCSV": template.URL("/api/reports/utilization?" + query.Encode()),
+		"CustomersCSV":   template.URL("/api/reports/customers?" + query.Encode()),
+		"ActiveSection":  "reports",
+		"FlashMessage":   flashMessage,
+		"FlashType":      flashType,
+	}
+	err = wc.renderLayout(c, data)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Failed to serve reports", "error", err)
+		c.String(http.StatusInternalServerError, "Error while trying to render template: %v", err)
+		return
+	}
+}
+
+func (wc *WebController) RefreshReports(c *gin.Context) {
+	if _, err := wc.reportService.RefreshViews(c.Request.Context()); err != nil {
+		wc.addFlashMessage(c, "Error refreshing reports: "+err.Error(), "error")
+		c.Redirect(http.StatusSeeOther, "/reports")
+		return
+	}
+
+	wc.addFlashMessage(c, "Reports refreshed", "success")
+	c.Redirect(http.StatusSeeOther, "/reports")
+}
+
+func reportBars(interval string, periods []*reportModels.ActivityDTO) []reportBar {
+	var maxCount, maxRevenue int64
+	for _, period := range periods {
+		maxCount = max(maxCount, period.Rentals, period.Returns)
+		maxRevenue = max(maxRevenue, period.RevenueCents)
+	}
+
+	layout := "02/01"
+	if interval == reportModels.IntervalMonth {
+		layout = "01/2006"
+	}
+
+	bars := make([]reportBar, len(periods))
+	for i, period := range periods {
+		bars[i] = reportBar{
+			Label:         period.PeriodStart.Format(layout),
+			Period:        period,
+			RentalsHeight: barHeight(period.Rentals, maxCount),
+			ReturnsHeight: barHeight(period.Returns, maxCount),
+			RevenueHeight: barHeight(period.RevenueCents, maxRevenue),
+		}
+	}
+
+	return bars
+}
+
+func barHeight(value, tallest int64) int {
+	if tallest == 0 {
+		return 0
+	}
+
+	return int(value * 100 / tallest)
+}
@@ -0,0 +1,81 @@
+package web
+
+import (
+	reviewModels "blockbustermvc/internal/models/review"
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func (wc *WebController) CreateReview(c *gin.Context) {
+	movieId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		wc.addFlashMessage(c, "Invalid Movie ID", "error")
+		c.Redirect(http.StatusSeeOther, "/movies")
+		return
+	}
+	moviePage := "/movies/" + movieId.String()
+
+	userId, err := uuid.Parse(c.PostForm("user_id"))
+	if err != nil {
+		wc.addFlashMessage(c, "Invalid User ID", "error")
+		c.Redirect(http.StatusSeeOther, moviePage)
+		return
+	}
+
+	rating, err := strconv.ParseInt(c.PostForm("rating"), 10, 64)
+	if err != nil || rating < 1 || rating > 5 {
+		wc.addFlashMessage(c, "Rating must be between 1 and 5", "error")
+		c.Redirect(http.StatusSeeOther, moviePage)
+		return
+	}
+
+	review := reviewModels.CreateReviewDTO{
+		MovieID: movieId,
+		UserID:  userId,
+		Rating:  rating,
+		Body:    c.PostForm("body"),
+	}
+	if _, err = wc.reviewService.CreateReview(c.Request.Context(), &review); err != nil {
+		wc.addFlashMessage(c, "Error creating review: "+err.Error(), "error")
+		c.Redirect(http.StatusSeeOther, moviePage)
+		return
+	}
+
+	wc.addFlashMessage(c, "Review submitted, it will show up once approved", "success")
+	c.Redirect(http.StatusSeeOther, moviePage)
+}
+
+func (wc *WebController) ApproveReview(c *gin.Context) {
+	wc.moderateReview(c, wc.reviewService.ApproveReview, "Review approved")
+}
+
+func (wc *WebController) RejectReview(c *gin.Context) {
+	wc.moderateReview(c, wc.reviewService.RejectReview, "Review rejected")
+}
+
+func (wc *WebController) moderateReview(
+	c *gin.Context,
+	decide func(ctx context.Context, id uuid.UUID, moderation *reviewModels.ModerateReviewDTO) (*reviewModels.ReviewDTO, error),
+	successMessage string,
+) {
+	reviewId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		wc.addFlashMessage(c, "Invalid Review ID", "error")
+		c.Redirect(http.StatusSeeOther, "/movies")
+		return
+	}
+
+	review, err := decide(c.Request.Context(), reviewId, &reviewModels.ModerateReviewDTO{Note: c.PostForm("note")})
+	if err != nil {
+		wc.addFlashMessage(c, "Error moderating review: "+err.Error(), "error")
+		c.Redirect(http.StatusSeeOther, "/movies")
+		return
+	}
+
+	wc.addFlashMessage(c, successMessage, "success")
+	c.Redirect(http.StatusSeeOther, "/movies/"+review.MovieID.String())
+}
@@ -0,0 +1,197 @@
+package web
+
+import (
+	"blockbustermvc/internal/httputil"
+	stocktakeModels "blockbustermvc/internal/models/stocktake"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func (wc *WebController) ServeStocktakes(c *gin.Context) {
+	stocktakes, _ := wc.stocktakeService.GetAllStocktakes(c.Request.Context(), httputil.StoreID(c))
+
+	flashMessage, flashType := wc.getFlashMessage(c)
+
+	data := map[string]any{
+		"Title":         "Stocktakes",
+		"Stocktakes":    stocktakes,
+		"ActiveSection": "stocktakes",
+		"FlashMessage":  flashMessage,
+		"FlashType":     flashType,
+	}
+	err := wc.renderLayout(c, data)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Failed to serve stocktakes", "error", err)
+		c.String(http.StatusInternalServerError, "Error while trying to render template: %v", err)
+		return
+	}
+}
+
+func (wc *WebController) ServeStocktake(c *gin.Context) {
+	stocktakeId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		wc.addFlashMessage(c, "Invalid Stocktake ID", "error")
+		c.Redirect(http.StatusSeeOther, "/stocktakes")
+		return
+	}
+
+	stocktake, err := wc.stocktakeService.GetStocktake(c.Request.Context(), stocktakeId)
+	if err != nil {
+		wc.addFlashMessage(c, "Stocktake not found", "error")
+		c.Redirect(http.StatusSeeOther, "/stocktakes")
+		return
+	}
+
+	movies, _ := wc.movieService.GetStoreMovies(c.Request.Context(), httputil.StoreID(c))
+	differences, _ := wc.stocktakeService.GetDifferences(c.Request.Context(), stocktakeId)
+
+	flashMessage, flashType := wc.getFlashMessage(c)
+
+	data := map[string]any{
+		"Title":         "Stocktake",
+		"Stocktake":     stocktake,
+		"Movies":        movies,
+		"Differences":   differences,
+		"IsOpen":        stocktake.Status == stocktakeModels.StatusOpen,
+		"ActiveSection": "stocktake",
+		"FlashMessage":  flashMessage,
+		"FlashType":     flashType,
+	}
+	err = wc.renderLayout(c, data)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Failed to serve stocktake", "error", err)
+		c.String(http.StatusInternalServerError, "Error while trying to render template: %v", err)
+		return
+	}
+}
+
+func (wc *WebController) OpenStocktake(c *gin.Context) {
+	stocktake, err := wc.stocktakeService.OpenStocktake(c.Request.Context(), &stocktakeModels.CreateStocktakeDTO{
+		Note:    c.PostForm("note"),
+		StoreID: httputil.StoreID(c),
+	})
+	if err != nil {
+		wc.addFlashMessage(c, "Error opening stocktake: "+err.Error(), "error")
+		c.Redirect(http.StatusSeeOther, "/stocktakes")
+		return
+	}
+
+	wc.addFlashMessage(c, "Stocktake opened", "success")
+	c.Redirect(http.StatusSeeOther, "/stocktakes/"+stocktake.ID.String())
+}
+
+func (wc *WebController) RecordStocktakeCount(c *gin.Context) {
+	stocktakeId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		wc.addFlashMessage(c, "Invalid Stocktake ID", "error")
+		c.Redirect(http.StatusSeeOther, "/stocktakes")
+		return
+	}
+
+	redirectTo := "/stocktakes/" + stocktakeId.String()
+
+	movieId, err := uuid.Parse(c.PostForm("movie_id"))
+	if err != nil {
+		wc.addFlashMessage(c, "Invalid Movie ID", "error")
+		c.Redirect(http.StatusSeeOther, redirectTo)
+		return
+	}
+
+	countedQuantity, err := strconv.ParseInt(c.PostForm("counted_quantity"), 10, 64)
+	if err != nil || countedQuantity < 0 {
+		wc.addFlashMessage(c, "Error parsing counted quantity", "error")
+		c.Redirect(http.StatusSeeOther, redirectTo)
+		return
+	}
+
+	count := &stocktakeModels.RecordCountDTO{
+		MovieID:         movieId,
+		CountedQuantity: countedQuantity,
+	}
+
+	if _, err = wc.stocktakeService.RecordCount(c.Request.Context(), stocktakeId, count); err != nil {
+		wc.addFlashMessage(c, "Error recording count: "+err.Error(), "error")
+		c.Redirect(http.StatusSeeOther, redirectTo)
+		return
+	}
+
+	wc.addFlashMessage(c, "Count recorded", "success")
+	c.Redirect(http.StatusSeeOther, redirectTo)
+}
+
+func (wc *WebController) ScanStocktakeCopy(c *gin.Context) {
+	stocktakeId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		wc.addFlashMessage(c, "Invalid Stocktake ID", "error")
+		c.Redirect(http.StatusSeeOther, "/stocktakes")
+		return
+	}
+
+	redirectTo := "/stocktakes/" + stocktakeId.String()
+
+	scanned, failed := 0, []string{}
+	for _, code := range strings.Split(c.PostForm("codes"), "\n") {
+		code = strings.TrimSpace(code)
+		if code == "" {
+			continue
+		}
+
+		if _, err := wc.stocktakeService.ScanCopy(c.Request.Context(), stocktakeId, &stocktakeModels.ScanDTO{Code: code}); err != nil {
+			failed = append(failed, code)
+			continue
+		}
+		scanned++
+	}
+
+	if len(failed) > 0 {
+		wc.addFlashMessage(c, "Scanned "+strconv.Itoa(scanned)+" copies, could not count: "+strings.Join(failed, ", "), "error")
+		c.Redirect(http.StatusSeeOther, redirectTo)
+		return
+	}
+
+	wc.addFlashMessage(c, "Scanned "+strconv.Itoa(scanned)+" copies", "success")
+	c.Redirect(http.StatusSeeOther, redirectTo)
+}
+
+func (wc *WebController) ApproveStocktake(c *gin.Context) {
+	stocktakeId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		wc.addFlashMessage(c, "Invalid Stocktake ID", "error")
+		c.Redirect(http.StatusSeeOther, "/stocktakes")
+		return
+	}
+
+	redirectTo := "/stocktakes/" + stocktakeId.String()
+
+	if _, err = wc.stocktakeService.ApproveStocktake(c.Request.Context(), stocktakeId); err != nil {
+		wc.addFlashMessage(c, "Error approving stocktake: "+err.Error(), "error")
+		c.Redirect(http.StatusSeeOther, redirectTo)
+		return
+	}
+
+	wc.addFlashMessage(c, "Stocktake approved and stock adjusted", "success")
+	c.Redirect(http.StatusSeeOther, redirectTo)
+}
+
+func (wc *WebController) CancelStocktake(c *gin.Context) {
+	stocktakeId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		wc.addFlashMessage(c, "Invalid Stocktake ID", "error")
+		c.Redirect(http.StatusSeeOther, "/stocktakes")
+		return
+	}
+
+	if _, err = wc.stocktakeService.CancelStocktake(c.Request.Context(), stocktakeId); err != nil {
+		wc.addFlashMessage(c, "Error cancelling stocktake: "+err.Error(), "error")
+		c.Redirect(http.StatusSeeOther, "/stocktakes/"+stocktakeId.String())
+		return
+	}
+
+	wc.addFlashMessage(c, "Stocktake cancelled", "success")
+	c.Redirect(http.StatusSeeOther, "/stocktakes")
+}
@@ -0,0 +1,124 @@
+package web
+
+import (
+	"blockbustermvc/internal/httputil"
+	storeModels "blockbustermvc/internal/models/store"
+	transferModels "blockbustermvc/internal/models/transfer"
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func (wc *WebController) ServeTransfers(c *gin.Context) {
+	storeId := httputil.StoreID(c)
+
+	pending, _ := wc.transferService.GetPendingTransfers(c.Request.Context(), storeId)
+	transfers, _ := wc.transferService.GetStoreTransfers(c.Request.Context(), storeId)
+	movies, _ := wc.movieService.GetAllMovies(c.Request.Context())
+	stores, _ := wc.storeService.GetAllStores(c.Request.Context())
+
+	otherStores := []*storeModels.StoreDTO{}
+	for _, store := range stores {
+		if store.ID != storeId {
+			otherStores = append(otherStores, store)
+		}
+	}
+
+	flashMessage, flashType := wc.getFlashMessage(c)
+
+	data := map[string]any{
+		"Title":         "Stock Transfers",
+		"Pending":       pending,
+		"Transfers":     transfers,
+		"Movies":        movies,
+		"OtherStores":   otherStores,
+		"StoreID":       storeId,
+		"ActiveSection": "transfers",
+		"FlashMessage":  flashMessage,
+		"FlashType":     flashType,
+	}
+	err := wc.renderLayout(c, data)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Failed to serve transfers", "error", err)
+		c.String(http.StatusInternalServerError, "Error while trying to render template: %v", err)
+		return
+	}
+}
+
+func (wc *WebController) RequestTransfer(c *gin.Context) {
+	movieId, err := uuid.Parse(c.PostForm("movie_id"))
+	if err != nil {
+		wc.addFlashMessage(c, "Invalid Movie ID", "error")
+		c.Redirect(http.StatusSeeOther, "/transfers")
+		return
+	}
+
+	fromStoreId, err := uuid.Parse(c.PostForm("from_store_id"))
+	if err != nil {
+		wc.addFlashMessage(c, "Invalid Store ID", "error")
+		c.Redirect(http.StatusSeeOther, "/transfers")
+		return
+	}
+
+	quantity, err := strconv.ParseInt(c.PostForm("quantity"), 10, 64)
+	if err != nil || quantity < 1 {
+		wc.addFlashMessage(c, "Error parsing quantity", "error")
+		c.Redirect(http.StatusSeeOther, "/transfers")
+		return
+	}
+
+	transfer := &transferModels.CreateTransferDTO{
+		MovieID:     movieId,
+		FromStoreID: fromStoreId,
+		ToStoreID:   httputil.StoreID(c),
+		Quantity:    quantity,
+		Note:        c.PostForm("note"),
+	}
+
+	if _, err = wc.transferService.RequestTransfer(c.Request.Context(), transfer); err != nil {
+		wc.addFlashMessage(c, "Error requesting transfer: "+err.Error(), "error")
+		c.Redirect(http.StatusSeeOther, "/transfers")
+		return
+	}
+
+	wc.addFlashMessage(c, "Transfer requested", "success")
+	c.Redirect(http.StatusSeeOther, "/transfers")
+}
+
+func (wc *WebController) ShipTransfer(c *gin.Context) {
+	wc.changeTransfer(c, wc.transferService.ShipTransfer, "Transfer shipped")
+}
+
+func (wc *WebController) ReceiveTransfer(c *gin.Context) {
+	wc.changeTransfer(c, wc.transferService.ReceiveTransfer, "Transfer received and stock booked")
+}
+
+func (wc *WebController) CancelTransfer(c *gin.Context) {
+	wc.changeTransfer(c, wc.transferService.CancelTransfer, "Transfer cancelled")
+}
+
+func (wc *WebController) changeTransfer(
+	c *gin.Context,
+	change func(ctx context.Context, storeId, id uuid.UUID) (*transferModels.TransferDTO, error),
+	successMessage string,
+) {
+	transferId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		wc.addFlashMessage(c, "Invalid Transfer ID", "error")
+		c.Redirect(http.StatusSeeOther, "/transfers")
+		return
+	}
+
+	if _, err = change(c.Request.Context(), httputil.StoreID(c), transferId); err != nil {
+		wc.addFlashMessage(c, "Error updating transfer: "+err.Error(), "error")
+		c.Redirect(http.StatusSeeOther, "/transfers")
+		return
+	}
+
+	wc.addFlashMessage(c, successMessage, "success")
+	c.Redirect(http.StatusSeeOther, "/transfers")
+}
@@ -0,0 +1,251 @@
+package web
+
+import (
+	movieModels "blockbustermvc/internal/models/movie"
+	recommendationModels "blockbustermvc/internal/models/recommendation"
+	userModels "blockbustermvc/internal/models/user"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func (wc *WebController) ServeUsers(c *gin.Context) {
+	users, _ := wc.userService.GetAllUsers(c.Request.Context())
+
+	flashMessage, flashType := wc.getFlashMessage(c)
+	data := map[string]any{
+		"Title":         "User Management",
+		"Users":         users,
+		"ActiveSection": "users",
+		"FlashMessage":  flashMessage,
+		"FlashType":     flashType,
+	}
+	err := wc.renderLayout(c, data)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Failed to serve users", "error", err)
+		c.String(http.StatusInternalServerError, "Error while trying to render template: %v", err)
+		return
+	}
+}
+
+func (wc *WebController) CreateUser(c *gin.Context) {
+	name := c.PostForm("name")
+	email := c.PostForm("email")
+
+	user := &userModels.CreateUserDTO{
+		UserName: name,
+		Email:    email,
+	}
+
+	_, err := wc.userService.CreateUser(c.Request.Context(), user)
+	if err != nil {
+		wc.addFlashMessage(c, "Error creating user "+err.Error(), "error")
+	}
+
+	wc.addFlashMessage(c, "User created successfully", "success")
+
+	c.Redirect(http.StatusSeeOther, "/users")
+}
+
+func (wc *WebController) EditUserForm(c *gin.Context) {
+	userId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		wc.addFlashMessage(c, "Invalid User ID", "error")
+		c.Redirect(http.StatusSeeOther, "/users")
+		return
+	}
+
+	user, err := wc.userService.GetUser(c.Request.Context(), userId)
+	if err != nil {
+		wc.addFlashMessage(c, "User not found", "error")
+		c.Redirect(http.StatusSeeOther, "/user")
+		return
+	}
+
+	flashMessage, flashType := wc.getFlashMessage(c)
+
+	data := map[string]any{
+		"Title":         "Edit User",
+		"User":          user,
+		"ActiveSection": "users",
+		"FlashMessage":  flashMessage,
+		"FlashType":     flashType,
+		"IsEdit":        true,
+	}
+	err = wc.renderLayout(c, data)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Failed to serve user form", "error", err)
+		c.String(http.StatusInternalServerError, "Error while trying to render template: %v", err)
+		return
+	}
+}
+
+func (wc *WebController) ServeUserLoans(c *gin.Context) {
+	userId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		wc.addFlashMessage(c, "Invalid User ID", "error")
+		c.Redirect(http.StatusSeeOther, "/users")
+		return
+	}
+
+	user, err := wc.userService.GetUser(c.Request.Context(), userId)
+	if err != nil {
+		wc.addFlashMessage(c, "User not found", "error")
+		c.Redirect(http.StatusSeeOther, "/users")
+		return
+	}
+
+	includeArchived := c.Query("include_archived") == "true"
+
+	loans, _ := wc.loanService.GetUserLoans(c.Request.Context(), userId, includeArchived)
+	movies, _ := wc.movieService.GetAllMovies(c.Request.Context())
+	recommendations, _ := wc.recommendationService.GetUserRecommendations(c.Request.Context(), userId, recommendationModels.DefaultLimit)
+
+	movieMap := make(map[uuid.UUID]*movieModels.MovieDTO, len(movies))
+	for _, movie := range movies {
+		movieMap[movie.ID] = movie
+	}
+
+	flashMessage, flashType := wc.getFlashMessage(c)
+
+	data := map[string]any{
+		"Title":           "User Loans",
+		"User":            user,
+		"Loans":           loans,
+		"MovieMap":        movieMap,
+		"Recommendations": recommendations,
+		"ActiveSection":   "users",
+		"FlashMessage":    flashMessage,
+		"FlashType":       flashType,
+		"ShowUserLoans":   true,
+		"IncludeArchived": includeArchived,
+	}
+	err = wc.renderLayout(c, data)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Failed to serve user loans", "error", err)
+		c.String(http.StatusInternalServerError, "Error while trying to render template: %v", err)
+		return
+	}
+}
+
+func (wc *WebController) UpdateUser(c *gin.Context) {
+	userId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		wc.addFlashMessage(c, "Invalid User ID", "error")
+		c.Redirect(http.StatusSeeOther, "/users")
+		return
+	}
+
+	user, err := wc.userService.GetUser(c.Request.Context(), userId)
+	if err != nil {
+		wc.addFlashMessage(c, "User not found", "error")
+		c.Redirect(http.StatusSeeOther, "/users")
+		return
+	}
+
+	name := c.PostForm("name")
+	email := c.PostForm("email")
+	user.UserName = name
+	user.Email = email
+
+	version, err := strconv.ParseInt(c.PostForm("version"), 10, 64)
+	if err != nil {
+		wc.addFlashMessage(c, "Error parsing user version", "error")
+		c.Redirect(http.StatusSeeOther, "/users/"+userId.String()+"/edit")
+		return
+	}
+
+	updateUser := &userModels.UpdateUserDTO{
+		UserName: user.UserName,
+		Email:    user.Email,
+		Version:  version,
+	}
+
+	if _, err = wc.userService.UpdateUser(c.Request.Context(), userId, updateUser); err != nil {
+		if errors.Is(err, userModels.ErrVersionConflict) {
+			wc.addFlashMessage(c, "This user was changed by someone else while you were editing. Review the latest values and save again.", "warning")
+			c.Redirect(http.StatusSeeOther, "/users/"+userId.String()+"/edit")
+			return
+		}
+
+		wc.addFlashMessage(c, "Error trying to update user", "error")
+		c.Redirect(http.StatusSeeOther, "/users")
+		return
+	}
+
+	c.Redirect(http.StatusSeeOther, "/users")
+}
+
+func (wc *WebController) SearchUsers(c *gin.Context) {
+	query := c.Query("q")
+
+	var users []*userModels.UserDTO
+	var err error
+
+	if query != "" {
+
+		allUsers, err := wc.userService.GetAllUsers(c.Request.Context())
+		if err != nil {
+			wc.addFlashMessage(c, "Error finding users: "+err.Error(), "error")
+			slog.ErrorContext(c.Request.Context(), "Failed to search users", "error", err)
+			c.Redirect(http.StatusInternalServerError, "/users")
+			return
+		}
+
+		for _, user := range allUsers {
+			if strings.Contains(user.UserName, query) || strings.Contains(user.Email, query) {
+				users = append(users, user)
+			}
+		}
+	} else {
+		users, err = wc.userService.GetAllUsers(c.Request.Context())
+	}
+
+	if err != nil {
+		wc.addFlashMessage(c, "Error finding users: "+err.Error(), "error")
+		slog.ErrorContext(c.Request.Context(), "Failed to search users", "error", err)
+		c.Redirect(http.StatusInternalServerError, "/users")
+
+	}
+
+	flashMessage, flashType := wc.getFlashMessage(c)
+
+	data := map[string]any{
+		"Title":         "Find users",
+		"Users":         users,
+		"ActiveSection": "users",
+		"FlashMessage":  flashMessage,
+		"FlashType":     flashType,
+		"SearchQuery":   query,
+	}
+
+	err = wc.renderLayout(c, data)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Failed to search users", "error", err)
+		c.String(http.StatusInternalServerError, "Error rendering template: %v", err)
+		return
+	}
+}
+
+func (wc *WebController) DeleteUser(c *gin.Context) {
+	userId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		wc.addFlashMessage(c, "Invalid User ID", "error")
+		c.Redirect(http.StatusSeeOther, "/users")
+		return
+	}
+
+	if err = wc.userService.DeleteUser(c.Request.Context(), userId); err != nil {
+		wc.addFlashMessage(c, "Error while deleting user", "error")
+		c.Redirect(http.StatusSeeOther, "/users")
+		return
+	}
+
+	wc.addFlashMessage(c, "User deleted successfully", "success")
+	c.Redirect(http.StatusSeeOther, "/users")
+}
@@ -0,0 +1,193 @@
+package web
+
+import (
+	"blockbustermvc/internal/httputil"
+	eventModels "blockbustermvc/internal/models/event"
+	webhookModels "blockbustermvc/internal/models/webhook"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func (wc *WebController) ServeWebhooks(c *gin.Context) {
+	webhooks, _ := wc.webhookService.GetWebhooks(c.Request.Context())
+
+	flashMessage, flashType := wc.getFlashMessage(c)
+
+	data := map[string]any{
+		"Title":         "Webhooks",
+		"Webhooks":      webhooks,
+		"EventTypes":    eventModels.Types,
+		"ActiveSection": "webhooks",
+		"FlashMessage":  flashMessage,
+		"FlashType":     flashType,
+	}
+	err := wc.renderLayout(c, data)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Failed to serve webhooks", "error", err)
+		c.String(http.StatusInternalServerError, "Error while trying to render template: %v", err)
+		return
+	}
+}
+
+func (wc *WebController) ServeWebhook(c *gin.Context) {
+	webhookId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		wc.addFlashMessage(c, "Invalid Webhook ID", "error")
+		c.Redirect(http.StatusSeeOther, "/webhooks")
+		return
+	}
+
+	webhook, err := wc.webhookService.GetWebhook(c.Request.Context(), webhookId)
+	if err != nil {
+		wc.addFlashMessage(c, "Webhook not found", "error")
+		c.Redirect(http.StatusSeeOther, "/webhooks")
+		return
+	}
+
+	status := c.Query("status")
+	deliveries, _ := wc.webhookService.GetDeliveries(c.Request.Context(), webhookId, status)
+
+	flashMessage, flashType := wc.getFlashMessage(c)
+
+	data := map[string]any{
+		"Title":         "Webhook",
+		"Webhook":       webhook,
+		"Deliveries":    deliveries,
+		"Status":        status,
+		"Statuses":      webhookModels.Statuses,
+		"ActiveSection": "webhook",
+		"FlashMessage":  flashMessage,
+		"FlashType":     flashType,
+	}
+	err = wc.renderLayout(c, data)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Failed to serve webhook", "error", err)
+		c.String(http.StatusInternalServerError, "Error while trying to render template: %v", err)
+		return
+	}
+}
+
+func (wc *WebController) CreateWebhook(c *gin.Context) {
+	webhook := &webhookModels.CreateWebhookDTO{
+		URL:        strings.TrimSpace(c.PostForm("url")),
+		EventTypes: c.PostFormArray("event_types"),
+		Secret:     c.PostForm("secret"),
+	}
+
+	if err := httputil.ValidateStruct(webhook); err != nil {
+		wc.addFlashMessage(c, "Invalid webhook: "+err.Error(), "error")
+		c.Redirect(http.StatusSeeOther, "/webhooks")
+		return
+	}
+
+	created, err := wc.webhookService.CreateWebhook(c.Request.Context(), webhook)
+	if err != nil {
+		wc.addFlashMessage(c, "Error creating webhook: "+err.Error(), "error")
+		c.Redirect(http.StatusSeeOther, "/webhooks")
+		return
+	}
+
+	wc.addFlashMessage(c, "Webhook created", "success")
+	c.Redirect(http.StatusSeeOther, "/webhooks/"+created.ID.String())
+}
+
+func (wc *WebController) SendWebhookTest(c *gin.Context) {
+	webhookId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		wc.addFlashMessage(c, "Invalid Webhook ID", "error")
+		c.Redirect(http.StatusSeeOther, "/webhooks")
+		return
+	}
+	webhookPage := "/webhooks/" + webhookId.String()
+
+	delivery, err := wc.webhookService.SendTest(c.Request.Context(), webhookId)
+	if err != nil {
+		wc.addFlashMessage(c, "Error sending test event: "+err.Error(), "error")
+		c.Redirect(http.StatusSeeOther, webhookPage)
+		return
+	}
+
+	if delivery.Status != webhookModels.StatusDelivered {
+		wc.addFlashMessage(c, "Test event not accepted: "+delivery.LastError, "error")
+		c.Redirect(http.StatusSeeOther, webhookPage)
+		return
+	}
+
+	wc.addFlashMessage(c, "Test event delivered", "success")
+	c.Redirect(http.StatusSeeOther, webhookPage)
+}
+
+func (wc *WebController) ToggleWebhook(c *gin.Context) {
+	webhookId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		wc.addFlashMessage(c, "Invalid Webhook ID", "error")
+		c.Redirect(http.StatusSeeOther, "/webhooks")
+		return
+	}
+
+	webhook, err := wc.webhookService.GetWebhook(c.Request.Context(), webhookId)
+	if err != nil {
+		wc.addFlashMessage(c, "Webhook not found", "error")
+		c.Redirect(http.StatusSeeOther, "/webhooks")
+		return
+	}
+
+	_, err = wc.webhookService.UpdateWebhook(c.Request.Context(), webhookId, &webhookModels.UpdateWebhookDTO{
+		URL:        webhook.URL,
+		EventTypes: webhook.EventTypes,
+		Active:     !webhook.Active,
+	})
+	if err != nil {
+		wc.addFlashMessage(c, "Error updating webhook: "+err.Error(), "error")
+		c.Redirect(http.StatusSeeOther, "/webhooks")
+		return
+	}
+
+	if webhook.Active {
+		wc.addFlashMessage(c, "Webhook paused", "success")
+	} else {
+		wc.addFlashMessage(c, "Webhook resumed", "success")
+	}
+	c.Redirect(http.StatusSeeOther, "/webhooks")
+}
+
+func (wc *WebController) DeleteWebhook(c *gin.Context) {
+	webhookId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		wc.addFlashMessage(c, "Invalid Webhook ID", "error")
+		c.Redirect(http.StatusSeeOther, "/webhooks")
+		return
+	}
+
+	if err := wc.webhookService.DeleteWebhook(c.Request.Context(), webhookId); err != nil {
+		wc.addFlashMessage(c, "Error deleting webhook: "+err.Error(), "error")
+		c.Redirect(http.StatusSeeOther, "/webhooks")
+		return
+	}
+
+	wc.addFlashMessage(c, "Webhook deleted", "success")
+	c.Redirect(http.StatusSeeOther, "/webhooks")
+}
+
+func (wc *WebController) RetryWebhookDelivery(c *gin.Context) {
+	deliveryId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		wc.addFlashMessage(c, "Invalid Delivery ID", "error")
+		c.Redirect(http.StatusSeeOther, "/webhooks")
+		return
+	}
+
+	delivery, err := wc.webhookService.Retry(c.Request.Context(), deliveryId)
+	if err != nil {
+		wc.addFlashMessage(c, "Error retrying delivery: "+err.Error(), "error")
+		c.Redirect(http.StatusSeeOther, "/webhooks")
+		return
+	}
+
+	wc.addFlashMessage(c, "Delivery queued for another attempt", "success")
+	c.Redirect(http.StatusSeeOther, "/webhooks/"+delivery.WebhookID.String())
+}
@@ -0,0 +1,100 @@
+package web
+
+import (
+	wishlistModels "blockbustermvc/internal/models/wishlist"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func (wc *WebController) ServeWishlist(c *gin.Context) {
+	userId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		wc.addFlashMessage(c, "Invalid User ID", "error")
+		c.Redirect(http.StatusSeeOther, "/users")
+		return
+	}
+
+	user, err := wc.userService.GetUser(c.Request.Context(), userId)
+	if err != nil {
+		wc.addFlashMessage(c, "User not found", "error")
+		c.Redirect(http.StatusSeeOther, "/users")
+		return
+	}
+
+	items, _ := wc.wishlistService.GetUserWishlist(c.Request.Context(), userId)
+	movies, _ := wc.movieService.GetAllMovies(c.Request.Context())
+
+	flashMessage, flashType := wc.getFlashMessage(c)
+
+	data := map[string]any{
+		"Title":         "Wishlist",
+		"User":          user,
+		"Items":         items,
+		"Movies":        movies,
+		"ActiveSection": "wishlist",
+		"FlashMessage":  flashMessage,
+		"FlashType":     flashType,
+	}
+	err = wc.renderLayout(c, data)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Failed to serve wishlist", "error", err)
+		c.String(http.StatusInternalServerError, "Error while trying to render template: %v", err)
+		return
+	}
+}
+
+func (wc *WebController) AddWishlistItem(c *gin.Context) {
+	userId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		wc.addFlashMessage(c, "Invalid User ID", "error")
+		c.Redirect(http.StatusSeeOther, "/users")
+		return
+	}
+	wishlistPage := "/users/" + userId.String() + "/wishlist"
+
+	movieId, err := uuid.Parse(c.PostForm("movie_id"))
+	if err != nil {
+		wc.addFlashMessage(c, "Invalid Movie ID", "error")
+		c.Redirect(http.StatusSeeOther, wishlistPage)
+		return
+	}
+
+	item, err := wc.wishlistService.AddItem(c.Request.Context(), userId, &wishlistModels.AddWishlistItemDTO{MovieID: movieId})
+	if err != nil {
+		wc.addFlashMessage(c, "Error adding to wishlist: "+err.Error(), "error")
+		c.Redirect(http.StatusSeeOther, wishlistPage)
+		return
+	}
+
+	wc.addFlashMessage(c, item.MovieName+" added to the wishlist", "success")
+	c.Redirect(http.StatusSeeOther, wishlistPage)
+}
+
+func (wc *WebController) RemoveWishlistItem(c *gin.Context) {
+	userId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		wc.addFlashMessage(c, "Invalid User ID", "error")
+		c.Redirect(http.StatusSeeOther, "/users")
+		return
+	}
+	wishlistPage := "/users/" + userId.String() + "/wishlist"
+
+	movieId, err := uuid.Parse(c.Param("movieId"))
+	if err != nil {
+		wc.addFlashMessage(c, "Invalid Movie ID", "error")
+		c.Redirect(http.StatusSeeOther, wishlistPage)
+		return
+	}
+
+	if err = wc.wishlistService.RemoveItem(c.Request.Context(), userId, movieId); err != nil {
+		wc.addFlashMessage(c, "Error removing from wishlist: "+err.Error(), "error")
+		c.Redirect(http.StatusSeeOther, wishlistPage)
+		return
+	}
+
+	wc.addFlashMessage(c, "Movie removed from the wishlist", "success")
+	c.Redirect(http.StatusSeeOther, wishlistPage)
+}
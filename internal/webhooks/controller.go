@@ -0,0 +1,208 @@
+package webhooks
+
+import (
+	models "blockbustermvc/internal/models/webhook"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type WebhooksController struct {
+	webhookService models.IWebhookService
+}
+
+func NewWebhooksController(webhookService models.IWebhookService) *WebhooksController {
+	return &WebhooksController{
+		webhookService: webhookService,
+	}
+}
+
+func (wc *WebhooksController) RegisterRoutes(r *gin.RouterGroup) {
+	webhooks := r.Group("/webhooks")
+
+	{
+		webhooks.POST("", wc.CreateWebhook)
+		webhooks.GET("", wc.GetWebhooks)
+		webhooks.GET("/deliveries/:id/attempts", wc.GetAttempts)
+		webhooks.POST("/deliveries/:id/retry", wc.RetryDelivery)
+		webhooks.GET("/:id", wc.GetWebhook)
+		webhooks.PUT("/:id", wc.UpdateWebhook)
+		webhooks.DELETE("/:id", wc.DeleteWebhook)
+		webhooks.GET("/:id/deliveries", wc.GetDeliveries)
+		webhooks.POST("/:id/test", wc.SendTest)
+	}
+}
+
+func (wc *WebhooksController) CreateWebhook(ctx *gin.Context) {
+	var req models.CreateWebhookDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body",
+		})
+		return
+	}
+
+	webhook, err := wc.webhookService.CreateWebhook(ctx.Request.Context(), &req)
+	if err != nil {
+		respondWithWebhookError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, webhook)
+}
+
+func (wc *WebhooksController) GetWebhooks(ctx *gin.Context) {
+	webhooks, err := wc.webhookService.GetWebhooks(ctx.Request.Context())
+	if err != nil {
+		respondWithWebhookError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, webhooks)
+}
+
+func (wc *WebhooksController) GetWebhook(ctx *gin.Context) {
+	webhookId, ok := parseID(ctx, "Invalid webhook ID")
+	if !ok {
+		return
+	}
+
+	webhook, err := wc.webhookService.GetWebhook(ctx.Request.Context(), webhookId)
+	if err != nil {
+		respondWithWebhookError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, webhook)
+}
+
+func (wc *WebhooksController) UpdateWebhook(ctx *gin.Context) {
+	webhookId, ok := parseID(ctx, "Invalid webhook ID")
+	if !ok {
+		return
+	}
+
+	var req models.UpdateWebhookDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body",
+		})
+		return
+	}
+
+	webhook, err := wc.webhookService.UpdateWebhook(ctx.Request.Context(), webhookId, &req)
+	if err != nil {
+		respondWithWebhookError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, webhook)
+}
+
+func (wc *WebhooksController) DeleteWebhook(ctx *gin.Context) {
+	webhookId, ok := parseID(ctx, "Invalid webhook ID")
+	if !ok {
+		return
+	}
+
+	if err := wc.webhookService.DeleteWebhook(ctx.Request.Context(), webhookId); err != nil {
+		respondWithWebhookError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func (wc *WebhooksController) GetDeliveries(ctx *gin.Context) {
+	webhookId, ok := parseID(ctx, "Invalid webhook ID")
+	if !ok {
+		return
+	}
+
+	deliveries, err := wc.webhookService.GetDeliveries(ctx.Request.Context(), webhookId, ctx.Query("status"))
+	if err != nil {
+		respondWithWebhookError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, deliveries)
+}
+
+func (wc *WebhooksController) SendTest(ctx *gin.Context) {
+	webhookId, ok := parseID(ctx, "Invalid webhook ID")
+	if !ok {
+		return
+	}
+
+	delivery, err := wc.webhookService.SendTest(ctx.Request.Context(), webhookId)
+	if err != nil {
+		respondWithWebhookError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, delivery)
+}
+
+func (wc *WebhooksController) GetAttempts(ctx *gin.Context) {
+	deliveryId, ok := parseID(ctx, "Invalid delivery ID")
+	if !ok {
+		return
+	}
+
+	attempts, err := wc.webhookService.GetAttempts(ctx.Request.Context(), deliveryId)
+	if err != nil {
+		respondWithWebhookError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, attempts)
+}
+
+func (wc *WebhooksController) RetryDelivery(ctx *gin.Context) {
+	deliveryId, ok := parseID(ctx, "Invalid delivery ID")
+	if !ok {
+		return
+	}
+
+	delivery, err := wc.webhookService.Retry(ctx.Request.Context(), deliveryId)
+	if err != nil {
+		respondWithWebhookError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, delivery)
+}
+
+func parseID(ctx *gin.Context, message string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": message,
+		})
+		return uuid.Nil, false
+	}
+
+	return id, true
+}
+
+func respondWithWebhookError(ctx *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, models.ErrWebhookNotFound), errors.Is(err, models.ErrDeliveryNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, models.ErrNotFailed):
+		status = http.StatusConflict
+	case errors.Is(err, models.ErrUnknownEventType):
+		status = http.StatusUnprocessableEntity
+	}
+
+	if status == http.StatusInternalServerError {
+		slog.ErrorContext(ctx.Request.Context(), "Webhook request failed", "error", err)
+	}
+	ctx.JSON(status, gin.H{
+		"error": err.Error(),
+	})
+}
@@ -0,0 +1,45 @@
+package webhooks
+
+import (
+	"blockbustermvc/internal/config"
+	"blockbustermvc/internal/jobs"
+	jobModels "blockbustermvc/internal/models/job"
+	models "blockbustermvc/internal/models/webhook"
+	"context"
+	"log/slog"
+	"time"
+)
+
+// defaultDeliveryInterval is used when BLK_WEBHOOKS_INTERVAL is unset.
+const defaultDeliveryInterval = 15 * time.Second
+
+// ConfiguredDeliveryInterval reads how often queued deliveries are sent
+// from BLK_WEBHOOKS_INTERVAL. Zero unschedules the delivery job.
+func ConfiguredDeliveryInterval() (time.Duration, error) {
+	return config.Duration("BLK_WEBHOOKS_INTERVAL", defaultDeliveryInterval, true)
+}
+
+// DeliverJob is the job kind that sends due webhook deliveries.
+const DeliverJob = "webhooks.deliver"
+
+// RegisterJobs lets the job worker send webhook deliveries, and schedules
+// it every interval. Zero removes the schedule.
+func RegisterJobs(ctx context.Context, jobService jobModels.IJobService, service models.IWebhookService, interval time.Duration) error {
+	jobService.Register(DeliverJob, func(ctx context.Context, _ *jobModels.JobDTO) error {
+		result, err := service.Dispatch(ctx)
+		if err != nil {
+			return err
+		}
+
+		if result.Delivered+result.Failed > 0 {
+			slog.InfoContext(ctx, "Dispatched webhooks", "delivered", result.Delivered, "failed", result.Failed)
+		}
+		return nil
+	})
+
+	if interval == 0 {
+		return jobService.Unschedule(ctx, DeliverJob)
+	}
+
+	return jobService.Schedule(ctx, DeliverJob, DeliverJob, jobs.Every(interval))
+}
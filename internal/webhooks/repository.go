@@ -0,0 +1,563 @@
+package webhooks
+
+import (
+	models "blockbustermvc/internal/models/webhook"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// claimLease is how long a claimed delivery is hidden from other
+// dispatchers while it is sent.
+const claimLease = 5 * time.Minute
+
+const webhookColumns = `id, url, event_types, secret, active, created_at, updated_at`
+
+const deliveryColumns = `id, webhook_id, event_id, event_type, payload::TEXT, status, attempts, response_code, last_error,
+	next_attempt_at, delivered_at, created_at, updated_at`
+
+/*
+webhookRepository is a struct that represents a Postgres database for storing webhooks and their deliveries.
+
+Fields:
+- DB (*pgxpool.Pool): A pointer to a Postgres database connection pool.
+
+Behavior:
+- Provides methods for interacting with the webhooks, webhook_deliveries and webhook_attempts tables.
+- Hands queued deliveries out to dispatchers with FOR UPDATE SKIP LOCKED, so several servers never send the same one.
+*/
+type webhookRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewWebhookRepository(db *pgxpool.Pool) models.IWebhookRepository {
+	return &webhookRepository{
+		DB: db,
+	}
+}
+
+/*
+CreateWebhook is a method of webhookRepository struct that inserts a webhook into the postgres database.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- webhook (*models.CreateWebhookDTO): A pointer to a CreateWebhookDTO struct containing the URL, event types and secret.
+
+Returns:
+- (*models.WebhookDTO, error): A pointer to a WebhookDTO struct containing the active webhook, or an error if the insertion fails.
+*/
+func (r *webhookRepository) CreateWebhook(ctx context.Context, webhook *models.CreateWebhookDTO) (*models.WebhookDTO, error) {
+	now := time.Now()
+	return scanWebhook(r.DB.QueryRow(ctx, `
+		INSERT INTO webhooks (url, event_types, secret, active, created_at, updated_at)
+		VALUES ($1, $2, $3, true, $4, $4)
+		RETURNING `+webhookColumns,
+		webhook.URL,
+		webhook.EventTypes,
+		webhook.Secret,
+		now,
+	))
+}
+
+/*
+GetWebhooks is a method of webhookRepository struct that retrieves every webhook from the postgres database.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+
+Returns:
+- ([]*models.WebhookDTO, error): A slice of WebhookDTO structs, oldest first, or an error if the retrieval fails.
+*/
+func (r *webhookRepository) GetWebhooks(ctx context.Context) ([]*models.WebhookDTO, error) {
+	rows, err := r.DB.Query(ctx, `SELECT `+webhookColumns+` FROM webhooks ORDER BY created_at`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	webhooks := []*models.WebhookDTO{}
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, webhook)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over webhooks: %w", err)
+	}
+
+	return webhooks, nil
+}
+
+/*
+GetWebhook is a method of webhookRepository struct that retrieves a webhook from the postgres database by its ID.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- id (uuid.UUID): The ID of the webhook.
+
+Returns:
+- (*models.WebhookDTO, error): A pointer to a WebhookDTO struct, or models.ErrWebhookNotFound if there is none.
+*/
+func (r *webhookRepository) GetWebhook(ctx context.Context, id uuid.UUID) (*models.WebhookDTO, error) {
+	return scanWebhook(r.DB.QueryRow(ctx, `SELECT `+webhookColumns+` FROM webhooks WHERE id = $1`, id))
+}
+
+/*
+UpdateWebhook is a method of webhookRepository struct that replaces the settings of a webhook in the postgres database.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- id (uuid.UUID): The ID of the webhook.
+- webhook (*models.UpdateWebhookDTO): A pointer to an UpdateWebhookDTO struct containing the new settings.
+
+Returns:
+- (*models.WebhookDTO, error): A pointer to a WebhookDTO struct containing the updated webhook, or models.ErrWebhookNotFound if there is none.
+
+Behavior:
+- An empty secret keeps the current one.
+*/
+func (r *webhookRepository) UpdateWebhook(ctx context.Context, id uuid.UUID, webhook *models.UpdateWebhookDTO) (*models.WebhookDTO, error) {
+	return scanWebhook(r.DB.QueryRow(ctx, `
+		UPDATE webhooks
+		SET url = $2, event_types = $3, secret = COALESCE(NULLIF($4, ''), secret), active = $5, updated_at = $6
+		WHERE id = $1
+		RETURNING `+webhookColumns,
+		id,
+		webhook.URL,
+		webhook.EventTypes,
+		webhook.Secret,
+		webhook.Active,
+		time.Now(),
+	))
+}
+
+/*
+DeleteWebhook is a method of webhookRepository struct that deletes a webhook and its deliveries from the postgres database.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- id (uuid.UUID): The ID of the webhook.
+
+Returns:
+- (error): models.ErrWebhookNotFound if there is no such webhook, or an error if the deletion fails.
+*/
+func (r *webhookRepository) DeleteWebhook(ctx context.Context, id uuid.UUID) error {
+	tag, err := r.DB.Exec(ctx, `DELETE FROM webhooks WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return models.ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+/*
+QueueDeliveries is a method of webhookRepository struct that queues a domain event for every active webhook subscribed to its type.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- eventId (uuid.UUID): The ID of the event.
+- eventType (string): The type of the event.
+- payload (json.RawMessage): The payload of the event.
+
+Returns:
+- (int, error): The number of deliveries queued, or an error if queueing fails.
+
+Behavior:
+- Each webhook gets each event at most once, however often the event is queued.
+*/
+func (r *webhookRepository) QueueDeliveries(ctx context.Context, eventId uuid.UUID, eventType string, payload json.RawMessage) (int, error) {
+	tag, err := r.DB.Exec(ctx, `
+		INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, payload, status, next_attempt_at, created_at, updated_at)
+		SELECT id, $1, $2, $3::JSONB, $4, $5, $5, $5
+		FROM webhooks
+		WHERE active AND $2 = ANY(event_types)
+		ON CONFLICT (webhook_id, event_id) WHERE event_id IS NOT NULL DO NOTHING`,
+		eventId,
+		eventType,
+		string(payload),
+		models.StatusQueued,
+		time.Now(),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to queue webhook deliveries: %w", err)
+	}
+
+	return int(tag.RowsAffected()), nil
+}
+
+/*
+QueueDelivery is a method of webhookRepository struct that queues a delivery without an event, such as a test event, for one webhook.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- webhookId (uuid.UUID): The ID of the webhook.
+- eventType (string): The type sent with the delivery.
+- payload (json.RawMessage): The data sent with the delivery.
+
+Returns:
+- (*models.DeliveryDTO, error): A pointer to a DeliveryDTO struct containing the queued delivery, or an error if the insertion fails.
+
+Behavior:
+- The delivery comes back claimed, so dispatchers leave it to the caller until the lease runs out.
+*/
+func (r *webhookRepository) QueueDelivery(ctx context.Context, webhookId uuid.UUID, eventType string, payload json.RawMessage) (*models.DeliveryDTO, error) {
+	now := time.Now()
+	return scanDelivery(r.DB.QueryRow(ctx, `
+		INSERT INTO webhook_deliveries (webhook_id, event_type, payload, status, next_attempt_at, created_at, updated_at)
+		VALUES ($1, $2, $3::JSONB, $4, $5, $6, $6)
+		RETURNING `+deliveryColumns,
+		webhookId,
+		eventType,
+		string(payload),
+		models.StatusQueued,
+		now.Add(claimLease),
+		now,
+	))
+}
+
+/*
+GetDelivery is a method of webhookRepository struct that retrieves a webhook delivery from the postgres database by its ID.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- id (uuid.UUID): The ID of the delivery.
+
+Returns:
+- (*models.DeliveryDTO, error): A pointer to a DeliveryDTO struct, or models.ErrDeliveryNotFound if there is none.
+*/
+func (r *webhookRepository) GetDelivery(ctx context.Context, id uuid.UUID) (*models.DeliveryDTO, error) {
+	return scanDelivery(r.DB.QueryRow(ctx, `SELECT `+deliveryColumns+` FROM webhook_deliveries WHERE id = $1`, id))
+}
+
+/*
+GetDeliveries is a method of webhookRepository struct that retrieves the deliveries of a webhook from the postgres database.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- webhookId (uuid.UUID): The ID of the webhook.
+- status (string): Only deliveries with this status are returned; an empty status returns all of them.
+- limit (int): The maximum number of deliveries returned.
+
+Returns:
+- ([]*models.DeliveryDTO, error): A slice of DeliveryDTO structs, newest first, or an error if the retrieval fails.
+*/
+func (r *webhookRepository) GetDeliveries(ctx context.Context, webhookId uuid.UUID, status string, limit int) ([]*models.DeliveryDTO, error) {
+	rows, err := r.DB.Query(ctx, `
+		SELECT `+deliveryColumns+`
+		FROM webhook_deliveries
+		WHERE webhook_id = $1 AND ($2 = '' OR status = $2)
+		ORDER BY created_at DESC
+		LIMIT $3`,
+		webhookId,
+		status,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := []*models.DeliveryDTO{}
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over webhook deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+/*
+GetAttempts is a method of webhookRepository struct that retrieves the delivery log of a webhook delivery from the postgres database.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- deliveryId (uuid.UUID): The ID of the delivery.
+
+Returns:
+- ([]*models.AttemptDTO, error): A slice of AttemptDTO structs, first attempt first, or an error if the retrieval fails.
+*/
+func (r *webhookRepository) GetAttempts(ctx context.Context, deliveryId uuid.UUID) ([]*models.AttemptDTO, error) {
+	rows, err := r.DB.Query(ctx, `
+		SELECT id, delivery_id, attempt, response_code, response_body, error, duration_ms, created_at
+		FROM webhook_attempts
+		WHERE delivery_id = $1
+		ORDER BY attempt`,
+		deliveryId,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook attempts: %w", err)
+	}
+	defer rows.Close()
+
+	attempts := []*models.AttemptDTO{}
+	for rows.Next() {
+		var attempt models.AttemptDTO
+		err := rows.Scan(
+			&attempt.ID,
+			&attempt.DeliveryID,
+			&attempt.Attempt,
+			&attempt.ResponseCode,
+			&attempt.ResponseBody,
+			&attempt.Error,
+			&attempt.DurationMs,
+			&attempt.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook attempt: %w", err)
+		}
+		attempts = append(attempts, &attempt)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over webhook attempts: %w", err)
+	}
+
+	return attempts, nil
+}
+
+/*
+Requeue is a method of webhookRepository struct that puts a failed delivery back in the queue.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- id (uuid.UUID): The ID of the delivery.
+
+Returns:
+- (*models.DeliveryDTO, error): A pointer to a DeliveryDTO struct containing the requeued delivery, or an error if the update fails.
+
+Behavior:
+- Resets the attempt counter, so the delivery gets the full number of attempts again.
+- Returns models.ErrDeliveryNotFound if there is no such delivery, or models.ErrNotFailed if it has not failed.
+*/
+func (r *webhookRepository) Requeue(ctx context.Context, id uuid.UUID) (*models.DeliveryDTO, error) {
+	delivery, err := scanDelivery(r.DB.QueryRow(ctx, `
+		UPDATE webhook_deliveries
+		SET status = $2, attempts = 0, next_attempt_at = $4, updated_at = $4
+		WHERE id = $1 AND status = $3
+		RETURNING `+deliveryColumns,
+		id,
+		models.StatusQueued,
+		models.StatusFailed,
+		time.Now(),
+	))
+	if errors.Is(err, models.ErrDeliveryNotFound) {
+		if _, err := r.GetDelivery(ctx, id); err != nil {
+			return nil, err
+		}
+		return nil, models.ErrNotFailed
+	}
+
+	return delivery, err
+}
+
+/*
+ClaimDue is a method of webhookRepository struct that hands out queued deliveries whose next attempt is due.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- limit (int): The maximum number of deliveries claimed.
+
+Returns:
+- ([]*models.DeliveryView, error): The claimed deliveries with the URL and secret of their webhook, or an error if claiming fails.
+
+Behavior:
+- Deliveries of inactive webhooks stay queued until the webhook is activated again.
+- Pushes the next attempt of each claimed delivery back by a lease, so other dispatchers skip it while it is sent.
+- A delivery whose dispatcher dies is picked up again once the lease runs out.
+*/
+func (r *webhookRepository) ClaimDue(ctx context.Context, limit int) ([]*models.DeliveryView, error) {
+	now := time.Now()
+	rows, err := r.DB.Query(ctx, `
+		WITH claimed AS (
+			UPDATE webhook_deliveries
+			SET next_attempt_at = $3
+			WHERE id IN (
+				SELECT d.id FROM webhook_deliveries d
+				JOIN webhooks w ON w.id = d.webhook_id
+				WHERE d.status = $1 AND d.next_attempt_at <= $2 AND w.active
+				ORDER BY d.next_attempt_at
+				LIMIT $4
+				FOR UPDATE OF d SKIP LOCKED
+			)
+			RETURNING `+deliveryColumns+`
+		)
+		SELECT c.*, w.url, w.secret
+		FROM claimed c
+		JOIN webhooks w ON w.id = c.webhook_id
+		ORDER BY c.created_at`,
+		models.StatusQueued,
+		now,
+		now.Add(claimLease),
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	var views []*models.DeliveryView
+	for rows.Next() {
+		var view models.DeliveryView
+		var payload string
+		err := rows.Scan(
+			&view.ID,
+			&view.WebhookID,
+			&view.EventID,
+			&view.EventType,
+			&payload,
+			&view.Status,
+			&view.Attempts,
+			&view.ResponseCode,
+			&view.LastError,
+			&view.NextAttemptAt,
+			&view.DeliveredAt,
+			&view.CreatedAt,
+			&view.UpdatedAt,
+			&view.URL,
+			&view.Secret,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan claimed webhook delivery: %w", err)
+		}
+		view.Payload = json.RawMessage(payload)
+		views = append(views, &view)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over claimed webhook deliveries: %w", err)
+	}
+
+	return views, nil
+}
+
+/*
+RecordAttempt is a method of webhookRepository struct that logs a delivery attempt and updates its delivery.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- attempt (*models.AttemptDTO): A pointer to an AttemptDTO struct describing the request and its response.
+- status (string): The delivery's status after the attempt.
+- nextAttemptAt (time.Time): When a still queued delivery is tried again.
+
+Returns:
+- (error): An error if the attempt cannot be recorded.
+
+Behavior:
+- Inserts the delivery log entry and updates the delivery in one transaction.
+*/
+func (r *webhookRepository) RecordAttempt(ctx context.Context, attempt *models.AttemptDTO, status string, nextAttemptAt time.Time) error {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin webhook attempt log: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	now := time.Now()
+	_, err = tx.Exec(ctx, `
+		INSERT INTO webhook_attempts (delivery_id, attempt, response_code, response_body, error, duration_ms, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		attempt.DeliveryID,
+		attempt.Attempt,
+		attempt.ResponseCode,
+		attempt.ResponseBody,
+		attempt.Error,
+		attempt.DurationMs,
+		now,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to log webhook attempt: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE webhook_deliveries
+		SET status = $2, attempts = $3, response_code = $4, last_error = $5, next_attempt_at = $6,
+			delivered_at = CASE WHEN $2 = 'delivered' THEN $7::TIMESTAMPTZ ELSE delivered_at END,
+			updated_at = $7
+		WHERE id = $1`,
+		attempt.DeliveryID,
+		status,
+		attempt.Attempt,
+		attempt.ResponseCode,
+		attempt.Error,
+		nextAttemptAt,
+		now,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook delivery: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit webhook attempt log: %w", err)
+	}
+
+	return nil
+}
+
+func scanWebhook(row pgx.Row) (*models.WebhookDTO, error) {
+	var webhook models.WebhookDTO
+	err := row.Scan(
+		&webhook.ID,
+		&webhook.URL,
+		&webhook.EventTypes,
+		&webhook.Secret,
+		&webhook.Active,
+		&webhook.CreatedAt,
+		&webhook.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, models.ErrWebhookNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan webhook: %w", err)
+	}
+
+	return &webhook, nil
+}
+
+func scanDelivery(row pgx.Row) (*models.DeliveryDTO, error) {
+	var delivery models.DeliveryDTO
+	var payload string
+	err := row.Scan(
+		&delivery.ID,
+		&delivery.WebhookID,
+		&delivery.EventID,
+		&delivery.EventType,
+		&payload,
+		&delivery.Status,
+		&delivery.Attempts,
+		&delivery.ResponseCode,
+		&delivery.LastError,
+		&delivery.NextAttemptAt,
+		&delivery.DeliveredAt,
+		&delivery.CreatedAt,
+		&delivery.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, models.ErrDeliveryNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan webhook delivery: %w", err)
+	}
+
+	delivery.Payload = json.RawMessage(payload)
+	return &delivery, nil
+}
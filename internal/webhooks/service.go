@@ -0,0 +1,250 @@
+package webhooks
+
+import (
+	eventModels "blockbustermvc/internal/models/event"
+	models "blockbustermvc/internal/models/webhook"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// dispatchBatch is how many deliveries are claimed at a time.
+	dispatchBatch = 50
+
+	// listLimit caps how many deliveries are listed at once.
+	listLimit = 200
+
+	// requestTimeout is how long a webhook has to answer.
+	requestTimeout = 10 * time.Second
+
+	// responseLimit caps how much of a response body is kept in the log.
+	responseLimit = 1024
+)
+
+type WebhookService struct {
+	webhookRepository models.IWebhookRepository
+	client            *http.Client
+}
+
+func NewWebhookService(webhookRepo models.IWebhookRepository) models.IWebhookService {
+	return &WebhookService{
+		webhookRepository: webhookRepo,
+		client: &http.Client{
+			Timeout: requestTimeout,
+			// A redirect is reported as the response it is, instead of
+			// being followed with the body dropped
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+func (w WebhookService) CreateWebhook(ctx context.Context, webhook *models.CreateWebhookDTO) (*models.WebhookDTO, error) {
+	eventTypes, err := validateEventTypes(webhook.EventTypes)
+	if err != nil {
+		return nil, err
+	}
+	webhook.EventTypes = eventTypes
+
+	return w.webhookRepository.CreateWebhook(ctx, webhook)
+}
+
+func (w WebhookService) GetWebhooks(ctx context.Context) ([]*models.WebhookDTO, error) {
+	return w.webhookRepository.GetWebhooks(ctx)
+}
+
+func (w WebhookService) GetWebhook(ctx context.Context, id uuid.UUID) (*models.WebhookDTO, error) {
+	return w.webhookRepository.GetWebhook(ctx, id)
+}
+
+func (w WebhookService) UpdateWebhook(ctx context.Context, id uuid.UUID, webhook *models.UpdateWebhookDTO) (*models.WebhookDTO, error) {
+	eventTypes, err := validateEventTypes(webhook.EventTypes)
+	if err != nil {
+		return nil, err
+	}
+	webhook.EventTypes = eventTypes
+
+	return w.webhookRepository.UpdateWebhook(ctx, id, webhook)
+}
+
+func (w WebhookService) DeleteWebhook(ctx context.Context, id uuid.UUID) error {
+	return w.webhookRepository.DeleteWebhook(ctx, id)
+}
+
+func (w WebhookService) GetDeliveries(ctx context.Context, webhookId uuid.UUID, status string) ([]*models.DeliveryDTO, error) {
+	if _, err := w.webhookRepository.GetWebhook(ctx, webhookId); err != nil {
+		return nil, err
+	}
+
+	return w.webhookRepository.GetDeliveries(ctx, webhookId, status, listLimit)
+}
+
+func (w WebhookService) GetAttempts(ctx context.Context, deliveryId uuid.UUID) ([]*models.AttemptDTO, error) {
+	if _, err := w.webhookRepository.GetDelivery(ctx, deliveryId); err != nil {
+		return nil, err
+	}
+
+	return w.webhookRepository.GetAttempts(ctx, deliveryId)
+}
+
+func (w WebhookService) Retry(ctx context.Context, deliveryId uuid.UUID) (*models.DeliveryDTO, error) {
+	return w.webhookRepository.Requeue(ctx, deliveryId)
+}
+
+// SendTest sends a webhook.test delivery right away and returns it with
+// the outcome. A failed test is retried like any other delivery.
+func (w WebhookService) SendTest(ctx context.Context, webhookId uuid.UUID) (*models.DeliveryDTO, error) {
+	webhook, err := w.webhookRepository.GetWebhook(ctx, webhookId)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := json.Marshal(map[string]any{
+		"webhook_id": webhook.ID,
+		"message":    "Test event from Blockbuster",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode test event: %w", err)
+	}
+
+	delivery, err := w.webhookRepository.QueueDelivery(ctx, webhook.ID, models.TestEventType, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	view := &models.DeliveryView{DeliveryDTO: *delivery, URL: webhook.URL, Secret: webhook.Secret}
+	if err := w.deliver(ctx, view, &models.DispatchResultDTO{}); err != nil {
+		return nil, err
+	}
+
+	return w.webhookRepository.GetDelivery(ctx, delivery.ID)
+}
+
+// QueueEvent queues a domain event for every active webhook subscribed to
+// its type.
+func (w WebhookService) QueueEvent(ctx context.Context, eventId uuid.UUID, eventType string, payload json.RawMessage) (int, error) {
+	return w.webhookRepository.QueueDeliveries(ctx, eventId, eventType, payload)
+}
+
+// Dispatch sends every queued delivery whose next attempt is due. Failed
+// sends are retried with a growing delay until models.MaxAttempts.
+func (w WebhookService) Dispatch(ctx context.Context) (*models.DispatchResultDTO, error) {
+	result := &models.DispatchResultDTO{}
+
+	for {
+		views, err := w.webhookRepository.ClaimDue(ctx, dispatchBatch)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, view := range views {
+			if err := w.deliver(ctx, view, result); err != nil {
+				return nil, err
+			}
+		}
+
+		if len(views) < dispatchBatch {
+			return result, nil
+		}
+	}
+}
+
+func (w WebhookService) deliver(ctx context.Context, view *models.DeliveryView, result *models.DispatchResultDTO) error {
+	attempt := &models.AttemptDTO{
+		DeliveryID: view.ID,
+		Attempt:    view.Attempts + 1,
+	}
+
+	started := time.Now()
+	responseCode, responseBody, err := w.post(ctx, view)
+	attempt.DurationMs = time.Since(started).Milliseconds()
+	attempt.ResponseBody = responseBody
+	if responseCode != 0 {
+		attempt.ResponseCode = &responseCode
+	}
+
+	status, nextAttemptAt := models.StatusDelivered, time.Now()
+	if err != nil {
+		attempt.Error = err.Error()
+
+		status = models.StatusQueued
+		nextAttemptAt = nextAttemptAt.Add(models.RetryDelay(attempt.Attempt))
+		if attempt.Attempt >= models.MaxAttempts {
+			status = models.StatusFailed
+		}
+		result.Failed++
+	} else {
+		result.Delivered++
+	}
+
+	// A delivery that was sent is recorded even when the dispatcher is stopping
+	return w.webhookRepository.RecordAttempt(context.WithoutCancel(ctx), attempt, status, nextAttemptAt)
+}
+
+// post sends a delivery and returns the response code and the start of
+// the response body. Anything but a 2xx response is an error.
+func (w WebhookService) post(ctx context.Context, view *models.DeliveryView) (int, string, error) {
+	body, err := json.Marshal(&models.EnvelopeDTO{
+		ID:        view.ID,
+		Type:      view.EventType,
+		EventID:   view.EventID,
+		CreatedAt: view.CreatedAt,
+		Data:      view.Payload,
+	})
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to encode delivery: %w", err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, view.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, "", err
+	}
+
+	timestamp := time.Now().Unix()
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "Blockbuster-Webhooks/1.0")
+	request.Header.Set(models.HeaderEvent, view.EventType)
+	request.Header.Set(models.HeaderDelivery, view.ID.String())
+	request.Header.Set(models.HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	request.Header.Set(models.HeaderSignature, Sign(view.Secret, timestamp, body))
+
+	response, err := w.client.Do(request)
+	if err != nil {
+		return 0, "", err
+	}
+	defer response.Body.Close()
+
+	// The log is TEXT, which takes neither invalid UTF-8 nor NUL bytes
+	responseBody, _ := io.ReadAll(io.LimitReader(response.Body, responseLimit))
+	text := strings.ReplaceAll(strings.ToValidUTF8(string(responseBody), "\uFFFD"), "\x00", "")
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return response.StatusCode, text, fmt.Errorf("unexpected response %s", response.Status)
+	}
+
+	return response.StatusCode, text, nil
+}
+
+// validateEventTypes rejects unknown event types and drops duplicates.
+func validateEventTypes(eventTypes []string) ([]string, error) {
+	for _, eventType := range eventTypes {
+		if !slices.Contains(eventModels.Types, eventType) {
+			return nil, fmt.Errorf("%w: %s", models.ErrUnknownEventType, eventType)
+		}
+	}
+
+	eventTypes = slices.Clone(eventTypes)
+	slices.Sort(eventTypes)
+	return slices.Compact(eventTypes), nil
+}
@@ -0,0 +1,144 @@
+package webhooks
+
+import (
+	models "blockbustermvc/internal/models/webhook"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type recordedAttempt struct {
+	attempt       *models.AttemptDTO
+	status        string
+	nextAttemptAt time.Time
+}
+
+type fakeWebhookRepository struct {
+	models.IWebhookRepository
+	due      []*models.DeliveryView
+	attempts []recordedAttempt
+}
+
+func (f *fakeWebhookRepository) ClaimDue(context.Context, int) ([]*models.DeliveryView, error) {
+	due := f.due
+	f.due = nil
+	return due, nil
+}
+
+func (f *fakeWebhookRepository) RecordAttempt(_ context.Context, attempt *models.AttemptDTO, status string, nextAttemptAt time.Time) error {
+	f.attempts = append(f.attempts, recordedAttempt{attempt: attempt, status: status, nextAttemptAt: nextAttemptAt})
+	return nil
+}
+
+// receivedDelivery is what the test webhook saw of one request.
+type receivedDelivery struct {
+	header http.Header
+	body   []byte
+}
+
+func TestDispatchSignsAndRecordsDeliveries(t *testing.T) {
+	const secret = "whsec_test"
+
+	tests := []struct {
+		name         string
+		responseCode int
+		attempts     int
+		wantStatus   string
+		wantRetry    bool
+	}{
+		{name: "delivered", responseCode: http.StatusOK, wantStatus: models.StatusDelivered},
+		{name: "server error is retried", responseCode: http.StatusInternalServerError, wantStatus: models.StatusQueued, wantRetry: true},
+		{name: "redirect is not followed", responseCode: http.StatusFound, wantStatus: models.StatusQueued, wantRetry: true},
+		{name: "last attempt fails", responseCode: http.StatusBadGateway, attempts: models.MaxAttempts - 1, wantStatus: models.StatusFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			received := make(chan receivedDelivery, 1)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				received <- receivedDelivery{header: r.Header.Clone(), body: body}
+				if tt.responseCode == http.StatusFound {
+					w.Header().Set("Location", "/elsewhere")
+				}
+				w.WriteHeader(tt.responseCode)
+				io.WriteString(w, "webhook says hi")
+			}))
+			defer server.Close()
+
+			delivery := &models.DeliveryView{
+				DeliveryDTO: models.DeliveryDTO{
+					ID:        uuid.New(),
+					EventType: "loan.created",
+					Payload:   json.RawMessage(`{"loan_id":"42"}`),
+					Attempts:  tt.attempts,
+					CreatedAt: time.Now(),
+				},
+				URL:    server.URL,
+				Secret: secret,
+			}
+			repo := &fakeWebhookRepository{due: []*models.DeliveryView{delivery}}
+
+			started := time.Now()
+			if _, err := NewWebhookService(repo).Dispatch(t.Context()); err != nil {
+				t.Fatalf("Dispatch() error = %v", err)
+			}
+
+			request := <-received
+			mac := hmac.New(sha256.New, []byte(secret))
+			mac.Write([]byte(request.header.Get(models.HeaderTimestamp) + "."))
+			mac.Write(request.body)
+			wantSignature := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+			if got := request.header.Get(models.HeaderSignature); !hmac.Equal([]byte(got), []byte(wantSignature)) {
+				t.Errorf("%s = %q, want %q", models.HeaderSignature, got, wantSignature)
+			}
+			if got := request.header.Get(models.HeaderDelivery); got != delivery.ID.String() {
+				t.Errorf("%s = %q, want %q", models.HeaderDelivery, got, delivery.ID)
+			}
+
+			var envelope models.EnvelopeDTO
+			if err := json.Unmarshal(request.body, &envelope); err != nil {
+				t.Fatalf("request body is not an envelope: %v", err)
+			}
+			if envelope.ID != delivery.ID || envelope.Type != delivery.EventType || string(envelope.Data) != string(delivery.Payload) {
+				t.Errorf("envelope = %+v, want delivery %s of %s with its payload", envelope, delivery.ID, delivery.EventType)
+			}
+
+			if len(repo.attempts) != 1 {
+				t.Fatalf("recorded %d attempts, want 1", len(repo.attempts))
+			}
+			recorded := repo.attempts[0]
+			if recorded.attempt.Attempt != tt.attempts+1 {
+				t.Errorf("attempt number = %d, want %d", recorded.attempt.Attempt, tt.attempts+1)
+			}
+			if recorded.attempt.ResponseCode == nil || *recorded.attempt.ResponseCode != tt.responseCode {
+				t.Errorf("response code = %v, want %d", recorded.attempt.ResponseCode, tt.responseCode)
+			}
+			if recorded.attempt.ResponseBody != "webhook says hi" {
+				t.Errorf("response body = %q, want %q", recorded.attempt.ResponseBody, "webhook says hi")
+			}
+			if recorded.status != tt.wantStatus {
+				t.Errorf("status = %q, want %q", recorded.status, tt.wantStatus)
+			}
+
+			if tt.wantRetry {
+				earliest := started.Add(models.RetryDelay(tt.attempts + 1))
+				if recorded.nextAttemptAt.Before(earliest) {
+					t.Errorf("next attempt at %v, want at least %v", recorded.nextAttemptAt, earliest)
+				}
+				if recorded.attempt.Error == "" {
+					t.Error("attempt error is empty, want the unexpected response")
+				}
+			}
+		})
+	}
+}
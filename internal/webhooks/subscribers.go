@@ -0,0 +1,16 @@
+package webhooks
+
+import (
+	eventModels "blockbustermvc/internal/models/event"
+	models "blockbustermvc/internal/models/webhook"
+	"context"
+)
+
+// Subscribe queues every domain event for the webhooks subscribed to its
+// type. A redelivered event is not queued twice for the same webhook.
+func Subscribe(eventService eventModels.IEventService, service models.IWebhookService) {
+	eventService.Subscribe("webhooks", func(ctx context.Context, event *eventModels.EventDTO) error {
+		_, err := service.QueueEvent(ctx, event.ID, event.Type, event.Payload)
+		return err
+	})
+}
@@ -0,0 +1,116 @@
+package wishlists
+
+import (
+	models "blockbustermvc/internal/models/wishlist"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type WishlistController struct {
+	wishlistService models.IWishlistService
+}
+
+func NewWishlistController(wishlistService models.IWishlistService) *WishlistController {
+	return &WishlistController{
+		wishlistService: wishlistService,
+	}
+}
+
+func (wc *WishlistController) RegisterRoutes(r *gin.RouterGroup) {
+	wishlist := r.Group("/users/:id/wishlist")
+
+	{
+		wishlist.GET("", wc.GetWishlist)
+		wishlist.POST("", wc.AddItem)
+		wishlist.DELETE("/:movieId", wc.RemoveItem)
+	}
+}
+
+func (wc *WishlistController) GetWishlist(ctx *gin.Context) {
+	userId, ok := parseUserID(ctx)
+	if !ok {
+		return
+	}
+
+	items, err := wc.wishlistService.GetUserWishlist(ctx.Request.Context(), userId)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, items)
+}
+
+func (wc *WishlistController) AddItem(ctx *gin.Context) {
+	userId, ok := parseUserID(ctx)
+	if !ok {
+		return
+	}
+
+	var req models.AddWishlistItemDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request body",
+		})
+		return
+	}
+
+	item, err := wc.wishlistService.AddItem(ctx.Request.Context(), userId, &req)
+	if err != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, item)
+}
+
+func (wc *WishlistController) RemoveItem(ctx *gin.Context) {
+	userId, ok := parseUserID(ctx)
+	if !ok {
+		return
+	}
+
+	movieId, err := uuid.Parse(ctx.Param("movieId"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid movie ID",
+		})
+		return
+	}
+
+	if err := wc.wishlistService.RemoveItem(ctx.Request.Context(), userId, movieId); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, models.ErrWishlistItemNotFound) {
+			status = http.StatusNotFound
+		}
+		if status == http.StatusInternalServerError {
+			slog.ErrorContext(ctx.Request.Context(), "Failed to remove wishlist item", "error", err)
+		}
+		ctx.JSON(status, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func parseUserID(ctx *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid user ID",
+		})
+		return uuid.Nil, false
+	}
+
+	return id, true
+}
@@ -0,0 +1,38 @@
+package wishlists
+
+import (
+	notificationModels "blockbustermvc/internal/models/notification"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// QueueAvailabilityNotifications queues a notification for every customer
+// still waiting for the movie and marks their wishlist entries as notified.
+// It runs inside the stock movement's transaction, so the notifications
+// are queued if and only if the restock is committed.
+func QueueAvailabilityNotifications(ctx context.Context, tx pgx.Tx, storeId, movieId uuid.UUID) error {
+	_, err := tx.Exec(ctx, `
+		WITH waiting AS (
+			UPDATE wishlist_items
+			SET notified_at = $4, updated_at = $4
+			WHERE movie_id = $2 AND notified_at IS NULL
+			RETURNING user_id
+		)
+		INSERT INTO notifications (user_id, kind, movie_id, store_id, status, created_at, updated_at)
+		SELECT user_id, $3, $2, $1, $5, $4, $4 FROM waiting`,
+		storeId,
+		movieId,
+		notificationModels.KindMovieAvailable,
+		time.Now(),
+		notificationModels.StatusQueued,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to queue availability notifications: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,151 @@
+package wishlists
+
+import (
+	models "blockbustermvc/internal/models/wishlist"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const selectWishlistItem = `
+	SELECT w.id, w.user_id, w.movie_id, m.name, m.director, m.year, m.quantity > 0,
+		w.notified_at, w.created_at, w.updated_at
+	FROM wishlist_items w
+	JOIN movies m ON m.id = w.movie_id`
+
+/*
+wishlistRepository is a struct that represents a Postgres database for storing customer wishlists.
+
+Fields:
+- DB (*pgxpool.Pool): A pointer to a Postgres database connection pool.
+
+Behavior:
+- Provides methods for interacting with the wishlist_items table in the database.
+*/
+type wishlistRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewWishlistRepository(db *pgxpool.Pool) models.IWishlistRepository {
+	return &wishlistRepository{
+		DB: db,
+	}
+}
+
+/*
+AddItem is a method of wishlistRepository struct that puts a movie on a user's wishlist in the postgres database.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- userId (uuid.UUID): The ID of the user.
+- movieId (uuid.UUID): The ID of the movie.
+
+Returns:
+- (*models.WishlistItemDTO, error): A pointer to a WishlistItemDTO struct containing the wishlist entry, or an error if the insertion fails.
+
+Behavior:
+- Adding a movie that is already on the wishlist keeps the entry but clears its notified_at, so the user is told again on the next restock.
+*/
+func (r *wishlistRepository) AddItem(ctx context.Context, userId, movieId uuid.UUID) (*models.WishlistItemDTO, error) {
+	query := `
+		INSERT INTO wishlist_items (user_id, movie_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $3)
+		ON CONFLICT (user_id, movie_id) DO UPDATE SET notified_at = NULL, updated_at = EXCLUDED.updated_at
+		RETURNING id`
+
+	var id uuid.UUID
+	if err := r.DB.QueryRow(ctx, query, userId, movieId, time.Now()).Scan(&id); err != nil {
+		return nil, fmt.Errorf("failed to add movie to wishlist: %w", err)
+	}
+
+	return scanWishlistItem(r.DB.QueryRow(ctx, selectWishlistItem+` WHERE w.id = $1`, id))
+}
+
+/*
+GetUserWishlist is a method of wishlistRepository struct that retrieves a user's wishlist from the postgres database.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- userId (uuid.UUID): The ID of the user.
+
+Returns:
+- ([]*models.WishlistItemDTO, error): A slice of WishlistItemDTO structs, most recently added first, or an error if the retrieval fails.
+*/
+func (r *wishlistRepository) GetUserWishlist(ctx context.Context, userId uuid.UUID) ([]*models.WishlistItemDTO, error) {
+	rows, err := r.DB.Query(ctx, selectWishlistItem+` WHERE w.user_id = $1 ORDER BY w.created_at DESC`, userId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wishlist: %w", err)
+	}
+	defer rows.Close()
+
+	items := []*models.WishlistItemDTO{}
+	for rows.Next() {
+		item, err := scanWishlistItem(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over wishlist: %w", err)
+	}
+
+	return items, nil
+}
+
+/*
+RemoveItem is a method of wishlistRepository struct that takes a movie off a user's wishlist in the postgres database.
+
+Parameters:
+- ctx (context.Context): Cancels the query when the calling request or job ends.
+- userId (uuid.UUID): The ID of the user.
+- movieId (uuid.UUID): The ID of the movie.
+
+Returns:
+- (error): An error if the deletion fails.
+
+Behavior:
+- Returns models.ErrWishlistItemNotFound if the movie is not on the wishlist.
+*/
+func (r *wishlistRepository) RemoveItem(ctx context.Context, userId, movieId uuid.UUID) error {
+	tag, err := r.DB.Exec(ctx, `DELETE FROM wishlist_items WHERE user_id = $1 AND movie_id = $2`, userId, movieId)
+	if err != nil {
+		return fmt.Errorf("failed to remove movie from wishlist: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return models.ErrWishlistItemNotFound
+	}
+
+	return nil
+}
+
+func scanWishlistItem(row pgx.Row) (*models.WishlistItemDTO, error) {
+	var item models.WishlistItemDTO
+	err := row.Scan(
+		&item.ID,
+		&item.UserID,
+		&item.MovieID,
+		&item.MovieName,
+		&item.Director,
+		&item.Year,
+		&item.Available,
+		&item.NotifiedAt,
+		&item.CreatedAt,
+		&item.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, models.ErrWishlistItemNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan wishlist item: %w", err)
+	}
+
+	return &item, nil
+}
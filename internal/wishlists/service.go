@@ -0,0 +1,52 @@
+package wishlists
+
+import (
+	movieService "blockbustermvc/internal/models/movie"
+	userService "blockbustermvc/internal/models/user"
+	models "blockbustermvc/internal/models/wishlist"
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type WishlistService struct {
+	wishlistRepository models.IWishlistRepository
+	movieService       movieService.IMovieService
+	userService        userService.IUserService
+}
+
+func NewWishlistService(
+	wishlistRepo models.IWishlistRepository,
+	movieService movieService.IMovieService,
+	userService userService.IUserService,
+) models.IWishlistService {
+	return &WishlistService{
+		wishlistRepository: wishlistRepo,
+		movieService:       movieService,
+		userService:        userService,
+	}
+}
+
+func (w WishlistService) AddItem(ctx context.Context, userId uuid.UUID, item *models.AddWishlistItemDTO) (*models.WishlistItemDTO, error) {
+	if _, err := w.userService.GetUser(ctx, userId); err != nil {
+		return nil, err
+	}
+
+	if _, err := w.movieService.GetMovie(ctx, item.MovieID); err != nil {
+		return nil, err
+	}
+
+	return w.wishlistRepository.AddItem(ctx, userId, item.MovieID)
+}
+
+func (w WishlistService) GetUserWishlist(ctx context.Context, userId uuid.UUID) ([]*models.WishlistItemDTO, error) {
+	if _, err := w.userService.GetUser(ctx, userId); err != nil {
+		return nil, err
+	}
+
+	return w.wishlistRepository.GetUserWishlist(ctx, userId)
+}
+
+func (w WishlistService) RemoveItem(ctx context.Context, userId, movieId uuid.UUID) error {
+	return w.wishlistRepository.RemoveItem(ctx, userId, movieId)
+}